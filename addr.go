@@ -0,0 +1,158 @@
+package routing
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+)
+
+// Address is an IP address assigned to a network interface.
+type Address struct {
+	Index     int        // The index of the interface.
+	Interface string     // The name of the interface; empty if it could not be determined.
+	IPNet     *net.IPNet // The address and the prefix length of its subnet.
+}
+
+// AddressEvent is an address being assigned to or removed from an interface.
+type AddressEvent struct {
+	Address Address
+	Removed bool // The address was removed.
+}
+
+// key identifies an address by interface index and address, as the kernel does.
+func (a Address) key() string {
+	return a.IPNet.String() + "%" + strconv.Itoa(a.Index)
+}
+
+// Addresses returns the IP addresses assigned to the host's network interfaces.
+func Addresses() ([]Address, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []Address
+	for _, ifi := range ifaces {
+		ifAddrs, err := ifi.Addrs()
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range ifAddrs {
+			if ipNet, ok := a.(*net.IPNet); ok {
+				addrs = append(addrs, Address{Index: ifi.Index, Interface: ifi.Name, IPNet: ipNet})
+			}
+		}
+	}
+
+	return addrs, nil
+}
+
+// addrUpdate is a notification about one address.
+type addrUpdate struct {
+	addr    Address
+	removed bool
+}
+
+// addrUpdateSource delivers address notifications to an AddressWatcher.
+type addrUpdateSource interface {
+	// receive blocks until notifications arrive. With full set, the updates list every address,
+	// as after notifications were lost, and addresses missing from them have been removed.
+	receive(ctx context.Context) (updates []addrUpdate, full bool, err error)
+	Close() error
+}
+
+// AddressWatcher reports IP addresses being assigned to or removed from the host's interfaces, such as a new
+// lease after a DHCP renewal or a SLAAC address, even when the routing table stays the same.
+// It is notified by the kernel rather than polling. An AddressWatcher is not safe for concurrent use.
+type AddressWatcher struct {
+	source  addrUpdateSource
+	addrs   map[string]Address // The assigned addresses, keyed by Address.key.
+	pending []AddressEvent     // Events received but not yet returned by Next.
+}
+
+// WatchAddresses starts watching the host's IPv4 and IPv6 addresses through RTNLGRP_IPV4_IFADDR and
+// RTNLGRP_IPV6_IFADDR notifications. Only changes after it returns are reported; Addresses gives the
+// addresses to start from. It is only available on Linux and returns ErrNotSupported elsewhere.
+func WatchAddresses() (*AddressWatcher, error) {
+	source, err := newAddrUpdateSource()
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := Addresses()
+	if err != nil {
+		source.Close()
+		return nil, err
+	}
+
+	return newAddressWatcher(source, addrs), nil
+}
+
+// newAddressWatcher returns an AddressWatcher receiving from source, starting from the given addresses.
+func newAddressWatcher(source addrUpdateSource, addrs []Address) *AddressWatcher {
+	w := &AddressWatcher{source: source, addrs: make(map[string]Address, len(addrs))}
+	for _, a := range addrs {
+		w.addrs[a.key()] = a
+	}
+
+	return w
+}
+
+// Next blocks until an address is assigned or removed and returns the change. It returns ctx.Err() once ctx is done.
+// Notifications for addresses already known, such as IPv6 lifetime updates, are skipped.
+func (w *AddressWatcher) Next(ctx context.Context) (AddressEvent, error) {
+	for len(w.pending) == 0 {
+		updates, full, err := w.source.receive(ctx)
+		if err != nil {
+			return AddressEvent{}, err
+		}
+		w.apply(updates, full)
+	}
+
+	ev := w.pending[0]
+	w.pending = w.pending[1:]
+
+	return ev, nil
+}
+
+// Close stops watching.
+func (w *AddressWatcher) Close() error {
+	return w.source.Close()
+}
+
+// apply records updates and queues an event for every address assigned or removed.
+func (w *AddressWatcher) apply(updates []addrUpdate, full bool) {
+	seen := make(map[string]bool, len(updates))
+	for _, u := range updates {
+		key := u.addr.key()
+		old, known := w.addrs[key]
+		if u.removed {
+			if known {
+				delete(w.addrs, key)
+				w.pending = append(w.pending, AddressEvent{Address: old, Removed: true})
+			}
+			continue
+		}
+
+		seen[key] = true
+		if !known {
+			w.addrs[key] = u.addr
+			w.pending = append(w.pending, AddressEvent{Address: u.addr})
+		}
+	}
+	if !full {
+		return
+	}
+
+	var gone []string
+	for key := range w.addrs {
+		if !seen[key] {
+			gone = append(gone, key)
+		}
+	}
+	sort.Strings(gone)
+	for _, key := range gone {
+		w.pending = append(w.pending, AddressEvent{Address: w.addrs[key], Removed: true})
+		delete(w.addrs, key)
+	}
+}
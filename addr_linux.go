@@ -0,0 +1,96 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"syscall"
+)
+
+// netlinkAddrSource receives RTNLGRP_IPV4_IFADDR and RTNLGRP_IPV6_IFADDR notifications.
+type netlinkAddrSource struct {
+	sub *nlSubscription
+}
+
+// newAddrUpdateSource subscribes to the kernel's address notifications.
+func newAddrUpdateSource() (addrUpdateSource, error) {
+	sub, err := netlinkSubscribe(syscall.RTNLGRP_IPV4_IFADDR, syscall.RTNLGRP_IPV6_IFADDR)
+	if err != nil {
+		return nil, err
+	}
+
+	return netlinkAddrSource{sub: sub}, nil
+}
+
+// receive decodes the next batch of RTM_NEWADDR and RTM_DELADDR notifications.
+// When notifications were lost, it lists every address instead.
+func (s netlinkAddrSource) receive(ctx context.Context) ([]addrUpdate, bool, error) {
+	msgs, err := s.sub.Receive(ctx)
+	if errors.Is(err, errNotificationsLost) {
+		addrs, err := Addresses()
+		if err != nil {
+			return nil, false, err
+		}
+		updates := make([]addrUpdate, len(addrs))
+		for i, a := range addrs {
+			updates[i] = addrUpdate{addr: a}
+		}
+		return updates, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var updates []addrUpdate
+	for _, m := range msgs {
+		if m.Header.Type != syscall.RTM_NEWADDR && m.Header.Type != syscall.RTM_DELADDR {
+			continue
+		}
+		if a, ok := parseAddrMsg(m.Data); ok {
+			updates = append(updates, addrUpdate{addr: a, removed: m.Header.Type == syscall.RTM_DELADDR})
+		}
+	}
+
+	return updates, false, nil
+}
+
+// Close closes the netlink socket.
+func (s netlinkAddrSource) Close() error {
+	return s.sub.Close()
+}
+
+// parseAddrMsg decodes the payload of an RTM_NEWADDR or RTM_DELADDR message: a struct ifaddrmsg and its attributes.
+// IFA_LOCAL holds the local address of point-to-point interfaces, whose IFA_ADDRESS is the peer's.
+func parseAddrMsg(b []byte) (Address, bool) {
+	if len(b) < syscall.SizeofIfAddrmsg {
+		return Address{}, false
+	}
+	family, prefixLen := b[0], int(b[1])
+	index := int(binary.NativeEndian.Uint32(b[4:8]))
+	attrs := netlinkAttrs(b[syscall.SizeofIfAddrmsg:])
+
+	ip := attrs[syscall.IFA_LOCAL]
+	if ip == nil {
+		ip = attrs[syscall.IFA_ADDRESS]
+	}
+	bits := net.IPv4len * 8
+	if family == syscall.AF_INET6 {
+		bits = net.IPv6len * 8
+	}
+	if len(ip)*8 != bits || prefixLen > bits {
+		return Address{}, false
+	}
+
+	// The label of IPv4 addresses may be an alias such as "eth0:1"; IPv6 addresses have none.
+	name := interfaceName(index)
+	if name == "" {
+		name = nlString(attrs[syscall.IFA_LABEL])
+	}
+
+	return Address{
+		Index:     index,
+		Interface: name,
+		IPNet:     &net.IPNet{IP: net.IP(ip), Mask: net.CIDRMask(prefixLen, bits)},
+	}, true
+}
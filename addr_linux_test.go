@@ -0,0 +1,88 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestParseAddrMsg(t *testing.T) {
+	tests := []struct {
+		family    uint8
+		prefixLen uint8
+		attrs     [][]byte
+		expected  string
+		ok        bool
+	}{
+		{syscall.AF_INET, 24, [][]byte{nlAttr(syscall.IFA_ADDRESS, []byte{192, 0, 2, 10}), nlAttr(syscall.IFA_LABEL, []byte("x0:1\x00"))}, "192.0.2.10/24", true},
+		{syscall.AF_INET, 32, [][]byte{nlAttr(syscall.IFA_ADDRESS, []byte{192, 0, 2, 1}), nlAttr(syscall.IFA_LOCAL, []byte{192, 0, 2, 2})}, "192.0.2.2/32", true},
+		{syscall.AF_INET6, 64, [][]byte{nlAttr(syscall.IFA_ADDRESS, net.ParseIP("2001:db8::1"))}, "2001:db8::1/64", true},
+		{syscall.AF_INET, 24, nil, "", false},
+		{syscall.AF_INET, 40, [][]byte{nlAttr(syscall.IFA_ADDRESS, []byte{192, 0, 2, 10})}, "", false},
+	}
+
+	for _, test := range tests {
+		msg := make([]byte, syscall.SizeofIfAddrmsg)
+		msg[0], msg[1] = test.family, test.prefixLen
+		binary.NativeEndian.PutUint32(msg[4:8], 1<<30) // No such interface, so the label is used.
+		for _, a := range test.attrs {
+			msg = append(msg, a...)
+		}
+
+		a, ok := parseAddrMsg(msg)
+		if ok != test.ok {
+			t.Errorf("parseAddrMsg(%s) ok = %v, want %v", test.expected, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if prefixLen, _ := a.IPNet.Mask.Size(); a.IPNet.IP.String()+"/"+strconv.Itoa(prefixLen) != test.expected {
+			t.Errorf("parseAddrMsg = %v, want %s", a.IPNet, test.expected)
+		}
+		if a.Index != 1<<30 {
+			t.Errorf("Expected index %d, got %d", 1<<30, a.Index)
+		}
+	}
+
+	msg := make([]byte, syscall.SizeofIfAddrmsg)
+	msg[0], msg[1] = syscall.AF_INET, 24
+	msg = append(msg, nlAttr(syscall.IFA_ADDRESS, []byte{192, 0, 2, 10})...)
+	msg = append(msg, nlAttr(syscall.IFA_LABEL, []byte("x0:1\x00"))...)
+	if a, _ := parseAddrMsg(msg); a.Interface != "x0:1" {
+		t.Errorf("Expected the label of an unknown interface, got %q", a.Interface)
+	}
+}
+
+func TestWatchAddresses(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+
+	w, err := WatchAddresses()
+	if err != nil {
+		t.Fatalf("WatchAddresses failed %s", err.Error())
+	}
+	defer w.Close()
+
+	setLoopbackUp(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for {
+		ev, err := w.Next(ctx)
+		if err != nil {
+			t.Fatalf("Expected 127.0.0.1 to be assigned to lo: %s", err.Error())
+		}
+		if ev.Address.IPNet.IP.Equal(net.IPv4(127, 0, 0, 1)) {
+			if ev.Address.Interface != "lo" || ev.Removed {
+				t.Errorf("Unexpected event %+v", ev)
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package routing
+
+// newAddrUpdateSource always returns ErrNotSupported, as netlink is only available on Linux.
+func newAddrUpdateSource() (addrUpdateSource, error) {
+	return nil, ErrNotSupported
+}
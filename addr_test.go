@@ -0,0 +1,95 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+// fakeAddrSource replays batches of address updates.
+type fakeAddrSource struct {
+	batches []fakeAddrBatch
+	closed  bool
+}
+
+type fakeAddrBatch struct {
+	updates []addrUpdate
+	full    bool
+}
+
+func (s *fakeAddrSource) receive(ctx context.Context) ([]addrUpdate, bool, error) {
+	if len(s.batches) == 0 {
+		return nil, false, context.Canceled
+	}
+	b := s.batches[0]
+	s.batches = s.batches[1:]
+
+	return b.updates, b.full, nil
+}
+
+func (s *fakeAddrSource) Close() error {
+	s.closed = true
+	return nil
+}
+
+func mustAddress(t *testing.T, index int, name, cidr string) Address {
+	t.Helper()
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		t.Fatalf("ParseCIDR failed %s", err.Error())
+	}
+	ipNet.IP = ip
+
+	return Address{Index: index, Interface: name, IPNet: ipNet}
+}
+
+func TestAddressWatcherNext(t *testing.T) {
+	lo := mustAddress(t, 1, "lo", "127.0.0.1/8")
+	lease := mustAddress(t, 2, "eth0", "192.0.2.10/24")
+	renewed := mustAddress(t, 2, "eth0", "192.0.2.23/24")
+	slaac := mustAddress(t, 2, "eth0", "2001:db8::1234/64")
+
+	src := &fakeAddrSource{batches: []fakeAddrBatch{
+		{updates: []addrUpdate{{addr: slaac}, {addr: slaac}}}, // The second one is a lifetime update.
+		{updates: []addrUpdate{{addr: lease, removed: true}, {addr: renewed}}},
+		{updates: []addrUpdate{{addr: lease, removed: true}}},               // Already gone.
+		{updates: []addrUpdate{{addr: renewed}, {addr: slaac}}, full: true}, // Notifications were lost and lo is gone.
+	}}
+	w := newAddressWatcher(src, []Address{lo, lease})
+
+	expected := []AddressEvent{
+		{Address: slaac},
+		{Address: lease, Removed: true},
+		{Address: renewed},
+		{Address: lo, Removed: true},
+	}
+	for i, want := range expected {
+		got, err := w.Next(context.Background())
+		if err != nil {
+			t.Fatalf("Next %d failed %s", i, err.Error())
+		}
+		if got.Address.key() != want.Address.key() || got.Address.Interface != want.Address.Interface || got.Removed != want.Removed {
+			t.Errorf("Event %d = %v %v, want %v %v", i, got.Address.IPNet, got.Removed, want.Address.IPNet, want.Removed)
+		}
+	}
+
+	if _, err := w.Next(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the source's error once it is exhausted, got %v", err)
+	}
+	if w.Close(); !src.closed {
+		t.Error("Expected Close to close the source")
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	addrs, err := Addresses()
+	if err != nil {
+		t.Fatalf("Addresses failed %s", err.Error())
+	}
+	for _, a := range addrs {
+		if a.Index <= 0 || a.IPNet == nil {
+			t.Errorf("Expected an interface index and an address, got %+v", a)
+		}
+	}
+}
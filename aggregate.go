@@ -0,0 +1,202 @@
+package routing
+
+import (
+	"encoding/binary"
+	"net"
+	"sort"
+)
+
+// aggPrefix is an IPv4 prefix as a host-order address and a prefix length.
+type aggPrefix struct {
+	addr uint32
+	ones int
+}
+
+// contains reports whether q lies within p.
+func (p aggPrefix) contains(q aggPrefix) bool {
+	return q.ones >= p.ones && q.addr&prefixMask(p.ones) == p.addr
+}
+
+// truncate returns the prefix of length ones covering p.
+func (p aggPrefix) truncate(ones int) aggPrefix {
+	return aggPrefix{p.addr & prefixMask(ones), ones}
+}
+
+// prefixMask returns the netmask for a prefix length as a host-order integer.
+func prefixMask(ones int) uint32 {
+	if ones == 0 {
+		return 0
+	}
+
+	return ^uint32(0) << (32 - ones)
+}
+
+// aggRoute is a route taking part in aggregation.
+type aggRoute struct {
+	prefix aggPrefix
+	pos    int          // Index of the first input route it stands for, which fixes its place in the result.
+	rt     RoutingTable // The route the aggregate copies its nexthop and attributes from.
+}
+
+// aggKey groups routes that differ only in their prefix, so that they may be merged.
+type aggKey struct {
+	table   int
+	typ     RouteType
+	metric  uint32
+	nexthop string
+	prefSrc string
+	onLink  bool
+	metrics RouteMetrics
+}
+
+// Aggregate merges routes that share a nexthop into fewer, shorter prefixes: prefixes covered by another
+// route with the same nexthop are dropped and adjacent prefixes are joined into their common parent.
+// A merge is skipped when another route for an overlapping prefix would then be selected for some
+// addresses instead, so forwarding through the result is the same as through routes.
+// The result keeps the order of routes, each aggregate taking the place of the first route it replaces.
+// Routes whose prefix cannot be decoded are returned unchanged.
+func Aggregate(routes []RoutingTable) []RoutingTable {
+	var result []aggRoute
+	groups := make(map[aggKey][]aggRoute)
+	var keys []aggKey
+	for i, rt := range routes {
+		dst, _, ones, err := decodeDestination(rt)
+		if err != nil || dst.To4() == nil {
+			result = append(result, aggRoute{pos: i, rt: rt})
+			continue
+		}
+		key := aggKey{rt.Table, rt.Type, rt.Metric, nexthopKey(rt), rt.PrefSrc, rt.OnLink, rt.Metrics}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		p := aggPrefix{binary.BigEndian.Uint32(dst.To4()), ones}
+		groups[key] = append(groups[key], aggRoute{prefix: p.truncate(ones), pos: i, rt: rt})
+	}
+
+	// owners maps each prefix in a table to the index of the group holding it, or -1 if several groups do.
+	type tablePrefix struct {
+		table  int
+		prefix aggPrefix
+	}
+	owners := make(map[tablePrefix]int)
+	own := func(table int, p aggPrefix, g int) {
+		if o, ok := owners[tablePrefix{table, p}]; ok && o != g {
+			g = -1
+		}
+		owners[tablePrefix{table, p}] = g
+	}
+	for g, key := range keys {
+		for _, r := range groups[key] {
+			own(key.table, r.prefix, g)
+		}
+	}
+	// safe reports whether p can be replaced by its covering prefix of length ones, i.e. whether no other
+	// group has a route containing p that is at least that specific.
+	safe := func(table int, p aggPrefix, ones, g int) bool {
+		for l := ones; l <= p.ones; l++ {
+			if o, ok := owners[tablePrefix{table, p.truncate(l)}]; ok && o != g {
+				return false
+			}
+		}
+		return true
+	}
+
+	for g, key := range keys {
+		members := groups[key]
+		sort.SliceStable(members, func(i, j int) bool {
+			if members[i].prefix.addr != members[j].prefix.addr {
+				return members[i].prefix.addr < members[j].prefix.addr
+			}
+			return members[i].prefix.ones < members[j].prefix.ones
+		})
+
+		// Drop covered prefixes. Sorting puts each prefix after those containing it.
+		byPrefix := make(map[aggPrefix]aggRoute)
+		var covers []aggRoute
+		for _, r := range members {
+			for len(covers) > 0 && !covers[len(covers)-1].prefix.contains(r.prefix) {
+				covers = covers[:len(covers)-1]
+			}
+			if len(covers) > 0 {
+				c := covers[len(covers)-1]
+				if safe(key.table, r.prefix, c.prefix.ones, g) {
+					c.pos = min(c.pos, r.pos)
+					covers[len(covers)-1] = c
+					byPrefix[c.prefix] = c
+					continue
+				}
+			}
+			covers = append(covers, r)
+			byPrefix[r.prefix] = r
+		}
+
+		// Join siblings, longest prefixes first so that joined parents can be joined again.
+		for ones := 32; ones > 0; ones-- {
+			var level []aggPrefix
+			for p := range byPrefix {
+				if p.ones == ones {
+					level = append(level, p)
+				}
+			}
+			sort.Slice(level, func(i, j int) bool { return level[i].addr < level[j].addr })
+			for _, p := range level {
+				r, ok := byPrefix[p]
+				if !ok {
+					continue // Already joined with its sibling.
+				}
+				sibling, ok := byPrefix[aggPrefix{p.addr ^ 1<<(32-ones), ones}]
+				parent := p.truncate(ones - 1)
+				if !ok || !safe(key.table, p, parent.ones, g) || !safe(key.table, sibling.prefix, parent.ones, g) {
+					continue
+				}
+				delete(byPrefix, p)
+				delete(byPrefix, sibling.prefix)
+				first := r
+				if sibling.pos < r.pos {
+					first = sibling
+				}
+				byPrefix[parent] = aggRoute{prefix: parent, pos: first.pos, rt: first.rt}
+				own(key.table, parent, g)
+			}
+		}
+
+		for _, r := range byPrefix {
+			result = append(result, r)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].pos < result[j].pos })
+	aggregated := make([]RoutingTable, len(result))
+	for i, r := range result {
+		aggregated[i] = r.route()
+	}
+
+	return aggregated
+}
+
+// route returns the route for the aggregate, with the destination and mask of its prefix.
+func (r aggRoute) route() RoutingTable {
+	rt := r.rt
+	dst, _, ones, err := decodeDestination(rt)
+	if err != nil || dst.To4() == nil || ones == r.prefix.ones {
+		return rt
+	}
+
+	addr := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(addr, r.prefix.addr)
+	mask := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(mask, prefixMask(r.prefix.ones))
+	rt.Destination, rt.Mask = formatHexIP(addr), formatHexIP(mask)
+	rt.Raw = RawColumns{} // The raw columns describe the original prefix.
+	if flagContains(rt.Flags, "H") {
+		flags := make(map[string]RouteFlag, len(rt.Flags))
+		for k, f := range rt.Flags {
+			if k != "H" {
+				flags[k] = f
+			}
+		}
+		rt.Flags = flags
+	}
+
+	return rt
+}
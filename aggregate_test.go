@@ -0,0 +1,55 @@
+package routing
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	table, err := ParseIPRoute(strings.NewReader(`10.0.0.0/24 via 192.168.1.1 dev eth0
+10.0.1.0/24 via 192.168.1.1 dev eth0
+10.0.2.0/23 via 192.168.1.1 dev eth0
+10.0.1.128/25 via 192.168.1.1 dev eth0
+172.16.0.0/24 via 192.168.1.1 dev eth0
+172.16.1.0/24 via 192.168.1.2 dev eth0
+198.51.100.0/25 via 192.168.1.1 dev eth0
+198.51.100.128/25 via 192.168.1.1 dev eth0
+198.51.100.0/24 via 192.168.1.2 dev eth0
+203.0.113.0/24 via 192.168.1.1 dev eth0
+203.0.113.64/26 via 192.168.1.2 dev eth0
+203.0.113.64/27 via 192.168.1.1 dev eth0
+192.168.1.0/24 dev eth0 scope link
+`))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	var got []string
+	for _, rt := range Aggregate(table) {
+		got = append(got, rt.String())
+	}
+	want := []string{
+		"10.0.0.0/22 via 192.168.1.1 dev eth0",
+		"172.16.0.0/24 via 192.168.1.1 dev eth0",
+		"172.16.1.0/24 via 192.168.1.2 dev eth0",
+		"198.51.100.0/25 via 192.168.1.1 dev eth0",
+		"198.51.100.128/25 via 192.168.1.1 dev eth0",
+		"198.51.100.0/24 via 192.168.1.2 dev eth0",
+		"203.0.113.0/24 via 192.168.1.1 dev eth0",
+		"203.0.113.64/26 via 192.168.1.2 dev eth0",
+		"203.0.113.64/27 via 192.168.1.1 dev eth0",
+		"192.168.1.0/24 dev eth0 scope link",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected aggregate\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	for _, ip := range []string{"10.0.1.200", "198.51.100.1", "203.0.113.70", "203.0.113.100"} {
+		before, _ := LookupRoute(table, net.ParseIP(ip))
+		after, _ := LookupRoute(Aggregate(table), net.ParseIP(ip))
+		if before.Gateway != after.Gateway {
+			t.Errorf("Aggregation changed the gateway for %s from %s to %s", ip, before.Gateway, after.Gateway)
+		}
+	}
+}
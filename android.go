@@ -0,0 +1,124 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+)
+
+// Android's netd marks sockets with the ID of the network they use in the low 16 bits of the firewall mark and
+// gives every network its own routing table, selected by rules on that mark. Traffic of sockets not bound to a
+// network carries netID 0 and is sent to the default network's table by a rule such as
+//
+//	22000:	from all fwmark 0x0/0xffff iif lo lookup wlan0
+//
+// whose priority differs between Android releases.
+const androidNetIDMask = 0xffff
+
+// ActiveNetworkDefaultRoute returns the default route of Android's active network, the network ConnectivityService
+// made the default, from that network's routing table. Every network keeps its own default route, in a table
+// named after its interface, so the main table usually has none.
+// It returns ErrNoDefaultGateway if there is no default network, e.g. while offline, and is only available on
+// Linux, which includes Android, returning ErrNotSupported elsewhere.
+func ActiveNetworkDefaultRoute() (RoutingTable, error) {
+	return ActiveNetworkDefaultRouteContext(context.Background())
+}
+
+// ActiveNetworkDefaultRouteContext is like ActiveNetworkDefaultRoute but returns early if ctx is done.
+func ActiveNetworkDefaultRouteContext(ctx context.Context) (RoutingTable, error) {
+	rules, err := RulesContext(ctx)
+	if err != nil {
+		return RoutingTable{}, err
+	}
+	routes, err := NetlinkSource{}.Routes(ctx)
+	if err != nil {
+		return RoutingTable{}, err
+	}
+
+	return activeNetworkDefaultRoute(rules, routes)
+}
+
+// androidDefaultNetworkTable returns the table of the default network, looked up for locally generated traffic
+// carrying netID 0 by the rule with the highest precedence.
+func androidDefaultNetworkTable(rules []Rule) (int, bool) {
+	var best *Rule
+	for i, r := range rules {
+		if r.Action != RuleActionLookup || r.Invert || r.IIF != "lo" || r.OIF != "" || r.Src != nil || r.Dst != nil {
+			continue
+		}
+		if r.FwMark&androidNetIDMask != 0 || r.FwMask&androidNetIDMask != androidNetIDMask {
+			continue
+		}
+		switch r.Table {
+		case TableUnspec, TableMain, TableLocal, TableDefault:
+			continue
+		}
+		if best == nil || r.Priority < best.Priority {
+			best = &rules[i]
+		}
+	}
+	if best == nil {
+		return 0, false
+	}
+
+	return best.Table, true
+}
+
+// activeNetworkDefaultRoute returns the default route in the default network's table.
+func activeNetworkDefaultRoute(rules []Rule, routes []RoutingTable) (RoutingTable, error) {
+	table, ok := androidDefaultNetworkTable(rules)
+	if !ok {
+		return RoutingTable{}, fmt.Errorf("no default network rule: %w", ErrNoDefaultGateway)
+	}
+
+	var inTable []RoutingTable
+	for _, rt := range routes {
+		if rt.Table == table {
+			inTable = append(inTable, rt)
+		}
+	}
+	rt, ok := defaultRoute(inTable)
+	if !ok {
+		return RoutingTable{}, fmt.Errorf("default network table %s: %w", TableName(table), ErrNoDefaultGateway)
+	}
+
+	return rt, nil
+}
+
+// androidSource reads the routes of every table but local over netlink, as /proc/net/route is not readable by
+// apps on modern Android and would only show the main table anyway. The default network's routes come first, so
+// DefaultRoute and the functions built on it report the active network.
+type androidSource struct {
+	routes RouteSource
+	rules  func(ctx context.Context) ([]Rule, error)
+}
+
+// newAndroidSource returns an androidSource reading the kernel over netlink.
+func newAndroidSource() androidSource {
+	return androidSource{routes: NetlinkSource{}, rules: RulesContext}
+}
+
+// Routes reads the routes of all networks, the default network's first.
+func (s androidSource) Routes(ctx context.Context) ([]RoutingTable, error) {
+	all, err := s.routes.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.rules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := androidDefaultNetworkTable(rules)
+
+	var active, others []RoutingTable
+	for _, rt := range all {
+		switch {
+		case rt.Table == TableLocal:
+		case ok && rt.Table == table:
+			active = append(active, rt)
+		default:
+			others = append(others, rt)
+		}
+	}
+
+	return append(active, others...), nil
+}
@@ -0,0 +1,115 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+// androidRules are the rules netd installs on Android 13 with Wi-Fi (table 1030) as the default network and
+// mobile data (table 1021) still connected, abridged.
+var androidRules = []Rule{
+	{Priority: 0, Action: RuleActionLookup, Table: TableLocal, SuppressPrefixLen: -1},
+	{Priority: 16000, FwMark: 0x10064, FwMask: 0x1ffff, IIF: "lo", Action: RuleActionLookup, Table: 1021, SuppressPrefixLen: -1},
+	{Priority: 16000, FwMark: 0x10065, FwMask: 0x1ffff, IIF: "lo", Action: RuleActionLookup, Table: 1030, SuppressPrefixLen: -1},
+	{Priority: 17000, FwMark: 0x64, FwMask: 0xffff, IIF: "lo", Action: RuleActionLookup, Table: 1021, SuppressPrefixLen: -1},
+	{Priority: 23000, FwMark: 0x0, FwMask: 0xffff, OIF: "rmnet_data1", IIF: "lo", Action: RuleActionLookup, Table: 1021, SuppressPrefixLen: -1},
+	{Priority: 29000, FwMark: 0x0, FwMask: 0xffff, IIF: "lo", Action: RuleActionLookup, Table: 1030, SuppressPrefixLen: -1},
+	{Priority: 32000, Action: RuleActionUnreachable, SuppressPrefixLen: -1},
+}
+
+// androidRoutes has a default route in both networks' tables and a local route.
+func androidRoutes() []RoutingTable {
+	_, dflt, _ := net.ParseCIDR("0.0.0.0/0")
+	_, wlan, _ := net.ParseCIDR("192.168.1.0/24")
+	_, local, _ := net.ParseCIDR("192.168.1.23/32")
+
+	return []RoutingTable{
+		unicastRoute(dflt, net.ParseIP("10.64.0.1").To4(), "rmnet_data1", 1021),
+		unicastRoute(local, nil, "wlan0", TableLocal),
+		unicastRoute(wlan, nil, "wlan0", 1030),
+		unicastRoute(dflt, net.ParseIP("192.168.1.1").To4(), "wlan0", 1030),
+	}
+}
+
+func TestAndroidDefaultNetworkTable(t *testing.T) {
+	table, ok := androidDefaultNetworkTable(androidRules)
+	if !ok || table != 1030 {
+		t.Errorf("androidDefaultNetworkTable() = %d, %v, want 1030, true", table, ok)
+	}
+
+	// Before Android 12 the default network rule had priority 22000.
+	older := []Rule{
+		{Priority: 22000, FwMark: 0x0, FwMask: 0xffff, IIF: "lo", Action: RuleActionLookup, Table: 1021, SuppressPrefixLen: -1},
+		{Priority: 32766, Action: RuleActionLookup, Table: TableMain, SuppressPrefixLen: -1},
+	}
+	if table, ok := androidDefaultNetworkTable(older); !ok || table != 1021 {
+		t.Errorf("androidDefaultNetworkTable(older) = %d, %v, want 1021, true", table, ok)
+	}
+
+	// A desktop's rules have no default network.
+	desktop := []Rule{
+		{Priority: 0, Action: RuleActionLookup, Table: TableLocal, SuppressPrefixLen: -1},
+		{Priority: 32766, Action: RuleActionLookup, Table: TableMain, SuppressPrefixLen: -1},
+		{Priority: 32767, Action: RuleActionLookup, Table: TableDefault, SuppressPrefixLen: -1},
+	}
+	if table, ok := androidDefaultNetworkTable(desktop); ok {
+		t.Errorf("androidDefaultNetworkTable(desktop) = %d, true, want false", table)
+	}
+}
+
+func TestActiveNetworkDefaultRoute(t *testing.T) {
+	rt, err := activeNetworkDefaultRoute(androidRules, androidRoutes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt.Interface != "wlan0" || rt.Gateway != "192.168.1.1" || rt.Table != 1030 {
+		t.Errorf("activeNetworkDefaultRoute() = %s, want the default via 192.168.1.1 dev wlan0 table 1030", rt)
+	}
+
+	if _, err := activeNetworkDefaultRoute(androidRules, androidRoutes()[:3]); !errors.Is(err, ErrNoDefaultGateway) {
+		t.Errorf("without a default route in table 1030: err = %v, want ErrNoDefaultGateway", err)
+	}
+	if _, err := activeNetworkDefaultRoute(nil, androidRoutes()); !errors.Is(err, ErrNoDefaultGateway) {
+		t.Errorf("without rules: err = %v, want ErrNoDefaultGateway", err)
+	}
+}
+
+func TestAndroidSource(t *testing.T) {
+	src := androidSource{
+		routes: routeSourceFunc(func(context.Context) ([]RoutingTable, error) { return androidRoutes(), nil }),
+		rules:  func(context.Context) ([]Rule, error) { return androidRules, nil },
+	}
+	routes, err := src.Routes(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, rt := range routes {
+		got = append(got, rt.String())
+	}
+	want := []string{
+		"192.168.1.0/24 dev wlan0 table 1030 scope link",
+		"default via 192.168.1.1 dev wlan0 table 1030",
+		"default via 10.64.0.1 dev rmnet_data1 table 1021",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Routes() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("route %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if rt, ok := defaultRoute(routes); !ok || rt.Interface != "wlan0" {
+		t.Errorf("defaultRoute() = %s, %v, want the route through wlan0", rt, ok)
+	}
+
+	src.rules = func(context.Context) ([]Rule, error) { return nil, ErrNotSupported }
+	if _, err := src.Routes(context.Background()); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("with failing rules: err = %v, want ErrNotSupported", err)
+	}
+}
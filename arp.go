@@ -0,0 +1,240 @@
+package routing
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// procARPPath is the location of the kernel's IPv4 neighbor (ARP) table.
+const procARPPath = "/proc/net/arp"
+
+// ARPState describes the resolution state of an ARP entry.
+type ARPState int
+
+// ARP entry states. ParseARP derives the first three from the ATF_* flags in /proc/net/arp, which do not tell
+// whether a resolved address was confirmed recently; GatewayHWAddr refines them with the kernel's neighbor states.
+const (
+	ARPIncomplete ARPState = iota // Resolution is in progress or has failed; no hardware address is known.
+	ARPComplete                   // The hardware address has been resolved, recently or not.
+	ARPPermanent                  // The entry was configured statically and never expires.
+	ARPReachable                  // The hardware address was confirmed recently.
+	ARPStale                      // The hardware address has not been confirmed recently and may be out of date.
+)
+
+// ARP flag bits from the Flags column of /proc/net/arp.
+const (
+	atfComplete  = 0x2
+	atfPermanent = 0x4
+)
+
+// String returns the state name as printed by `ip neigh`, e.g. "STALE", or "COMPLETE" for a resolved entry
+// whose neighbor state is unknown.
+func (s ARPState) String() string {
+	switch s {
+	case ARPComplete:
+		return "COMPLETE"
+	case ARPPermanent:
+		return "PERMANENT"
+	case ARPReachable:
+		return "REACHABLE"
+	case ARPStale:
+		return "STALE"
+	}
+
+	return "INCOMPLETE"
+}
+
+// ARPEntry represents a single entry in the Linux ARP table.
+type ARPEntry struct {
+	IP     net.IP           // The IPv4 address of the neighbor.
+	HWType int              // ARP hardware type, e.g. 1 for Ethernet.
+	Flags  int              // Raw ATF_* flags of the entry.
+	HWAddr net.HardwareAddr // The resolved hardware address; empty for incomplete entries.
+	Mask   string           // Proxy ARP mask, "*" for regular entries.
+	Device string           // The network interface the neighbor was seen on.
+	State  ARPState         // Resolution state derived from Flags.
+}
+
+// GetLinuxARPTable retrieves the current ARP table from /proc/net/arp and appends its entries to table.
+func GetLinuxARPTable(table *[]ARPEntry) error {
+	return GetLinuxARPTableContext(context.Background(), table)
+}
+
+// GetLinuxARPTableContext is like GetLinuxARPTable but returns early if ctx is done.
+func GetLinuxARPTableContext(ctx context.Context, table *[]ARPEntry) error {
+	entries, err := defaultManager.ARPTable(ctx)
+	if err != nil {
+		return err
+	}
+	*table = append(*table, entries...)
+
+	return nil
+}
+
+// ARPTable reads /proc/net/arp from the manager's filesystem, the host's unless WithFS was given.
+func (m *Manager) ARPTable(ctx context.Context) ([]ARPEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f, fErr := openProc(m.fsys, procARPPath)
+	if fErr != nil {
+		return nil, procUnavailable(m.fsys, procARPPath, fErr)
+	}
+	defer f.Close()
+
+	entries, err := ParseARP(f)
+	if err != nil {
+		var pErr *ParseError
+		if errors.As(err, &pErr) {
+			pErr.File = procARPPath
+		}
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ParseARP parses ARP table entries in the format of /proc/net/arp read from r.
+// The first line is treated as the header.
+func ParseARP(r io.Reader) ([]ARPEntry, error) {
+	var table []ARPEntry
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if line == 1 || strings.TrimSpace(scanner.Text()) == "" {
+			continue // Skip the header row and blank lines.
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 {
+			return nil, &ParseError{Line: line, Value: scanner.Text(), Err: errTruncatedRow}
+		}
+
+		entry := ARPEntry{Mask: fields[4], Device: fields[5]}
+
+		entry.IP = net.ParseIP(fields[0]).To4()
+		if entry.IP == nil {
+			return nil, &ParseError{Line: line, Column: "IP address", Value: fields[0], Err: errNotIPv4}
+		}
+
+		hwType, err := strconv.ParseInt(fields[1], 0, 32)
+		if err != nil {
+			return nil, &ParseError{Line: line, Column: "HW type", Value: fields[1], Err: err}
+		}
+		entry.HWType = int(hwType)
+
+		flags, err := strconv.ParseInt(fields[2], 0, 32)
+		if err != nil {
+			return nil, &ParseError{Line: line, Column: "Flags", Value: fields[2], Err: err}
+		}
+		entry.Flags = int(flags)
+
+		switch {
+		case entry.Flags&atfPermanent != 0:
+			entry.State = ARPPermanent
+		case entry.Flags&atfComplete != 0:
+			entry.State = ARPComplete
+		}
+
+		if entry.State != ARPIncomplete {
+			entry.HWAddr, err = net.ParseMAC(fields[3])
+			if err != nil {
+				return nil, &ParseError{Line: line, Column: "HW address", Value: fields[3], Err: err}
+			}
+		}
+
+		table = append(table, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// GatewayHWAddr returns the hardware (MAC) address of the default gateway and the state of its ARP entry.
+// The state is ARPReachable or ARPStale where the kernel's neighbor table can be read over netlink, and
+// ARPComplete otherwise; a stale address is returned too, but should not be relied on, e.g. to detect ARP spoofing.
+// It returns ErrGatewayUnresolved if the gateway has no ARP entry or the entry is incomplete.
+func GatewayHWAddr() (net.HardwareAddr, ARPState, error) {
+	return GatewayHWAddrContext(context.Background())
+}
+
+// GatewayHWAddrContext is like GatewayHWAddr but returns early if ctx is done.
+func GatewayHWAddrContext(ctx context.Context) (net.HardwareAddr, ARPState, error) {
+	return defaultManager.GatewayHWAddr(ctx)
+}
+
+// GatewayHWAddr is like the package-level GatewayHWAddr but uses the manager's routes and ARP table.
+// Managers reading another filesystem given by WithFS report ARPComplete, as the host's neighbor states would not
+// describe their ARP table.
+func (m *Manager) GatewayHWAddr(ctx context.Context) (net.HardwareAddr, ARPState, error) {
+	gw, err := m.DefaultRoute(ctx)
+	if err != nil {
+		return nil, ARPIncomplete, err
+	}
+
+	table, err := m.ARPTable(ctx)
+	if err != nil {
+		return nil, ARPIncomplete, err
+	}
+
+	entry, ok := findNeighbor(table, gw)
+	if !ok {
+		return nil, ARPIncomplete, fmt.Errorf("%w: no ARP entry for %s on %s", ErrGatewayUnresolved, gw.Gateway, gw.Interface)
+	}
+	if m.fsys == nil && entry.State == ARPComplete {
+		neighbors, err := NeighborsContext(ctx)
+		if err != nil {
+			m.log().Debug("neighbor states unavailable", "err", err)
+		}
+		entry.State = neighborARPState(entry, neighbors)
+	}
+	if entry.State == ARPIncomplete {
+		return nil, entry.State, fmt.Errorf("%w: ARP entry for %s is incomplete", ErrGatewayUnresolved, gw.Gateway)
+	}
+
+	return entry.HWAddr, entry.State, nil
+}
+
+// neighborARPState returns the state of a complete ARP entry according to the kernel's neighbor entry for it, or
+// ARPComplete if there is none. An entry the kernel is still confirming counts as stale until it is confirmed.
+func neighborARPState(entry ARPEntry, neighbors []Neighbor) ARPState {
+	for _, n := range neighbors {
+		if !n.IP.Equal(entry.IP) || n.Device != entry.Device {
+			continue
+		}
+		switch {
+		case n.State&NeighReachable != 0:
+			return ARPReachable
+		case n.State&(NeighStale|NeighDelay|NeighProbe) != 0:
+			return ARPStale
+		case n.State&(NeighIncomplete|NeighFailed) != 0:
+			return ARPIncomplete
+		}
+	}
+
+	return entry.State
+}
+
+// findNeighbor returns the ARP entry for the gateway of rt on the route's interface.
+func findNeighbor(table []ARPEntry, rt RoutingTable) (ARPEntry, bool) {
+	gw := net.ParseIP(rt.Gateway)
+	for _, entry := range table {
+		if entry.IP.Equal(gw) && entry.Device == rt.Interface {
+			return entry, true
+		}
+	}
+
+	return ARPEntry{}, false
+}
@@ -0,0 +1,94 @@
+package routing
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+const arpFixture = `IP address       HW type     Flags       HW address            Mask     Device
+192.168.1.1      0x1         0x2         52:54:00:12:34:56     *        eth0
+192.168.1.77     0x1         0x0         00:00:00:00:00:00     *        eth0
+10.8.0.5         0x1         0x6         02:00:00:aa:bb:cc     *        br0
+`
+
+func TestParseARP(t *testing.T) {
+	table, err := ParseARP(strings.NewReader(arpFixture))
+	if err != nil {
+		t.Fatalf("ParseARP failed %s", err.Error())
+	}
+	if len(table) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(table))
+	}
+
+	if table[0].IP.String() != "192.168.1.1" || table[0].HWAddr.String() != "52:54:00:12:34:56" || table[0].State != ARPComplete || table[0].Device != "eth0" {
+		t.Errorf("Unexpected entry %+v", table[0])
+	}
+	if table[1].State != ARPIncomplete || table[1].HWAddr != nil {
+		t.Errorf("Expected an incomplete entry, got %+v", table[1])
+	}
+	if table[2].State != ARPPermanent || table[2].State.String() != "PERMANENT" {
+		t.Errorf("Expected a permanent entry, got %+v", table[2])
+	}
+
+	_, err = ParseARP(strings.NewReader("header\n192.168.1.1 0x1 0x2 zz:zz * eth0\n"))
+	var pErr *ParseError
+	if !errors.As(err, &pErr) || pErr.Line != 2 || pErr.Column != "HW address" {
+		t.Errorf("Expected a HW address parse error, got %v", err)
+	}
+}
+
+func TestGetLinuxARPTable(t *testing.T) {
+	useProcFS(t)
+	table := new([]ARPEntry)
+	if err := GetLinuxARPTable(table); err != nil {
+		t.Errorf("Calling routing library failed %s", err.Error())
+	}
+	if len(*table) != 1 || (*table)[0].IP.String() != "192.0.2.1" {
+		t.Errorf("Unexpected ARP table %+v", *table)
+	}
+}
+
+func TestNeighborARPState(t *testing.T) {
+	table, err := ParseARP(strings.NewReader(arpFixture))
+	if err != nil {
+		t.Fatalf("ParseARP failed %s", err.Error())
+	}
+
+	cases := []struct {
+		neighbors []Neighbor
+		want      ARPState
+	}{
+		{nil, ARPComplete},
+		{[]Neighbor{{IP: net.ParseIP("192.168.1.1"), Device: "eth0", State: NeighReachable}}, ARPReachable},
+		{[]Neighbor{{IP: net.ParseIP("192.168.1.1"), Device: "eth0", State: NeighStale}}, ARPStale},
+		{[]Neighbor{{IP: net.ParseIP("192.168.1.1"), Device: "eth0", State: NeighDelay}}, ARPStale},
+		{[]Neighbor{{IP: net.ParseIP("192.168.1.1"), Device: "eth0", State: NeighFailed}}, ARPIncomplete},
+		{[]Neighbor{{IP: net.ParseIP("192.168.1.1"), Device: "wlan0", State: NeighStale}}, ARPComplete},
+	}
+	for _, c := range cases {
+		if got := neighborARPState(table[0], c.neighbors); got != c.want {
+			t.Errorf("neighborARPState(%v) = %s, want %s", c.neighbors, got, c.want)
+		}
+	}
+	if ARPStale.String() != "STALE" || ARPComplete.String() == ARPReachable.String() {
+		t.Errorf("Unexpected state names %s %s %s", ARPStale, ARPComplete, ARPReachable)
+	}
+}
+
+func TestFindNeighbor(t *testing.T) {
+	table, err := ParseARP(strings.NewReader(arpFixture))
+	if err != nil {
+		t.Fatalf("ParseARP failed %s", err.Error())
+	}
+
+	entry, ok := findNeighbor(table, RoutingTable{Interface: "eth0", Gateway: "192.168.1.1"})
+	if !ok || entry.HWAddr.String() != "52:54:00:12:34:56" {
+		t.Errorf("Unexpected neighbor %+v", entry)
+	}
+
+	if _, ok := findNeighbor(table, RoutingTable{Interface: "wlan0", Gateway: "192.168.1.1"}); ok {
+		t.Errorf("Expected no neighbor on another interface")
+	}
+}
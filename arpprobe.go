@@ -0,0 +1,59 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+)
+
+// ARP packet fields for IPv4 over Ethernet, from RFC 826.
+const (
+	arpHWEthernet = 1
+	arpProtoIPv4  = 0x0800
+	arpOpRequest  = 1
+	arpOpReply    = 2
+	arpPacketLen  = 28
+)
+
+// CheckGatewayARP probes the default gateway with ARP requests on its egress interface and reports latency and loss.
+// It works where ICMP is filtered, as hosts must answer ARP to be reachable at all, but it needs a raw packet
+// socket: ErrRawSocketNotPermitted is returned without CAP_NET_RAW, and ErrNotSupported outside Linux.
+func CheckGatewayARP(ctx context.Context) (GatewayCheck, error) {
+	return defaultManager.CheckGatewayARP(ctx)
+}
+
+// CheckGatewayARP is like the package-level CheckGatewayARP but probes the manager's default gateway.
+func (m *Manager) CheckGatewayARP(ctx context.Context) (GatewayCheck, error) {
+	rt, err := m.DefaultRoute(ctx)
+	if err != nil {
+		return GatewayCheck{}, err
+	}
+
+	return arpProbe(ctx, rt.Interface, net.ParseIP(rt.Gateway).To4(), gatewayProbes, gatewayProbeTimeout)
+}
+
+// arpRequest encodes a who-has request for target sent from the given hardware and protocol addresses.
+func arpRequest(srcHW net.HardwareAddr, srcIP, target net.IP) []byte {
+	b := make([]byte, arpPacketLen)
+	binary.BigEndian.PutUint16(b[0:2], arpHWEthernet)
+	binary.BigEndian.PutUint16(b[2:4], arpProtoIPv4)
+	b[4], b[5] = 6, 4
+	binary.BigEndian.PutUint16(b[6:8], arpOpRequest)
+	copy(b[8:14], srcHW)
+	copy(b[14:18], srcIP.To4())
+	copy(b[24:28], target.To4())
+
+	return b
+}
+
+// parseARPReply returns the sender hardware address of b if it is an ARP reply from target.
+func parseARPReply(b []byte, target net.IP) (net.HardwareAddr, bool) {
+	if len(b) < arpPacketLen || binary.BigEndian.Uint16(b[0:2]) != arpHWEthernet ||
+		binary.BigEndian.Uint16(b[2:4]) != arpProtoIPv4 || b[4] != 6 || b[5] != 4 ||
+		binary.BigEndian.Uint16(b[6:8]) != arpOpReply || !bytes.Equal(b[14:18], target.To4()) {
+		return nil, false
+	}
+
+	return net.HardwareAddr(bytes.Clone(b[8:14])), true
+}
@@ -0,0 +1,145 @@
+package routing
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// capNetRaw is the capability bit needed to open packet sockets, from linux/capability.h.
+const capNetRaw = 13
+
+// arpProbe sends count ARP requests for ip out of the named interface, waiting up to timeout for each reply.
+func arpProbe(ctx context.Context, ifname string, ip net.IP, count int, timeout time.Duration) (GatewayCheck, error) {
+	check := GatewayCheck{Gateway: ip}
+	if ip == nil {
+		return check, fmt.Errorf("arp probe: %w", errNotIPv4)
+	}
+	if !hasCapability(capNetRaw) {
+		return check, ErrRawSocketNotPermitted
+	}
+
+	iface, err := net.InterfaceByName(ifname)
+	if err != nil {
+		return check, err
+	}
+	if len(iface.HardwareAddr) != 6 {
+		return check, fmt.Errorf("arp probe: %s is not an Ethernet interface", ifname)
+	}
+
+	proto := htons(syscall.ETH_P_ARP)
+	fd, err := syscall.Socket(syscall.AF_PACKET, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC|syscall.SOCK_NONBLOCK, int(proto))
+	if errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.EACCES) {
+		return check, ErrRawSocketNotPermitted
+	}
+	if err != nil {
+		return check, err
+	}
+	f := os.NewFile(uintptr(fd), "arp") // Registers the descriptor with the runtime poller so deadlines work.
+	defer f.Close()
+	if err := syscall.Bind(fd, &syscall.SockaddrLinklayer{Protocol: proto, Ifindex: iface.Index}); err != nil {
+		return check, err
+	}
+	stop := context.AfterFunc(ctx, func() { f.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	dst := &syscall.SockaddrLinklayer{Protocol: proto, Ifindex: iface.Index, Halen: 6, Addr: [8]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}}
+	req := arpRequest(iface.HardwareAddr, interfaceIPv4(iface), ip)
+
+	var total time.Duration
+	buf := make([]byte, 1500)
+	for i := 0; i < count; i++ {
+		if err := ctx.Err(); err != nil {
+			return check, err
+		}
+
+		start := time.Now()
+		if err := syscall.Sendto(fd, req, 0, dst); err != nil {
+			return check, err
+		}
+		check.Sent++
+
+		deadline := start.Add(timeout)
+		ctxDeadline := false
+		if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+			deadline, ctxDeadline = d, true
+		}
+		f.SetReadDeadline(deadline)
+		for {
+			n, err := f.Read(buf)
+			if errors.Is(err, os.ErrDeadlineExceeded) && ctxDeadline {
+				// The read deadline can fire before ctx's own timer; wait for it so the error is reported.
+				<-ctx.Done()
+				return check, ctx.Err()
+			}
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				break // No reply to this request.
+			}
+			if err != nil {
+				return check, err
+			}
+			if hw, ok := parseARPReply(buf[:n], ip); ok {
+				check.Received++
+				check.HWAddr = hw
+				total += time.Since(start)
+				break
+			}
+		}
+	}
+
+	if check.Received > 0 {
+		check.Latency = total / time.Duration(check.Received)
+	}
+	check.Loss = float64(check.Sent-check.Received) / float64(check.Sent)
+
+	return check, ctx.Err()
+}
+
+// interfaceIPv4 returns the first IPv4 address of iface, or 0.0.0.0 to send an RFC 5227 probe if it has none.
+func interfaceIPv4(iface *net.Interface) net.IP {
+	addrs, _ := iface.Addrs()
+	for _, a := range addrs {
+		if ipNet, ok := a.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+			return ipNet.IP.To4()
+		}
+	}
+
+	return net.IPv4zero.To4()
+}
+
+// htons converts a 16-bit value to network byte order, as packet socket protocol numbers expect.
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
+
+// hasCapability reports whether the process has the given capability in its effective set.
+// It errs on the side of true when /proc/self/status cannot be read, leaving the socket call to decide.
+func hasCapability(bit uint) bool {
+	f, err := os.Open("/proc/self/status")
+	if err != nil {
+		return true
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		v, ok := strings.CutPrefix(scanner.Text(), "CapEff:")
+		if !ok {
+			continue
+		}
+		caps, err := strconv.ParseUint(strings.TrimSpace(v), 16, 64)
+		if err != nil {
+			return true
+		}
+		return caps&(1<<bit) != 0
+	}
+
+	return true
+}
@@ -0,0 +1,62 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCheckGatewayARP(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+
+	ip := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("ip", args...).CombinedOutput(); err != nil {
+			t.Skipf("Running ip %s failed: %s: %s", strings.Join(args, " "), err, out)
+		}
+	}
+	ip("link", "add", "veth0", "type", "veth", "peer", "name", "veth1")
+	ip("addr", "add", "192.0.2.2/24", "dev", "veth0")
+	ip("addr", "add", "192.0.2.1/32", "dev", "veth1")
+	ip("link", "set", "veth0", "up")
+	ip("link", "set", "veth1", "up")
+	// veth1 answers for the gateway, but only accepts requests from veth0's address, local to it too, if told so.
+	if err := os.WriteFile("/proc/sys/net/ipv4/conf/veth1/accept_local", []byte("1"), 0o644); err != nil {
+		t.Skipf("Cannot accept local sources on veth1: %s", err)
+	}
+	peer, err := net.InterfaceByName("veth1")
+	if err != nil {
+		t.Fatalf("InterfaceByName failed %s", err.Error())
+	}
+
+	m := NewManager(WithSource(&memTable{routes: mustParseIPRoute(t, "default via 192.0.2.1 dev veth0")}))
+	check, err := m.CheckGatewayARP(context.Background())
+	if errors.Is(err, ErrRawSocketNotPermitted) {
+		t.Skipf("Cannot probe the gateway: %s", err)
+	}
+	if err != nil {
+		t.Fatalf("CheckGatewayARP failed %s", err.Error())
+	}
+	if check.Sent != gatewayProbes || !check.Reachable() || check.HWAddr.String() != peer.HardwareAddr.String() {
+		t.Errorf("Expected replies from %s, got %+v", peer.HardwareAddr, check)
+	}
+
+	m = NewManager(WithSource(&memTable{routes: mustParseIPRoute(t, "default via 192.0.2.3 dev veth0")}))
+	ctx, cancel := context.WithTimeout(context.Background(), gatewayProbeTimeout/2)
+	defer cancel()
+	if check, err := m.CheckGatewayARP(ctx); !errors.Is(err, context.DeadlineExceeded) || check.Reachable() {
+		t.Errorf("Expected no reply for an unused address, got %+v, %v", check, err)
+	}
+}
+
+func TestHTONS(t *testing.T) {
+	if htons(0x0806) != 0x0608 {
+		t.Errorf("Unexpected byte order %#x", htons(0x0806))
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package routing
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// arpProbe always returns ErrNotSupported, as packet sockets are only used on Linux.
+func arpProbe(ctx context.Context, ifname string, ip net.IP, count int, timeout time.Duration) (GatewayCheck, error) {
+	return GatewayCheck{Gateway: ip}, ErrNotSupported
+}
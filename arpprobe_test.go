@@ -0,0 +1,29 @@
+package routing
+
+import (
+	"net"
+	"testing"
+)
+
+func TestARPRequestReply(t *testing.T) {
+	hw, _ := net.ParseMAC("02:00:00:00:00:01")
+	gw := net.ParseIP("192.0.2.1")
+	req := arpRequest(hw, net.ParseIP("192.0.2.10"), gw)
+	if len(req) != arpPacketLen || req[7] != arpOpRequest || net.IP(req[24:28]).String() != "192.0.2.1" {
+		t.Fatalf("Unexpected request % x", req)
+	}
+	if _, ok := parseARPReply(req, gw); ok {
+		t.Errorf("Expected a request not to be taken for a reply")
+	}
+
+	gwHW, _ := net.ParseMAC("02:00:00:00:00:fe")
+	reply := arpRequest(gwHW, gw, net.ParseIP("192.0.2.10"))
+	reply[7] = arpOpReply
+	got, ok := parseARPReply(reply, gw)
+	if !ok || got.String() != "02:00:00:00:00:fe" {
+		t.Errorf("Unexpected reply parse %s %t", got, ok)
+	}
+	if _, ok := parseARPReply(reply, net.ParseIP("192.0.2.2")); ok {
+		t.Errorf("Expected a reply from another address to be ignored")
+	}
+}
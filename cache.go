@@ -0,0 +1,110 @@
+package routing
+
+import (
+	"context"
+	"maps"
+	"net"
+	"slices"
+	"sync"
+	"time"
+)
+
+// CachedSource wraps a RouteSource and reuses its result for a fixed TTL.
+// It is safe for concurrent use; errors are never cached.
+type CachedSource struct {
+	source RouteSource
+	ttl    time.Duration
+	now    func() time.Time // Clock used for expiry, replaceable in tests.
+
+	mu         sync.RWMutex
+	routes     []RoutingTable
+	expires    time.Time
+	generation uint64 // Incremented by Invalidate, so reads that raced with it are not stored.
+}
+
+// NewCachedSource returns a CachedSource serving routes from source for up to ttl after each read.
+// Concurrent misses are coalesced into a single read of source.
+func NewCachedSource(source RouteSource, ttl time.Duration) *CachedSource {
+	c := &CachedSource{ttl: ttl, now: time.Now}
+	c.source = NewCoalescedSource(cacheFill{cache: c, source: source})
+
+	return c
+}
+
+// Routes returns the cached routing table, reading it from the wrapped source once the TTL has expired.
+// The returned routes are copies, maps and slices included, and may be modified by the caller.
+func (c *CachedSource) Routes(ctx context.Context) ([]RoutingTable, error) {
+	c.mu.RLock()
+	if c.routes != nil && c.now().Before(c.expires) {
+		routes := cloneRoutes(c.routes)
+		c.mu.RUnlock()
+		return routes, nil
+	}
+	c.mu.RUnlock()
+
+	return c.source.Routes(ctx)
+}
+
+// Invalidate drops the cached table so the next call to Routes reads from the wrapped source.
+func (c *CachedSource) Invalidate() {
+	c.mu.Lock()
+	c.routes = nil
+	c.generation++
+	c.mu.Unlock()
+}
+
+// cacheFill is the read shared by concurrent misses of a CachedSource. It stores the routes before the read
+// completes, so that no caller misses the cache between the end of one read and the store, and starts another.
+type cacheFill struct {
+	cache  *CachedSource
+	source RouteSource
+}
+
+// Routes returns the cached routes if a read completed since the caller missed the cache, and reads and caches
+// the routes of the wrapped source otherwise. A read that Invalidate overtook is returned but not cached.
+func (f cacheFill) Routes(ctx context.Context) ([]RoutingTable, error) {
+	c := f.cache
+	c.mu.RLock()
+	routes := c.routes
+	fresh := routes != nil && c.now().Before(c.expires)
+	generation := c.generation
+	c.mu.RUnlock()
+	if fresh {
+		return routes, nil
+	}
+
+	routes, err := f.source.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.routes = routes
+		c.expires = c.now().Add(c.ttl)
+	}
+	c.mu.Unlock()
+
+	return routes, nil
+}
+
+// cloneRoutes returns a deep copy of routes, so that callers sharing a read cannot modify each other's routes.
+func cloneRoutes(routes []RoutingTable) []RoutingTable {
+	out := make([]RoutingTable, len(routes))
+	for i, rt := range routes {
+		rt.Flags = maps.Clone(rt.Flags)
+		rt.Nexthops = slices.Clone(rt.Nexthops)
+		if rt.SRv6 != nil {
+			srv6 := *rt.SRv6
+			srv6.Segments = make([]net.IP, len(rt.SRv6.Segments))
+			for j, seg := range rt.SRv6.Segments {
+				srv6.Segments[j] = slices.Clone(seg)
+			}
+			srv6.Nexthop = slices.Clone(srv6.Nexthop)
+			rt.SRv6 = &srv6
+		}
+		out[i] = rt
+	}
+
+	return out
+}
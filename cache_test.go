@@ -0,0 +1,93 @@
+package routing
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCachedSource(t *testing.T) {
+	var mu sync.Mutex
+	reads := 0
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reads++
+		return []RoutingTable{{Interface: "eth0"}}, nil
+	})
+
+	now := time.Unix(0, 0)
+	c := NewCachedSource(src, time.Minute)
+	c.now = func() time.Time { return now }
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Routes(context.Background()); err != nil {
+				t.Errorf("Routes failed %s", err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+
+	reads = 0
+	if _, err := c.Routes(context.Background()); err != nil || reads != 0 {
+		t.Errorf("Expected a cached read, got %d reads and error %v", reads, err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := c.Routes(context.Background()); err != nil || reads != 1 {
+		t.Errorf("Expected a fresh read after expiry, got %d reads and error %v", reads, err)
+	}
+
+	c.Invalidate()
+	if _, err := c.Routes(context.Background()); err != nil || reads != 2 {
+		t.Errorf("Expected a fresh read after invalidation, got %d reads and error %v", reads, err)
+	}
+}
+
+func TestCachedSourceCopies(t *testing.T) {
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		return mustParseIPRoute(t, "default proto static\n\tnexthop via 192.0.2.1 dev eth0 weight 1\n\tnexthop via 192.0.2.2 dev eth1 weight 1"), nil
+	})
+	c := NewCachedSource(src, time.Minute)
+
+	for range 2 {
+		routes, err := c.Routes(context.Background())
+		if err != nil {
+			t.Fatalf("Routes failed %s", err.Error())
+		}
+		if !flagContains(routes[0].Flags, "G") || routes[0].Nexthops[0].Gateway != "192.0.2.1" {
+			t.Fatalf("Expected an unmodified route, got %+v", routes[0])
+		}
+		// Callers may modify what they got without affecting each other.
+		delete(routes[0].Flags, "G")
+		routes[0].Nexthops[0].Gateway = "198.51.100.1"
+	}
+}
+
+func TestCachedSourceInvalidateDuringRead(t *testing.T) {
+	var c *CachedSource
+	reads := 0
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		reads++
+		if reads == 1 {
+			c.Invalidate() // The table changes while it is being read.
+		}
+		return []RoutingTable{{Interface: "eth0"}}, nil
+	})
+	c = NewCachedSource(src, time.Minute)
+
+	if _, err := c.Routes(context.Background()); err != nil {
+		t.Fatalf("Routes failed %s", err.Error())
+	}
+	if _, err := c.Routes(context.Background()); err != nil || reads != 2 {
+		t.Errorf("Expected the read overtaken by Invalidate not to be cached, got %d reads and error %v", reads, err)
+	}
+	if _, err := c.Routes(context.Background()); err != nil || reads != 2 {
+		t.Errorf("Expected the next read to be cached, got %d reads and error %v", reads, err)
+	}
+}
@@ -0,0 +1,35 @@
+package routing
+
+import "context"
+
+// ProcessCapabilities tells which of the kernel interfaces this package uses the current process may use, so
+// callers can turn features off up front instead of failing with EPERM when they are first used.
+type ProcessCapabilities struct {
+	ReadProc     bool  // /proc/net/route can be read, as by the default Manager.
+	ProcErr      error // Why /proc/net/route cannot be read, usually a *ProcUnavailableError; nil if ReadProc.
+	NetlinkDump  bool  // Routing tables can be dumped over netlink, as by NetlinkSource and Rules.
+	NetlinkWrite bool  // Routes can be changed over netlink, as by AddRoute, which needs CAP_NET_ADMIN.
+	RawSockets   bool  // Raw and packet sockets can be opened, as by CheckGatewayARP, which needs CAP_NET_RAW.
+}
+
+// Capabilities probes what the current process may do in its network namespace. Every probe tries the operation
+// itself, or a request the kernel rejects only after checking permissions, so it accounts for user namespaces,
+// seccomp and SELinux policies as well as capabilities, and changes nothing.
+// Outside Linux only ReadProc can be true.
+func Capabilities() ProcessCapabilities {
+	return CapabilitiesContext(context.Background())
+}
+
+// CapabilitiesContext is like Capabilities but returns early if ctx is done, reporting the probes not yet made
+// as not permitted.
+func CapabilitiesContext(ctx context.Context) ProcessCapabilities {
+	var c ProcessCapabilities
+	if _, err := (ProcSource{}).Routes(ctx); err != nil {
+		c.ProcErr = err
+	} else {
+		c.ReadProc = true
+	}
+	probeCapabilities(ctx, &c)
+
+	return c
+}
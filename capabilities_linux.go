@@ -0,0 +1,42 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"syscall"
+)
+
+// capNetAdmin is the capability bit needed to change routes, from linux/capability.h.
+const capNetAdmin = 12
+
+// probeCapabilities fills in the netlink and raw socket capabilities of c.
+func probeCapabilities(ctx context.Context, c *ProcessCapabilities) {
+	if _, err := netlinkDump(ctx, syscall.RTM_GETROUTE, syscall.AF_INET); err == nil || errors.Is(err, errDumpInterrupted) {
+		c.NetlinkDump = true
+	}
+	c.NetlinkWrite = probeNetlinkWrite(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.IPPROTO_ICMP)
+	if err == nil {
+		syscall.Close(fd)
+		c.RawSockets = true
+	}
+}
+
+// probeNetlinkWrite reports whether the kernel accepts route changes from the process. It sends a new route
+// request for an address family without routes: rtnetlink checks CAP_NET_ADMIN over the network namespace
+// before looking for a handler, so the request fails with EPERM, or EACCES under SELinux, without permission and
+// with EOPNOTSUPP otherwise.
+func probeNetlinkWrite(ctx context.Context) bool {
+	const familyNone = 0xff
+	msg := make([]byte, sizeofRtMsg)
+	msg[0] = familyNone
+
+	_, err := netlinkRequest(ctx, syscall.RTM_NEWROUTE, syscall.NLM_F_CREATE|syscall.NLM_F_EXCL, msg)
+	var errno syscall.Errno
+
+	return errors.As(err, &errno) && errno != syscall.EPERM && errno != syscall.EACCES
+}
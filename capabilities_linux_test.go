@@ -0,0 +1,49 @@
+package routing
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	c := Capabilities()
+	if !c.ReadProc || c.ProcErr != nil {
+		t.Errorf("Expected /proc/net/route to be readable, got %v", c.ProcErr)
+	}
+	if !c.NetlinkDump {
+		t.Error("Expected netlink dumps to be permitted")
+	}
+
+	if os.Getenv("ROUTING_TEST_USERNS") != "" {
+		if c.NetlinkWrite || c.RawSockets {
+			t.Errorf("Expected no privileges over the host's network namespace, got %+v", c)
+		}
+		return
+	}
+	if hasCapability(capNetAdmin) != c.NetlinkWrite {
+		t.Errorf("NetlinkWrite = %v, but CAP_NET_ADMIN is %v", c.NetlinkWrite, !c.NetlinkWrite)
+	}
+	if hasCapability(capNetRaw) != c.RawSockets {
+		t.Errorf("RawSockets = %v, but CAP_NET_RAW is %v", c.RawSockets, !c.RawSockets)
+	}
+
+	// Capabilities in a new user namespace do not extend to the network namespace the host's user namespace owns.
+	var out bytes.Buffer
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "ROUTING_TEST_USERNS=1")
+	cmd.Stdout, cmd.Stderr = &out, &out
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags:  syscall.CLONE_NEWUSER,
+		UidMappings: []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}},
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("Cannot create a user namespace: %s", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Errorf("Test failed in a new user namespace: %s\n%s", err, out.String())
+	}
+}
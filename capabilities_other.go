@@ -0,0 +1,8 @@
+//go:build !linux
+
+package routing
+
+import "context"
+
+// probeCapabilities leaves the netlink and raw socket capabilities unset, as they are only used on Linux.
+func probeCapabilities(ctx context.Context, c *ProcessCapabilities) {}
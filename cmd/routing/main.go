@@ -0,0 +1,181 @@
+// Command routing inspects the Linux routing table.
+//
+// Usage:
+//
+//	routing [flags] list
+//	routing [flags] default
+//	routing [flags] lookup <ip>
+//	routing [flags] watch
+//
+// Output is printed in the layout of `route -n` unless -json is given. With -kernel-order, list prints routes
+// in the order the kernel tries them rather than table order.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/noopduck/routing"
+)
+
+func main() {
+	jsonOut := flag.Bool("json", false, "print output as JSON")
+	source := flag.String("source", "proc", "route source: proc, ip or netlink")
+	table := flag.String("table", "main", "routing table for the netlink source, or \"all\"")
+	interval := flag.Duration("interval", 2*time.Second, "poll interval for watch")
+	kernelOrder := flag.Bool("kernel-order", false, "list routes in the order the kernel tries them")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] list|default|lookup <ip>|watch\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var src routing.RouteSource
+	switch *source {
+	case "proc":
+		src = routing.ProcSource{}
+	case "ip":
+		src = routing.IPRouteSource{}
+	case "netlink":
+		id, ok := routing.TableID(*table)
+		if *table == "all" {
+			id, ok = routing.TableUnspec, true
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown table %q\n", *table)
+			os.Exit(2)
+		}
+		src = routing.NetlinkSource{Table: id}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown source %q\n", *source)
+		os.Exit(2)
+	}
+
+	var err error
+	switch flag.Arg(0) {
+	case "list":
+		err = list(ctx, src, *kernelOrder, *jsonOut)
+	case "default":
+		err = defaultRoute(ctx, src, *jsonOut)
+	case "lookup":
+		err = lookup(ctx, src, flag.Arg(1), *jsonOut)
+	case "watch":
+		err = watch(ctx, src, *interval, *jsonOut)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// list prints the full routing table, in table order or, with kernelOrder, in selection precedence.
+func list(ctx context.Context, src routing.RouteSource, kernelOrder, jsonOut bool) error {
+	routes, err := src.Routes(ctx)
+	if err != nil {
+		return err
+	}
+	if kernelOrder {
+		routing.SortKernelOrder(routes)
+	}
+
+	return printRoutes(routes, jsonOut)
+}
+
+// defaultRoute prints the route holding the default gateway, as Manager.DefaultRoute selects it.
+func defaultRoute(ctx context.Context, src routing.RouteSource, jsonOut bool) error {
+	rt, err := routing.NewManager(routing.WithSource(src)).DefaultRoute(ctx)
+	if err != nil {
+		return err
+	}
+
+	return printRoutes([]routing.RoutingTable{rt}, jsonOut)
+}
+
+// lookup prints the route that would be used to reach addr.
+func lookup(ctx context.Context, src routing.RouteSource, addr string, jsonOut bool) error {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return fmt.Errorf("invalid IP address %q", addr)
+	}
+
+	routes, err := src.Routes(ctx)
+	if err != nil {
+		return err
+	}
+
+	rt, ok := routing.LookupRoute(routes, ip)
+	if !ok {
+		return fmt.Errorf("no route to %s", ip)
+	}
+
+	return printRoutes([]routing.RoutingTable{rt}, jsonOut)
+}
+
+// watch prints the table and then every route as it is added or removed.
+func watch(ctx context.Context, src routing.RouteSource, interval time.Duration, jsonOut bool) error {
+	w := routing.NewWatcher(src, interval)
+
+	change, err := w.Next(ctx)
+	if err != nil {
+		return err
+	}
+	if err := printRoutes(change.Added, jsonOut); err != nil {
+		return err
+	}
+
+	for {
+		change, err := w.Next(ctx)
+		if err != nil {
+			return err
+		}
+		for _, rt := range change.Added {
+			printChange("added", rt, jsonOut)
+		}
+		for _, rt := range change.Removed {
+			printChange("removed", rt, jsonOut)
+		}
+	}
+}
+
+// printRoutes writes routes to stdout as a table or as JSON.
+func printRoutes(routes []routing.RoutingTable, jsonOut bool) error {
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(routes)
+	}
+
+	return routing.WriteTable(os.Stdout, routes)
+}
+
+// printChange writes a single watch event to stdout.
+func printChange(kind string, rt routing.RoutingTable, jsonOut bool) {
+	if jsonOut {
+		b, _ := json.Marshal(struct {
+			Event string               `json:"event"`
+			Route routing.RoutingTable `json:"route"`
+		}{kind, rt})
+		fmt.Println(string(b))
+		return
+	}
+
+	sign := "+"
+	if kind == "removed" {
+		sign = "-"
+	}
+	fmt.Printf("%s %s\n", sign, rt)
+}
@@ -0,0 +1,111 @@
+package routing
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// CNIDelegation describes the pod subnet delegated to a node and how the node reaches the rest of the cluster,
+// as a bridge-based CNI plugin sees it when wiring up a pod.
+type CNIDelegation struct {
+	Subnet       *net.IPNet // IPv4 pod subnet of this node, e.g. 10.244.1.0/24.
+	Bridge       string     // Host bridge the pods attach to, e.g. "cni0".
+	Gateway      net.IP     // Address of the bridge, the pods' default gateway; the first address of Subnet when nil.
+	PodInterface string     // Interface inside the pod; "eth0" when empty.
+	Uplink       string     // Host interface towards the other nodes, e.g. "eth0"; needed only with Peers.
+	Peers        []CNIPeer  // Pod subnets of other nodes, routed directly to them as in host-gw mode.
+	Table        int        // Host table to install into; main when zero.
+}
+
+// CNIPeer is the pod subnet of another node and the node's address on the uplink.
+type CNIPeer struct {
+	Subnet *net.IPNet // The other node's pod subnet.
+	NodeIP net.IP     // The other node's address, reachable directly through the uplink.
+}
+
+// CNIRouteSet holds the routes a CNI plugin installs for a delegation, each list in the order to add them,
+// e.g. with AddRoute, since a gateway must be reachable when a route through it is added.
+type CNIRouteSet struct {
+	Host []RoutingTable // Routes of the host: the pod subnet on the bridge outside main and the peers' subnets via the uplink.
+	Pod  []RoutingTable // Routes of a pod's network namespace: the default via the bridge.
+}
+
+// CNIRoutes computes the routes a bridge CNI plugin installs for the delegation d, ready to pass to AddRoute:
+// on the host, every peer's subnet via that node on the uplink; in a pod, the default route via the bridge address.
+// The pod subnet's own routes are left to the kernel, which adds them to the main table when the bridge and the
+// pod's interface get their addresses; only a host Table other than main gets the pod subnet through the bridge.
+func CNIRoutes(d CNIDelegation) (CNIRouteSet, error) {
+	if d.Subnet == nil || d.Subnet.IP.To4() == nil || len(d.Subnet.Mask) != net.IPv4len {
+		return CNIRouteSet{}, fmt.Errorf("pod subnet %v: %w", d.Subnet, errNotIPv4)
+	}
+	if d.Bridge == "" {
+		return CNIRouteSet{}, errors.New("pod subnet needs a bridge")
+	}
+	subnet := &net.IPNet{IP: d.Subnet.IP.To4().Mask(d.Subnet.Mask), Mask: d.Subnet.Mask}
+
+	gw := d.Gateway.To4()
+	switch {
+	case d.Gateway == nil:
+		first, last := ipv4Range(subnet)
+		if last-first < 2 {
+			return CNIRouteSet{}, fmt.Errorf("pod subnet %s has no room for a gateway", subnet)
+		}
+		gw = make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(gw, first+1)
+	case gw == nil:
+		return CNIRouteSet{}, fmt.Errorf("gateway %s: %w", d.Gateway, errNotIPv4)
+	case !subnet.Contains(gw):
+		return CNIRouteSet{}, fmt.Errorf("gateway %s is outside pod subnet %s", gw, subnet)
+	}
+	podIface := d.PodInterface
+	if podIface == "" {
+		podIface = "eth0"
+	}
+
+	set := CNIRouteSet{
+		Pod: []RoutingTable{unicastRoute(&net.IPNet{IP: net.IPv4zero.To4(), Mask: net.CIDRMask(0, 32)}, gw, podIface, TableUnspec)},
+	}
+	if d.Table != TableUnspec && d.Table != TableMain {
+		set.Host = append(set.Host, unicastRoute(subnet, nil, d.Bridge, d.Table))
+	}
+	for _, p := range d.Peers {
+		if p.Subnet == nil || p.Subnet.IP.To4() == nil || len(p.Subnet.Mask) != net.IPv4len || p.NodeIP.To4() == nil {
+			return CNIRouteSet{}, fmt.Errorf("peer %v via %s: %w", p.Subnet, p.NodeIP, errNotIPv4)
+		}
+		if d.Uplink == "" {
+			return CNIRouteSet{}, errors.New("peer subnets need an uplink")
+		}
+		if p.Subnet.Contains(subnet.IP) || subnet.Contains(p.Subnet.IP) {
+			return CNIRouteSet{}, fmt.Errorf("peer subnet %s overlaps pod subnet %s", p.Subnet, subnet)
+		}
+		peer := &net.IPNet{IP: p.Subnet.IP.To4().Mask(p.Subnet.Mask), Mask: p.Subnet.Mask}
+		set.Host = append(set.Host, unicastRoute(peer, p.NodeIP.To4(), d.Uplink, d.Table))
+	}
+
+	return set, nil
+}
+
+// unicastRoute returns a unicast route to dst through iface, via gw unless it is nil.
+func unicastRoute(dst *net.IPNet, gw net.IP, iface string, table int) RoutingTable {
+	flags := FlagUp
+	gateway := net.IPv4zero
+	if gw != nil {
+		flags |= FlagGateway
+		gateway = gw
+	}
+	if ones, _ := dst.Mask.Size(); ones == 32 {
+		flags |= FlagHost
+	}
+
+	return RoutingTable{
+		Interface:   iface,
+		Destination: formatHexIP(dst.IP),
+		Gateway:     gateway.String(),
+		Flags:       computeRouteFlag(flags),
+		Mask:        formatHexIP(net.IP(dst.Mask)),
+		Table:       table,
+		Type:        RouteTypeUnicast,
+	}
+}
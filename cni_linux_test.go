@@ -0,0 +1,63 @@
+package routing
+
+import (
+	"net"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCNIRoutesInstall(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+	setLoopbackUp(t)
+
+	// The veth pair stands in for the bridge and the uplink.
+	ip := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("ip", args...).CombinedOutput(); err != nil {
+			t.Skipf("Running ip %s failed: %s: %s", strings.Join(args, " "), err, out)
+		}
+	}
+	ip("link", "add", "veth0", "type", "veth", "peer", "name", "veth1")
+	ip("addr", "add", "192.0.2.1/24", "dev", "veth0")
+	ip("addr", "add", "10.244.1.1/24", "dev", "veth1")
+	ip("link", "set", "veth0", "up")
+	ip("link", "set", "veth1", "up")
+
+	_, subnet, _ := net.ParseCIDR("10.244.1.0/24")
+	_, peer, _ := net.ParseCIDR("10.244.2.0/24")
+	set, err := CNIRoutes(CNIDelegation{
+		Subnet: subnet,
+		Bridge: "veth1",
+		Uplink: "veth0",
+		Peers:  []CNIPeer{{Subnet: peer, NodeIP: net.ParseIP("192.0.2.12")}},
+	})
+	if err != nil {
+		t.Fatalf("CNIRoutes failed %s", err.Error())
+	}
+	for _, rt := range set.Host {
+		if err := AddRoute(rt); err != nil {
+			t.Fatalf("AddRoute(%s) failed %s", rt, err.Error())
+		}
+	}
+
+	routes, err := NetlinkSource{Table: TableMain}.Routes(t.Context())
+	if err != nil {
+		t.Fatalf("Routes failed %s", err.Error())
+	}
+	var got []string
+	for _, rt := range routes {
+		got = append(got, rt.String())
+	}
+	for _, want := range []string{
+		"10.244.1.0/24 dev veth1 proto kernel scope link src 10.244.1.1",
+		"10.244.2.0/24 via 192.0.2.12 dev veth0",
+	} {
+		if !slices.Contains(got, want) {
+			t.Errorf("Expected %q in the main table, got:\n%s", want, strings.Join(got, "\n"))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package routing
+
+import (
+	"net"
+	"testing"
+)
+
+func TestCNIRoutes(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("10.244.1.0/24")
+	_, peer, _ := net.ParseCIDR("10.244.2.0/24")
+	set, err := CNIRoutes(CNIDelegation{
+		Subnet: subnet,
+		Bridge: "cni0",
+		Uplink: "ens3",
+		Peers:  []CNIPeer{{Subnet: peer, NodeIP: net.ParseIP("192.0.2.12")}},
+	})
+	if err != nil {
+		t.Fatalf("CNIRoutes failed %s", err.Error())
+	}
+
+	// The kernel adds the pod subnet's route to main when the bridge gets its address.
+	want := []string{"10.244.2.0/24 via 192.0.2.12 dev ens3"}
+	if len(set.Host) != len(want) {
+		t.Fatalf("Expected %d host routes, got %v", len(want), set.Host)
+	}
+	for i, rt := range set.Host {
+		if rt.String() != want[i] {
+			t.Errorf("Host route %d = %q, want %q", i, rt.String(), want[i])
+		}
+	}
+
+	want = []string{"default via 10.244.1.1 dev eth0"}
+	if len(set.Pod) != len(want) {
+		t.Fatalf("Expected %d pod routes, got %v", len(want), set.Pod)
+	}
+	for i, rt := range set.Pod {
+		if rt.String() != want[i] {
+			t.Errorf("Pod route %d = %q, want %q", i, rt.String(), want[i])
+		}
+	}
+}
+
+func TestCNIRoutesTable(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("10.244.1.0/24")
+	set, err := CNIRoutes(CNIDelegation{Subnet: subnet, Bridge: "cni0", Table: 100})
+	if err != nil {
+		t.Fatalf("CNIRoutes failed %s", err.Error())
+	}
+	if len(set.Host) != 1 || set.Host[0].String() != "10.244.1.0/24 dev cni0 table 100 scope link" {
+		t.Errorf("Expected the pod subnet on the bridge in table 100, got %v", set.Host)
+	}
+}
+
+func TestCNIRoutesInvalid(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("10.244.1.0/24")
+	_, v6, _ := net.ParseCIDR("fd00::/64")
+	_, tiny, _ := net.ParseCIDR("10.244.1.0/31")
+	_, overlap, _ := net.ParseCIDR("10.244.0.0/16")
+	peer := []CNIPeer{{Subnet: overlap, NodeIP: net.ParseIP("192.0.2.12")}}
+
+	for name, d := range map[string]CNIDelegation{
+		"no subnet":       {Bridge: "cni0"},
+		"IPv6 subnet":     {Subnet: v6, Bridge: "cni0"},
+		"no bridge":       {Subnet: subnet},
+		"no room":         {Subnet: tiny, Bridge: "cni0"},
+		"outside gateway": {Subnet: subnet, Bridge: "cni0", Gateway: net.ParseIP("10.244.2.1")},
+		"no uplink":       {Subnet: subnet, Bridge: "cni0", Peers: []CNIPeer{{Subnet: tiny, NodeIP: net.ParseIP("192.0.2.12")}}},
+		"overlapping":     {Subnet: subnet, Bridge: "cni0", Uplink: "ens3", Peers: peer},
+	} {
+		if _, err := CNIRoutes(d); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package routing
+
+import (
+	"context"
+	"sync"
+)
+
+// flight is a read of the wrapped source shared by concurrent callers.
+type flight struct {
+	done   chan struct{}
+	routes []RoutingTable
+	err    error
+}
+
+// CoalescedSource wraps a RouteSource so that concurrent calls share a single read.
+// Callers arriving while a read is in progress wait for it and receive its result.
+type CoalescedSource struct {
+	source RouteSource
+
+	mu   sync.Mutex
+	call *flight // Read in progress, or nil.
+}
+
+// NewCoalescedSource returns a CoalescedSource reading from source.
+func NewCoalescedSource(source RouteSource) *CoalescedSource {
+	return &CoalescedSource{source: source}
+}
+
+// Routes returns the routing table, joining a read already in progress if there is one.
+// The shared read is not cancelled when a caller's ctx is done; only that caller stops waiting.
+// Every caller gets its own copy of the routes, maps and slices included.
+func (c *CoalescedSource) Routes(ctx context.Context) ([]RoutingTable, error) {
+	c.mu.Lock()
+	f := c.call
+	if f == nil {
+		f = &flight{done: make(chan struct{})}
+		c.call = f
+		go func() {
+			f.routes, f.err = c.source.Routes(context.WithoutCancel(ctx))
+
+			c.mu.Lock()
+			c.call = nil
+			c.mu.Unlock()
+			close(f.done)
+		}()
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-f.done:
+	}
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return cloneRoutes(f.routes), nil
+}
@@ -0,0 +1,56 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalescedSource(t *testing.T) {
+	var reads atomic.Int32
+	release := make(chan struct{})
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		reads.Add(1)
+		<-release
+		return []RoutingTable{{Interface: "eth0"}}, nil
+	})
+
+	c := NewCoalescedSource(src)
+
+	var wg sync.WaitGroup
+	results := make(chan int, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			routes, err := c.Routes(context.Background())
+			if err != nil {
+				t.Errorf("Routes failed %s", err.Error())
+			}
+			results <- len(routes)
+		}()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.Routes(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancellation error, got %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond) // Give every goroutine time to join the read in progress.
+	close(release)
+	wg.Wait()
+	close(results)
+
+	for n := range results {
+		if n != 1 {
+			t.Errorf("Expected one route, got %d", n)
+		}
+	}
+	if reads.Load() != 1 {
+		t.Errorf("Expected concurrent calls to share one read, got %d", reads.Load())
+	}
+}
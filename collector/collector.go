@@ -0,0 +1,105 @@
+// Package collector exports routing table state as Prometheus metrics.
+// It reads routes through a routing.RouteSource on every scrape, so the
+// metrics always reflect the table at scrape time.
+package collector
+
+import (
+	"context"
+	"sync"
+
+	"github.com/noopduck/routing"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	entriesDesc = prometheus.NewDesc(
+		"routing_table_entries",
+		"Number of routes in the routing table per interface.",
+		[]string{"iface"}, nil,
+	)
+	defaultGatewayDesc = prometheus.NewDesc(
+		"routing_default_gateway_info",
+		"Default gateway currently in use; the value is always 1.",
+		[]string{"gateway", "iface"}, nil,
+	)
+	changesDesc = prometheus.NewDesc(
+		"routing_table_changes_total",
+		"Number of routes added or removed between scrapes.",
+		nil, nil,
+	)
+)
+
+// routeList is a RouteSource returning routes already read.
+type routeList []routing.RoutingTable
+
+// Routes returns the routes of the list.
+func (l routeList) Routes(ctx context.Context) ([]routing.RoutingTable, error) {
+	return l, nil
+}
+
+// Collector implements prometheus.Collector for the routing table.
+type Collector struct {
+	source routing.RouteSource
+
+	mu       sync.Mutex
+	previous map[string]struct{} // Routes seen on the last scrape, keyed by their String form.
+	changes  float64
+}
+
+// New returns a Collector reading routes from source.
+func New(source routing.RouteSource) *Collector {
+	return &Collector{source: source}
+}
+
+// Describe sends the descriptors of all metrics exported by the collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- entriesDesc
+	ch <- defaultGatewayDesc
+	ch <- changesDesc
+}
+
+// Collect reads the routing table and sends the current metrics.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	routes, err := c.source.Routes(context.Background())
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(entriesDesc, err)
+		return
+	}
+
+	perIface := make(map[string]int)
+	current := make(map[string]struct{}, len(routes))
+	for _, rt := range routes {
+		perIface[rt.Interface]++
+		current[rt.String()] = struct{}{}
+	}
+
+	for iface, n := range perIface {
+		ch <- prometheus.MustNewConstMetric(entriesDesc, prometheus.GaugeValue, float64(n), iface)
+	}
+
+	// The routes are read once per scrape; the manager only picks the default route among them, by the rules of
+	// Manager.DefaultRoute, so that down, discarding and gateway-less default routes are not reported.
+	m := routing.NewManager(routing.WithSource(routeList(routes)))
+	if gw, err := m.DefaultRoute(context.Background()); err == nil {
+		ch <- prometheus.MustNewConstMetric(defaultGatewayDesc, prometheus.GaugeValue, 1, gw.Gateway, gw.Interface)
+	}
+
+	c.mu.Lock()
+	if c.previous != nil {
+		for key := range current {
+			if _, ok := c.previous[key]; !ok {
+				c.changes++
+			}
+		}
+		for key := range c.previous {
+			if _, ok := current[key]; !ok {
+				c.changes++
+			}
+		}
+	}
+	c.previous = current
+	changes := c.changes
+	c.mu.Unlock()
+
+	ch <- prometheus.MustNewConstMetric(changesDesc, prometheus.CounterValue, changes)
+}
@@ -0,0 +1,101 @@
+package collector
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/noopduck/routing"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// staticSource returns whatever routes it currently holds.
+type staticSource struct {
+	routes []routing.RoutingTable
+}
+
+func (s *staticSource) Routes(ctx context.Context) ([]routing.RoutingTable, error) {
+	return s.routes, nil
+}
+
+func mustParse(t *testing.T, text string) []routing.RoutingTable {
+	t.Helper()
+	routes, err := routing.ParseIPRoute(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	return routes
+}
+
+func TestCollector(t *testing.T) {
+	src := &staticSource{routes: mustParse(t, "default via 192.168.1.1 dev eth0\n192.168.1.0/24 dev eth0\n10.8.0.0/24 dev tun0\n")}
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(New(src))
+
+	if _, err := reg.Gather(); err != nil {
+		t.Fatalf("Gather failed %s", err.Error())
+	}
+
+	src.routes = mustParse(t, "default via 10.8.0.1 dev tun0\n192.168.1.0/24 dev eth0\n10.8.0.0/24 dev tun0\n")
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed %s", err.Error())
+	}
+
+	values := make(map[string]float64)
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			key := mf.GetName()
+			for _, l := range m.GetLabel() {
+				key += "," + l.GetName() + "=" + l.GetValue()
+			}
+			if m.GetGauge() != nil {
+				values[key] = m.GetGauge().GetValue()
+			}
+			if m.GetCounter() != nil {
+				values[key] = m.GetCounter().GetValue()
+			}
+		}
+	}
+
+	expected := map[string]float64{
+		"routing_table_entries,iface=eth0":                         1,
+		"routing_table_entries,iface=tun0":                         2,
+		"routing_default_gateway_info,gateway=10.8.0.1,iface=tun0": 1,
+		"routing_table_changes_total":                              2,
+	}
+	for key, want := range expected {
+		if got, ok := values[key]; !ok || got != want {
+			t.Errorf("%s = %v, want %v (present %t)", key, got, want, ok)
+		}
+	}
+}
+
+func TestCollectorDefaultGateway(t *testing.T) {
+	// The discarding default route has the lowest metric, but carries no traffic to a gateway.
+	src := &staticSource{routes: mustParse(t, "unreachable default metric 1\ndefault dev wg0 metric 5\ndefault via 192.168.1.1 dev eth0 metric 100\n")}
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(New(src))
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed %s", err.Error())
+	}
+	var gateways []string
+	for _, mf := range families {
+		if mf.GetName() != "routing_default_gateway_info" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "gateway" {
+					gateways = append(gateways, l.GetValue())
+				}
+			}
+		}
+	}
+	if len(gateways) != 1 || gateways[0] != "192.168.1.1" {
+		t.Errorf("Expected 192.168.1.1 as the default gateway, got %v", gateways)
+	}
+}
@@ -0,0 +1,52 @@
+package routing
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// csvHeader lists the columns written by WriteCSV, in order.
+var csvHeader = []string{
+	"interface", "destination", "prefix_len", "gateway", "mask", "flags",
+	"ref_cnt", "use", "metric", "mtu", "window", "irtt",
+}
+
+// WriteCSV writes the routes to w as CSV with a header row.
+// Addresses are written in dotted notation and flags as their letters ordered by bit, e.g. "UG".
+func WriteCSV(w io.Writer, routes []RoutingTable) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, rt := range routes {
+		dst, mask, ones, err := decodeDestination(rt)
+		if err != nil {
+			return err
+		}
+
+		record := []string{
+			rt.Interface,
+			dst.String(),
+			strconv.Itoa(ones),
+			rt.Gateway,
+			mask.String(),
+			flagLetters(rt.Flags),
+			strconv.Itoa(int(rt.RefCnt)),
+			strconv.Itoa(int(rt.Use)),
+			strconv.FormatUint(uint64(rt.Metric), 10),
+			strconv.FormatUint(uint64(rt.Metrics.MTU), 10),
+			strconv.Itoa(int(rt.Window)),
+			strconv.Itoa(int(rt.IRTT)),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
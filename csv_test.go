@@ -0,0 +1,25 @@
+package routing
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	routes := []RoutingTable{
+		{Interface: "eth0", Destination: "00000000", Gateway: "192.168.2.1", Flags: computeRouteFlag(0x3), Metric: 4294967295, Mask: "00000000"},
+		{Interface: "eth0", Destination: "0002A8C0", Gateway: "0.0.0.0", Flags: computeRouteFlag(0x1), Metric: 100, Mask: "00FFFFFF"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, routes); err != nil {
+		t.Fatalf("WriteCSV failed %s", err.Error())
+	}
+
+	expected := "interface,destination,prefix_len,gateway,mask,flags,ref_cnt,use,metric,mtu,window,irtt\n" +
+		"eth0,0.0.0.0,0,192.168.2.1,0.0.0.0,UG,0,0,4294967295,0,0,0\n" +
+		"eth0,192.168.2.0,24,0.0.0.0,255.255.255.0,U,0,0,100,0,0,0\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected CSV output\n%s\nwant\n%s", buf.String(), expected)
+	}
+}
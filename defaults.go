@@ -0,0 +1,127 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"sort"
+)
+
+// DefaultRouteEntry is a default route, 0.0.0.0/0 or ::/0, of a multi-homed host's candidates.
+type DefaultRouteEntry struct {
+	Family    Family // FamilyIPv4 or FamilyIPv6.
+	Gateway   net.IP // The next hop; nil for a default route straight out of an interface, such as a tunnel.
+	Interface string // The network interface the route leaves through.
+	Metric    uint32 // Metric for the route; the lowest wins.
+	Preferred bool   // The route has the lowest metric of its family; several are when they tie, see EqualCostDefaults.
+}
+
+// DefaultRoutes returns every IPv4 and IPv6 default route, IPv4 first and each family ordered by metric.
+// Unlike DefaultRoute it lists every candidate, not only the one in use, and every path of a multipath
+// default route as its own entry. Routes that are down or discard traffic,
+// such as unreachable defaults, are left out. Hosts without IPv6 only report IPv4 routes.
+func DefaultRoutes() ([]DefaultRouteEntry, error) {
+	return DefaultRoutesContext(context.Background())
+}
+
+// DefaultRoutesContext is like DefaultRoutes but returns early if ctx is done.
+func DefaultRoutesContext(ctx context.Context) ([]DefaultRouteEntry, error) {
+	return defaultManager.DefaultRoutes(ctx)
+}
+
+// DefaultRoutes returns the default routes of the manager's routes and IPv6Routes, as DefaultRoutes does.
+// Sources given to WithSource that report no IPv6 routes only report IPv4 routes.
+func (m *Manager) DefaultRoutes(ctx context.Context) ([]DefaultRouteEntry, error) {
+	routes, err := m.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var v4 []DefaultRouteEntry
+	for _, rt := range routes {
+		if _, _, ones, err := decodeDestination(rt); err != nil || ones != 0 || !flagContains(rt.Flags, "U") || !isForwarding(rt.Type, rt.Flags) {
+			continue
+		}
+		paths := rt.Nexthops
+		if len(paths) == 0 {
+			paths = []Nexthop{{Gateway: rt.Gateway, Interface: rt.Interface}}
+		}
+		for _, nh := range paths {
+			v4 = append(v4, DefaultRouteEntry{
+				Family:    FamilyIPv4,
+				Gateway:   gatewayIP(net.ParseIP(nh.Gateway)),
+				Interface: nh.Interface,
+				Metric:    rt.Metric,
+			})
+		}
+	}
+
+	v6Routes, err := m.IPv6Routes(ctx)
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, ErrNoIPv6Routes) {
+		m.log().Debug("IPv6 routing table unavailable", "err", err)
+	} else if err != nil {
+		return nil, err
+	}
+	var v6 []DefaultRouteEntry
+	for _, rt := range v6Routes {
+		if rt.PrefixLen() != 0 || !isForwarding(RouteTypeUnicast, rt.Flags) {
+			continue
+		}
+		v6 = append(v6, DefaultRouteEntry{
+			Family:    FamilyIPv6,
+			Gateway:   gatewayIP(rt.Gateway),
+			Interface: rt.Interface,
+			Metric:    rt.Metric,
+		})
+	}
+
+	return append(rankDefaults(v4), rankDefaults(v6)...), nil
+}
+
+// isForwarding reports whether a route of type typ with flags forwards traffic rather than discarding it.
+func isForwarding(typ RouteType, flags map[string]RouteFlag) bool {
+	return (typ == RouteTypeUnspec || typ == RouteTypeUnicast) && !flagContains(flags, "!")
+}
+
+// gatewayIP returns ip, or nil if it is missing or unspecified.
+func gatewayIP(ip net.IP) net.IP {
+	if ip == nil || ip.IsUnspecified() {
+		return nil
+	}
+
+	return ip
+}
+
+// rankDefaults orders default routes of one family by metric, keeping the table order among equal metrics,
+// and marks those with the lowest metric as preferred.
+func rankDefaults(routes []DefaultRouteEntry) []DefaultRouteEntry {
+	sort.SliceStable(routes, func(i, j int) bool { return routes[i].Metric < routes[j].Metric })
+	for i := range routes {
+		routes[i].Preferred = routes[i].Metric == routes[0].Metric
+	}
+
+	return routes
+}
+
+// EqualCostDefaults returns the default routes of family that share the lowest metric when there are two or more,
+// as in an ECMP default route, and nil otherwise. In that case "the default gateway" is ambiguous: the kernel
+// balances traffic over the paths or, for separate IPv4 routes, uses whichever comes first in the table, so
+// callers reporting or probing a single gateway should handle the whole set. routes is typically the result
+// of DefaultRoutes.
+func EqualCostDefaults(routes []DefaultRouteEntry, family Family) []DefaultRouteEntry {
+	var lowest []DefaultRouteEntry
+	for _, rt := range routes {
+		switch {
+		case rt.Family != family:
+		case len(lowest) == 0 || rt.Metric < lowest[0].Metric:
+			lowest = []DefaultRouteEntry{rt}
+		case rt.Metric == lowest[0].Metric:
+			lowest = append(lowest, rt)
+		}
+	}
+	if len(lowest) < 2 {
+		return nil
+	}
+
+	return lowest
+}
@@ -0,0 +1,121 @@
+package routing
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDefaultRoutes(t *testing.T) {
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		return ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev eth0 metric 700\n" +
+			"default via 198.51.100.1 dev wlan0 metric 600\n" +
+			"default dev wg0 metric 700\n" +
+			"unreachable default metric 4000\n" +
+			"192.0.2.0/24 dev eth0\n"))
+	})
+	ipv6Routes := ipv6RouteFixture +
+		"00000000000000000000000000000000 00 00000000000000000000000000000000 00 fe800000000000000000000000000002 00000064 00000001 00000000 00000003     wlan0\n" +
+		"00000000000000000000000000000000 00 00000000000000000000000000000000 00 00000000000000000000000000000000 ffffffff 00000001 00000000 00200200       lo\n"
+	m := NewManager(WithSource(src), WithFS(fstest.MapFS{"proc/net/ipv6_route": {Data: []byte(ipv6Routes)}}))
+
+	routes, err := m.DefaultRoutes(context.Background())
+	if err != nil {
+		t.Fatalf("DefaultRoutes failed %s", err.Error())
+	}
+	expected := []DefaultRouteEntry{
+		{Family: FamilyIPv4, Gateway: net.ParseIP("198.51.100.1"), Interface: "wlan0", Metric: 600, Preferred: true},
+		{Family: FamilyIPv4, Gateway: net.ParseIP("192.0.2.1"), Interface: "eth0", Metric: 700},
+		{Family: FamilyIPv4, Interface: "wg0", Metric: 700},
+		{Family: FamilyIPv6, Gateway: net.ParseIP("fe80::2"), Interface: "wlan0", Metric: 100, Preferred: true},
+		{Family: FamilyIPv6, Gateway: net.ParseIP("fe80::1"), Interface: "eth0", Metric: 1024},
+	}
+	if len(routes) != len(expected) {
+		t.Fatalf("Expected %d default routes, got %+v", len(expected), routes)
+	}
+	for i, want := range expected {
+		got := routes[i]
+		if got.Family != want.Family || !got.Gateway.Equal(want.Gateway) || got.Interface != want.Interface ||
+			got.Metric != want.Metric || got.Preferred != want.Preferred {
+			t.Errorf("Route %d = %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestDefaultRoutesSourceWithoutIPv6(t *testing.T) {
+	routes := failoverRoutes(t)
+	delete(routes[0].Flags, "U") // The route via 192.0.2.1 is down.
+
+	defaults, err := NewManager(WithSource(&memTable{routes: routes})).DefaultRoutes(context.Background())
+	if err != nil {
+		t.Fatalf("DefaultRoutes failed %s", err.Error())
+	}
+	var gateways []string
+	for _, rt := range defaults {
+		if rt.Family != FamilyIPv4 {
+			t.Errorf("Expected only the source's IPv4 routes, got %+v", rt)
+		}
+		gateways = append(gateways, rt.Gateway.String())
+	}
+	if strings.Join(gateways, " ") != "<nil> 203.0.113.1 198.51.100.1" {
+		t.Errorf("Expected the defaults via wg0, 203.0.113.1 and 198.51.100.1, got %v", gateways)
+	}
+}
+
+func TestDefaultRoutesWithoutIPv6(t *testing.T) {
+	useProcFS(t)
+
+	routes, err := DefaultRoutes()
+	if err != nil {
+		t.Fatalf("DefaultRoutes failed %s", err.Error())
+	}
+	if len(routes) != 1 || routes[0].Family != FamilyIPv4 || routes[0].Gateway.String() != "192.0.2.1" || !routes[0].Preferred {
+		t.Errorf("Expected the IPv4 default via 192.0.2.1, got %+v", routes)
+	}
+}
+
+func TestEqualCostDefaults(t *testing.T) {
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		routes, err := ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev eth0 metric 600\ndefault via 198.51.100.1 dev wlan0 metric 700\n"))
+		if err != nil {
+			return nil, err
+		}
+		routes[0].Nexthops = []Nexthop{{Gateway: "192.0.2.1", Interface: "eth0", Weight: 1}, {Gateway: "192.0.2.2", Interface: "eth1", Weight: 1}}
+		return routes, nil
+	})
+	m := NewManager(WithSource(src), WithFS(fstest.MapFS{"proc/net/ipv6_route": {Data: []byte(ipv6RouteFixture)}}))
+
+	routes, err := m.DefaultRoutes(context.Background())
+	if err != nil {
+		t.Fatalf("DefaultRoutes failed %s", err.Error())
+	}
+	ecmp := EqualCostDefaults(routes, FamilyIPv4)
+	if len(ecmp) != 2 || ecmp[0].Gateway.String() != "192.0.2.1" || ecmp[1].Gateway.String() != "192.0.2.2" || ecmp[1].Interface != "eth1" {
+		t.Errorf("Expected both paths of the multipath default, got %+v", ecmp)
+	}
+	for _, rt := range ecmp {
+		if !rt.Preferred {
+			t.Errorf("Expected every equal-cost default to be preferred, got %+v", rt)
+		}
+	}
+	if ecmp := EqualCostDefaults(routes, FamilyIPv6); ecmp != nil {
+		t.Errorf("Expected a single IPv6 default, got %+v", ecmp)
+	}
+
+	separate := []DefaultRouteEntry{
+		{Family: FamilyIPv6, Gateway: net.ParseIP("fe80::1"), Interface: "eth0", Metric: 1024},
+		{Family: FamilyIPv6, Gateway: net.ParseIP("fe80::2"), Interface: "eth1", Metric: 1024},
+		{Family: FamilyIPv6, Gateway: net.ParseIP("fe80::3"), Interface: "wlan0", Metric: 2048},
+	}
+	if ecmp := EqualCostDefaults(separate, FamilyIPv6); len(ecmp) != 2 || ecmp[1].Interface != "eth1" {
+		t.Errorf("Expected the two routes with metric 1024, got %+v", ecmp)
+	}
+	if ecmp := EqualCostDefaults(routes[1:], FamilyIPv4); ecmp != nil {
+		t.Errorf("Expected defaults with metrics 600 and 700 not to be equal-cost, got %+v", ecmp)
+	}
+	if ecmp := EqualCostDefaults(nil, FamilyIPv4); ecmp != nil {
+		t.Errorf("Expected nil without routes, got %+v", ecmp)
+	}
+}
@@ -0,0 +1,107 @@
+package routing
+
+import (
+	"context"
+	"strconv"
+)
+
+// DeviceKind classifies the network interface a route leaves through, telling overlay devices such as tunnels
+// apart from the underlay.
+type DeviceKind uint8
+
+// Device kinds. The zero value means the kind is unknown, as for routes not classified by WithDeviceKinds.
+const (
+	DeviceUnknown   DeviceKind = iota
+	DevicePhysical             // A hardware interface, e.g. an Ethernet or Wi-Fi adapter.
+	DeviceLoopback             // The loopback interface.
+	DeviceBridge               // A software bridge.
+	DeviceBond                 // A bonded (link aggregation) interface.
+	DeviceVLAN                 // An 802.1Q VLAN interface.
+	DeviceTunTap               // A tun or tap device driven by a user space program, e.g. a VPN client.
+	DeviceWireGuard            // A WireGuard tunnel.
+	DeviceGRE                  // A GRE tunnel, including gretap and their IPv6 variants.
+	DeviceVXLAN                // A VXLAN overlay.
+	DeviceVirtual              // Another virtual device, e.g. veth, dummy or macvlan.
+)
+
+// deviceKindNames are the names of the device kinds, indexed by value.
+var deviceKindNames = [...]string{
+	"unknown", "physical", "loopback", "bridge", "bond", "vlan", "tuntap", "wireguard", "gre", "vxlan", "virtual",
+}
+
+// String returns the name of the kind, e.g. "wireguard". Unknown values are printed as their number.
+func (k DeviceKind) String() string {
+	if int(k) < len(deviceKindNames) {
+		return deviceKindNames[k]
+	}
+
+	return strconv.Itoa(int(k))
+}
+
+// ParseDeviceKind returns the device kind with the given name, as returned by DeviceKind.String.
+func ParseDeviceKind(s string) (DeviceKind, bool) {
+	for i, name := range deviceKindNames {
+		if name == s {
+			return DeviceKind(i), true
+		}
+	}
+
+	return DeviceUnknown, false
+}
+
+// Overlay reports whether the device is a tunnel or overlay carrying traffic inside other packets:
+// tun/tap, WireGuard, GRE or VXLAN.
+func (k DeviceKind) Overlay() bool {
+	switch k {
+	case DeviceTunTap, DeviceWireGuard, DeviceGRE, DeviceVXLAN:
+		return true
+	}
+
+	return false
+}
+
+// deviceKindOf classifies a device by the kind of its kernel driver, as in `ip -d link` (e.g. "vxlan"),
+// which is empty for hardware interfaces.
+func deviceKindOf(kind string, loopback bool) DeviceKind {
+	switch kind {
+	case "":
+		if loopback {
+			return DeviceLoopback
+		}
+		return DevicePhysical
+	case "bridge":
+		return DeviceBridge
+	case "bond":
+		return DeviceBond
+	case "vlan":
+		return DeviceVLAN
+	case "tun":
+		return DeviceTunTap
+	case "wireguard":
+		return DeviceWireGuard
+	case "gre", "gretap", "ip6gre", "ip6gretap", "erspan", "ip6erspan":
+		return DeviceGRE
+	case "vxlan":
+		return DeviceVXLAN
+	}
+
+	return DeviceVirtual
+}
+
+// DeviceKinds returns the kind of each network interface of the host, keyed by name.
+// It is only available on Linux and returns ErrNotSupported elsewhere.
+func DeviceKinds() (map[string]DeviceKind, error) {
+	return DeviceKindsContext(context.Background())
+}
+
+// ClassifyDevices returns a copy of routes with DeviceKind set from the kind of each route's interface.
+// Routes through interfaces missing from kinds are left DeviceUnknown.
+func ClassifyDevices(routes []RoutingTable, kinds map[string]DeviceKind) []RoutingTable {
+	out := make([]RoutingTable, len(routes))
+	for i, rt := range routes {
+		rt.DeviceKind = kinds[rt.Interface]
+		out[i] = rt
+	}
+
+	return out
+}
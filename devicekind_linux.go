@@ -0,0 +1,43 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"syscall"
+)
+
+// Link information attributes nested in IFLA_LINKINFO, from linux/if_link.h.
+const iflaInfoKind = 1
+
+// DeviceKindsContext is like DeviceKinds but returns early if ctx is done.
+func DeviceKindsContext(ctx context.Context) (map[string]DeviceKind, error) {
+	msgs, err := netlinkDump(ctx, syscall.RTM_GETLINK, syscall.AF_UNSPEC)
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := make(map[string]DeviceKind, len(msgs))
+	for _, m := range msgs {
+		if name, kind, ok := parseLinkKind(m.Data); ok {
+			kinds[name] = kind
+		}
+	}
+
+	return kinds, nil
+}
+
+// parseLinkKind decodes the name and kind of an interface from the payload of an RTM_NEWLINK message.
+func parseLinkKind(b []byte) (string, DeviceKind, bool) {
+	if len(b) < syscall.SizeofIfInfomsg {
+		return "", DeviceUnknown, false
+	}
+	attrs := netlinkAttrs(b[syscall.SizeofIfInfomsg:])
+	name := nlString(attrs[syscall.IFLA_IFNAME])
+	if name == "" {
+		return "", DeviceUnknown, false
+	}
+	info := netlinkAttrs(attrs[syscall.IFLA_LINKINFO])
+	loopback := binary.NativeEndian.Uint16(b[2:4]) == syscall.ARPHRD_LOOPBACK
+
+	return name, deviceKindOf(nlString(info[iflaInfoKind]), loopback), true
+}
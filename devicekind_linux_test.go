@@ -0,0 +1,29 @@
+package routing
+
+import (
+	"context"
+	"syscall"
+	"testing"
+)
+
+func TestDeviceKinds(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+	setLoopbackUp(t)
+
+	msg := make([]byte, syscall.SizeofIfInfomsg)
+	msg = append(msg, nlAttr(syscall.IFLA_IFNAME, []byte("br0\x00"))...)
+	msg = append(msg, nlAttr(syscall.IFLA_LINKINFO, nlAttr(iflaInfoKind, []byte("bridge")))...)
+	if _, err := netlinkRequest(context.Background(), syscall.RTM_NEWLINK, syscall.NLM_F_CREATE|syscall.NLM_F_EXCL, msg); err != nil {
+		t.Skipf("Cannot create a bridge: %s", err)
+	}
+
+	kinds, err := DeviceKinds()
+	if err != nil {
+		t.Fatalf("DeviceKinds failed %s", err.Error())
+	}
+	if kinds["lo"] != DeviceLoopback || kinds["br0"] != DeviceBridge {
+		t.Errorf("Unexpected device kinds %v", kinds)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package routing
+
+import "context"
+
+// DeviceKindsContext is like DeviceKinds but returns early if ctx is done.
+// Netlink is only available on Linux, so it always returns ErrNotSupported here.
+func DeviceKindsContext(ctx context.Context) (map[string]DeviceKind, error) {
+	return nil, ErrNotSupported
+}
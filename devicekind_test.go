@@ -0,0 +1,80 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDeviceKindOf(t *testing.T) {
+	tests := []struct {
+		kind     string
+		loopback bool
+		expected DeviceKind
+	}{
+		{"", false, DevicePhysical},
+		{"", true, DeviceLoopback},
+		{"bridge", false, DeviceBridge},
+		{"bond", false, DeviceBond},
+		{"vlan", false, DeviceVLAN},
+		{"tun", false, DeviceTunTap},
+		{"wireguard", false, DeviceWireGuard},
+		{"ip6gretap", false, DeviceGRE},
+		{"vxlan", false, DeviceVXLAN},
+		{"veth", false, DeviceVirtual},
+	}
+	for _, tt := range tests {
+		if got := deviceKindOf(tt.kind, tt.loopback); got != tt.expected {
+			t.Errorf("deviceKindOf(%q, %t) = %s, want %s", tt.kind, tt.loopback, got, tt.expected)
+		}
+	}
+
+	if !DeviceWireGuard.Overlay() || DeviceBridge.Overlay() {
+		t.Error("Expected only tunnels to be overlays")
+	}
+	if k, ok := ParseDeviceKind("vxlan"); !ok || k != DeviceVXLAN {
+		t.Errorf("ParseDeviceKind(vxlan) = %s, %t", k, ok)
+	}
+	if DeviceKind(200).String() != "200" {
+		t.Errorf("Unexpected name %s", DeviceKind(200))
+	}
+}
+
+func TestWithDeviceKinds(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev eth0\n10.9.0.0/24 dev wg0\n10.10.0.0/24 dev br0\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	src := deviceKindSource{
+		source: routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) { return routes, nil }),
+		kinds: func(context.Context) (map[string]DeviceKind, error) {
+			return map[string]DeviceKind{"eth0": DevicePhysical, "wg0": DeviceWireGuard}, nil
+		},
+	}
+	got, err := src.Routes(context.Background())
+	if err != nil {
+		t.Fatalf("Routes failed %s", err.Error())
+	}
+	expected := []DeviceKind{DevicePhysical, DeviceWireGuard, DeviceUnknown}
+	for i, rt := range got {
+		if rt.DeviceKind != expected[i] {
+			t.Errorf("Route %d through %s has kind %s, want %s", i, rt.Interface, rt.DeviceKind, expected[i])
+		}
+	}
+	if routes[1].DeviceKind != DeviceUnknown {
+		t.Error("Expected ClassifyDevices to leave its input unchanged")
+	}
+
+	b, err := json.Marshal(got[1])
+	if err != nil {
+		t.Fatalf("Marshal failed %s", err.Error())
+	}
+	if !strings.Contains(string(b), `"device_kind":"wireguard"`) {
+		t.Errorf("Expected the device kind in %s", b)
+	}
+	var rt RoutingTable
+	if err := json.Unmarshal(b, &rt); err != nil || rt.DeviceKind != DeviceWireGuard {
+		t.Errorf("Unmarshal gave %s, %v", rt.DeviceKind, err)
+	}
+}
@@ -0,0 +1,250 @@
+package routing
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/fs"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dhcpLeaseFiles lists where common DHCP clients keep their leases and how to parse them.
+var dhcpLeaseFiles = []struct {
+	globs []string
+	parse func(name string, r io.Reader, written time.Time) ([]DHCPLease, error)
+}{
+	// ISC dhclient, as used by Debian, RHEL and NetworkManager's dhclient backend.
+	{[]string{"var/lib/dhcp/dhclient*.leases", "var/lib/dhclient/dhclient*.lease*", "var/lib/NetworkManager/dhclient-*.lease"}, parseDHClientLeases},
+	// systemd-networkd, named by interface index, and NetworkManager's internal client, named "internal-<uuid>-<interface>.lease".
+	{[]string{"run/systemd/netif/leases/*", "var/lib/NetworkManager/internal-*.lease"}, parseKeyValueLease},
+}
+
+// DHCPLease describes the DHCP lease a default route was learned from.
+// Fields the lease file or route did not provide are left zero.
+type DHCPLease struct {
+	Interface string    // The network interface the lease was obtained on.
+	Address   net.IP    // The address assigned to the interface.
+	Router    net.IP    // The default gateway offered by the server.
+	Server    net.IP    // The DHCP server that granted the lease.
+	Expiry    time.Time // When the lease expires; zero if unknown.
+	File      string    // The lease file the lease was read from; empty if it is only known from the route protocol.
+}
+
+// DefaultRouteDHCP reports whether the default route was installed by DHCP and returns its lease.
+func DefaultRouteDHCP() (DHCPLease, bool, error) {
+	return DefaultRouteDHCPContext(context.Background())
+}
+
+// DefaultRouteDHCPContext is like DefaultRouteDHCP but returns early if ctx is done.
+func DefaultRouteDHCPContext(ctx context.Context) (DHCPLease, bool, error) {
+	return defaultManager.DefaultRouteDHCP(ctx)
+}
+
+// DefaultRouteDHCP reports whether the default route was installed by DHCP: either its protocol is "dhcp",
+// which needs a source reporting protocols such as WithNetlink, or the lease files of dhclient, systemd-networkd
+// or NetworkManager hold a lease offering the route's gateway on its interface. Lease files are read from
+// the manager's filesystem, the host's unless WithFS was given, and a route matched by protocol alone
+// returns a lease holding only the interface and router.
+func (m *Manager) DefaultRouteDHCP(ctx context.Context) (DHCPLease, bool, error) {
+	rt, err := m.DefaultRoute(ctx)
+	if err != nil {
+		return DHCPLease{}, false, err
+	}
+	gw := net.ParseIP(rt.Gateway)
+
+	fsys := rootFS(m.fsys)
+	var leases []DHCPLease
+	for _, client := range dhcpLeaseFiles {
+		for _, pattern := range client.globs {
+			files, _ := fs.Glob(fsys, pattern)
+			for _, name := range files {
+				l, err := readLeaseFile(fsys, name, client.parse)
+				if err != nil {
+					m.log().Debug("reading DHCP lease file failed", "file", name, "err", err)
+					continue
+				}
+				leases = append(leases, l...)
+			}
+		}
+	}
+
+	// The last matching lease is the most recent, as dhclient appends renewed leases to its file.
+	var lease DHCPLease
+	found := false
+	for _, l := range leases {
+		if l.Router.Equal(gw) && (l.Interface == "" || l.Interface == rt.Interface) {
+			lease, found = l, true
+		}
+	}
+	if found {
+		if lease.Interface == "" {
+			lease.Interface = rt.Interface
+		}
+		return lease, true, nil
+	}
+	if rt.Proto == "dhcp" {
+		return DHCPLease{Interface: rt.Interface, Router: gw}, true, nil
+	}
+
+	return DHCPLease{}, false, nil
+}
+
+// readLeaseFile opens the lease file name in fsys and parses it with parse, recording name in each lease.
+// parse is given the file's modification time, which relative lifetimes count from.
+func readLeaseFile(fsys fs.FS, name string, parse func(name string, r io.Reader, written time.Time) ([]DHCPLease, error)) ([]DHCPLease, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	leases, err := parse(name, f, info.ModTime())
+	if err != nil {
+		return nil, err
+	}
+	for i := range leases {
+		leases[i].File = "/" + name
+	}
+
+	return leases, nil
+}
+
+// parseDHClientLeases parses the lease blocks of an ISC dhclient lease file, e.g.
+//
+//	lease {
+//	  interface "eth0";
+//	  fixed-address 192.0.2.10;
+//	  option routers 192.0.2.1;
+//	  option dhcp-server-identifier 192.0.2.1;
+//	  expire 4 2026/10/15 08:00:00;
+//	}
+//
+// Times are UTC, or seconds since the epoch when dhclient runs with "db-time-format local".
+func parseDHClientLeases(_ string, r io.Reader, _ time.Time) ([]DHCPLease, error) {
+	var leases []DHCPLease
+	var cur *DHCPLease
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSuffix(strings.TrimSpace(line), ";")
+		switch {
+		case line == "lease {":
+			cur = &DHCPLease{}
+			continue
+		case line == "}" && cur != nil:
+			leases = append(leases, *cur)
+			cur = nil
+			continue
+		case cur == nil:
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "interface":
+			cur.Interface = strings.Trim(fields[1], `"`)
+		case "fixed-address":
+			cur.Address = net.ParseIP(fields[1])
+		case "expire":
+			cur.Expiry = parseDHClientTime(fields[1:])
+		case "option":
+			if len(fields) < 3 {
+				continue
+			}
+			switch fields[1] {
+			case "routers":
+				first, _, _ := strings.Cut(fields[2], ",")
+				cur.Router = net.ParseIP(first)
+			case "dhcp-server-identifier":
+				cur.Server = net.ParseIP(fields[2])
+			}
+		}
+	}
+
+	return leases, scanner.Err()
+}
+
+// parseDHClientTime parses the time of a dhclient lease statement: "4 2026/10/15 08:00:00", "epoch 1791964800" or "never".
+func parseDHClientTime(fields []string) time.Time {
+	if len(fields) == 2 && fields[0] == "epoch" {
+		sec, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return time.Time{}
+		}
+		return time.Unix(sec, 0).UTC()
+	}
+	if len(fields) != 3 {
+		return time.Time{}
+	}
+	t, err := time.Parse("2006/01/02 15:04:05", fields[1]+" "+fields[2])
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// parseKeyValueLease parses a systemd-networkd or NetworkManager lease file of KEY=value lines.
+// The interface is taken from the file name: an index for networkd, what follows the connection UUID for NetworkManager.
+// LIFETIME counts from written, when the file was last written.
+func parseKeyValueLease(name string, r io.Reader, written time.Time) ([]DHCPLease, error) {
+	lease := DHCPLease{}
+	base := path.Base(name)
+	if iface, ok := strings.CutSuffix(base, ".lease"); ok {
+		lease.Interface = nmLeaseInterface(iface)
+	} else if idx, err := strconv.Atoi(base); err == nil {
+		if ifi, err := net.InterfaceByIndex(idx); err == nil {
+			lease.Interface = ifi.Name
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		switch key {
+		case "ADDRESS":
+			lease.Address = net.ParseIP(value)
+		case "ROUTER":
+			first, _, _ := strings.Cut(value, " ")
+			lease.Router = net.ParseIP(first)
+		case "SERVER_ADDRESS":
+			lease.Server = net.ParseIP(value)
+		case "LIFETIME":
+			if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+				lease.Expiry = written.Add(time.Duration(sec) * time.Second)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return []DHCPLease{lease}, nil
+}
+
+// nmLeaseInterface returns the interface of a NetworkManager lease named "internal-<uuid>-<interface>" without its
+// suffix, or "" if the name does not have that form. Interface names may contain "-" themselves, e.g. "br-lan".
+func nmLeaseInterface(name string) string {
+	const uuidLen = 36 // e.g. 4c7e1c3a-5f2b-4d0e-9a6b-2f8e1d3c4b5a
+	rest, ok := strings.CutPrefix(name, "internal-")
+	if !ok || len(rest) <= uuidLen || rest[uuidLen] != '-' {
+		return ""
+	}
+
+	return rest[uuidLen+1:]
+}
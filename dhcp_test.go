@@ -0,0 +1,93 @@
+package routing
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+const dhclientFixture = `lease {
+  interface "eth0";
+  fixed-address 192.0.2.10;
+  option routers 192.0.2.1;
+  option dhcp-server-identifier 192.0.2.254;
+  expire 3 2026/10/14 08:00:00;
+}
+lease {
+  interface "eth0";
+  fixed-address 192.0.2.10;
+  option subnet-mask 255.255.255.0;
+  option routers 192.0.2.1,192.0.2.2;
+  option dhcp-server-identifier 192.0.2.254;
+  renew 4 2026/10/15 02:00:00;
+  expire 4 2026/10/15 08:00:00;
+}
+`
+
+func TestDefaultRouteDHCPDHClient(t *testing.T) {
+	fsys := maps.Clone(procFS)
+	fsys["var/lib/dhcp/dhclient.eth0.leases"] = &fstest.MapFile{Data: []byte(dhclientFixture)}
+
+	lease, ok, err := NewManager(WithFS(fsys)).DefaultRouteDHCP(context.Background())
+	if err != nil || !ok {
+		t.Fatalf("DefaultRouteDHCP = %+v, %t, %v", lease, ok, err)
+	}
+	if lease.Server.String() != "192.0.2.254" || lease.Address.String() != "192.0.2.10" || lease.Interface != "eth0" {
+		t.Errorf("Unexpected lease %+v", lease)
+	}
+	if want := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC); !lease.Expiry.Equal(want) {
+		t.Errorf("Expected the latest lease expiring at %s, got %s", want, lease.Expiry)
+	}
+	if lease.File != "/var/lib/dhcp/dhclient.eth0.leases" {
+		t.Errorf("Unexpected lease file %q", lease.File)
+	}
+}
+
+func TestDefaultRouteDHCPNetworkManager(t *testing.T) {
+	written := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	fsys := maps.Clone(procFS)
+	fsys["var/lib/NetworkManager/internal-4c7e1c3a-5f2b-4d0e-9a6b-2f8e1d3c4b5a-eth0.lease"] = &fstest.MapFile{
+		Data:    []byte("# This is private data. Do not parse.\nADDRESS=192.0.2.10\nROUTER=192.0.2.1\nSERVER_ADDRESS=192.0.2.254\nLIFETIME=3600\n"),
+		ModTime: written,
+	}
+
+	lease, ok, err := NewManager(WithFS(fsys)).DefaultRouteDHCP(context.Background())
+	if err != nil || !ok {
+		t.Fatalf("DefaultRouteDHCP = %+v, %t, %v", lease, ok, err)
+	}
+	if lease.Interface != "eth0" || lease.Server.String() != "192.0.2.254" || !lease.Expiry.Equal(written.Add(time.Hour)) {
+		t.Errorf("Unexpected lease %+v", lease)
+	}
+}
+
+func TestNMLeaseInterface(t *testing.T) {
+	for name, want := range map[string]string{
+		"internal-4c7e1c3a-5f2b-4d0e-9a6b-2f8e1d3c4b5a-eth0":   "eth0",
+		"internal-4c7e1c3a-5f2b-4d0e-9a6b-2f8e1d3c4b5a-br-lan": "br-lan",
+		"internal-4c7e1c3a-eth0":                               "",
+		"eth0":                                                 "",
+	} {
+		if got := nmLeaseInterface(name); got != want {
+			t.Errorf("nmLeaseInterface(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestDefaultRouteDHCPProtocol(t *testing.T) {
+	source := routeSourceFunc(func(context.Context) ([]RoutingTable, error) {
+		return ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev eth0 proto dhcp metric 100"))
+	})
+
+	lease, ok, err := NewManager(WithSource(source), WithFS(fstest.MapFS{})).DefaultRouteDHCP(context.Background())
+	if err != nil || !ok || lease.Router.String() != "192.0.2.1" || lease.File != "" {
+		t.Errorf("DefaultRouteDHCP = %+v, %t, %v", lease, ok, err)
+	}
+
+	_, ok, err = NewManager(WithFS(procFS)).DefaultRouteDHCP(context.Background())
+	if err != nil || ok {
+		t.Errorf("Expected a route without lease or protocol not to be DHCP, got %t, %v", ok, err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build mips || mips64 || ppc64 || s390x
+
+package routing
+
+// procRouteFixture is /proc/net/route as a big-endian kernel such as s390x prints it.
+const procRouteFixture = `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	00000000	C0000201	0003	0	0	100	00000000	0	0	0
+eth0	C0000200	00000000	0001	0	0	100	FFFFFF00	0	0	0
+tun0	0A000001	0A000801	0007	0	0	0	FFFFFFFF	0	0	0
+`
@@ -0,0 +1,10 @@
+//go:build 386 || amd64 || arm || arm64 || loong64 || mips64le || mipsle || ppc64le || riscv64 || wasm
+
+package routing
+
+// procRouteFixture is /proc/net/route as a little-endian kernel prints it.
+const procRouteFixture = `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	00000000	010200C0	0003	0	0	100	00000000	0	0	0
+eth0	000200C0	00000000	0001	0	0	100	00FFFFFF	0	0	0
+tun0	0100000A	0108000A	0007	0	0	0	FFFFFFFF	0	0	0
+`
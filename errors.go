@@ -0,0 +1,143 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoDefaultGateway is returned when the routing table has no usable default route.
+	ErrNoDefaultGateway = errors.New("could not locate default GW")
+
+	// ErrProcUnavailable is returned when /proc/net/route cannot be opened or read.
+	// The underlying error, such as fs.ErrNotExist or fs.ErrPermission, remains in the chain.
+	ErrProcUnavailable = errors.New("routing table unavailable")
+
+	// ErrGatewayUnresolved is returned when the default gateway has no resolved neighbor entry.
+	ErrGatewayUnresolved = errors.New("default gateway hardware address not resolved")
+
+	// ErrNotSupported is returned by netlink-based APIs on platforms other than Linux.
+	ErrNotSupported = errors.New("not supported on this platform")
+
+	// ErrRawSocketNotPermitted is returned when a probe needs a raw socket but the process lacks CAP_NET_RAW.
+	ErrRawSocketNotPermitted = errors.New("raw sockets require CAP_NET_RAW")
+
+	// ErrNoIPv6Routes is returned by Manager.IPv6Routes when the source given to WithSource does not report IPv6
+	// routes, rather than mixing its IPv4 routes with the host's IPv6 routes.
+	ErrNoIPv6Routes = errors.New("route source does not report IPv6 routes")
+
+	// ErrParse is matched by every *ParseError.
+	ErrParse = errors.New("parse error")
+)
+
+var (
+	errUnknownColumn = errors.New("unknown column")
+	errTruncatedRow  = errors.New("truncated row")
+	errExtraColumns  = errors.New("row has more columns than the header")
+	errUnknownFlags  = errors.New("unknown flag bits")
+	errMissingColumn = errors.New("missing from header")
+)
+
+// ParseWarning describes a recoverable anomaly found while parsing routing table input.
+// The affected row is still returned, with the offending value zeroed or saturated.
+type ParseWarning struct {
+	File   string // Name of the file being parsed; empty if the input was not a file.
+	Line   int    // Line of the input, starting at 1.
+	Column string // Name of the affected column; empty when the whole row is affected.
+	Value  string // The offending value or row.
+	Err    error  // What was wrong with the value.
+}
+
+// String formats the warning like a ParseError, e.g. `/proc/net/route: line 3, column Flags: "zz": invalid syntax`.
+func (w ParseWarning) String() string {
+	msg := fmt.Sprintf("%q: %v", w.Value, w.Err)
+	if w.Column != "" {
+		msg = fmt.Sprintf("column %s: %s", w.Column, msg)
+	}
+	if w.Line > 0 {
+		msg = fmt.Sprintf("line %d, %s", w.Line, msg)
+	}
+	if w.File != "" {
+		msg = w.File + ": " + msg
+	}
+
+	return msg
+}
+
+// CollectWarnings returns a warning callback that appends every warning to dst.
+// It is not safe for concurrent use.
+func CollectWarnings(dst *[]ParseWarning) func(ParseWarning) {
+	return func(w ParseWarning) {
+		*dst = append(*dst, w)
+	}
+}
+
+// ParseError describes a value in routing table input that could not be parsed.
+type ParseError struct {
+	File   string // Name of the file being parsed; empty if the input was not a file.
+	Line   int    // Line of the input holding the value, starting at 1; zero if unknown.
+	Column string // Name of the column or field holding the value.
+	Value  string // The value that failed to parse.
+	Err    error  // The underlying error.
+}
+
+// Error formats the error with its position, e.g. `/proc/net/route: line 4, column Gateway: invalid value "zz"`.
+// Errors without a value, such as a missing column, leave out the "invalid value" part.
+func (e *ParseError) Error() string {
+	msg := "column " + e.Column
+	if e.Value != "" {
+		msg = fmt.Sprintf("column %s: invalid value %q", e.Column, e.Value)
+	}
+	if e.Line > 0 {
+		msg = fmt.Sprintf("line %d, %s", e.Line, msg)
+	}
+	if e.File != "" {
+		msg = e.File + ": " + msg
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrParse, so errors.Is(err, ErrParse) matches any ParseError.
+func (e *ParseError) Is(target error) bool {
+	return target == ErrParse
+}
+
+// ProcUnavailableError describes a /proc file that could not be opened and the likely reason, such as /proc not being
+// mounted in a scratch container. errors.Is matches it against ErrProcUnavailable and against its underlying error.
+type ProcUnavailableError struct {
+	Path   string // The file that could not be opened, e.g. "/proc/net/route".
+	Reason string // Likely cause in the environment; empty if there is no better explanation than Err.
+	Err    error  // The underlying error, such as fs.ErrNotExist or fs.ErrPermission.
+
+	procUnusable bool // /proc as a whole is unusable, rather than just the file missing.
+}
+
+// Error formats the error with its reason, e.g.
+// `routing table unavailable: /proc/net/route: /proc is not mounted, as in scratch containers: open ...`.
+func (e *ProcUnavailableError) Error() string {
+	msg := ErrProcUnavailable.Error() + ": " + e.Path
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+
+	return msg + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ProcUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrProcUnavailable.
+func (e *ProcUnavailableError) Is(target error) bool {
+	return target == ErrProcUnavailable
+}
@@ -0,0 +1,93 @@
+package routing
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseErrorChain(t *testing.T) {
+	_, err := ParseIPRoute(strings.NewReader("default via 192.168.1.1 dev eth0\n10.0.0.0/8 dev eth0 metric ten\n"))
+	if !errors.Is(err, ErrParse) {
+		t.Fatalf("Expected ErrParse, got %v", err)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("Expected the strconv error to remain in the chain, got %v", err)
+	}
+
+	var pErr *ParseError
+	if !errors.As(err, &pErr) || pErr.Line != 2 || pErr.Column != "metric" || pErr.Value != "ten" {
+		t.Errorf("Unexpected parse error detail %+v", pErr)
+	}
+	if !strings.HasPrefix(err.Error(), `line 2, column metric: invalid value "ten"`) {
+		t.Errorf("Unexpected error message %q", err.Error())
+	}
+}
+
+func TestDecodeDestinationParseError(t *testing.T) {
+	_, _, _, err := decodeDestination(RoutingTable{Destination: "zz", Mask: "00000000"})
+
+	var pErr *ParseError
+	if !errors.As(err, &pErr) || pErr.Column != "Destination" {
+		t.Errorf("Expected a Destination parse error, got %v", err)
+	}
+}
+
+func TestParseErrorFile(t *testing.T) {
+	// The third route, on line 4 of the file, has a malformed gateway.
+	lines := strings.SplitAfter(procRouteFixture, "\n")
+	lines[3] = strings.Replace(lines[3], "\t"+strings.Fields(lines[3])[2]+"\t", "\tzz\t", 1)
+	fsys := fstest.MapFS{"proc/net/route": {Data: []byte(strings.Join(lines, ""))}}
+
+	var err error
+	for _, err = range procRoutes(fsys, procRoutePath, nil) {
+		if err != nil {
+			break
+		}
+	}
+
+	var pErr *ParseError
+	if !errors.As(err, &pErr) {
+		t.Fatalf("Expected a ParseError, got %v", err)
+	}
+	expected := `/proc/net/route: line 4, column Gateway: invalid value "zz": strconv.ParseUint: parsing "zz": invalid syntax`
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+}
+
+func TestParseRouteRowWarnings(t *testing.T) {
+	description := splitHeader("Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT       ")
+	if len(description) != 11 || description[8] != "MTU" {
+		t.Fatalf("Unexpected header %q", description)
+	}
+	header := newProcHeader(description)
+
+	var warnings []ParseWarning
+	rt, err := parseRouteRow(header, "eth0\t00000000\t010200C0\tzz\t0\t0\t4294967296\t00000000\t99999999999", CollectWarnings(&warnings))
+	if err != nil {
+		t.Fatalf("parseRouteRow failed %s", err.Error())
+	}
+	if rt.Interface != "eth0" || rt.Gateway != "192.0.2.1" || rt.Metric != math.MaxInt32 {
+		t.Errorf("Unexpected route %+v", rt)
+	}
+
+	columns := make(map[string]bool)
+	for _, w := range warnings {
+		columns[w.Column] = true
+	}
+	for _, c := range []string{"", "Flags", "Metric", "MTU"} {
+		if !columns[c] {
+			t.Errorf("Expected a warning for column %q, got %v", c, warnings)
+		}
+	}
+
+	warnings = nil
+	rt, _ = parseRouteRow(header, "eth0\t0000000A\t00000000\t1001\t0\t0\t0\t000000FF\t0\t0\t0", CollectWarnings(&warnings))
+	if len(warnings) != 1 || !errors.Is(warnings[0].Err, errUnknownFlags) || !flagContains(rt.Flags, "U") {
+		t.Errorf("Expected an unknown flag warning, got %v", warnings)
+	}
+}
@@ -0,0 +1,56 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"net"
+)
+
+// ExpVar is an expvar.Var exposing the routing table and default gateway.
+// The table is read from Source each time the variable is rendered, e.g. on a request to /debug/vars.
+type ExpVar struct {
+	Source RouteSource // Source of routes; ProcSource is used when nil.
+}
+
+// expVarJSON is the JSON document rendered by ExpVar.
+type expVarJSON struct {
+	Routes           []RoutingTable `json:"routes"`
+	DefaultGateway   string         `json:"default_gateway,omitempty"`
+	DefaultInterface string         `json:"default_interface,omitempty"`
+	Error            string         `json:"error,omitempty"`
+}
+
+// String renders the current routing state as JSON, as required by expvar.Var.
+// Read errors are reported in an "error" field rather than producing invalid JSON.
+func (v ExpVar) String() string {
+	src := v.Source
+	if src == nil {
+		src = ProcSource{}
+	}
+
+	var doc expVarJSON
+	routes, err := src.Routes(context.Background())
+	if err != nil {
+		doc.Error = err.Error()
+	} else {
+		doc.Routes = routes
+		if rt, ok := LookupRoute(routes, net.IPv4zero); ok {
+			doc.DefaultGateway = rt.Gateway
+			doc.DefaultInterface = rt.Interface
+		}
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		b, _ = json.Marshal(expVarJSON{Error: err.Error()})
+	}
+
+	return string(b)
+}
+
+// PublishExpvar publishes the routing state of src under name in the expvar registry.
+// Like expvar.Publish, it panics if name is already registered.
+func PublishExpvar(name string, src RouteSource) {
+	expvar.Publish(name, ExpVar{Source: src})
+}
@@ -0,0 +1,42 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// routeSourceFunc adapts a function to the RouteSource interface.
+type routeSourceFunc func(ctx context.Context) ([]RoutingTable, error)
+
+func (f routeSourceFunc) Routes(ctx context.Context) ([]RoutingTable, error) {
+	return f(ctx)
+}
+
+func TestExpVar(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("default via 192.168.1.1 dev eth0\n192.168.1.0/24 dev eth0\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	v := ExpVar{Source: routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		return routes, nil
+	})}
+
+	var doc expVarJSON
+	if err := json.Unmarshal([]byte(v.String()), &doc); err != nil {
+		t.Fatalf("ExpVar produced invalid JSON %s", err.Error())
+	}
+	if len(doc.Routes) != 2 || doc.DefaultGateway != "192.168.1.1" || doc.DefaultInterface != "eth0" {
+		t.Errorf("Unexpected expvar document %+v", doc)
+	}
+
+	v.Source = routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		return nil, errors.New("boom")
+	})
+	if err := json.Unmarshal([]byte(v.String()), &doc); err != nil || doc.Error != "boom" {
+		t.Errorf("Expected error document, got %s", v.String())
+	}
+}
@@ -0,0 +1,197 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"time"
+)
+
+// NextBestDefaultGW returns the IPv4 default route with the lowest metric whose gateway is not exclude, typically
+// the gateway in use, so callers can fail over to it. Multipath default routes are skipped, as are default routes
+// without a gateway and those discarding traffic. It returns ErrNoDefaultGateway if there is no other candidate.
+func NextBestDefaultGW(exclude net.IP) (RoutingTable, error) {
+	return NextBestDefaultGWContext(context.Background(), exclude)
+}
+
+// NextBestDefaultGWContext is like NextBestDefaultGW but returns early if ctx is done.
+func NextBestDefaultGWContext(ctx context.Context, exclude net.IP) (RoutingTable, error) {
+	return defaultManager.NextBestDefaultGW(ctx, exclude)
+}
+
+// NextBestDefaultGW returns the next candidate default route of the manager's routes, as NextBestDefaultGW does.
+func (m *Manager) NextBestDefaultGW(ctx context.Context, exclude net.IP) (RoutingTable, error) {
+	routes, err := m.Routes(ctx)
+	if err != nil {
+		return RoutingTable{}, err
+	}
+
+	rt, ok := bestDefaultGW(routes, exclude)
+	if !ok {
+		return RoutingTable{}, ErrNoDefaultGateway
+	}
+
+	return rt, nil
+}
+
+// bestDefaultGW returns the default route through a gateway other than exclude with the lowest metric, the first
+// in table order among equal metrics. A nil exclude returns the best default route overall.
+func bestDefaultGW(routes []RoutingTable, exclude net.IP) (RoutingTable, bool) {
+	var candidates []RoutingTable
+	for _, rt := range routes {
+		if _, _, ones, err := decodeDestination(rt); err != nil || ones != 0 || !isForwarding(rt.Type, rt.Flags) {
+			continue
+		}
+		gw := gatewayIP(net.ParseIP(rt.Gateway))
+		if gw == nil || len(rt.Nexthops) > 0 || gw.Equal(exclude) {
+			continue
+		}
+		candidates = append(candidates, rt)
+	}
+	if len(candidates) == 0 {
+		return RoutingTable{}, false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Metric < candidates[j].Metric })
+
+	return candidates[0], true
+}
+
+// FailoverMode selects how GatewayFailover moves traffic off a gateway that stopped answering.
+type FailoverMode int
+
+const (
+	// FailoverBumpMetric re-adds the failed default route with a metric above the next best one's, keeping it as
+	// a backup that takes over again if the new gateway fails in turn. It fails rather than replace another
+	// default route already holding that metric.
+	FailoverBumpMetric FailoverMode = iota
+	// FailoverReinstall replaces the failed default route with one through the next best gateway, with the failed
+	// route's metric, table and protocol, so software expecting that route finds it again.
+	FailoverReinstall
+)
+
+// Defaults of GatewayFailover.
+const (
+	defaultFailoverInterval = 10 * time.Second
+	defaultFailoverFailures = 3
+)
+
+// GatewayFailover probes the gateway of the best IPv4 default route with ICMP echo requests, as CheckGateway does,
+// and switches to the next best default route when it stops answering. It never fails back on its own: a
+// demoted gateway becomes active again only when the routes are changed or the new gateway fails too.
+// Changing routes needs CAP_NET_ADMIN.
+type GatewayFailover struct {
+	Manager    *Manager                        // Reads the routes; the default manager when nil.
+	Writer     RouteWriter                     // Applies the switch; the kernel's main table over netlink when nil.
+	Mode       FailoverMode                    // How to switch gateways.
+	Interval   time.Duration                   // Time between probes; 10 seconds when zero.
+	Failures   int                             // Consecutive probes without a reply before switching; 3 when zero.
+	OnFailover func(failed, next RoutingTable) // Called after a switch, with the failed and the newly active route; optional.
+
+	probe func(ctx context.Context, gw net.IP) (GatewayCheck, error) // Replaces the ICMP probe in tests.
+}
+
+// Run probes the active gateway every Interval until ctx is done, failing over after Failures consecutive probes
+// go unanswered. A probe that cannot be sent, such as without permission for ICMP sockets, and a failed switch end
+// Run with that error. Without a default route, or without another gateway to switch to, it keeps probing.
+func (f *GatewayFailover) Run(ctx context.Context) error {
+	m := f.Manager
+	if m == nil {
+		m = defaultManager
+	}
+	interval := f.Interval
+	if interval <= 0 {
+		interval = defaultFailoverInterval
+	}
+	failures := f.Failures
+	if failures <= 0 {
+		failures = defaultFailoverFailures
+	}
+	probe := f.probe
+	if probe == nil {
+		probe = func(ctx context.Context, gw net.IP) (GatewayCheck, error) {
+			return pingHost(ctx, gw, gatewayProbes, gatewayProbeTimeout, m.log())
+		}
+	}
+
+	var failed int
+	var lastGW net.IP
+	for {
+		routes, err := m.Routes(ctx)
+		if err != nil {
+			return err
+		}
+		active, ok := bestDefaultGW(routes, nil)
+		if ok {
+			gw := net.ParseIP(active.Gateway)
+			if !gw.Equal(lastGW) {
+				failed, lastGW = 0, gw
+			}
+			check, err := probe(ctx, gw)
+			if err != nil {
+				return err
+			}
+			if check.Reachable() {
+				failed = 0
+			} else {
+				failed++
+			}
+			if failed >= failures {
+				if err := f.failover(ctx, m, routes, active); err != nil {
+					return err
+				}
+				failed = 0
+			}
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// failover moves traffic from the failed active route to the next best default route, if there is one.
+func (f *GatewayFailover) failover(ctx context.Context, m *Manager, routes []RoutingTable, active RoutingTable) error {
+	next, ok := bestDefaultGW(routes, net.ParseIP(active.Gateway))
+	if !ok {
+		m.log().Warn("default gateway stopped answering and there is no other to fail over to", "gateway", active.Gateway)
+		return nil
+	}
+	w := f.Writer
+	if w == nil {
+		w = NetlinkSource{Table: TableMain}
+	}
+
+	switch f.Mode {
+	case FailoverBumpMetric:
+		if next.Metric == math.MaxUint32 {
+			return fmt.Errorf("failing over to %s: cannot demote %s below metric %d", next.Gateway, active.Gateway, next.Metric)
+		}
+		demoted := active
+		demoted.Metric = next.Metric + 1
+		if err := w.AddRoute(ctx, demoted); err != nil {
+			return fmt.Errorf("failing over to %s: %w", next.Gateway, err)
+		}
+		if err := w.DeleteRoute(ctx, active); err != nil {
+			return fmt.Errorf("failing over to %s: %w", next.Gateway, err)
+		}
+	case FailoverReinstall:
+		replacement := active
+		replacement.Gateway, replacement.Interface = next.Gateway, next.Interface
+		replacement.OnLink = next.OnLink
+		if err := w.ReplaceRoute(ctx, replacement); err != nil {
+			return fmt.Errorf("failing over to %s: %w", next.Gateway, err)
+		}
+		next = replacement
+	default:
+		return fmt.Errorf("unknown failover mode %d", f.Mode)
+	}
+	m.Invalidate()
+	m.log().Info("default gateway stopped answering, failed over", "from", active.Gateway, "to", next.Gateway)
+	if f.OnFailover != nil {
+		f.OnFailover(active, next)
+	}
+
+	return nil
+}
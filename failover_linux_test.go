@@ -0,0 +1,104 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runKernelFailover sets up default routes through 192.0.2.1 with metric 600 and 198.51.100.1 with metric 700, as
+// DHCP clients install them, and fails over from the first in mode. It returns the default routes left afterwards.
+func runKernelFailover(t *testing.T, mode FailoverMode) []RoutingTable {
+	t.Helper()
+	setLoopbackUp(t)
+
+	ip := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("ip", args...).CombinedOutput(); err != nil {
+			t.Skipf("Running ip %s failed: %s: %s", strings.Join(args, " "), err, out)
+		}
+	}
+	ip("link", "add", "veth0", "type", "veth", "peer", "name", "veth1")
+	ip("addr", "add", "192.0.2.2/24", "dev", "veth0")
+	ip("addr", "add", "198.51.100.2/24", "dev", "veth1")
+	ip("link", "set", "veth0", "up")
+	ip("link", "set", "veth1", "up")
+	ip("route", "add", "default", "via", "192.0.2.1", "dev", "veth0", "metric", "600")
+	ip("route", "add", "default", "via", "198.51.100.1", "dev", "veth1", "metric", "700")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	f := &GatewayFailover{
+		Manager:    NewManager(WithNetlink()),
+		Mode:       mode,
+		Interval:   time.Millisecond,
+		Failures:   1,
+		OnFailover: func(failed, next RoutingTable) { cancel() },
+		probe: func(ctx context.Context, gw net.IP) (GatewayCheck, error) {
+			return GatewayCheck{Gateway: gw, Sent: 1, Loss: 1}, nil
+		},
+	}
+	if err := f.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() = %v, want a failover", err)
+	}
+
+	routes, err := NetlinkSource{Table: TableMain}.Routes(context.Background())
+	if err != nil {
+		t.Fatalf("NetlinkSource failed %s", err.Error())
+	}
+	var defaults []RoutingTable
+	for _, rt := range routes {
+		if ones, err := rt.PrefixLen(); err == nil && ones == 0 {
+			defaults = append(defaults, rt)
+		}
+	}
+
+	return defaults
+}
+
+func TestGatewayFailoverKernel(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+
+	defaults := runKernelFailover(t, FailoverBumpMetric)
+	var got []string
+	for _, rt := range defaults {
+		got = append(got, rt.String())
+	}
+	want := []string{
+		"default via 198.51.100.1 dev veth1 metric 700",
+		"default via 192.0.2.1 dev veth0 metric 701",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected 192.0.2.1 to be demoted to metric 701, got:\n%s", strings.Join(got, "\n"))
+	}
+
+	rt, err := NextBestDefaultGW(nil)
+	if err != nil || rt.Gateway != "198.51.100.1" {
+		t.Errorf("Expected 198.51.100.1 to be the active gateway, got %s, %v", rt, err)
+	}
+}
+
+func TestGatewayFailoverKernelReinstall(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+
+	defaults := runKernelFailover(t, FailoverReinstall)
+	var got []string
+	for _, rt := range defaults {
+		got = append(got, rt.String())
+	}
+	want := []string{
+		"default via 198.51.100.1 dev veth1 metric 600",
+		"default via 198.51.100.1 dev veth1 metric 700",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected the route with metric 600 to be replaced, got:\n%s", strings.Join(got, "\n"))
+	}
+}
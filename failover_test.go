@@ -0,0 +1,104 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failoverRoutes has three default gateways, with metrics above 127 as DHCP clients install them, a default route
+// without a gateway and a discarding default.
+func failoverRoutes(t *testing.T) []RoutingTable {
+	return mustParseIPRoute(t, `default via 192.0.2.1 dev eth0 metric 300
+unreachable default metric 1
+default dev wg0 metric 5
+default via 198.51.100.1 dev wlan0 metric 700
+default via 203.0.113.1 dev wwan0 metric 600
+192.0.2.0/24 dev eth0`)
+}
+
+func TestNextBestDefaultGW(t *testing.T) {
+	m := NewManager(WithSource(&memTable{routes: failoverRoutes(t)}))
+
+	rt, err := m.NextBestDefaultGW(context.Background(), nil)
+	if err != nil || rt.Gateway != "192.0.2.1" {
+		t.Errorf("NextBestDefaultGW(nil) = %s, %v, want the route via 192.0.2.1", rt, err)
+	}
+	rt, err = m.NextBestDefaultGW(context.Background(), net.ParseIP("192.0.2.1"))
+	if err != nil || rt.Gateway != "203.0.113.1" {
+		t.Errorf("NextBestDefaultGW(192.0.2.1) = %s, %v, want the route with metric 600", rt, err)
+	}
+
+	single := NewManager(WithSource(&memTable{routes: failoverRoutes(t)[:1]}))
+	if _, err := single.NextBestDefaultGW(context.Background(), net.ParseIP("192.0.2.1")); !errors.Is(err, ErrNoDefaultGateway) {
+		t.Errorf("Expected ErrNoDefaultGateway without another gateway, got %v", err)
+	}
+}
+
+// runFailover runs a GatewayFailover over table whose probes only reach the gateways in up, until it fails over.
+func runFailover(t *testing.T, table *memTable, mode FailoverMode, up ...string) (failed, next RoutingTable) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var probes int
+	f := &GatewayFailover{
+		Manager:  NewManager(WithSource(table)),
+		Writer:   table,
+		Mode:     mode,
+		Interval: time.Millisecond,
+		Failures: 2,
+		OnFailover: func(f, n RoutingTable) {
+			failed, next = f, n
+			cancel()
+		},
+		probe: func(ctx context.Context, gw net.IP) (GatewayCheck, error) {
+			probes++
+			check := GatewayCheck{Gateway: gw, Sent: 1, Loss: 1}
+			for _, u := range up {
+				if gw.Equal(net.ParseIP(u)) {
+					check.Received, check.Loss = 1, 0
+				}
+			}
+			return check, nil
+		},
+	}
+	if err := f.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() = %v, want a failover", err)
+	}
+	if probes != 2 {
+		t.Errorf("Failed over after %d probes, want 2", probes)
+	}
+
+	return failed, next
+}
+
+func TestGatewayFailoverBumpMetric(t *testing.T) {
+	table := &memTable{routes: failoverRoutes(t)}
+	failed, next := runFailover(t, table, FailoverBumpMetric, "203.0.113.1")
+	if failed.Gateway != "192.0.2.1" || next.Gateway != "203.0.113.1" {
+		t.Errorf("Failed over from %s to %s, want from 192.0.2.1 to 203.0.113.1", failed, next)
+	}
+
+	want := []string{
+		"add default via 192.0.2.1 dev eth0 metric 601",
+		"delete default via 192.0.2.1 dev eth0 metric 300",
+	}
+	if strings.Join(table.ops, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected changes:\n%s", strings.Join(table.ops, "\n"))
+	}
+}
+
+func TestGatewayFailoverReinstall(t *testing.T) {
+	table := &memTable{routes: failoverRoutes(t)}
+	failed, next := runFailover(t, table, FailoverReinstall)
+	if failed.Gateway != "192.0.2.1" || next.String() != "default via 203.0.113.1 dev wwan0 metric 300" {
+		t.Errorf("Failed over from %s to %s, want to 203.0.113.1 with metric 300", failed, next)
+	}
+	if strings.Join(table.ops, "\n") != "replace default via 203.0.113.1 dev wwan0 metric 300" {
+		t.Errorf("Unexpected changes:\n%s", strings.Join(table.ops, "\n"))
+	}
+}
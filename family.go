@@ -0,0 +1,23 @@
+package routing
+
+// Family identifies the address family of a route, rule or address.
+type Family uint8
+
+// Address families. The values are independent of the platform's AF_* constants.
+const (
+	FamilyUnspec Family = 0
+	FamilyIPv4   Family = 4
+	FamilyIPv6   Family = 6
+)
+
+// String returns the family name used by iproute2, "inet" or "inet6".
+func (f Family) String() string {
+	switch f {
+	case FamilyIPv4:
+		return "inet"
+	case FamilyIPv6:
+		return "inet6"
+	}
+
+	return "unspec"
+}
@@ -0,0 +1,75 @@
+package routing
+
+import "net"
+
+// FilterRoutes returns the routes for which keep returns true, preserving their order.
+func FilterRoutes(routes []RoutingTable, keep func(RoutingTable) bool) []RoutingTable {
+	var out []RoutingTable
+	for _, rt := range routes {
+		if keep(rt) {
+			out = append(out, rt)
+		}
+	}
+
+	return out
+}
+
+// RoutesByInterface returns the routes using the network interface iface.
+func RoutesByInterface(routes []RoutingTable, iface string) []RoutingTable {
+	return FilterRoutes(routes, func(rt RoutingTable) bool {
+		return rt.Interface == iface
+	})
+}
+
+// RoutesWithFlag returns the routes carrying the flag with the given letter, e.g. "G".
+func RoutesWithFlag(routes []RoutingTable, letter string) []RoutingTable {
+	return FilterRoutes(routes, func(rt RoutingTable) bool {
+		return flagContains(rt.Flags, letter)
+	})
+}
+
+// RoutesMatching returns the routes whose destination network lies within prefix.
+// A route matches if its destination equals prefix or is a more specific subnet of it.
+func RoutesMatching(routes []RoutingTable, prefix net.IPNet) []RoutingTable {
+	prefixLen, _ := prefix.Mask.Size()
+
+	return FilterRoutes(routes, func(rt RoutingTable) bool {
+		dst, _, ones, err := decodeDestination(rt)
+		if err != nil {
+			return false
+		}
+		return ones >= prefixLen && prefix.Contains(dst)
+	})
+}
+
+// ExcludeCloned returns the routes that are not kernel-generated clones, keeping those a configuration
+// management tool would manage; see RoutingTable.Cloned.
+func ExcludeCloned(routes []RoutingTable) []RoutingTable {
+	return FilterRoutes(routes, func(rt RoutingTable) bool { return !rt.Cloned() })
+}
+
+// RoutesOnDownLinks returns the routes whose interface is in links but not operational, i.e. administratively
+// down or without carrier, so they cannot carry traffic. links is typically the result of Links.
+func RoutesOnDownLinks(routes []RoutingTable, links []Link) []RoutingTable {
+	down := downLinks(links)
+	return FilterRoutes(routes, func(rt RoutingTable) bool { return down[rt.Interface] })
+}
+
+// ExcludeDownLinks returns the routes RoutesOnDownLinks does not, keeping routes without an interface and
+// routes through interfaces missing from links.
+func ExcludeDownLinks(routes []RoutingTable, links []Link) []RoutingTable {
+	down := downLinks(links)
+	return FilterRoutes(routes, func(rt RoutingTable) bool { return !down[rt.Interface] })
+}
+
+// downLinks returns the names of the links that are not up with carrier.
+func downLinks(links []Link) map[string]bool {
+	down := make(map[string]bool)
+	for _, l := range links {
+		if !l.Up || !l.Carrier {
+			down[l.Name] = true
+		}
+	}
+
+	return down
+}
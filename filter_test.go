@@ -0,0 +1,123 @@
+package routing
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFilterHelpers(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader(`default via 192.168.1.1 dev eth0
+10.8.0.0/24 dev tun0 scope link
+10.0.0.0/8 via 10.8.0.1 dev tun0
+192.168.1.0/24 dev eth0 scope link
+`))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	if got := RoutesByInterface(routes, "tun0"); len(got) != 2 {
+		t.Errorf("Expected 2 tun0 routes, got %v", got)
+	}
+
+	if got := RoutesWithFlag(routes, "G"); len(got) != 2 || got[0].Gateway != "192.168.1.1" || got[1].Gateway != "10.8.0.1" {
+		t.Errorf("Unexpected gateway routes %v", got)
+	}
+
+	_, prefix, _ := net.ParseCIDR("10.0.0.0/8")
+	got := RoutesMatching(routes, *prefix)
+	if len(got) != 2 || got[0].String() != "10.8.0.0/24 dev tun0 scope link" || got[1].String() != "10.0.0.0/8 via 10.8.0.1 dev tun0" {
+		t.Errorf("Unexpected matching routes %v", got)
+	}
+}
+
+func TestExcludeCloned(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("default via 192.168.1.1 dev eth0\n198.51.100.7 via 192.168.1.1 dev eth0\n203.0.113.9 via 192.168.1.254 dev eth0\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	routes[1].Flags = computeRouteFlag(FlagUp | FlagGateway | FlagHost | FlagCache)
+	routes[2].Flags = computeRouteFlag(FlagUp | FlagGateway | FlagHost | FlagDynamic)
+
+	if got := ExcludeCloned(routes); len(got) != 1 || got[0].Gateway != "192.168.1.1" || got[0].Cloned() {
+		t.Errorf("Expected only the default route, got %v", got)
+	}
+
+	m := NewManager(WithSource(routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) { return routes, nil })), WithoutCloned(),
+		WithFS(fstest.MapFS{"proc/net/ipv6_route": {Data: []byte(ipv6RouteFixture +
+			"20010db8000000030000000000000001 80 00000000000000000000000000000000 00 fe800000000000000000000000000001 00000000 00000001 00000000 01000003     eth0\n")}}))
+	if got, err := m.Routes(context.Background()); err != nil || len(got) != 1 {
+		t.Errorf("Expected WithoutCloned to drop the cloned IPv4 routes, got %v, %v", got, err)
+	}
+	v6, err := m.IPv6Routes(context.Background())
+	if err != nil {
+		t.Fatalf("IPv6Routes failed %s", err.Error())
+	}
+	if len(v6) != 3 {
+		t.Errorf("Expected WithoutCloned to drop the cached IPv6 route, got %v", v6)
+	}
+	if all, _ := NewManager(WithFS(m.fsys)).IPv6Routes(context.Background()); len(all) != 4 || !all[3].Cloned() {
+		t.Errorf("Expected the cached IPv6 route without WithoutCloned, got %v", all)
+	}
+	// Routes read over netlink, where /proc is unusable, are filtered the same way.
+	all, _ := NewManager(WithFS(m.fsys)).IPv6Routes(context.Background())
+	if got, err := m.filterIPv6(all); err != nil || len(got) != 3 {
+		t.Errorf("Expected filterIPv6 to drop the cached IPv6 route, got %v, %v", got, err)
+	}
+
+	if got, _ := Query().From(routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) { return routes, nil })).ExcludeCloned().All(); len(got) != 1 {
+		t.Errorf("Expected the query to drop cloned routes, got %v", got)
+	}
+}
+
+func TestDownLinks(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev eth0\n" +
+		"default via 198.51.100.1 dev wlan0 metric 20\n" +
+		"10.8.0.0/24 dev tun0 scope link\n" +
+		"10.9.0.0/24 dev wg0 scope link\n" +
+		"blackhole 203.0.113.0/24\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	links := []Link{
+		{Index: 2, Name: "eth0", Up: true, Carrier: false}, // Cable unplugged.
+		{Index: 3, Name: "wlan0", Up: true, Carrier: true},
+		{Index: 4, Name: "tun0", Up: false},
+	}
+
+	down := RoutesOnDownLinks(routes, links)
+	if len(down) != 2 || down[0].Interface != "eth0" || down[1].Interface != "tun0" {
+		t.Errorf("Expected the eth0 and tun0 routes, got %v", down)
+	}
+
+	src := upLinkSource{
+		source: routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) { return routes, nil }),
+		links:  func() ([]Link, error) { return links, nil },
+	}
+	up, err := src.Routes(context.Background())
+	if err != nil {
+		t.Fatalf("Routes failed %s", err.Error())
+	}
+	expected := []string{"default via 198.51.100.1 dev wlan0 metric 20", "10.9.0.0/24 dev wg0 scope link", "blackhole 203.0.113.0/24"}
+	if len(up) != len(expected) {
+		t.Fatalf("Expected %d routes, got %v", len(expected), up)
+	}
+	for i, rt := range up {
+		if rt.String() != expected[i] {
+			t.Errorf("Route %d = %q, want %q", i, rt.String(), expected[i])
+		}
+	}
+
+	m := NewManager(WithoutDownInterfaces(), WithFS(fstest.MapFS{"proc/net/ipv6_route": {Data: []byte(ipv6RouteFixture +
+		"20010db8000000030000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     wlan0\n")}}))
+	m.links = func() ([]Link, error) { return links, nil }
+	v6, err := m.IPv6Routes(context.Background())
+	if err != nil {
+		t.Fatalf("IPv6Routes failed %s", err.Error())
+	}
+	if len(v6) != 1 || v6[0].Interface != "wlan0" {
+		t.Errorf("Expected only the IPv6 route through wlan0, got %v", v6)
+	}
+}
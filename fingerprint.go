@@ -0,0 +1,31 @@
+package routing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+)
+
+// Fingerprint returns a stable hash of the routing table as a hex string, so agents can tell whether anything
+// changed since the last poll without keeping or shipping the whole table. Routes are normalized to their String
+// form and sorted, so the order they were read in and counters such as RefCnt and Use do not change the result.
+func Fingerprint(routes []RoutingTable) string {
+	keys := make([]string, len(routes))
+	for i, rt := range routes {
+		keys[i] = rt.String()
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Fingerprint returns the Fingerprint of the captured routes.
+func (s Snapshot) Fingerprint() string {
+	return Fingerprint(s.Table)
+}
@@ -0,0 +1,38 @@
+package routing
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("default via 192.168.1.1 dev eth0 metric 100\n" +
+		"192.168.1.0/24 dev eth0 proto kernel scope link src 192.168.1.20\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	fp := Fingerprint(routes)
+	if len(fp) != 64 {
+		t.Errorf("Expected a hex SHA-256, got %q", fp)
+	}
+
+	reordered := slices.Clone(routes)
+	slices.Reverse(reordered)
+	reordered[0].Use = 42
+	if got := Fingerprint(reordered); got != fp {
+		t.Errorf("Expected order and counters not to change the fingerprint, got %s, want %s", got, fp)
+	}
+	if got := (Snapshot{Table: routes}).Fingerprint(); got != fp {
+		t.Errorf("Snapshot.Fingerprint = %s, want %s", got, fp)
+	}
+
+	changed := slices.Clone(routes)
+	changed[0].Metric = 50
+	if Fingerprint(changed) == fp {
+		t.Error("Expected a changed metric to change the fingerprint")
+	}
+	if Fingerprint(routes[:1]) == fp || Fingerprint(nil) == fp {
+		t.Error("Expected a removed route to change the fingerprint")
+	}
+}
@@ -0,0 +1,64 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	errFlagNoLetter    = errors.New("route flag needs a letter")
+	errFlagNotOneBit   = errors.New("route flag bit must have exactly one bit set")
+	errFlagLetterTaken = errors.New("route flag letter already registered")
+	errFlagBitTaken    = errors.New("route flag bit already registered")
+)
+
+var (
+	registerMu      sync.Mutex                  // Serializes RegisterRouteFlag.
+	registeredFlags atomic.Pointer[[]RouteFlag] // The IPv4 flags including registered ones; nil until a flag is registered.
+)
+
+// ipv4Flags returns the flags of IPv4 routes, the built-in ones and those added with RegisterRouteFlag, ordered by bit.
+// The returned slice must not be modified.
+func ipv4Flags() []RouteFlag {
+	if flags := registeredFlags.Load(); flags != nil {
+		return *flags
+	}
+
+	return routeFlags
+}
+
+// RegisterRouteFlag adds a flag to those decoded from the Flags column of /proc/net/route and other IPv4 route
+// sources, for patched or vendor kernels setting bits the package does not know. Without it such bits are
+// dropped with a warning. The letter and the bit must not be in use by another IPv4 flag.
+// IPv6 flags cannot be registered, as the IPv6 flag bits are all taken.
+// It is safe for concurrent use, but routes decoded before the call do not carry the new flag.
+func RegisterRouteFlag(flag RouteFlag) error {
+	if flag.Letter == "" {
+		return errFlagNoLetter
+	}
+	if bit := uint16(flag.Bit); bit == 0 || bit&(bit-1) != 0 {
+		return fmt.Errorf("%w: %#x", errFlagNotOneBit, bit)
+	}
+
+	registerMu.Lock()
+	defer registerMu.Unlock()
+
+	current := ipv4Flags()
+	for _, f := range current {
+		if f.Letter == flag.Letter {
+			return fmt.Errorf("%w: %q is %s", errFlagLetterTaken, flag.Letter, f.Name)
+		}
+		if f.Bit == flag.Bit {
+			return fmt.Errorf("%w: %#x is %s", errFlagBitTaken, uint16(flag.Bit), f.Name)
+		}
+	}
+
+	flags := append(slices.Clone(current), flag)
+	slices.SortFunc(flags, func(a, b RouteFlag) int { return int(uint16(a.Bit)) - int(uint16(b.Bit)) })
+	registeredFlags.Store(&flags)
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package routing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegisterRouteFlag(t *testing.T) {
+	t.Cleanup(func() { registeredFlags.Store(nil) })
+
+	lines := strings.Split(strings.TrimSpace(procRouteFixture), "\n")
+	row := strings.Replace(lines[1], "\t0003\t", "\t4003\t", 1)
+	header := newProcHeader(splitHeader(lines[0]))
+	var warnings []ParseWarning
+	rt, err := parseRouteRow(header, row, CollectWarnings(&warnings))
+	if err != nil {
+		t.Fatalf("parseRouteRow failed %s", err.Error())
+	}
+	if len(warnings) != 1 || !errors.Is(warnings[0].Err, errUnknownFlags) || flagLetters(rt.Flags) != "UG" {
+		t.Errorf("Expected the unknown bit to be dropped with a warning, got %q and %v", flagLetters(rt.Flags), warnings)
+	}
+
+	vendor := RouteFlag{Letter: "V", Bit: 0x4000, Name: "Vendor", Desc: "Route installed by the vendor's offload engine"}
+	if err := RegisterRouteFlag(vendor); err != nil {
+		t.Fatalf("RegisterRouteFlag failed %s", err.Error())
+	}
+	warnings = nil
+	rt, err = parseRouteRow(header, row, CollectWarnings(&warnings))
+	if err != nil {
+		t.Fatalf("parseRouteRow failed %s", err.Error())
+	}
+	if len(warnings) != 0 || flagLetters(rt.Flags) != "UGV" || rt.Flags["V"] != vendor {
+		t.Errorf("Expected the registered flag to be decoded, got %q and %v", flagLetters(rt.Flags), warnings)
+	}
+	if flags := RouteFlags(FamilyIPv4); flags[len(flags)-1] != vendor {
+		t.Errorf("Expected RouteFlags to list the registered flag last, got %v", flags)
+	}
+	var f RouteFlag
+	if err := f.UnmarshalJSON([]byte(`"V"`)); err != nil || f != vendor {
+		t.Errorf("Expected the registered letter to decode from JSON, got %v, %v", f, err)
+	}
+
+	tests := []struct {
+		flag RouteFlag
+		err  error
+	}{
+		{RouteFlag{Bit: 0x2000}, errFlagNoLetter},
+		{RouteFlag{Letter: "W", Bit: 0x3000}, errFlagNotOneBit},
+		{RouteFlag{Letter: "W"}, errFlagNotOneBit},
+		{RouteFlag{Letter: "G", Bit: 0x2000}, errFlagLetterTaken},
+		{RouteFlag{Letter: "W", Bit: 0x4000}, errFlagBitTaken},
+		{RouteFlag{Letter: "W", Bit: FlagReject}, errFlagBitTaken},
+	}
+	for _, test := range tests {
+		if err := RegisterRouteFlag(test.flag); !errors.Is(err, test.err) {
+			t.Errorf("RegisterRouteFlag(%+v) = %v, want %v", test.flag, err, test.err)
+		}
+	}
+}
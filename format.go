@@ -0,0 +1,124 @@
+package routing
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// WriteTable writes the routes to w in the aligned layout used by `route -n`.
+// Addresses are printed numerically and flags as their letters ordered by bit.
+func WriteTable(w io.Writer, routes []RoutingTable) error {
+	if _, err := io.WriteString(w, "Kernel IP routing table\n"); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "Destination     Gateway         Genmask         Flags Metric Ref    Use Iface\n"); err != nil {
+		return err
+	}
+
+	for _, rt := range routes {
+		dst, mask, _, err := decodeDestination(rt)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(w, "%-15s %-15s %-15s %-5s %-6d %-2d %7d %s\n",
+			dst, rt.Gateway, mask, flagLetters(rt.Flags), rt.Metric, rt.RefCnt, rt.Use, rt.Interface)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// String renders the route the way `ip route` prints it, e.g. "default via 192.168.1.1 dev eth0 metric 100".
+// When the scope is unknown, gateway-less routes are printed with "scope link", the scope the kernel assigns to them.
+// Multipath routes span several lines, one per nexthop, and special route types such as blackhole prefix the line.
+func (rt RoutingTable) String() string {
+	var b strings.Builder
+
+	if !rt.Type.isUnicast() {
+		fmt.Fprintf(&b, "%s ", rt.Type)
+	}
+	dst, _, ones, err := decodeDestination(rt)
+	switch {
+	case err != nil:
+		b.WriteString(rt.Destination) // Fall back to the raw column if it cannot be decoded.
+	case ones == 0 && dst.IsUnspecified():
+		b.WriteString("default")
+	case ones == 32:
+		b.WriteString(dst.String())
+	default:
+		fmt.Fprintf(&b, "%s/%d", dst, ones)
+	}
+	if rt.TOS != 0 {
+		fmt.Fprintf(&b, " tos %#x", rt.TOS)
+	}
+	if rt.NexthopID != 0 {
+		fmt.Fprintf(&b, " nhid %d", rt.NexthopID)
+	}
+	if rt.SRv6 != nil {
+		fmt.Fprintf(&b, " %s", rt.SRv6)
+	}
+
+	gw := net.ParseIP(rt.Gateway)
+	hasGateway := gw != nil && !gw.IsUnspecified()
+	multipath := len(rt.Nexthops) > 0 // Multipath routes print their gateways on nexthop lines instead.
+	if hasGateway && !multipath {
+		fmt.Fprintf(&b, " via %s", gw)
+	}
+	if rt.Interface != "" && !multipath {
+		fmt.Fprintf(&b, " dev %s", rt.Interface)
+	}
+	if rt.Table != TableUnspec && rt.Table != TableMain {
+		fmt.Fprintf(&b, " table %s", TableName(rt.Table))
+	}
+	if rt.Proto != "" && rt.Proto != "boot" { // ip hides the default protocol of routes added by hand.
+		fmt.Fprintf(&b, " proto %s", rt.Proto)
+	}
+	switch {
+	case rt.Scope != "" && rt.Scope != "global":
+		fmt.Fprintf(&b, " scope %s", rt.Scope)
+	case rt.Scope == "" && !hasGateway && rt.NexthopID == 0 && rt.Type.isUnicast():
+		b.WriteString(" scope link")
+	}
+	if rt.PrefSrc != "" {
+		fmt.Fprintf(&b, " src %s", rt.PrefSrc)
+	}
+	if rt.Metric != 0 {
+		fmt.Fprintf(&b, " metric %d", rt.Metric)
+	}
+	switch {
+	case rt.FromRealm != "":
+		to := rt.Realm
+		if to == "" {
+			to = RealmName(0)
+		}
+		fmt.Fprintf(&b, " realms %s/%s", rt.FromRealm, to)
+	case rt.Realm != "":
+		fmt.Fprintf(&b, " realm %s", rt.Realm)
+	}
+	if rt.OnLink {
+		b.WriteString(" onlink")
+	}
+	if !rt.Metrics.IsZero() {
+		fmt.Fprintf(&b, " %s", rt.Metrics)
+	}
+	for _, nh := range rt.Nexthops {
+		b.WriteString("\n\tnexthop")
+		if gw := net.ParseIP(nh.Gateway); gw != nil && !gw.IsUnspecified() {
+			fmt.Fprintf(&b, " via %s", gw)
+		}
+		if nh.Interface != "" {
+			fmt.Fprintf(&b, " dev %s", nh.Interface)
+		}
+		fmt.Fprintf(&b, " weight %d", nh.Weight)
+		if nh.OnLink {
+			b.WriteString(" onlink")
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,43 @@
+package routing
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteTable(t *testing.T) {
+	routes := []RoutingTable{
+		{Interface: "eth0", Destination: "00000000", Gateway: "192.168.2.1", Flags: computeRouteFlag(0x3), Metric: 100, Mask: "00000000"},
+		{Interface: "eth0", Destination: "0002A8C0", Gateway: "0.0.0.0", Flags: computeRouteFlag(0x1), Metric: 100, Mask: "00FFFFFF"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, routes); err != nil {
+		t.Fatalf("WriteTable failed %s", err.Error())
+	}
+
+	expected := "Kernel IP routing table\n" +
+		"Destination     Gateway         Genmask         Flags Metric Ref    Use Iface\n" +
+		"0.0.0.0         192.168.2.1     0.0.0.0         UG    100    0        0 eth0\n" +
+		"192.168.2.0     0.0.0.0         255.255.255.0   U     100    0        0 eth0\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected table output\n%s\nwant\n%s", buf.String(), expected)
+	}
+}
+
+func TestRoutingTableString(t *testing.T) {
+	cases := []struct {
+		route    RoutingTable
+		expected string
+	}{
+		{RoutingTable{Interface: "eth0", Destination: "00000000", Gateway: "192.168.1.1", Flags: computeRouteFlag(0x3), Metric: 100, Mask: "00000000"}, "default via 192.168.1.1 dev eth0 metric 100"},
+		{RoutingTable{Interface: "eth0", Destination: "0001A8C0", Gateway: "0.0.0.0", Flags: computeRouteFlag(0x1), Mask: "00FFFFFF"}, "192.168.1.0/24 dev eth0 scope link"},
+		{RoutingTable{Interface: "tun0", Destination: "0100000A", Gateway: "10.8.0.1", Flags: computeRouteFlag(0x7), Mask: "FFFFFFFF"}, "10.0.0.1 via 10.8.0.1 dev tun0"},
+	}
+
+	for _, c := range cases {
+		if got := c.route.String(); got != c.expected {
+			t.Errorf("String() = %q, want %q", got, c.expected)
+		}
+	}
+}
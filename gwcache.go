@@ -0,0 +1,94 @@
+package routing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultGatewayCache memoizes the default route for hot paths, such as proxies consulting the default gateway
+// for every connection, which would otherwise read the routing table each time. The cached route is dropped
+// after a TTL, by Invalidate, or as the kernel reports route changes while InvalidateOnChange runs.
+// It is safe for concurrent use; errors, including ErrNoDefaultGateway, are never cached.
+type DefaultGatewayCache struct {
+	manager *Manager
+	ttl     time.Duration
+	now     func() time.Time // Clock used for expiry, replaceable in tests.
+
+	mu         sync.RWMutex
+	route      RoutingTable
+	valid      bool
+	expires    time.Time
+	generation uint64 // Incremented by Invalidate, so reads that raced with it are not stored.
+}
+
+// NewDefaultGatewayCache returns a cache of the default route of m, or of the package-level functions if m is nil.
+// The route is reused for up to ttl after each read; a non-positive ttl keeps it until it is invalidated,
+// which is only safe while InvalidateOnChange runs.
+func NewDefaultGatewayCache(m *Manager, ttl time.Duration) *DefaultGatewayCache {
+	if m == nil {
+		m = defaultManager
+	}
+
+	return &DefaultGatewayCache{manager: m, ttl: ttl, now: time.Now}
+}
+
+// DefaultRoute returns the cached default route, reading it through the manager once it has expired or was invalidated.
+func (c *DefaultGatewayCache) DefaultRoute(ctx context.Context) (RoutingTable, error) {
+	c.mu.RLock()
+	if c.valid && (c.ttl <= 0 || c.now().Before(c.expires)) {
+		rt := c.route
+		c.mu.RUnlock()
+		return rt, nil
+	}
+	generation := c.generation
+	c.mu.RUnlock()
+
+	rt, err := c.manager.DefaultRoute(ctx)
+	if err != nil {
+		return RoutingTable{}, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.route, c.valid, c.expires = rt, true, c.now().Add(c.ttl)
+	}
+	c.mu.Unlock()
+
+	return rt, nil
+}
+
+// DefaultGateway returns the gateway of the cached default route in dotted notation.
+func (c *DefaultGatewayCache) DefaultGateway(ctx context.Context) (string, error) {
+	rt, err := c.DefaultRoute(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return rt.Gateway, nil
+}
+
+// Invalidate drops the cached route so the next call reads the routing table again.
+func (c *DefaultGatewayCache) Invalidate() {
+	c.mu.Lock()
+	c.valid = false
+	c.generation++
+	c.mu.Unlock()
+}
+
+// InvalidateOnChange invalidates the cache whenever the kernel reports an IPv4 route being added, changed or removed,
+// and also drops the manager's cached routing table if it has one. Once subscribed to the notifications it reads the
+// default route again, as a route cached before then may predate changes it did not see. It blocks until ctx is done
+// and returns ctx.Err(), or an error if the notifications cannot be received. It is only available on Linux and
+// returns ErrNotSupported elsewhere.
+func (c *DefaultGatewayCache) InvalidateOnChange(ctx context.Context) error {
+	invalidate := func() {
+		c.manager.Invalidate()
+		c.Invalidate()
+	}
+
+	return watchRouteNotifications(ctx, func() {
+		invalidate()
+		c.DefaultRoute(ctx) // A failed read is not cached; the next caller reads again.
+	}, invalidate)
+}
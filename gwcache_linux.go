@@ -0,0 +1,25 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"syscall"
+)
+
+// watchRouteNotifications calls subscribed once it receives IPv4 route notifications, then fn for every batch of
+// them, and when notifications were lost, until ctx is done.
+func watchRouteNotifications(ctx context.Context, subscribed, fn func()) error {
+	sub, err := netlinkSubscribe(syscall.RTNLGRP_IPV4_ROUTE)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+	subscribed()
+
+	for {
+		if _, err := sub.Receive(ctx); err != nil && !errors.Is(err, errNotificationsLost) {
+			return err
+		}
+		fn()
+	}
+}
@@ -0,0 +1,67 @@
+package routing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultGatewayCacheInvalidateOnChange(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+	setLoopbackUp(t)
+
+	route := func(gw string) RoutingTable {
+		routes, err := ParseIPRoute(strings.NewReader("default via " + gw + " dev lo onlink\n"))
+		if err != nil {
+			t.Fatalf("ParseIPRoute failed %s", err.Error())
+		}
+		return routes[0]
+	}
+	if err := AddRoute(route("192.0.2.1")); err != nil {
+		t.Fatalf("AddRoute failed %s", err.Error())
+	}
+
+	c := NewDefaultGatewayCache(NewManager(WithNetlink()), 0)
+	if gw, err := c.DefaultGateway(context.Background()); err != nil || gw != "192.0.2.1" {
+		t.Fatalf("DefaultGateway = %s, %v, want 192.0.2.1", gw, err)
+	}
+
+	// This change is made before InvalidateOnChange subscribes, so only its read after subscribing can see it.
+	if err := ReplaceRoute(route("192.0.2.2")); err != nil {
+		t.Fatalf("ReplaceRoute failed %s", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- c.InvalidateOnChange(ctx) }()
+
+	waitGateway := func(want string) {
+		t.Helper()
+		for {
+			if got, _ := c.DefaultGateway(ctx); got == want {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				t.Fatalf("Expected the cache to be invalidated and report %s", want)
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}
+	waitGateway("192.0.2.2")
+
+	// The cache was invalidated after subscribing, so later changes are seen too.
+	if err := ReplaceRoute(route("192.0.2.3")); err != nil {
+		t.Fatalf("ReplaceRoute failed %s", err.Error())
+	}
+	waitGateway("192.0.2.3")
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected InvalidateOnChange to return context.Canceled, got %v", err)
+	}
+}
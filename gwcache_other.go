@@ -0,0 +1,10 @@
+//go:build !linux
+
+package routing
+
+import "context"
+
+// watchRouteNotifications always returns ErrNotSupported, as netlink is only available on Linux.
+func watchRouteNotifications(ctx context.Context, subscribed, fn func()) error {
+	return ErrNotSupported
+}
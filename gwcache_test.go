@@ -0,0 +1,67 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultGatewayCache(t *testing.T) {
+	tables := []string{
+		"192.0.2.0/24 dev eth0\n",
+		"default via 192.0.2.1 dev eth0\n",
+		"default via 198.51.100.1 dev wlan0\n",
+	}
+	reads := 0
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		text := tables[min(reads, len(tables)-1)]
+		reads++
+		return ParseIPRoute(strings.NewReader(text))
+	})
+	c := NewDefaultGatewayCache(NewManager(WithSource(src)), time.Minute)
+	now := time.Unix(1700000000, 0)
+	c.now = func() time.Time { return now }
+
+	if _, err := c.DefaultGateway(context.Background()); !errors.Is(err, ErrNoDefaultGateway) {
+		t.Errorf("Expected ErrNoDefaultGateway, got %v", err)
+	}
+	for range 3 {
+		if gw, err := c.DefaultGateway(context.Background()); err != nil || gw != "192.0.2.1" {
+			t.Errorf("DefaultGateway = %s, %v, want 192.0.2.1", gw, err)
+		}
+	}
+	if reads != 2 {
+		t.Errorf("Expected the error not to be cached and the route to be, got %d reads", reads)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if gw, _ := c.DefaultGateway(context.Background()); gw != "198.51.100.1" || reads != 3 {
+		t.Errorf("Expected the route to be read again after the TTL, got %s after %d reads", gw, reads)
+	}
+
+	c.Invalidate()
+	c.DefaultGateway(context.Background())
+	if reads != 4 {
+		t.Errorf("Expected Invalidate to force a read, got %d reads", reads)
+	}
+}
+
+func TestDefaultGatewayCacheWithoutTTL(t *testing.T) {
+	reads := 0
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		reads++
+		return ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev eth0\n"))
+	})
+	c := NewDefaultGatewayCache(NewManager(WithSource(src)), 0)
+	now := time.Unix(1700000000, 0)
+	c.now = func() time.Time { return now }
+
+	c.DefaultRoute(context.Background())
+	now = now.Add(24 * time.Hour)
+	c.DefaultRoute(context.Background())
+	if reads != 1 {
+		t.Errorf("Expected the route to be kept until invalidated, got %d reads", reads)
+	}
+}
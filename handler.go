@@ -0,0 +1,157 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxDebugEvents is the number of recent route changes a DebugHandler keeps.
+const maxDebugEvents = 100
+
+// DebugHandler is an http.Handler serving the routing table, the default route and recent changes to them.
+// It answers with JSON, or with an HTML page when the client accepts text/html or the query has format=html.
+// The first request starts watching the routing table in the background; Close stops it.
+type DebugHandler struct {
+	manager  *Manager
+	interval time.Duration // How often the background watcher polls.
+
+	start  sync.Once
+	cancel context.CancelFunc // Stops the background watcher; nil until it is started.
+
+	mu     sync.Mutex
+	events []debugEvent // Recorded changes, oldest first.
+}
+
+// debugEvent is a change observed by a DebugHandler.
+type debugEvent struct {
+	Time    time.Time      `json:"time"`
+	Added   []RoutingTable `json:"added,omitempty"`
+	Removed []RoutingTable `json:"removed,omitempty"`
+}
+
+// debugJSON is the document served by a DebugHandler.
+type debugJSON struct {
+	Routes       []RoutingTable `json:"routes"`
+	DefaultRoute *RoutingTable  `json:"default_route"`
+	Events       []debugEvent   `json:"events"` // Most recent first.
+	Error        string         `json:"error,omitempty"`
+}
+
+// Handler returns a DebugHandler for the routing table in /proc/net/route, e.g. to mount under /debug/routes.
+func Handler() *DebugHandler {
+	return defaultManager.Handler()
+}
+
+// Handler returns a DebugHandler for the manager's routes.
+func (m *Manager) Handler() *DebugHandler {
+	return &DebugHandler{manager: m, interval: time.Second}
+}
+
+// ServeHTTP serves the current routing state. Read errors are reported with status 500 and an "error" field.
+func (h *DebugHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.start.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		h.cancel = cancel
+		go h.record(ctx)
+	})
+
+	doc := debugJSON{Routes: []RoutingTable{}, Events: h.recentEvents()}
+	status := http.StatusOK
+	routes, err := h.manager.Routes(r.Context())
+	if err != nil {
+		doc.Error, status = err.Error(), http.StatusInternalServerError
+	} else {
+		doc.Routes = routes
+		if rt, ok := defaultRoute(routes); ok {
+			doc.DefaultRoute = &rt
+		}
+	}
+
+	if r.URL.Query().Get("format") == "html" || (r.URL.Query().Get("format") == "" && strings.Contains(r.Header.Get("Accept"), "text/html")) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		debugTemplate.Execute(w, doc)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(doc)
+}
+
+// Close stops the background watcher. Later requests still serve the routing table but record no more changes.
+func (h *DebugHandler) Close() {
+	h.start.Do(func() {})
+	if h.cancel != nil {
+		h.cancel()
+	}
+}
+
+// record watches the routing table until ctx is done, keeping the most recent changes.
+// The initial table is not a change. Read errors are logged by the watcher and retried after the interval.
+func (h *DebugHandler) record(ctx context.Context) {
+	w := h.manager.Watch(h.interval)
+	first := true
+	for {
+		change, err := w.Next(ctx)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(h.interval):
+			}
+			continue
+		}
+		if first {
+			first = false
+			continue
+		}
+
+		h.mu.Lock()
+		h.events = append(h.events, debugEvent{Time: time.Now(), Added: change.Added, Removed: change.Removed})
+		if len(h.events) > maxDebugEvents {
+			h.events = h.events[len(h.events)-maxDebugEvents:]
+		}
+		h.mu.Unlock()
+	}
+}
+
+// recentEvents returns the recorded changes, most recent first.
+func (h *DebugHandler) recentEvents() []debugEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	events := make([]debugEvent, len(h.events))
+	for i, e := range h.events {
+		events[len(events)-1-i] = e
+	}
+
+	return events
+}
+
+// debugTemplate renders a debugJSON document as an HTML page.
+var debugTemplate = template.Must(template.New("routes").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Routing table</title></head>
+<body>
+{{if .Error}}<p>Error: {{.Error}}</p>{{end}}
+<h1>Default route</h1>
+<pre>{{with .DefaultRoute}}{{.}}{{else}}none{{end}}</pre>
+<h1>Routes</h1>
+<pre>{{range .Routes}}{{.}}
+{{end}}</pre>
+<h1>Recent changes</h1>
+<pre>{{range .Events}}{{.Time.Format "2006-01-02 15:04:05"}}
+{{range .Added}}+ {{.}}
+{{end}}{{range .Removed}}- {{.}}
+{{end}}{{else}}none{{end}}</pre>
+</body>
+</html>
+`))
@@ -0,0 +1,54 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDebugHandler(t *testing.T) {
+	var calls atomic.Int32
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		text := "default via 192.168.1.1 dev eth0\n192.168.1.0/24 dev eth0\n"
+		if calls.Add(1) > 2 {
+			text = "default via 10.8.0.1 dev tun0\n192.168.1.0/24 dev eth0\n"
+		}
+		return ParseIPRoute(strings.NewReader(text))
+	})
+	h := NewManager(WithSource(src)).Handler()
+	h.interval = time.Millisecond
+	defer h.Close()
+
+	var doc debugJSON
+	for deadline := time.Now().Add(5 * time.Second); len(doc.Events) == 0 && time.Now().Before(deadline); {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/routes", nil))
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+			t.Fatalf("Unexpected response %d %s", rec.Code, rec.Header().Get("Content-Type"))
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("Invalid JSON: %s", err.Error())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if len(doc.Routes) != 2 || doc.DefaultRoute == nil {
+		t.Errorf("Expected two routes and a default route, got %+v", doc)
+	}
+	if len(doc.Events) != 1 || len(doc.Events[0].Added) != 1 || doc.Events[0].Added[0].Gateway != "10.8.0.1" {
+		t.Fatalf("Expected the gateway change as an event, got %+v", doc.Events)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/debug/routes", nil)
+	req.Header.Set("Accept", "text/html")
+	h.ServeHTTP(rec, req)
+	if body := rec.Body.String(); !strings.Contains(body, "+ default via 10.8.0.1 dev tun0") {
+		t.Errorf("Expected the change in the HTML page, got %s", body)
+	}
+}
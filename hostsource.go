@@ -0,0 +1,17 @@
+//go:build !android
+
+package routing
+
+// hostSource returns the source of the host's routes, used when no path, file system or source is configured:
+// /proc/net/route, or the main table over netlink where /proc is unusable.
+func (o options) hostSource() RouteSource {
+	return procFallbackSource{
+		proc:     ProcSource{OnWarning: logWarnings(o.logger)},
+		fallback: NetlinkSource{Table: TableMain},
+		logger:   o.logger,
+	}
+}
+
+// hostIPv6OverNetlink reports whether the host's IPv6 routes are read over netlink rather than from
+// /proc/net/ipv6_route, which is only used as a fallback here.
+const hostIPv6OverNetlink = false
@@ -0,0 +1,12 @@
+package routing
+
+// hostSource returns the source of the host's routes, used when no path, file system or source is configured.
+// Android keeps apps from reading /proc/net/route and routes each network in its own table, so the routes of
+// every table are read over netlink, the active network's first.
+func (o options) hostSource() RouteSource {
+	return newAndroidSource()
+}
+
+// hostIPv6OverNetlink reports whether the host's IPv6 routes are read over netlink rather than from
+// /proc/net/ipv6_route, which Android keeps apps from reading as well.
+const hostIPv6OverNetlink = true
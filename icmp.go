@@ -0,0 +1,152 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"time"
+)
+
+// Echo message types and the header length from RFC 792.
+const (
+	icmpEchoReply   = 0
+	icmpEchoRequest = 8
+	icmpHeaderLen   = 8
+)
+
+// gatewayProbes is the number of echo requests CheckGateway sends.
+const gatewayProbes = 3
+
+// gatewayProbeTimeout bounds how long CheckGateway waits for each reply.
+const gatewayProbeTimeout = time.Second
+
+// GatewayCheck is the result of probing the default gateway with ICMP echo requests or ARP probes.
+type GatewayCheck struct {
+	Gateway  net.IP        // The address that was probed.
+	Sent     int           // Number of probes sent.
+	Received int           // Number of matching replies received.
+	Latency  time.Duration // Average round trip time of the replies; zero if none arrived.
+	Loss     float64       // Fraction of probes without a reply, from 0 to 1.
+
+	HWAddr net.HardwareAddr // Hardware address that answered; only set by CheckGatewayARP.
+}
+
+// Reachable reports whether the gateway answered at least one probe.
+func (c GatewayCheck) Reachable() bool {
+	return c.Received > 0
+}
+
+// CheckGateway sends ICMP echo requests to the default gateway and reports latency and loss.
+// A gateway that does not answer is not an error; the result then has a Loss of 1.
+// Unprivileged ICMP sockets are used where the kernel allows them, otherwise a raw socket is needed.
+func CheckGateway(ctx context.Context) (GatewayCheck, error) {
+	return defaultManager.CheckGateway(ctx)
+}
+
+// CheckGateway is like the package-level CheckGateway but probes the manager's default gateway.
+func (m *Manager) CheckGateway(ctx context.Context) (GatewayCheck, error) {
+	rt, err := m.DefaultRoute(ctx)
+	if err != nil {
+		return GatewayCheck{}, err
+	}
+
+	return pingHost(ctx, net.ParseIP(rt.Gateway), gatewayProbes, gatewayProbeTimeout, m.log())
+}
+
+// pingHost sends count echo requests to ip, waiting up to timeout for each reply.
+func pingHost(ctx context.Context, ip net.IP, count int, timeout time.Duration, logger *slog.Logger) (GatewayCheck, error) {
+	check := GatewayCheck{Gateway: ip}
+
+	conn, dst, err := listenICMP(ip, logger)
+	if err != nil {
+		return check, err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	token := make([]byte, 8) // Identifies our replies, as unprivileged sockets rewrite the echo identifier.
+	rand.Read(token)
+	id := uint16(os.Getpid())
+
+	var total time.Duration
+	buf := make([]byte, 1500)
+	for seq := 1; seq <= count; seq++ {
+		if err := ctx.Err(); err != nil {
+			return check, err
+		}
+
+		start := time.Now()
+		if _, err := conn.WriteTo(icmpEcho(id, uint16(seq), token), dst); err != nil {
+			return check, err
+		}
+		check.Sent++
+
+		deadline := start.Add(timeout)
+		if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+			deadline = d
+		}
+		conn.SetReadDeadline(deadline)
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break // No reply to this request.
+			}
+			if err != nil {
+				return check, err
+			}
+			if isEchoReply(buf[:n], uint16(seq), token) {
+				check.Received++
+				total += time.Since(start)
+				break
+			}
+		}
+	}
+
+	if check.Received > 0 {
+		check.Latency = total / time.Duration(check.Received)
+	}
+	check.Loss = float64(check.Sent-check.Received) / float64(check.Sent)
+
+	return check, ctx.Err()
+}
+
+// icmpEcho encodes an echo request carrying payload.
+func icmpEcho(id, seq uint16, payload []byte) []byte {
+	b := make([]byte, icmpHeaderLen+len(payload))
+	b[0] = icmpEchoRequest
+	binary.BigEndian.PutUint16(b[4:6], id)
+	binary.BigEndian.PutUint16(b[6:8], seq)
+	copy(b[icmpHeaderLen:], payload)
+	binary.BigEndian.PutUint16(b[2:4], icmpChecksum(b))
+
+	return b
+}
+
+// isEchoReply reports whether b is the reply to the echo request with the given sequence number and payload.
+func isEchoReply(b []byte, seq uint16, payload []byte) bool {
+	return len(b) >= icmpHeaderLen && b[0] == icmpEchoReply && b[1] == 0 &&
+		binary.BigEndian.Uint16(b[6:8]) == seq && bytes.Equal(b[icmpHeaderLen:], payload)
+}
+
+// icmpChecksum computes the Internet checksum of b as defined in RFC 1071.
+func icmpChecksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[i:]))
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum > 0xffff {
+		sum = sum&0xffff + sum>>16
+	}
+
+	return ^uint16(sum)
+}
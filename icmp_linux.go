@@ -0,0 +1,31 @@
+package routing
+
+import (
+	"log/slog"
+	"net"
+	"os"
+	"syscall"
+)
+
+// listenICMP opens a socket for sending echo requests to ip and returns the destination address to use with it.
+// It prefers an unprivileged ICMP datagram socket, which the kernel allows for groups in net.ipv4.ping_group_range,
+// and falls back to a raw socket, which needs CAP_NET_RAW. The fallback is logged at debug level.
+func listenICMP(ip net.IP, logger *slog.Logger) (net.PacketConn, net.Addr, error) {
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, syscall.IPPROTO_ICMP)
+	if err == nil {
+		f := os.NewFile(uintptr(fd), "icmp")
+		defer f.Close() // FilePacketConn duplicates the descriptor.
+		var conn net.PacketConn
+		if conn, err = net.FilePacketConn(f); err == nil {
+			return conn, &net.UDPAddr{IP: ip}, nil
+		}
+	}
+	logger.Debug("unprivileged ICMP socket unavailable, falling back to a raw socket", "err", err)
+
+	conn, err := net.ListenPacket("ip4:icmp", "0.0.0.0")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return conn, &net.IPAddr{IP: ip}, nil
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package routing
+
+import (
+	"log/slog"
+	"net"
+)
+
+// listenICMP opens a raw socket for sending echo requests to ip, which usually requires root.
+func listenICMP(ip net.IP, logger *slog.Logger) (net.PacketConn, net.Addr, error) {
+	conn, err := net.ListenPacket("ip4:icmp", "0.0.0.0")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return conn, &net.IPAddr{IP: ip}, nil
+}
@@ -0,0 +1,42 @@
+package routing
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestICMPEcho(t *testing.T) {
+	b := icmpEcho(1, 2, []byte("token"))
+	if icmpChecksum(b) != 0 {
+		t.Errorf("Expected a valid checksum, got %#x", icmpChecksum(b))
+	}
+
+	reply := append([]byte(nil), b...)
+	reply[0] = icmpEchoReply
+	if !isEchoReply(reply, 2, []byte("token")) {
+		t.Errorf("Expected the reply to match")
+	}
+	if isEchoReply(reply, 3, []byte("token")) || isEchoReply(reply, 2, []byte("other")) || isEchoReply(b, 2, []byte("token")) {
+		t.Errorf("Expected mismatched replies to be rejected")
+	}
+}
+
+func TestPingLoopback(t *testing.T) {
+	check, err := pingHost(context.Background(), net.IPv4(127, 0, 0, 1), 2, time.Second, discardLogger)
+	if err != nil {
+		t.Skipf("Cannot send ICMP echo requests: %s", err)
+	}
+	if check.Sent != 2 || check.Received != 2 || check.Loss != 0 || !check.Reachable() || check.Latency <= 0 {
+		t.Errorf("Unexpected result %+v", check)
+	}
+}
+
+func TestCheckGatewayCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := CheckGateway(ctx); err == nil {
+		t.Errorf("Expected an error for a canceled context")
+	}
+}
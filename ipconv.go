@@ -0,0 +1,61 @@
+package routing
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// HexToIP decodes an IPv4 address printed as a 32-bit hex integer, as in /proc/net/route.
+// The kernel prints the address bytes as it holds them in memory, read as an integer in the host's byte order,
+// so order must be the byte order of the machine that produced s: binary.LittleEndian for "0100A8C0" to
+// mean 192.168.0.1, or binary.NativeEndian for the local kernel.
+func HexToIP(s string, order binary.ByteOrder) (net.IP, error) {
+	val, err := strconv.ParseUint(strings.TrimSpace(s), 16, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := make(net.IP, net.IPv4len)
+	order.PutUint32(ip, uint32(val))
+
+	return ip, nil
+}
+
+// IPToHex encodes an IPv4 address as the 8-digit upper-case hex integer that HexToIP decodes with the same order.
+func IPToHex(ip net.IP, order binary.ByteOrder) (string, error) {
+	val, err := IPToDecimal(ip, order)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%08X", val), nil
+}
+
+// IPToDecimal returns the 32-bit integer whose bytes in the given order are the IPv4 address.
+// With binary.LittleEndian it is the inverse of DecimalToIP.
+func IPToDecimal(ip net.IP, order binary.ByteOrder) (uint32, error) {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return 0, fmt.Errorf("%w: %s", errNotIPv4, ip)
+	}
+
+	return order.Uint32(ip4), nil
+}
+
+// MaskToPrefixLen returns the prefix length of a subnet mask in the little-endian hex form of RoutingTable's Mask,
+// e.g. 24 for "00FFFFFF". Malformed and non-contiguous masks, such as "00FF00FF", fail with a ParseError.
+func MaskToPrefixLen(hexMask string) (int, error) {
+	mask, err := parseHexIP(hexMask)
+	if err != nil {
+		return 0, &ParseError{Column: "Mask", Value: hexMask, Err: err}
+	}
+	ones, bits := net.IPMask(mask).Size()
+	if bits == 0 {
+		return 0, &ParseError{Column: "Mask", Value: hexMask, Err: errNonContiguousMask}
+	}
+
+	return ones, nil
+}
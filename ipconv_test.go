@@ -0,0 +1,77 @@
+package routing
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestHexToIP(t *testing.T) {
+	cases := []struct {
+		hex   string
+		order binary.ByteOrder
+		ip    string
+	}{
+		{"0100A8C0", binary.LittleEndian, "192.168.0.1"},
+		{"C0A80001", binary.BigEndian, "192.168.0.1"},
+		{"00FFFFFF", binary.LittleEndian, "255.255.255.0"},
+		{"FFFFFF00", binary.BigEndian, "255.255.255.0"},
+	}
+	for _, c := range cases {
+		ip, err := HexToIP(c.hex, c.order)
+		if err != nil || ip.String() != c.ip {
+			t.Errorf("HexToIP(%s, %s) = %s, %v; want %s", c.hex, c.order, ip, err, c.ip)
+		}
+		back, err := IPToHex(ip, c.order)
+		if err != nil || back != c.hex {
+			t.Errorf("IPToHex(%s, %s) = %s, %v; want %s", ip, c.order, back, err, c.hex)
+		}
+	}
+
+	if _, err := HexToIP("zz", binary.LittleEndian); err == nil {
+		t.Errorf("Expected an error for invalid hex")
+	}
+	if _, err := IPToHex(net.ParseIP("2001:db8::1"), binary.LittleEndian); err == nil {
+		t.Errorf("Expected an error for an IPv6 address")
+	}
+}
+
+func TestIPToDecimalInvertsDecimalToIP(t *testing.T) {
+	v, err := IPToDecimal(net.ParseIP("192.0.2.1"), binary.LittleEndian)
+	if err != nil {
+		t.Fatalf("IPToDecimal failed %s", err.Error())
+	}
+	if DecimalToIP(int64(v)) != "192.0.2.1" {
+		t.Errorf("Round trip gave %s", DecimalToIP(int64(v)))
+	}
+}
+
+func TestMaskToPrefixLen(t *testing.T) {
+	cases := []struct {
+		mask string
+		ones int
+	}{
+		{"00000000", 0},
+		{"000000FF", 8},
+		{"00FFFFFF", 24},
+		{"FCFFFFFF", 30},
+		{"FFFFFFFF", 32},
+	}
+	for _, c := range cases {
+		if ones, err := MaskToPrefixLen(c.mask); err != nil || ones != c.ones {
+			t.Errorf("MaskToPrefixLen(%s) = %d, %v; want %d", c.mask, ones, err, c.ones)
+		}
+	}
+
+	for _, mask := range []string{"00FF00FF", "zz"} {
+		if _, err := MaskToPrefixLen(mask); !errors.Is(err, ErrParse) {
+			t.Errorf("MaskToPrefixLen(%s): expected a ParseError, got %v", mask, err)
+		}
+	}
+
+	rt := RoutingTable{Destination: "0000A8C0", Mask: "00FFFFFF"}
+	if ones, err := rt.PrefixLen(); err != nil || ones != 24 {
+		t.Errorf("PrefixLen() = %d, %v; want 24", ones, err)
+	}
+}
@@ -0,0 +1,420 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"net"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	errNotIPv4      = errors.New("not an IPv4 address")
+	errMissingValue = errors.New("missing value")
+	errUnknownTable = errors.New("unknown routing table")
+	errUnknownType  = errors.New("unknown route type")
+	errUnknownTOS   = errors.New("unknown TOS value")
+)
+
+// RouteSource is implemented by anything that can produce the current routing table.
+type RouteSource interface {
+	Routes(ctx context.Context) ([]RoutingTable, error)
+}
+
+// ProcSource reads routes from /proc/net/route.
+type ProcSource struct {
+	Path      string             // File to read, e.g. a copy captured from another host; /proc/net/route when empty.
+	FS        fs.FS              // Filesystem Path is read from; the host's when nil. See WithFS.
+	OnWarning func(ParseWarning) // Called for recoverable anomalies in the file; may be nil.
+}
+
+// Routes returns the routing table as reported by /proc/net/route.
+func (s ProcSource) Routes(ctx context.Context) ([]RoutingTable, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	path := s.Path
+	if path == "" {
+		path = procRoutePath
+	}
+
+	table := new([]RoutingTable)
+	if err := appendRoutes(ctx, procRoutes(s.FS, path, s.OnWarning), table); err != nil {
+		return nil, err
+	}
+
+	return *table, nil
+}
+
+// IPRouteSource reads routes by running `ip -4 -j route show`.
+// Unlike /proc/net/route it also provides the protocol, scope and preferred source of each route.
+type IPRouteSource struct {
+	Path string // Path to the ip binary; "ip" is looked up in $PATH when empty.
+}
+
+// Routes runs the ip command and decodes its JSON output.
+func (s IPRouteSource) Routes(ctx context.Context) ([]RoutingTable, error) {
+	path := s.Path
+	if path == "" {
+		path = "ip"
+	}
+
+	out, err := exec.CommandContext(ctx, path, "-4", "-j", "route", "show").Output()
+	if err != nil {
+		return nil, fmt.Errorf("running %s: %w", path, err)
+	}
+
+	return ParseIPRouteJSON(strings.NewReader(string(out)))
+}
+
+// ipRouteJSON is a single entry of `ip -j route show` output.
+type ipRouteJSON struct {
+	Type     string   `json:"type"`
+	Dst      string   `json:"dst"`
+	Gateway  string   `json:"gateway"`
+	Dev      string   `json:"dev"`
+	Protocol string   `json:"protocol"`
+	Scope    string   `json:"scope"`
+	PrefSrc  string   `json:"prefsrc"`
+	Table    string   `json:"table"`
+	Metric   uint32   `json:"metric"`
+	Weight   int      `json:"weight"`
+	NHID     uint32   `json:"nhid"`
+	TOS      string   `json:"tos"`       // A number such as "0x10" or a name from rt_dsfield.
+	FlowFrom string   `json:"flow_from"` // Source realm, printed as "realms FROM/TO".
+	FlowTo   string   `json:"flow_to"`   // Destination realm, printed as "realm TO".
+	Flags    []string `json:"flags"`
+
+	Metrics       []map[string]json.RawMessage `json:"metrics"` // RTA_METRICS, printed by ip as a one-element array.
+	parsedMetrics RouteMetrics                 // Metrics set by the text parser.
+
+	Nexthops []ipRouteJSON `json:"nexthops"` // Paths of a multipath route, carrying only gateway, dev, weight and flags.
+}
+
+// ParseIPRouteJSON decodes the output of `ip -4 -j route show` read from r.
+// Destinations and masks are stored in the same hex format used by /proc/net/route.
+func ParseIPRouteJSON(r io.Reader) ([]RoutingTable, error) {
+	var entries []ipRouteJSON
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	table := make([]RoutingTable, 0, len(entries))
+	for _, e := range entries {
+		rt, err := e.toRoutingTable()
+		if err != nil {
+			return nil, err
+		}
+		table = append(table, rt)
+	}
+
+	return table, nil
+}
+
+// toRoutingTable converts an ip route entry into the package's route model.
+func (e ipRouteJSON) toRoutingTable() (RoutingTable, error) {
+	dst, err := parseIPRouteDst(e.Dst)
+	if err != nil {
+		return RoutingTable{}, err
+	}
+
+	gw := net.IPv4zero
+	if e.Gateway != "" {
+		gw = net.ParseIP(e.Gateway)
+		if gw.To4() == nil {
+			return RoutingTable{}, &ParseError{Column: "gateway", Value: e.Gateway, Err: errNotIPv4}
+		}
+	}
+
+	typ := RouteTypeUnicast // ip omits the type of unicast routes.
+	if e.Type != "" {
+		t, ok := ParseRouteType(e.Type)
+		if !ok {
+			return RoutingTable{}, &ParseError{Column: "type", Value: e.Type, Err: errUnknownType}
+		}
+		typ = t
+	}
+
+	var bits int16 = 0x1
+	onlink := false
+	for _, f := range e.Flags {
+		switch f {
+		case "linkdown", "dead":
+			bits = 0 // The route exists but cannot currently be used.
+		case "onlink":
+			onlink = true
+		}
+	}
+	if !gw.IsUnspecified() {
+		bits |= 0x2
+	}
+	if ones, _ := dst.Mask.Size(); ones == 32 {
+		bits |= 0x4
+	}
+	if typ == RouteTypeUnreachable || typ == RouteTypeProhibit {
+		bits |= FlagReject
+	}
+
+	var nexthops []Nexthop
+	for _, nh := range e.Nexthops {
+		nhGw := net.IPv4zero
+		if nh.Gateway != "" {
+			nhGw = net.ParseIP(nh.Gateway)
+			if nhGw.To4() == nil {
+				return RoutingTable{}, &ParseError{Column: "nexthop via", Value: nh.Gateway, Err: errNotIPv4}
+			}
+		}
+		nexthops = append(nexthops, Nexthop{Gateway: nhGw.String(), Interface: nh.Dev, Weight: nh.Weight, OnLink: slices.Contains(nh.Flags, "onlink")})
+	}
+	if len(nexthops) > 0 && gw.IsUnspecified() && e.Dev == "" {
+		gw = net.ParseIP(nexthops[0].Gateway) // Mirror the first path, as /proc/net/route does.
+		e.Dev = nexthops[0].Interface
+		if !gw.IsUnspecified() {
+			bits |= FlagGateway
+		}
+	}
+
+	metrics := e.parsedMetrics
+	for _, obj := range e.Metrics {
+		for key, raw := range obj {
+			var val any
+			if err := json.Unmarshal(raw, &val); err != nil {
+				return RoutingTable{}, &ParseError{Column: key, Value: string(raw), Err: err}
+			}
+			if arr, ok := val.([]any); ok && len(arr) > 0 {
+				val = arr[len(arr)-1] // Locked metrics are printed as ["lock", value].
+			}
+			s := fmt.Sprint(val)
+			if f, ok := val.(float64); ok {
+				s = strconv.FormatFloat(f, 'f', -1, 64)
+			}
+			if _, err := metrics.setIPRouteMetric(key, s); err != nil {
+				return RoutingTable{}, &ParseError{Column: key, Value: string(raw), Err: err}
+			}
+		}
+	}
+
+	table := TableMain // ip omits the table for routes in main.
+	if e.Table != "" {
+		id, ok := TableID(e.Table)
+		if !ok {
+			return RoutingTable{}, &ParseError{Column: "table", Value: e.Table, Err: errUnknownTable}
+		}
+		table = id
+	}
+
+	var tos uint8
+	if e.TOS != "" {
+		v, ok := ParseDSField(e.TOS)
+		if !ok {
+			return RoutingTable{}, &ParseError{Column: "tos", Value: e.TOS, Err: errUnknownTOS}
+		}
+		tos = v
+	}
+
+	return RoutingTable{
+		Interface:   e.Dev,
+		Destination: formatHexIP(dst.IP),
+		Gateway:     gw.String(),
+		Flags:       computeRouteFlag(bits),
+		Metric:      e.Metric,
+		Mask:        formatHexIP(net.IP(dst.Mask)),
+		Window:      clampInt8(int(min(metrics.Window, math.MaxInt32))),
+		Proto:       e.Protocol,
+		Scope:       e.Scope,
+		PrefSrc:     e.PrefSrc,
+		Realm:       e.FlowTo,
+		FromRealm:   e.FlowFrom,
+		TOS:         tos,
+		NexthopID:   e.NHID,
+		Table:       table,
+		Nexthops:    nexthops,
+		Type:        typ,
+		OnLink:      onlink,
+		Metrics:     metrics,
+	}, nil
+}
+
+// parseIPRouteDst parses the dst field of ip route output.
+// It accepts "default", a bare host address or a CIDR prefix.
+func parseIPRouteDst(dst string) (*net.IPNet, error) {
+	switch {
+	case dst == "default":
+		return &net.IPNet{IP: net.IPv4zero.To4(), Mask: net.CIDRMask(0, 32)}, nil
+	case !strings.Contains(dst, "/"):
+		ip := net.ParseIP(dst).To4()
+		if ip == nil {
+			return nil, &ParseError{Column: "dst", Value: dst, Err: errNotIPv4}
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(32, 32)}, nil
+	}
+
+	_, ipNet, err := net.ParseCIDR(dst)
+	if err != nil {
+		return nil, &ParseError{Column: "dst", Value: dst, Err: err}
+	}
+	if ipNet.IP.To4() == nil {
+		return nil, &ParseError{Column: "dst", Value: dst, Err: errNotIPv4}
+	}
+
+	return ipNet, nil
+}
+
+// clampInt8 converts v to int8, saturating at the type's limits like strconv does for the proc columns.
+func clampInt8(v int) int8 {
+	switch {
+	case v > math.MaxInt8:
+		return math.MaxInt8
+	case v < math.MinInt8:
+		return math.MinInt8
+	}
+
+	return int8(v)
+}
+
+// ipRouteFlagWords are the keywords in `ip route show` output that are not followed by a value.
+var ipRouteFlagWords = map[string]bool{
+	"onlink": true, "linkdown": true, "dead": true, "pervasive": true,
+	"offload": true, "trap": true, "notify": true, "rt_offload": true, "rt_trap": true,
+}
+
+// ParseIPRoute decodes the plain-text output of `ip -4 route show` read from r.
+// Indented "nexthop" lines are attached to the preceding multipath route.
+// A leading type keyword such as "blackhole" or "local" sets the route's Type.
+func ParseIPRoute(r io.Reader) ([]RoutingTable, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var table []RoutingTable
+	var last *ipRouteJSON // Route that nexthop lines belong to.
+	var lastLine int
+	flush := func() error {
+		if last == nil {
+			return nil
+		}
+		rt, err := last.toRoutingTable()
+		if err != nil {
+			var pErr *ParseError
+			if errors.As(err, &pErr) {
+				pErr.Line = lastLine
+			}
+			return err
+		}
+		table = append(table, rt)
+		last = nil
+		return nil
+	}
+
+	for n, line := range strings.Split(string(b), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if line[0] == ' ' || line[0] == '\t' {
+			if fields[0] != "nexthop" || last == nil {
+				continue // Continuation lines other than nexthops carry nothing we model.
+			}
+			var nh ipRouteJSON
+			if err := parseIPRouteFields(&nh, fields[1:]); err != nil {
+				err.Line = n + 1
+				return nil, err
+			}
+			last.Nexthops = append(last.Nexthops, nh)
+			continue
+		}
+
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		var e ipRouteJSON
+		if _, ok := ParseRouteType(fields[0]); ok {
+			e.Type, fields = fields[0], fields[1:]
+		}
+		if len(fields) == 0 {
+			return nil, &ParseError{Line: n + 1, Column: "dst", Err: errMissingValue}
+		}
+
+		e.Dst = fields[0]
+		if err := parseIPRouteFields(&e, fields[1:]); err != nil {
+			err.Line = n + 1
+			return nil, err
+		}
+		last, lastLine = &e, n+1
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// parseIPRouteFields decodes the "key value" and flag words following the destination of an ip route line.
+func parseIPRouteFields(e *ipRouteJSON, fields []string) *ParseError {
+	for i := 0; i < len(fields); i++ {
+		key := fields[i]
+		if ipRouteFlagWords[key] {
+			e.Flags = append(e.Flags, key)
+			continue
+		}
+		if i+1 >= len(fields) {
+			return &ParseError{Column: key, Err: errMissingValue}
+		}
+		i++
+		val := fields[i]
+		if val == "lock" && i+1 < len(fields) {
+			i++ // Locked metrics are printed as "mtu lock 1400".
+			val = fields[i]
+		}
+		var err error
+		switch key {
+		case "via":
+			e.Gateway = val
+		case "dev":
+			e.Dev = val
+		case "proto":
+			e.Protocol = val
+		case "scope":
+			e.Scope = val
+		case "src":
+			e.PrefSrc = val
+		case "table":
+			e.Table = val
+		case "metric":
+			e.Metric, err = parseUint32(val)
+		case "weight":
+			e.Weight, err = strconv.Atoi(val)
+		case "nhid":
+			var id uint64
+			id, err = strconv.ParseUint(val, 10, 32)
+			e.NHID = uint32(id)
+		case "tos", "dsfield":
+			e.TOS = val
+		case "realm":
+			e.FlowTo = val
+		case "realms":
+			from, to, ok := strings.Cut(val, "/")
+			if !ok {
+				from, to = "", from // A single realm is the destination's, as with "realm".
+			}
+			e.FlowFrom, e.FlowTo = from, to
+		default:
+			_, err = e.parsedMetrics.setIPRouteMetric(key, val)
+		}
+		if err != nil {
+			return &ParseError{Column: key, Value: val, Err: err}
+		}
+	}
+
+	return nil
+}
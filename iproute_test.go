@@ -0,0 +1,120 @@
+package routing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const ipRouteJSONFixture = `[{"dst":"default","gateway":"192.168.1.1","dev":"eth0","protocol":"dhcp","prefsrc":"192.168.1.5","metric":600,"flags":[]},` +
+	`{"dst":"192.168.1.0/24","dev":"eth0","protocol":"kernel","scope":"link","prefsrc":"192.168.1.5","metric":100,"flags":[]},` +
+	`{"dst":"10.0.0.1","gateway":"192.168.1.254","dev":"eth0","flags":["onlink"]}]`
+
+func TestParseIPRouteJSON(t *testing.T) {
+	table, err := ParseIPRouteJSON(strings.NewReader(ipRouteJSONFixture))
+	if err != nil {
+		t.Fatalf("ParseIPRouteJSON failed %s", err.Error())
+	}
+
+	if len(table) != 3 {
+		t.Fatalf("Expected 3 routes, got %d", len(table))
+	}
+
+	expected := []string{
+		"default via 192.168.1.1 dev eth0 proto dhcp src 192.168.1.5 metric 600",
+		"192.168.1.0/24 dev eth0 proto kernel scope link src 192.168.1.5 metric 100",
+		"10.0.0.1 via 192.168.1.254 dev eth0 onlink",
+	}
+	for i, rt := range table {
+		if rt.String() != expected[i] {
+			t.Errorf("Route %d = %q, want %q", i, rt.String(), expected[i])
+		}
+	}
+
+	if table[0].Destination != "00000000" || !flagContains(table[0].Flags, "G") {
+		t.Errorf("Unexpected default route %+v", table[0])
+	}
+	if !flagContains(table[2].Flags, "H") {
+		t.Errorf("Expected host flag on %+v", table[2])
+	}
+}
+
+func TestParseIPRouteJSONRejectsIPv6(t *testing.T) {
+	_, err := ParseIPRouteJSON(strings.NewReader(`[{"dst":"2001:db8::/32","dev":"eth0","flags":[]}]`))
+	if err == nil {
+		t.Errorf("Expected an error for an IPv6 destination")
+	}
+}
+
+const ipRouteTextFixture = `default via 192.168.1.1 dev eth0 proto dhcp src 192.168.1.5 metric 100 
+10.8.0.0/24 dev tun0 proto kernel scope link src 10.8.0.2 mtu lock 1400
+10.9.0.0/16 proto static metric 1024
+	nexthop via 10.8.0.1 dev tun0 weight 1
+	nexthop via 192.168.1.254 dev eth0 weight 1
+blackhole 10.99.0.0/16
+prohibit 10.98.0.0/16 metric 5
+unicast 192.168.1.0/24 dev eth0 proto kernel scope link src 192.168.1.5 metric 100 linkdown
+`
+
+func TestParseIPRoute(t *testing.T) {
+	table, err := ParseIPRoute(strings.NewReader(ipRouteTextFixture))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	expected := []string{
+		"default via 192.168.1.1 dev eth0 proto dhcp src 192.168.1.5 metric 100",
+		"10.8.0.0/24 dev tun0 proto kernel scope link src 10.8.0.2 mtu 1400",
+		"10.9.0.0/16 proto static metric 1024\n\tnexthop via 10.8.0.1 dev tun0 weight 1\n\tnexthop via 192.168.1.254 dev eth0 weight 1",
+		"blackhole 10.99.0.0/16",
+		"prohibit 10.98.0.0/16 metric 5",
+		"192.168.1.0/24 dev eth0 proto kernel scope link src 192.168.1.5 metric 100",
+	}
+	if len(table) != len(expected) {
+		t.Fatalf("Expected %d routes, got %d", len(expected), len(table))
+	}
+	for i, rt := range table {
+		if rt.String() != expected[i] {
+			t.Errorf("Route %d = %q, want %q", i, rt.String(), expected[i])
+		}
+	}
+
+	if len(table[2].Nexthops) != 2 || table[2].Gateway != "10.8.0.1" || table[2].Interface != "tun0" || !flagContains(table[2].Flags, "G") {
+		t.Errorf("Expected the multipath route to mirror its first nexthop, got %+v", table[2])
+	}
+	if table[1].Metrics.MTU != 1400 {
+		t.Errorf("Expected locked MTU to be parsed, got %d", table[1].Metrics.MTU)
+	}
+	if table[3].Type != RouteTypeBlackhole || table[4].Type != RouteTypeProhibit || !flagContains(table[4].Flags, "!") {
+		t.Errorf("Expected special route types, got %v and %v", table[3].Type, table[4].Type)
+	}
+	if table[5].Type != RouteTypeUnicast || flagContains(table[5].Flags, "U") {
+		t.Errorf("Expected linkdown unicast route to not be up %+v", table[5])
+	}
+}
+
+func TestParseIPRouteTOS(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("198.51.100.0/24 tos 0x10 via 192.0.2.1 dev eth0\n203.0.113.0/24 dsfield 0x28 dev eth0\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	if routes[0].TOS != 0x10 || routes[1].TOS != 0x28 {
+		t.Errorf("Unexpected TOS %#x %#x", routes[0].TOS, routes[1].TOS)
+	}
+	if got := routes[0].String(); got != "198.51.100.0/24 tos 0x10 via 192.0.2.1 dev eth0" {
+		t.Errorf("Unexpected rendering %q", got)
+	}
+
+	table, err := ParseIPRouteJSON(strings.NewReader(`[{"dst":"198.51.100.0/24","tos":"0x10","dev":"eth0","flags":[]}]`))
+	if err != nil {
+		t.Fatalf("ParseIPRouteJSON failed %s", err.Error())
+	}
+	if table[0].TOS != 0x10 {
+		t.Errorf("Expected TOS 0x10, got %#x", table[0].TOS)
+	}
+
+	var perr *ParseError
+	if _, err := ParseIPRoute(strings.NewReader("198.51.100.0/24 tos nosuchvalue dev eth0\n")); !errors.As(err, &perr) || perr.Column != "tos" {
+		t.Errorf("Expected a ParseError for an unknown TOS, got %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package routing
+
+import (
+	"errors"
+	"io"
+)
+
+// ipRouteSaveMagic starts the output of `ip route save`, in host byte order.
+const ipRouteSaveMagic = 0x45311224
+
+var errNotRouteSave = errors.New("not an ip route save dump")
+
+// ReadIPRouteSave reads the binary dump written by `ip route save`: a magic number followed by the RTM_NEWROUTE
+// netlink messages of the kernel's route dump. It returns the IPv4 and the IPv6 routes. The dump holds interface
+// indexes and is in host byte order, so it must be read on the host that saved it for interfaces to be named.
+// Saved routes can be compared with the current ones with DiffRoutes and restored with RouteSet.Apply.
+// It is only available on Linux and returns ErrNotSupported elsewhere.
+func ReadIPRouteSave(r io.Reader) ([]RoutingTable, []IPv6Route, error) {
+	return readIPRouteSave(r)
+}
+
+// WriteIPRouteSave writes the IPv4 routes to w in the format of `ip route save`, so `ip route restore` can install
+// them. Interfaces are written as the indexes they have on this host, and must exist.
+// It is only available on Linux and returns ErrNotSupported elsewhere.
+func WriteIPRouteSave(w io.Writer, routes []RoutingTable) error {
+	return writeIPRouteSave(w, routes)
+}
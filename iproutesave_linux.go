@@ -0,0 +1,67 @@
+package routing
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"syscall"
+)
+
+// readIPRouteSave implements ReadIPRouteSave.
+func readIPRouteSave(r io.Reader) ([]RoutingTable, []IPv6Route, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(b) < 4 || binary.NativeEndian.Uint32(b) != ipRouteSaveMagic {
+		return nil, nil, errNotRouteSave
+	}
+	msgs, err := syscall.ParseNetlinkMessage(b[4:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", errNotRouteSave, err)
+	}
+	size := 0
+	for _, m := range msgs {
+		size += nlAttrAlign(int(m.Header.Len))
+	}
+	if size < len(b)-4 { // ParseNetlinkMessage ignores trailing bytes too short for a header.
+		return nil, nil, fmt.Errorf("%w: truncated message", errNotRouteSave)
+	}
+
+	names := loadRouteNames()
+	var routes []RoutingTable
+	var v6routes []IPv6Route
+	for _, m := range msgs {
+		if m.Header.Type != syscall.RTM_NEWROUTE {
+			continue
+		}
+		if rt, ok := parseRouteMsg(m.Data, names); ok {
+			routes = append(routes, rt)
+		} else if rt, _, ok := parseIPv6RouteMsg(m.Data, names); ok {
+			v6routes = append(v6routes, rt)
+		}
+	}
+
+	return routes, v6routes, nil
+}
+
+// writeIPRouteSave implements WriteIPRouteSave, writing each route as the message AddRoute would send.
+func writeIPRouteSave(w io.Writer, routes []RoutingTable) error {
+	b := binary.NativeEndian.AppendUint32(nil, ipRouteSaveMagic)
+	for _, rt := range routes {
+		data, err := routeMsg(routeAdd, rt)
+		if err != nil {
+			return fmt.Errorf("route %s: %w", rt, err)
+		}
+		msg := make([]byte, syscall.NLMSG_HDRLEN, syscall.NLMSG_HDRLEN+nlAttrAlign(len(data)))
+		binary.NativeEndian.PutUint32(msg[0:4], uint32(syscall.NLMSG_HDRLEN+len(data)))
+		binary.NativeEndian.PutUint16(msg[4:6], syscall.RTM_NEWROUTE)
+		binary.NativeEndian.PutUint16(msg[6:8], syscall.NLM_F_MULTI) // As in the kernel's dump ip saves.
+		msg = append(msg, data...)
+		b = append(b, msg[:cap(msg)]...)
+	}
+
+	_, err := w.Write(b)
+
+	return err
+}
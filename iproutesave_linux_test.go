@@ -0,0 +1,112 @@
+package routing
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIPRouteSaveRoundTrip(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("198.51.100.0/24 via 127.0.0.2 dev lo proto static metric 600 table 4242\n" +
+		"blackhole 203.0.113.0/24 table 4242\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	var buf bytes.Buffer
+	if err := WriteIPRouteSave(&buf, routes); err != nil {
+		t.Fatalf("WriteIPRouteSave failed %s", err.Error())
+	}
+	got, v6, err := ReadIPRouteSave(&buf)
+	if err != nil {
+		t.Fatalf("ReadIPRouteSave failed %s", err.Error())
+	}
+	if len(v6) != 0 {
+		t.Errorf("Expected no IPv6 routes, got %v", v6)
+	}
+	want := []string{
+		"198.51.100.0/24 via 127.0.0.2 dev lo table 4242 proto static metric 600",
+		"blackhole 203.0.113.0/24 table 4242",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d routes, got %v", len(want), got)
+	}
+	for i, rt := range got {
+		if rt.String() != want[i] {
+			t.Errorf("Route %d = %q, want %q", i, rt.String(), want[i])
+		}
+	}
+}
+
+func TestReadIPRouteSaveInvalid(t *testing.T) {
+	for _, input := range []string{"", "default via 192.168.1.1 dev eth0\n", "\x24\x12\x31\x45\x10\x00"} {
+		if _, _, err := ReadIPRouteSave(strings.NewReader(input)); !errors.Is(err, errNotRouteSave) {
+			t.Errorf("ReadIPRouteSave(%q) = %v, want errNotRouteSave", input, err)
+		}
+	}
+}
+
+func TestIPRouteSaveInterop(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+	setLoopbackUp(t)
+
+	routes, err := ParseIPRoute(strings.NewReader("198.51.100.0/24 via 127.0.0.2 dev lo table 4242 metric 1024\n" +
+		"unreachable 203.0.113.0/24 table 4242\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	for _, rt := range routes {
+		if err := AddRoute(rt); err != nil {
+			t.Fatalf("AddRoute failed %s", err.Error())
+		}
+	}
+
+	saved, err := exec.Command("ip", "-4", "route", "save", "table", "4242").Output()
+	if err != nil {
+		t.Skipf("Running ip route save failed: %s", err)
+	}
+	got, _, err := ReadIPRouteSave(bytes.NewReader(saved))
+	if err != nil {
+		t.Fatalf("ReadIPRouteSave failed %s", err.Error())
+	}
+	if change := DiffRoutes(routes, got); len(change.Added)+len(change.Removed) != 0 {
+		t.Errorf("Expected the saved routes to match the installed ones, got %+v", change)
+	}
+
+	// ip route restore must install what WriteIPRouteSave wrote.
+	for _, rt := range routes {
+		if err := DeleteRoute(rt); err != nil {
+			t.Fatalf("DeleteRoute failed %s", err.Error())
+		}
+	}
+	f, err := os.Create(filepath.Join(t.TempDir(), "routes.save")) // ip route restore needs a seekable input.
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := WriteIPRouteSave(f, routes); err != nil {
+		t.Fatalf("WriteIPRouteSave failed %s", err.Error())
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	restore := exec.Command("ip", "route", "restore")
+	restore.Stdin = f
+	if out, err := restore.CombinedOutput(); err != nil {
+		t.Fatalf("ip route restore failed %s: %s", err, out)
+	}
+	installed, err := NetlinkSource{Table: 4242}.Routes(t.Context())
+	if err != nil {
+		t.Fatalf("Routes failed %s", err.Error())
+	}
+	if change := DiffRoutes(routes, installed); len(change.Added)+len(change.Removed) != 0 {
+		t.Errorf("Expected ip route restore to install the routes, got %+v", change)
+	}
+}
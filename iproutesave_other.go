@@ -0,0 +1,15 @@
+//go:build !linux
+
+package routing
+
+import "io"
+
+// readIPRouteSave implements ReadIPRouteSave; the dump is made of Linux netlink messages.
+func readIPRouteSave(r io.Reader) ([]RoutingTable, []IPv6Route, error) {
+	return nil, nil, ErrNotSupported
+}
+
+// writeIPRouteSave implements WriteIPRouteSave; the dump is made of Linux netlink messages.
+func writeIPRouteSave(w io.Writer, routes []RoutingTable) error {
+	return ErrNotSupported
+}
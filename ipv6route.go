@@ -0,0 +1,327 @@
+package routing
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errNotIPv6 = errors.New("not an IPv6 address")
+
+// procIPv6RoutePath is the location of the kernel's IPv6 routing table.
+const procIPv6RoutePath = "/proc/net/ipv6_route"
+
+// IPv6Route is an entry of the kernel's IPv6 routing table.
+// /proc/net/ipv6_route has no header and no protocol or lifetime columns; NetlinkSource.IPv6Routes fills those in.
+type IPv6Route struct {
+	Destination *net.IPNet           // The destination prefix.
+	Source      *net.IPNet           // The source prefix of a source-specific route; "::/0" otherwise.
+	Gateway     net.IP               // The next hop; "::" if directly connected.
+	Interface   string               // The network interface associated with the route.
+	Metric      uint32               // Metric for the route, used in route selection.
+	Flags       map[string]RouteFlag // IPv6 flags of the route; see IPv6RouteFlags.
+	Proto       string               // Protocol that installed the route (e.g. "kernel", "ra"), when known.
+	Expires     time.Duration        // Remaining lifetime of an expiring route; zero if it does not expire or is unknown.
+	Type        RouteType            // Kind of route, e.g. RouteTypeLocal; only NetlinkSource reports it, zero means unicast.
+	NexthopID   uint32               // ID of the NexthopObject the route uses; only NetlinkSource reports it.
+	SRv6        *SRv6Encap           // Segment routing behaviour of seg6 and seg6local routes; only NetlinkSource reports it.
+}
+
+// LearnedFromRA reports whether the route was installed from a router advertisement (SLAAC) rather than configured,
+// going by the "ra" protocol or the Addrconf flag.
+func (r IPv6Route) LearnedFromRA() bool {
+	return r.Proto == "ra" || flagContains(r.Flags, "A")
+}
+
+// Cloned reports whether the kernel generated the route: an exception cloned from another route, e.g. to record
+// a path MTU ("C"), or a route created by an ICMPv6 redirect ("D").
+func (r IPv6Route) Cloned() bool {
+	return isCloned(r.Flags)
+}
+
+// PrefixLen returns the prefix length of the route's destination.
+func (r IPv6Route) PrefixLen() int {
+	ones, _ := r.Destination.Mask.Size()
+
+	return ones
+}
+
+// String formats the route like `ip -6 route`, e.g. "default via fe80::1 dev eth0 proto ra metric 1024 expires 1798sec".
+func (r IPv6Route) String() string {
+	dst := r.Destination.String()
+	if r.PrefixLen() == 0 {
+		dst = "default"
+	}
+
+	var b strings.Builder
+	b.WriteString(dst)
+	if r.NexthopID != 0 {
+		fmt.Fprintf(&b, " nhid %d", r.NexthopID)
+	}
+	if r.SRv6 != nil {
+		fmt.Fprintf(&b, " %s", r.SRv6)
+	}
+	if r.Gateway != nil && !r.Gateway.IsUnspecified() {
+		b.WriteString(" via " + r.Gateway.String())
+	}
+	if r.Interface != "" {
+		b.WriteString(" dev " + r.Interface)
+	}
+	if r.Proto != "" {
+		b.WriteString(" proto " + r.Proto)
+	}
+	fmt.Fprintf(&b, " metric %d", r.Metric)
+	if r.Expires > 0 {
+		fmt.Fprintf(&b, " expires %dsec", int(r.Expires.Seconds()))
+	}
+
+	return b.String()
+}
+
+// IPv6Routes retrieves the IPv6 routing table from /proc/net/ipv6_route.
+func IPv6Routes() ([]IPv6Route, error) {
+	return IPv6RoutesContext(context.Background())
+}
+
+// IPv6RoutesContext is like IPv6Routes but returns early if ctx is done.
+func IPv6RoutesContext(ctx context.Context) ([]IPv6Route, error) {
+	return defaultManager.IPv6Routes(ctx)
+}
+
+// IPv6Routes returns the IPv6 routes matching the manager's IPv4 routes. They are read from the source given to
+// WithSource if it reports IPv6 routes too, such as a NetlinkSource or another Manager, and from the main table
+// over netlink with WithNetlink. Otherwise /proc/net/ipv6_route is read from the manager's filesystem, the host's
+// unless WithFS was given, or ipv6_route next to the file given to WithProcPath. A source given to WithSource that
+// reports no IPv6 routes fails with ErrNoIPv6Routes unless WithFS provides them, and WithFamily(FamilyIPv4)
+// reports none. When the host's /proc is unusable, and always on Android, the host's routes of every table are
+// read over netlink instead.
+func (m *Manager) IPv6Routes(ctx context.Context) ([]IPv6Route, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if m.ipv6 != nil {
+		routes, err := m.ipv6.IPv6Routes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return m.filterIPv6(routes)
+	}
+	file := m.ipv6Path
+	if file == "" {
+		file = procIPv6RoutePath
+	}
+	if m.fsys == nil && file == procIPv6RoutePath && hostIPv6OverNetlink {
+		routes, err := NetlinkSource{}.IPv6Routes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return m.filterIPv6(routes)
+	}
+
+	routes, err := procIPv6Routes(m.fsys, file)
+	var pErr *ProcUnavailableError
+	if errors.As(err, &pErr) && m.fsys == nil && pErr.procUnusable {
+		if routes, nlErr := (NetlinkSource{}).IPv6Routes(ctx); nlErr == nil {
+			m.log().Debug("reading IPv6 routes over netlink", "reason", pErr.Reason)
+			return m.filterIPv6(routes)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return m.filterIPv6(routes)
+}
+
+// IPv6Routes reads the IPv6 routes of ipv6_route next to the source's file, /proc/net/ipv6_route by default,
+// so that they match the IPv4 routes of Routes.
+func (s ProcSource) IPv6Routes(ctx context.Context) ([]IPv6Route, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	file := procIPv6RoutePath
+	if s.Path != "" {
+		file = ipv6RoutePath(s.Path)
+	}
+
+	return procIPv6Routes(s.FS, file)
+}
+
+// ipv6RoutePath returns the path of ipv6_route in the directory of routePath, a file like /proc/net/route.
+func ipv6RoutePath(routePath string) string {
+	return path.Join(path.Dir(routePath), "ipv6_route")
+}
+
+// procIPv6Routes reads file, in the format of /proc/net/ipv6_route, from fsys. It fails with a
+// ProcUnavailableError if the file cannot be opened.
+func procIPv6Routes(fsys fs.FS, file string) ([]IPv6Route, error) {
+	f, err := openProc(fsys, file)
+	if err != nil {
+		return nil, procUnavailable(fsys, file, err)
+	}
+	defer f.Close()
+
+	routes, err := ParseIPv6Routes(f)
+	if err != nil {
+		var pErr *ParseError
+		if errors.As(err, &pErr) {
+			pErr.File = file
+		}
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// ipv6RouteSource is implemented by route sources that report IPv6 routes too, such as ProcSource, NetlinkSource
+// and Manager.
+type ipv6RouteSource interface {
+	IPv6Routes(ctx context.Context) ([]IPv6Route, error)
+}
+
+// ipv6RoutesFunc adapts a function to ipv6RouteSource.
+type ipv6RoutesFunc func(ctx context.Context) ([]IPv6Route, error)
+
+// IPv6Routes calls f.
+func (f ipv6RoutesFunc) IPv6Routes(ctx context.Context) ([]IPv6Route, error) { return f(ctx) }
+
+// ipv6Source returns where a manager configured by o reads IPv6 routes, as Manager.IPv6Routes describes: a source,
+// or nil and the file to read.
+func (o options) ipv6Source() (ipv6RouteSource, string) {
+	switch {
+	case o.family == FamilyIPv4:
+		return ipv6RoutesFunc(func(ctx context.Context) ([]IPv6Route, error) { return nil, nil }), ""
+	case o.source != nil:
+		if src, ok := o.source.(ipv6RouteSource); ok {
+			return src, ""
+		}
+		if o.fsys == nil {
+			err := fmt.Errorf("%T: %w", o.source, ErrNoIPv6Routes)
+			return ipv6RoutesFunc(func(ctx context.Context) ([]IPv6Route, error) { return nil, err }), ""
+		}
+	case o.netlink:
+		return NetlinkSource{Table: TableMain}, ""
+	case o.procPath != "":
+		return nil, ipv6RoutePath(o.procPath)
+	}
+
+	return nil, procIPv6RoutePath
+}
+
+// filterIPv6 drops the kernel-generated routes if the manager was created with WithoutCloned, and the routes
+// through interfaces that are down or without carrier if it was created with WithoutDownInterfaces.
+func (m *Manager) filterIPv6(routes []IPv6Route) ([]IPv6Route, error) {
+	if m.excludeCloned {
+		routes = slices.DeleteFunc(routes, IPv6Route.Cloned)
+	}
+	if m.links == nil {
+		return routes, nil
+	}
+	links, err := m.links()
+	if err != nil {
+		return nil, err
+	}
+	down := downLinks(links)
+
+	return slices.DeleteFunc(routes, func(r IPv6Route) bool { return down[r.Interface] }), nil
+}
+
+// ParseIPv6Routes parses IPv6 routes in the format of /proc/net/ipv6_route read from r.
+// Addresses are 32 hex digits in network byte order, prefix lengths and the other numbers are hex.
+func ParseIPv6Routes(r io.Reader) ([]IPv6Route, error) {
+	var table []IPv6Route
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 10 {
+			return nil, &ParseError{Line: n, Value: scanner.Text(), Err: errTruncatedRow}
+		}
+
+		rt, err := parseIPv6RouteRow(fields)
+		if err != nil {
+			err.Line = n
+			return nil, err
+		}
+		table = append(table, rt)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// parseIPv6RouteRow converts the columns of a /proc/net/ipv6_route row into an IPv6Route.
+func parseIPv6RouteRow(fields []string) (IPv6Route, *ParseError) {
+	dst, err := parseIPv6Prefix(fields[0], fields[1])
+	if err != nil {
+		return IPv6Route{}, &ParseError{Column: "Destination", Value: fields[0] + " " + fields[1], Err: err}
+	}
+	src, err := parseIPv6Prefix(fields[2], fields[3])
+	if err != nil {
+		return IPv6Route{}, &ParseError{Column: "Source", Value: fields[2] + " " + fields[3], Err: err}
+	}
+	gw, err := parseIPv6Hex(fields[4])
+	if err != nil {
+		return IPv6Route{}, &ParseError{Column: "Next hop", Value: fields[4], Err: err}
+	}
+	metric, err := strconv.ParseUint(fields[5], 16, 32)
+	if err != nil {
+		return IPv6Route{}, &ParseError{Column: "Metric", Value: fields[5], Err: err}
+	}
+	flags, err := strconv.ParseUint(fields[8], 16, 32)
+	if err != nil {
+		return IPv6Route{}, &ParseError{Column: "Flags", Value: fields[8], Err: err}
+	}
+
+	return IPv6Route{
+		Destination: dst,
+		Source:      src,
+		Gateway:     gw,
+		Interface:   fields[9],
+		Metric:      uint32(metric),
+		Flags:       IPv6RouteFlags(uint32(flags)),
+	}, nil
+}
+
+// parseIPv6Prefix decodes an address column and its hex prefix length.
+func parseIPv6Prefix(addr, length string) (*net.IPNet, error) {
+	ip, err := parseIPv6Hex(addr)
+	if err != nil {
+		return nil, err
+	}
+	ones, err := strconv.ParseUint(length, 16, 8)
+	if err != nil || ones > 8*net.IPv6len {
+		return nil, errBadPrefixLength
+	}
+	mask := net.CIDRMask(int(ones), 8*net.IPv6len)
+
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}, nil
+}
+
+// parseIPv6Hex decodes an address printed as 32 hex digits without separators.
+func parseIPv6Hex(s string) (net.IP, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != net.IPv6len {
+		return nil, errNotIPv6
+	}
+
+	return net.IP(b), nil
+}
@@ -0,0 +1,98 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// ipv6RouteFixture holds an RA-learned default route, the prefix route of a SLAAC address and a static route.
+const ipv6RouteFixture = `00000000000000000000000000000000 00 00000000000000000000000000000000 00 fe800000000000000000000000000001 00000400 00000001 00000000 00450003     eth0
+20010db8000000010000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     eth0
+20010db8000000020000000000000000 40 00000000000000000000000000000000 00 20010db8000000010000000000000001 00000400 00000001 00000000 00000003     eth0
+`
+
+func TestParseIPv6Routes(t *testing.T) {
+	routes, err := ParseIPv6Routes(strings.NewReader(ipv6RouteFixture))
+	if err != nil {
+		t.Fatalf("ParseIPv6Routes failed %s", err.Error())
+	}
+	if len(routes) != 3 {
+		t.Fatalf("Expected 3 routes, got %d", len(routes))
+	}
+
+	want := []string{
+		"default via fe80::1 dev eth0 metric 1024",
+		"2001:db8:0:1::/64 dev eth0 metric 256",
+		"2001:db8:0:2::/64 via 2001:db8:0:1::1 dev eth0 metric 1024",
+	}
+	for i, rt := range routes {
+		if rt.String() != want[i] {
+			t.Errorf("Expected %q, got %q", want[i], rt.String())
+		}
+	}
+	if !routes[0].LearnedFromRA() || routes[2].LearnedFromRA() {
+		t.Errorf("Expected only the default route to be learned from RA, got %v and %v", routes[0].Flags, routes[2].Flags)
+	}
+	if got := flagLetters(routes[0].Flags); got != "UGAed" {
+		t.Errorf("Expected flags UGAed on the default route, got %s", got)
+	}
+}
+
+func TestParseIPv6RoutesError(t *testing.T) {
+	_, err := ParseIPv6Routes(strings.NewReader(strings.Replace(ipv6RouteFixture, "fe80", "zz80", 1)))
+	var pErr *ParseError
+	if !errors.As(err, &pErr) || pErr.Line != 1 || pErr.Column != "Next hop" {
+		t.Errorf("Expected a next hop parse error on line 1, got %v", err)
+	}
+}
+
+func TestManagerIPv6Routes(t *testing.T) {
+	m := NewManager(WithFS(fstest.MapFS{"proc/net/ipv6_route": {Data: []byte(ipv6RouteFixture)}}))
+	routes, err := m.IPv6Routes(context.Background())
+	if err != nil {
+		t.Fatalf("IPv6Routes failed %s", err.Error())
+	}
+	if len(routes) != 3 {
+		t.Errorf("Expected 3 routes, got %d", len(routes))
+	}
+
+	_, err = NewManager(WithFS(fstest.MapFS{})).IPv6Routes(context.Background())
+	if !errors.Is(err, ErrProcUnavailable) {
+		t.Errorf("Expected ErrProcUnavailable, got %v", err)
+	}
+}
+
+func TestManagerIPv6RoutesSource(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/net/ipv6_route": {Data: []byte(ipv6RouteFixture)},
+		"snapshot/route":      {Data: []byte(procRouteFixture)},
+		"snapshot/ipv6_route": {Data: []byte(strings.SplitAfter(ipv6RouteFixture, "\n")[0])},
+	}
+	ipv4 := &memTable{routes: failoverRoutes(t)}
+
+	tests := []struct {
+		name string
+		m    *Manager
+		want int
+	}{
+		{"WithFS", NewManager(WithFS(fsys)), 3},
+		{"WithProcPath", NewManager(WithFS(fsys), WithProcPath("snapshot/route")), 1},
+		{"WithSource and WithFS", NewManager(WithSource(ipv4), WithFS(fsys)), 3},
+		{"WithSource of a Manager", NewManager(WithSource(NewManager(WithFS(fsys), WithProcPath("snapshot/route")))), 1},
+		{"WithFamily", NewManager(WithFS(fsys), WithFamily(FamilyIPv4)), 0},
+	}
+	for _, tt := range tests {
+		routes, err := tt.m.IPv6Routes(context.Background())
+		if err != nil || len(routes) != tt.want {
+			t.Errorf("%s: IPv6Routes = %d routes, %v, want %d", tt.name, len(routes), err, tt.want)
+		}
+	}
+
+	// The host's IPv6 routes would not match the routes of the source.
+	if _, err := NewManager(WithSource(ipv4)).IPv6Routes(context.Background()); !errors.Is(err, ErrNoIPv6Routes) {
+		t.Errorf("Expected ErrNoIPv6Routes for a source without IPv6 routes, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package routing
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"iter"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// procRoutePath is the location of the kernel's IPv4 routing table.
+const procRoutePath = "/proc/net/route"
+
+// Routes returns an iterator over the entries of /proc/net/route.
+// Rows are read and parsed lazily, so breaking out of the loop early stops reading the file.
+// A read or parse error is yielded once with a zero RoutingTable and ends the iteration.
+func Routes() iter.Seq2[RoutingTable, error] {
+	return procRoutes(nil, procRoutePath, nil)
+}
+
+// openProc opens the file at path from fsys, or from the host's filesystem if fsys is nil.
+// Paths are absolute like "/proc/net/route"; the leading slash is dropped for fsys, whose paths are unrooted.
+func openProc(fsys fs.FS, path string) (fs.File, error) {
+	if fsys == nil {
+		return os.Open(path)
+	}
+
+	return fsys.Open(strings.TrimPrefix(path, "/"))
+}
+
+// procUnavailable returns the ProcUnavailableError for err, the failure to open path from fsys, telling why the
+// environment lacks the file. Files outside /proc, such as captures from other hosts, get no explanation.
+func procUnavailable(fsys fs.FS, path string, err error) error {
+	e := &ProcUnavailableError{Path: path, Err: err}
+	if !strings.HasPrefix(path, "/proc/") {
+		return e
+	}
+	exists := func(name string) bool {
+		_, err := fs.Stat(rootFS(fsys), name)
+		return err == nil
+	}
+
+	switch {
+	case fsys == nil && runtime.GOOS != "linux":
+		e.Reason, e.procUnusable = "/proc/net only exists on Linux", true
+	case errors.Is(err, fs.ErrPermission):
+		e.Reason, e.procUnusable = "access denied, as when /proc is mounted with hidepid or confined by a security policy", true
+	case !errors.Is(err, fs.ErrNotExist):
+	case !exists("proc/self"):
+		e.Reason, e.procUnusable = "/proc is not mounted, as in scratch or distroless containers", true
+	case !exists("proc/net"):
+		e.Reason, e.procUnusable = "/proc has no net directory, as when it is mounted with subset=pid", true
+	default:
+		e.Reason = "the kernel does not provide it, e.g. because the protocol is disabled"
+	}
+
+	return e
+}
+
+// rootFS returns fsys, or the host's root directory if fsys is nil, for looking up files by unrooted glob patterns.
+func rootFS(fsys fs.FS) fs.FS {
+	if fsys == nil {
+		return os.DirFS("/")
+	}
+
+	return fsys
+}
+
+// procRoutes returns an iterator over the routing table file at path in fsys reporting recoverable anomalies to warn.
+func procRoutes(fsys fs.FS, path string, warn func(ParseWarning)) iter.Seq2[RoutingTable, error] {
+	return func(yield func(RoutingTable, error) bool) {
+		f, fErr := openProc(fsys, path)
+		if fErr != nil {
+			yield(RoutingTable{}, procUnavailable(fsys, path, fErr)) // Yields an error if the file cannot be opened.
+			return
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		var header *procHeader // Taken from the first row.
+		line := 0
+		for scanner.Scan() {
+			line++
+			v := scanner.Text()
+			if header == nil {
+				h := newProcHeader(splitHeader(v))
+				for i, d := range h.names {
+					if h.columns[i] == colUnknown && warn != nil {
+						warn(ParseWarning{File: path, Line: line, Column: d, Err: errUnknownColumn})
+					}
+				}
+				if err := h.validate(); err != nil {
+					pErr := err.(*ParseError)
+					pErr.File, pErr.Line = path, line
+					yield(RoutingTable{}, err)
+					return
+				}
+				header = &h
+				continue
+			}
+			if strings.TrimSpace(v) == "" {
+				continue // Skip blank lines.
+			}
+
+			rowWarn := warn
+			if warn != nil {
+				rowWarn = func(w ParseWarning) {
+					w.File, w.Line = path, line
+					warn(w)
+				}
+			}
+
+			rtRow, err := parseRouteRow(*header, v, rowWarn)
+			if err != nil {
+				var pErr *ParseError
+				if errors.As(err, &pErr) {
+					pErr.File, pErr.Line = path, line
+				}
+				yield(RoutingTable{}, err)
+				return
+			}
+			if !yield(rtRow, nil) {
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			yield(RoutingTable{}, fmt.Errorf("%w: %w", ErrProcUnavailable, err)) // Yields an error if reading the file fails.
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package routing
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRoutesStopsEarly(t *testing.T) {
+	seen := 0
+	for rt, err := range procRoutes(procFS, procRoutePath, nil) {
+		if err != nil {
+			t.Fatalf("Routes failed %s", err.Error())
+		}
+		if rt.Interface == "" {
+			t.Errorf("Expected an interface on %+v", rt)
+		}
+		seen++
+		break
+	}
+
+	if seen != 1 {
+		t.Errorf("Expected to stop after one route, saw %d", seen)
+	}
+}
+
+func TestProcRoutesColumns(t *testing.T) {
+	reordered := fstest.MapFS{"proc/net/route": {Data: []byte("Mask\tIface\tFlags\tGateway\tDestination\n" +
+		"00FFFFFF" + "\teth0\t0001\t00000000\t" + "000200C0" + "\n" +
+		"00000000\teth0\n")}}
+	var warnings []ParseWarning
+	var routes []RoutingTable
+	for rt, err := range procRoutes(reordered, procRoutePath, CollectWarnings(&warnings)) {
+		if err != nil {
+			t.Fatalf("procRoutes failed %s", err.Error())
+		}
+		routes = append(routes, rt)
+	}
+	if len(routes) != 2 || routes[0].String() != "192.0.2.0/24 dev eth0 scope link" {
+		t.Errorf("Unexpected routes %v", routes)
+	}
+	if len(warnings) != 1 || !errors.Is(warnings[0].Err, errTruncatedRow) || warnings[0].Line != 3 {
+		t.Errorf("Expected a truncated row warning for line 3, got %v", warnings)
+	}
+
+	missing := fstest.MapFS{"proc/net/route": {Data: []byte("Iface\tDestination\tGateway\tFlags\neth0\t00000000\t00000000\t0001\n")}}
+	for _, err := range procRoutes(missing, procRoutePath, nil) {
+		var pErr *ParseError
+		if !errors.As(err, &pErr) || pErr.Column != "Mask" || pErr.Line != 1 {
+			t.Fatalf("Expected a missing Mask column error, got %v", err)
+		}
+		if err.Error() != "/proc/net/route: line 1, column Mask: missing from header" {
+			t.Errorf("Unexpected message %q", err.Error())
+		}
+	}
+}
+
+func TestProcUnavailable(t *testing.T) {
+	tests := []struct {
+		name     string
+		fsys     fstest.MapFS
+		path     string
+		unusable bool
+		reason   string
+	}{
+		{"not mounted", fstest.MapFS{}, procRoutePath, true, "/proc is not mounted"},
+		{"subset=pid", fstest.MapFS{"proc/self/status": {}}, procRoutePath, true, "subset=pid"},
+		{"file missing", fstest.MapFS{"proc/self/status": {}, "proc/net/dev": {}}, procIPv6RoutePath, false, "does not provide it"},
+		{"capture file", fstest.MapFS{}, "/srv/captures/route", false, ""},
+	}
+	for _, tt := range tests {
+		_, err := tt.fsys.Open(strings.TrimPrefix(tt.path, "/"))
+		err = procUnavailable(tt.fsys, tt.path, err)
+
+		var pErr *ProcUnavailableError
+		if !errors.As(err, &pErr) || !errors.Is(err, ErrProcUnavailable) || !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("%s: expected a ProcUnavailableError matching fs.ErrNotExist, got %v", tt.name, err)
+		}
+		if pErr.procUnusable != tt.unusable || !strings.Contains(pErr.Reason, tt.reason) || tt.reason == "" && pErr.Reason != "" {
+			t.Errorf("%s: unexpected reason %q, unusable %t", tt.name, pErr.Reason, pErr.procUnusable)
+		}
+		if !strings.HasPrefix(err.Error(), "routing table unavailable: "+tt.path+": ") {
+			t.Errorf("%s: unexpected message %q", tt.name, err)
+		}
+	}
+
+	err := procUnavailable(nil, procRoutePath, &fs.PathError{Op: "open", Path: procRoutePath, Err: fs.ErrPermission})
+	var pErr *ProcUnavailableError
+	if !errors.As(err, &pErr) || !pErr.procUnusable || !strings.Contains(pErr.Reason, "hidepid") {
+		t.Errorf("Expected a permission error to blame hidepid, got %v", err)
+	}
+}
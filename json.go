@@ -0,0 +1,297 @@
+package routing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+)
+
+// routeJSON is the wire representation of a RoutingTable entry.
+// Addresses are rendered in dotted notation and the destination is also given as a CIDR prefix.
+type routeJSON struct {
+	Interface   string            `json:"interface"`
+	DeviceKind  string            `json:"device_kind,omitempty"`
+	Destination string            `json:"destination"`
+	Prefix      string            `json:"prefix"`
+	Gateway     string            `json:"gateway"`
+	GatewayName string            `json:"gateway_name,omitempty"`
+	Mask        string            `json:"mask"`
+	Flags       []RouteFlag       `json:"flags"`
+	RefCnt      int8              `json:"ref_cnt"`
+	Use         int8              `json:"use"`
+	Metric      uint32            `json:"metric"`
+	Window      int8              `json:"window"`
+	IRTT        int8              `json:"irtt"`
+	Proto       string            `json:"proto,omitempty"`
+	Scope       string            `json:"scope,omitempty"`
+	PrefSrc     string            `json:"prefsrc,omitempty"`
+	Realm       string            `json:"realm,omitempty"`
+	FromRealm   string            `json:"from_realm,omitempty"`
+	TOS         uint8             `json:"tos,omitempty"`
+	Table       int               `json:"table"`
+	Nexthops    []Nexthop         `json:"nexthops,omitempty"`
+	NexthopID   uint32            `json:"nhid,omitempty"`
+	SRv6        *SRv6Encap        `json:"srv6,omitempty"`
+	Type        string            `json:"type"`
+	OnLink      bool              `json:"onlink,omitempty"`
+	Metrics     *RouteMetrics     `json:"metrics,omitempty"`
+	Raw         map[string]string `json:"raw,omitempty"`
+}
+
+// nexthopJSON mirrors Nexthop with lower_snake field names.
+type nexthopJSON struct {
+	Gateway   string `json:"gateway"`
+	Interface string `json:"interface"`
+	Weight    int    `json:"weight"`
+	OnLink    bool   `json:"onlink,omitempty"`
+}
+
+// MarshalJSON encodes the nexthop with gateway, interface, weight and onlink fields.
+func (nh Nexthop) MarshalJSON() ([]byte, error) {
+	return json.Marshal(nexthopJSON(nh))
+}
+
+// UnmarshalJSON decodes a nexthop produced by MarshalJSON.
+func (nh *Nexthop) UnmarshalJSON(data []byte) error {
+	var v nexthopJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*nh = Nexthop(v)
+
+	return nil
+}
+
+// metricsJSON mirrors RouteMetrics with lower_snake field names and times in milliseconds.
+type metricsJSON struct {
+	MTU        uint32 `json:"mtu,omitempty"`
+	Window     uint32 `json:"window,omitempty"`
+	RTT        int64  `json:"rtt_ms,omitempty"`
+	RTTVar     int64  `json:"rttvar_ms,omitempty"`
+	SSThresh   uint32 `json:"ssthresh,omitempty"`
+	CWnd       uint32 `json:"cwnd,omitempty"`
+	AdvMSS     uint32 `json:"advmss,omitempty"`
+	Reordering uint32 `json:"reordering,omitempty"`
+	HopLimit   uint32 `json:"hoplimit,omitempty"`
+	InitCwnd   uint32 `json:"initcwnd,omitempty"`
+	RTOMin     int64  `json:"rto_min_ms,omitempty"`
+	InitRwnd   uint32 `json:"initrwnd,omitempty"`
+	QuickAck   bool   `json:"quickack,omitempty"`
+	CongCtl    string `json:"congctl,omitempty"`
+}
+
+// MarshalJSON encodes the set metrics, giving times in milliseconds.
+func (m RouteMetrics) MarshalJSON() ([]byte, error) {
+	return json.Marshal(metricsJSON{
+		MTU: m.MTU, Window: m.Window, RTT: m.RTT.Milliseconds(), RTTVar: m.RTTVar.Milliseconds(),
+		SSThresh: m.SSThresh, CWnd: m.CWnd, AdvMSS: m.AdvMSS, Reordering: m.Reordering, HopLimit: m.HopLimit,
+		InitCwnd: m.InitCwnd, RTOMin: m.RTOMin.Milliseconds(), InitRwnd: m.InitRwnd, QuickAck: m.QuickAck, CongCtl: m.CongCtl,
+	})
+}
+
+// UnmarshalJSON decodes metrics produced by MarshalJSON.
+func (m *RouteMetrics) UnmarshalJSON(data []byte) error {
+	var v metricsJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*m = RouteMetrics{
+		MTU: v.MTU, Window: v.Window, RTT: time.Duration(v.RTT) * time.Millisecond, RTTVar: time.Duration(v.RTTVar) * time.Millisecond,
+		SSThresh: v.SSThresh, CWnd: v.CWnd, AdvMSS: v.AdvMSS, Reordering: v.Reordering, HopLimit: v.HopLimit,
+		InitCwnd: v.InitCwnd, RTOMin: time.Duration(v.RTOMin) * time.Millisecond, InitRwnd: v.InitRwnd, QuickAck: v.QuickAck, CongCtl: v.CongCtl,
+	}
+
+	return nil
+}
+
+// srv6JSON mirrors SRv6Encap with lower_snake field names.
+type srv6JSON struct {
+	Local     bool     `json:"local,omitempty"`
+	Mode      string   `json:"mode,omitempty"`
+	Segments  []net.IP `json:"segments,omitempty"`
+	Action    string   `json:"action,omitempty"`
+	Table     int      `json:"table,omitempty"`
+	VRFTable  int      `json:"vrftable,omitempty"`
+	Nexthop   net.IP   `json:"nexthop,omitempty"`
+	Interface string   `json:"interface,omitempty"`
+}
+
+// MarshalJSON encodes the set fields of the encapsulation.
+func (e SRv6Encap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(srv6JSON(e))
+}
+
+// UnmarshalJSON decodes an encapsulation produced by MarshalJSON.
+func (e *SRv6Encap) UnmarshalJSON(data []byte) error {
+	var v srv6JSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*e = SRv6Encap(v)
+
+	return nil
+}
+
+// routeFlagJSON mirrors RouteFlag with lower_snake field names.
+type routeFlagJSON struct {
+	Letter string `json:"letter"`
+	Bit    int16  `json:"bit"`
+	Name   string `json:"name"`
+	Desc   string `json:"description"`
+}
+
+// MarshalJSON encodes the flag as an object with letter, bit, name and description fields.
+func (f RouteFlag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(routeFlagJSON(f))
+}
+
+// UnmarshalJSON decodes a flag from either its object form or a bare letter such as "U".
+// Bare letters are resolved against the known route flags, preferring IPv4 ones for letters both families use.
+func (f *RouteFlag) UnmarshalJSON(data []byte) error {
+	var letter string
+	if err := json.Unmarshal(data, &letter); err == nil {
+		for _, rf := range slices.Concat(ipv4Flags(), ipv6RouteFlags) {
+			if rf.Letter == letter {
+				*f = rf
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown route flag %q", letter)
+	}
+
+	var v routeFlagJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = RouteFlag(v)
+
+	return nil
+}
+
+// MarshalJSON encodes the route with dotted addresses, a CIDR prefix and flags ordered by bit.
+func (rt RoutingTable) MarshalJSON() ([]byte, error) {
+	v := routeJSON{
+		Interface:   rt.Interface,
+		Gateway:     rt.Gateway,
+		GatewayName: rt.GatewayName,
+		Flags:       sortedFlags(rt.Flags),
+		RefCnt:      rt.RefCnt,
+		Use:         rt.Use,
+		Metric:      rt.Metric,
+		Window:      rt.Window,
+		IRTT:        rt.IRTT,
+		Proto:       rt.Proto,
+		Scope:       rt.Scope,
+		PrefSrc:     rt.PrefSrc,
+		Realm:       rt.Realm,
+		FromRealm:   rt.FromRealm,
+		TOS:         rt.TOS,
+		Table:       rt.Table,
+		Nexthops:    rt.Nexthops,
+		NexthopID:   rt.NexthopID,
+		SRv6:        rt.SRv6,
+		Type:        RouteTypeUnicast.String(),
+		OnLink:      rt.OnLink,
+		Raw:         rt.Raw.Map(),
+	}
+	if rt.DeviceKind != DeviceUnknown {
+		v.DeviceKind = rt.DeviceKind.String()
+	}
+	if !rt.Metrics.IsZero() {
+		v.Metrics = &rt.Metrics
+	}
+	if !rt.Type.isUnicast() {
+		v.Type = rt.Type.String()
+	}
+
+	dst, mask, ones, err := decodeDestination(rt)
+	if err != nil {
+		return nil, err
+	}
+
+	v.Destination = dst.String()
+	v.Mask = mask.String()
+	v.Prefix = fmt.Sprintf("%s/%d", dst, ones)
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes a route produced by MarshalJSON.
+// The destination and mask may be given either as dotted addresses or through the prefix field.
+func (rt *RoutingTable) UnmarshalJSON(data []byte) error {
+	var v routeJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	out := RoutingTable{
+		Interface:   v.Interface,
+		Gateway:     v.Gateway,
+		GatewayName: v.GatewayName,
+		Flags:       make(map[string]RouteFlag, len(v.Flags)),
+		RefCnt:      v.RefCnt,
+		Use:         v.Use,
+		Metric:      v.Metric,
+		Window:      v.Window,
+		IRTT:        v.IRTT,
+		Proto:       v.Proto,
+		Scope:       v.Scope,
+		PrefSrc:     v.PrefSrc,
+		Realm:       v.Realm,
+		FromRealm:   v.FromRealm,
+		TOS:         v.TOS,
+		Table:       v.Table,
+		Nexthops:    v.Nexthops,
+		NexthopID:   v.NexthopID,
+		SRv6:        v.SRv6,
+		Type:        RouteTypeUnicast,
+		OnLink:      v.OnLink,
+		Raw:         rawColumnsFromMap(v.Raw),
+	}
+	if v.Metrics != nil {
+		out.Metrics = *v.Metrics
+	}
+	if v.DeviceKind != "" {
+		kind, ok := ParseDeviceKind(v.DeviceKind)
+		if !ok {
+			return fmt.Errorf("unknown device kind %q", v.DeviceKind)
+		}
+		out.DeviceKind = kind
+	}
+	if v.Type != "" {
+		typ, ok := ParseRouteType(v.Type)
+		if !ok {
+			return fmt.Errorf("unknown route type %q", v.Type)
+		}
+		out.Type = typ
+	}
+
+	dst := net.ParseIP(v.Destination)
+	mask := net.ParseIP(v.Mask)
+	if v.Prefix != "" {
+		_, ipNet, err := net.ParseCIDR(v.Prefix)
+		if err != nil {
+			return fmt.Errorf("prefix %q: %w", v.Prefix, err)
+		}
+		dst = ipNet.IP
+		mask = net.IP(ipNet.Mask)
+	}
+	if dst.To4() == nil {
+		return fmt.Errorf("destination %q is not an IPv4 address", v.Destination)
+	}
+	if mask.To4() == nil {
+		return fmt.Errorf("mask %q is not an IPv4 address", v.Mask)
+	}
+	out.Destination = formatHexIP(dst)
+	out.Mask = formatHexIP(mask)
+
+	for _, f := range v.Flags {
+		out.Flags[f.Letter] = f
+	}
+
+	*rt = out
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package routing
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRoutingTableJSONRoundTrip(t *testing.T) {
+	route := RoutingTable{
+		Interface:   "eth0",
+		Destination: "0002A8C0",
+		Gateway:     "0.0.0.0",
+		Flags:       computeRouteFlag(0x1),
+		Metric:      100,
+		Mask:        "00FFFFFF",
+		Type:        RouteTypeUnicast,
+	}
+
+	b, err := json.Marshal(route)
+	if err != nil {
+		t.Fatalf("Marshal failed %s", err.Error())
+	}
+
+	for _, want := range []string{`"destination":"192.168.2.0"`, `"prefix":"192.168.2.0/24"`, `"mask":"255.255.255.0"`, `"ref_cnt":0`, `"letter":"U"`, `"type":"unicast"`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("Marshal output %s does not contain %s", b, want)
+		}
+	}
+
+	var decoded RoutingTable
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(route, decoded) {
+		t.Errorf("Round trip mismatch %+v %+v", route, decoded)
+	}
+}
+
+func TestRouteFlagUnmarshalLetter(t *testing.T) {
+	var rf RouteFlag
+	if err := json.Unmarshal([]byte(`"G"`), &rf); err != nil {
+		t.Fatalf("Unmarshal failed %s", err.Error())
+	}
+
+	if rf.Name != "Gateway" || rf.Bit != 0x2 {
+		t.Errorf("Unexpected flag %+v", rf)
+	}
+}
+
+func TestRouteFlagUnmarshalIPv6Letter(t *testing.T) {
+	var rf RouteFlag
+	if err := json.Unmarshal([]byte(`"e"`), &rf); err != nil {
+		t.Fatalf("Unmarshal failed %s", err.Error())
+	}
+
+	if rf.Name != "Expires" || rf.Bit != FlagExpires {
+		t.Errorf("Unexpected flag %+v", rf)
+	}
+}
+
+func TestRoutingTableJSONGatewayName(t *testing.T) {
+	rt := RoutingTable{Destination: "00000000", Mask: "00000000", Gateway: "192.0.2.1", GatewayName: "router.example.net", Flags: computeRouteFlag(FlagUp | FlagGateway)}
+	data, err := json.Marshal(rt)
+	if err != nil {
+		t.Fatalf("Marshal failed %s", err.Error())
+	}
+	if !strings.Contains(string(data), `"gateway_name":"router.example.net"`) {
+		t.Errorf("Expected a gateway_name field in %s", data)
+	}
+
+	var back RoutingTable
+	if err := json.Unmarshal(data, &back); err != nil || back.GatewayName != rt.GatewayName {
+		t.Errorf("Unmarshal = %+v, %v", back, err)
+	}
+}
@@ -0,0 +1,24 @@
+package routing
+
+import (
+	"context"
+	"net"
+)
+
+// RouteGetOptions narrows a kernel route lookup the way the optional arguments of `ip route get` do.
+// Zero fields are left for the kernel to choose.
+type RouteGetOptions struct {
+	Src  net.IP // Source address of the traffic, which policy rules may match on.
+	IIF  string // Input interface, to look up the route of forwarded rather than locally sent traffic.
+	OIF  string // Output interface the traffic is bound to, as with SO_BINDTODEVICE.
+	Mark uint32 // Firewall mark of the traffic, matched by fwmark policy rules.
+	TOS  uint8  // Type of service of the traffic.
+}
+
+// KernelRouteTo asks the kernel which route it would use for traffic to dst, like `ip route get`.
+// Unlike LookupRoute it applies policy rules, marks and every routing table, so the answer matches what the kernel does.
+// The returned route is a host route for dst whose Interface, Gateway and PrefSrc are the chosen output interface,
+// next hop and source address. It is only available on Linux and returns ErrNotSupported elsewhere.
+func KernelRouteTo(dst net.IP, opts *RouteGetOptions) (RoutingTable, error) {
+	return KernelRouteToContext(context.Background(), dst, opts)
+}
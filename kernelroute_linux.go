@@ -0,0 +1,75 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// rtaMark is the RTA_MARK attribute from linux/rtnetlink.h, carrying a firewall mark.
+const rtaMark = 16
+
+// KernelRouteToContext is like KernelRouteTo but returns early if ctx is done.
+func KernelRouteToContext(ctx context.Context, dst net.IP, opts *RouteGetOptions) (RoutingTable, error) {
+	msg, err := routeGetMsg(dst, opts)
+	if err != nil {
+		return RoutingTable{}, err
+	}
+
+	replies, err := netlinkRequest(ctx, syscall.RTM_GETROUTE, 0, msg)
+	if err != nil {
+		return RoutingTable{}, fmt.Errorf("route lookup for %s: %w", dst, err)
+	}
+	names := loadRouteNames()
+	for _, m := range replies {
+		if m.Header.Type != syscall.RTM_NEWROUTE {
+			continue
+		}
+		if rt, ok := parseRouteMsg(m.Data, names); ok {
+			rt.Proto = "" // The answer is a lookup result rather than an installed route, so it has no protocol.
+			return rt, nil
+		}
+	}
+
+	return RoutingTable{}, fmt.Errorf("route lookup for %s: no route in the kernel's answer", dst)
+}
+
+// routeGetMsg encodes the payload of an RTM_GETROUTE request for dst.
+func routeGetMsg(dst net.IP, opts *RouteGetOptions) ([]byte, error) {
+	if dst.To4() == nil {
+		return nil, &ParseError{Column: "dst", Value: dst.String(), Err: errNotIPv4}
+	}
+	if opts == nil {
+		opts = &RouteGetOptions{}
+	}
+
+	b := make([]byte, sizeofRtMsg)
+	b[0], b[1], b[3] = syscall.AF_INET, 32, opts.TOS
+	b = append(b, nlAttr(syscall.RTA_DST, dst.To4())...)
+	if opts.Src != nil {
+		if opts.Src.To4() == nil {
+			return nil, &ParseError{Column: "src", Value: opts.Src.String(), Err: errNotIPv4}
+		}
+		b[2] = 32
+		b = append(b, nlAttr(syscall.RTA_SRC, opts.Src.To4())...)
+	}
+	for _, dev := range []struct {
+		attr uint16
+		name string
+	}{{syscall.RTA_IIF, opts.IIF}, {syscall.RTA_OIF, opts.OIF}} {
+		if dev.name == "" {
+			continue
+		}
+		index, err := interfaceIndex(dev.name)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, nlAttr(dev.attr, nlUint32Bytes(uint32(index)))...)
+	}
+	if opts.Mark != 0 {
+		b = append(b, nlAttr(rtaMark, nlUint32Bytes(opts.Mark))...)
+	}
+
+	return b, nil
+}
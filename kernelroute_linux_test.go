@@ -0,0 +1,42 @@
+package routing
+
+import (
+	"net"
+	"testing"
+)
+
+func TestKernelRouteToLoopback(t *testing.T) {
+	rt, err := KernelRouteTo(net.IPv4(127, 0, 0, 1), &RouteGetOptions{Mark: 1})
+	if err != nil {
+		t.Fatalf("KernelRouteTo failed %s", err.Error())
+	}
+	if rt.Type != RouteTypeLocal || rt.Interface != "lo" || rt.PrefSrc != "127.0.0.1" {
+		t.Errorf("Unexpected route %q", rt)
+	}
+}
+
+func TestKernelRouteToMatchesLookup(t *testing.T) {
+	routes, err := ProcSource{}.Routes(t.Context())
+	if err != nil {
+		t.Fatalf("Calling routing library failed %s", err.Error())
+	}
+	dst := net.IPv4(198, 51, 100, 1)
+	want, ok := LookupRoute(routes, dst)
+	if !ok {
+		t.Skip("No route covers the test address")
+	}
+
+	got, err := KernelRouteTo(dst, nil)
+	if err != nil {
+		t.Fatalf("KernelRouteTo failed %s", err.Error())
+	}
+	if got.Interface != want.Interface || got.Gateway != want.Gateway || got.Destination != formatHexIP(dst) {
+		t.Errorf("Kernel chose %q, userland lookup %q", got, want)
+	}
+}
+
+func TestRouteGetMsgRejectsIPv6(t *testing.T) {
+	if _, err := routeGetMsg(net.ParseIP("2001:db8::1"), nil); err == nil {
+		t.Errorf("Expected an error for an IPv6 destination")
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package routing
+
+import (
+	"context"
+	"net"
+)
+
+// KernelRouteToContext is like KernelRouteTo but returns early if ctx is done.
+// Netlink is only available on Linux, so it always returns ErrNotSupported here.
+func KernelRouteToContext(ctx context.Context, dst net.IP, opts *RouteGetOptions) (RoutingTable, error) {
+	return RoutingTable{}, ErrNotSupported
+}
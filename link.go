@@ -0,0 +1,171 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"sort"
+)
+
+// Link is a network interface and its state.
+type Link struct {
+	Index   int              // The interface index, which stays the same when the interface is renamed.
+	Name    string           // The interface name, e.g. "eth0".
+	HWAddr  net.HardwareAddr // The link-layer address; empty for interfaces without one.
+	MTU     int              // The maximum transmission unit.
+	Up      bool             // The interface is administratively up.
+	Carrier bool             // The interface is operational, e.g. its cable is plugged in.
+}
+
+// LinkEvent is a change of a network interface.
+type LinkEvent struct {
+	Link    Link   // The interface as it is now, or as it was before it was removed.
+	Removed bool   // The interface was removed.
+	OldName string // The previous name of a renamed interface; empty otherwise.
+}
+
+// Links returns the network interfaces of the host and their state.
+func Links() ([]Link, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]Link, len(ifaces))
+	for i, ifi := range ifaces {
+		links[i] = linkFromInterface(ifi)
+	}
+
+	return links, nil
+}
+
+// linkFromInterface converts an interface from the net package.
+func linkFromInterface(ifi net.Interface) Link {
+	return Link{
+		Index:   ifi.Index,
+		Name:    ifi.Name,
+		HWAddr:  ifi.HardwareAddr,
+		MTU:     ifi.MTU,
+		Up:      ifi.Flags&net.FlagUp != 0,
+		Carrier: ifi.Flags&net.FlagRunning != 0,
+	}
+}
+
+// equal reports whether two observations of an interface are the same.
+func (l Link) equal(o Link) bool {
+	return l.Index == o.Index && l.Name == o.Name && bytes.Equal(l.HWAddr, o.HWAddr) &&
+		l.MTU == o.MTU && l.Up == o.Up && l.Carrier == o.Carrier
+}
+
+// linkUpdate is a notification about one interface.
+type linkUpdate struct {
+	link    Link
+	removed bool
+}
+
+// linkUpdateSource delivers interface notifications to a LinkWatcher.
+type linkUpdateSource interface {
+	// receive blocks until notifications arrive. With full set, the updates list every interface,
+	// as after notifications were lost, and interfaces missing from them have been removed.
+	receive(ctx context.Context) (updates []linkUpdate, full bool, err error)
+	Close() error
+}
+
+// LinkWatcher reports changes of the host's network interfaces: interfaces going up or down,
+// gaining or losing carrier, being renamed, added or removed. It is notified by the kernel rather than polling.
+// A LinkWatcher is not safe for concurrent use.
+type LinkWatcher struct {
+	source  linkUpdateSource
+	links   map[int]Link // The last known state of each interface, keyed by index.
+	pending []LinkEvent  // Events received but not yet returned by Next.
+}
+
+// WatchLinks starts watching the host's network interfaces through RTNLGRP_LINK notifications.
+// Only changes after it returns are reported; Links gives the state to start from.
+// It is only available on Linux and returns ErrNotSupported elsewhere.
+func WatchLinks() (*LinkWatcher, error) {
+	source, err := newLinkUpdateSource()
+	if err != nil {
+		return nil, err
+	}
+	links, err := Links()
+	if err != nil {
+		source.Close()
+		return nil, err
+	}
+
+	return newLinkWatcher(source, links), nil
+}
+
+// newLinkWatcher returns a LinkWatcher receiving from source, starting from the given state.
+func newLinkWatcher(source linkUpdateSource, links []Link) *LinkWatcher {
+	w := &LinkWatcher{source: source, links: make(map[int]Link, len(links))}
+	for _, l := range links {
+		w.links[l.Index] = l
+	}
+
+	return w
+}
+
+// Next blocks until an interface changes and returns the change. It returns ctx.Err() once ctx is done.
+// Notifications that do not change the reported state, such as statistics updates, are skipped.
+func (w *LinkWatcher) Next(ctx context.Context) (LinkEvent, error) {
+	for len(w.pending) == 0 {
+		updates, full, err := w.source.receive(ctx)
+		if err != nil {
+			return LinkEvent{}, err
+		}
+		w.apply(updates, full)
+	}
+
+	ev := w.pending[0]
+	w.pending = w.pending[1:]
+
+	return ev, nil
+}
+
+// Close stops watching.
+func (w *LinkWatcher) Close() error {
+	return w.source.Close()
+}
+
+// apply records updates and queues an event for every interface whose state changed.
+func (w *LinkWatcher) apply(updates []linkUpdate, full bool) {
+	seen := make(map[int]bool, len(updates))
+	for _, u := range updates {
+		old, known := w.links[u.link.Index]
+		if u.removed {
+			if known {
+				delete(w.links, u.link.Index)
+				w.pending = append(w.pending, LinkEvent{Link: old, Removed: true})
+			}
+			continue
+		}
+
+		seen[u.link.Index] = true
+		w.links[u.link.Index] = u.link
+		if known && old.equal(u.link) {
+			continue
+		}
+		ev := LinkEvent{Link: u.link}
+		if known && old.Name != u.link.Name {
+			ev.OldName = old.Name
+		}
+		w.pending = append(w.pending, ev)
+	}
+	if !full {
+		return
+	}
+
+	var gone []Link
+	for index, l := range w.links {
+		if !seen[index] {
+			gone = append(gone, l)
+		}
+	}
+	sort.Slice(gone, func(i, j int) bool { return gone[i].Index < gone[j].Index })
+	for _, l := range gone {
+		delete(w.links, l.Index)
+		w.pending = append(w.pending, LinkEvent{Link: l, Removed: true})
+	}
+}
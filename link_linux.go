@@ -0,0 +1,94 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"syscall"
+)
+
+// netlinkLinkSource receives RTNLGRP_LINK notifications.
+type netlinkLinkSource struct {
+	sub *nlSubscription
+}
+
+// newLinkUpdateSource subscribes to the kernel's interface notifications.
+func newLinkUpdateSource() (linkUpdateSource, error) {
+	sub, err := netlinkSubscribe(syscall.RTNLGRP_LINK)
+	if err != nil {
+		return nil, err
+	}
+
+	return netlinkLinkSource{sub: sub}, nil
+}
+
+// receive decodes the next batch of RTM_NEWLINK and RTM_DELLINK notifications.
+// When notifications were lost, it lists every interface instead.
+func (s netlinkLinkSource) receive(ctx context.Context) ([]linkUpdate, bool, error) {
+	msgs, err := s.sub.Receive(ctx)
+	if errors.Is(err, errNotificationsLost) {
+		links, err := Links()
+		if err != nil {
+			return nil, false, err
+		}
+		updates := make([]linkUpdate, len(links))
+		for i, l := range links {
+			updates[i] = linkUpdate{link: l}
+		}
+		return updates, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var updates []linkUpdate
+	for _, m := range msgs {
+		if m.Header.Type != syscall.RTM_NEWLINK && m.Header.Type != syscall.RTM_DELLINK {
+			continue
+		}
+		if l, ok := parseLinkMsg(m.Data); ok {
+			updates = append(updates, linkUpdate{link: l, removed: m.Header.Type == syscall.RTM_DELLINK})
+		}
+	}
+
+	return updates, false, nil
+}
+
+// Close closes the netlink socket.
+func (s netlinkLinkSource) Close() error {
+	return s.sub.Close()
+}
+
+// parseLinkMsg decodes the payload of an RTM_NEWLINK or RTM_DELLINK message: a struct ifinfomsg and its attributes.
+func parseLinkMsg(b []byte) (Link, bool) {
+	if len(b) < syscall.SizeofIfInfomsg {
+		return Link{}, false
+	}
+	flags := binary.NativeEndian.Uint32(b[8:12])
+	attrs := netlinkAttrs(b[syscall.SizeofIfInfomsg:])
+
+	l := Link{
+		Index:   int(int32(binary.NativeEndian.Uint32(b[4:8]))),
+		Name:    nlString(attrs[syscall.IFLA_IFNAME]),
+		MTU:     int(nlUint32(attrs[syscall.IFLA_MTU])),
+		Up:      flags&syscall.IFF_UP != 0,
+		Carrier: flags&syscall.IFF_RUNNING != 0,
+	}
+	if v := attrs[syscall.IFLA_ADDRESS]; len(v) > 0 && !isZeroBytes(v) {
+		l.HWAddr = net.HardwareAddr(v)
+	}
+
+	return l, true
+}
+
+// isZeroBytes reports whether every byte of b is zero, as in the address of the loopback interface.
+func isZeroBytes(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+
+	return true
+}
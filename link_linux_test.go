@@ -0,0 +1,54 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestParseLinkMsg(t *testing.T) {
+	msg := make([]byte, syscall.SizeofIfInfomsg)
+	binary.NativeEndian.PutUint32(msg[4:8], 7)
+	binary.NativeEndian.PutUint32(msg[8:12], syscall.IFF_UP|syscall.IFF_BROADCAST)
+	msg = append(msg, nlAttr(syscall.IFLA_IFNAME, []byte("eth1\x00"))...)
+	msg = append(msg, nlAttr(syscall.IFLA_MTU, nlUint32Bytes(9000))...)
+	msg = append(msg, nlAttr(syscall.IFLA_ADDRESS, []byte{0x02, 0, 0, 0, 0, 0x01})...)
+
+	l, ok := parseLinkMsg(msg)
+	if !ok {
+		t.Fatal("parseLinkMsg failed")
+	}
+	if l.Index != 7 || l.Name != "eth1" || l.MTU != 9000 || !l.Up || l.Carrier || l.HWAddr.String() != "02:00:00:00:00:01" {
+		t.Errorf("Unexpected link %+v", l)
+	}
+
+	if _, ok := parseLinkMsg(msg[:8]); ok {
+		t.Error("Expected a truncated message to be rejected")
+	}
+}
+
+func TestWatchLinks(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+
+	w, err := WatchLinks()
+	if err != nil {
+		t.Fatalf("WatchLinks failed %s", err.Error())
+	}
+	defer w.Close()
+
+	setLoopbackUp(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ev, err := w.Next(ctx)
+	if err != nil {
+		t.Fatalf("Next failed %s", err.Error())
+	}
+	if ev.Link.Name != "lo" || !ev.Link.Up || ev.Removed {
+		t.Errorf("Expected lo to come up, got %+v", ev)
+	}
+}
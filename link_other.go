@@ -0,0 +1,8 @@
+//go:build !linux
+
+package routing
+
+// newLinkUpdateSource always returns ErrNotSupported, as netlink is only available on Linux.
+func newLinkUpdateSource() (linkUpdateSource, error) {
+	return nil, ErrNotSupported
+}
@@ -0,0 +1,88 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeLinkSource replays batches of link updates.
+type fakeLinkSource struct {
+	batches []fakeLinkBatch
+	closed  bool
+}
+
+type fakeLinkBatch struct {
+	updates []linkUpdate
+	full    bool
+}
+
+func (s *fakeLinkSource) receive(ctx context.Context) ([]linkUpdate, bool, error) {
+	if len(s.batches) == 0 {
+		return nil, false, context.Canceled
+	}
+	b := s.batches[0]
+	s.batches = s.batches[1:]
+
+	return b.updates, b.full, nil
+}
+
+func (s *fakeLinkSource) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestLinkWatcherNext(t *testing.T) {
+	lo := Link{Index: 1, Name: "lo", MTU: 65536, Up: true, Carrier: true}
+	eth0 := Link{Index: 2, Name: "eth0", MTU: 1500, Up: true, Carrier: true}
+	down := eth0
+	down.Carrier = false
+	renamed := down
+	renamed.Name = "lan0"
+	wg0 := Link{Index: 3, Name: "wg0", MTU: 1420, Up: true}
+
+	src := &fakeLinkSource{batches: []fakeLinkBatch{
+		{updates: []linkUpdate{{link: lo}, {link: eth0}}}, // Unchanged, e.g. a statistics update.
+		{updates: []linkUpdate{{link: down}}},
+		{updates: []linkUpdate{{link: renamed}, {link: wg0}}},
+		{updates: []linkUpdate{{link: wg0, removed: true}, {link: wg0, removed: true}}},
+		{updates: []linkUpdate{{link: renamed}}, full: true}, // Notifications were lost and lo is gone.
+	}}
+	w := newLinkWatcher(src, []Link{lo, eth0})
+
+	expected := []LinkEvent{
+		{Link: down},
+		{Link: renamed, OldName: "eth0"},
+		{Link: wg0},
+		{Link: wg0, Removed: true},
+		{Link: lo, Removed: true},
+	}
+	for i, want := range expected {
+		got, err := w.Next(context.Background())
+		if err != nil {
+			t.Fatalf("Next %d failed %s", i, err.Error())
+		}
+		if !got.Link.equal(want.Link) || got.Removed != want.Removed || got.OldName != want.OldName {
+			t.Errorf("Event %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	if _, err := w.Next(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the source's error once it is exhausted, got %v", err)
+	}
+	if w.Close(); !src.closed {
+		t.Error("Expected Close to close the source")
+	}
+}
+
+func TestLinks(t *testing.T) {
+	links, err := Links()
+	if err != nil {
+		t.Fatalf("Links failed %s", err.Error())
+	}
+	for _, l := range links {
+		if l.Index <= 0 || l.Name == "" {
+			t.Errorf("Expected an index and a name, got %+v", l)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package routing
+
+import (
+	"context"
+	"net"
+)
+
+// LocalAddress is an address the kernel treats as the host's own, or as a broadcast address of one of its
+// networks, as listed in the local routing table.
+type LocalAddress struct {
+	Prefix    *net.IPNet // The address as a /32 or /128, or a whole range the host answers for, such as 127.0.0.0/8.
+	Interface string     // The interface the address belongs to.
+	Type      RouteType  // RouteTypeLocal, RouteTypeBroadcast or RouteTypeAnycast.
+}
+
+// LocalAddresses returns the local, broadcast and anycast addresses of the kernel's local routing table.
+// The kernel consults this table to decide which packets are for the host, so it is a more reliable
+// inventory of owned addresses than enumerating interfaces: it includes addresses on interfaces that
+// are down and whole local ranges. It is only available on Linux and returns ErrNotSupported elsewhere.
+func LocalAddresses() ([]LocalAddress, error) {
+	return LocalAddressesContext(context.Background())
+}
+
+// LocalAddressesContext is like LocalAddresses but returns early if ctx is done.
+func LocalAddressesContext(ctx context.Context) ([]LocalAddress, error) {
+	src := NetlinkSource{Table: TableLocal}
+	routes, err := src.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v6Routes, err := src.IPv6Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []LocalAddress
+	for _, rt := range routes {
+		if !isLocalType(rt.Type) {
+			continue
+		}
+		dst, mask, _, err := decodeDestination(rt)
+		if err != nil {
+			continue
+		}
+		addrs = append(addrs, LocalAddress{
+			Prefix:    &net.IPNet{IP: dst.To4(), Mask: net.IPMask(mask.To4())},
+			Interface: rt.Interface,
+			Type:      rt.Type,
+		})
+	}
+	for _, rt := range v6Routes {
+		if isLocalType(rt.Type) {
+			addrs = append(addrs, LocalAddress{Prefix: rt.Destination, Interface: rt.Interface, Type: rt.Type})
+		}
+	}
+
+	return addrs, nil
+}
+
+// isLocalType reports whether routes of type typ designate addresses of the host.
+func isLocalType(typ RouteType) bool {
+	return typ == RouteTypeLocal || typ == RouteTypeBroadcast || typ == RouteTypeAnycast
+}
@@ -0,0 +1,35 @@
+package routing
+
+import "testing"
+
+func TestLocalAddresses(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+	setLoopbackUp(t)
+
+	addrs, err := LocalAddresses()
+	if err != nil {
+		t.Fatalf("LocalAddresses failed %s", err.Error())
+	}
+	found := make(map[string]RouteType)
+	for _, a := range addrs {
+		if a.Interface != "lo" {
+			t.Errorf("Expected only addresses of lo, got %+v", a)
+		}
+		found[a.Prefix.String()] = a.Type
+	}
+	expected := map[string]RouteType{
+		"127.0.0.0/8":        RouteTypeLocal,
+		"127.0.0.1/32":       RouteTypeLocal,
+		"127.255.255.255/32": RouteTypeBroadcast,
+	}
+	for prefix, typ := range expected {
+		if got, ok := found[prefix]; !ok || got != typ {
+			t.Errorf("Expected %s as %s, got %v", prefix, typ, addrs)
+		}
+	}
+	if typ, ok := found["::1/128"]; ok && typ != RouteTypeLocal {
+		t.Errorf("Expected ::1 to be local, got %s", typ)
+	}
+}
@@ -0,0 +1,50 @@
+package routing
+
+import "net"
+
+// LookupRoute returns the route the kernel would select for ip from the given routes.
+// Usable routes are matched by longest prefix, with the lowest metric breaking ties; false is returned when none match.
+func LookupRoute(routes []RoutingTable, ip net.IP) (RoutingTable, bool) {
+	var best RoutingTable
+	bestLen := -1
+
+	for _, rt := range routes {
+		if !flagContains(rt.Flags, "U") {
+			continue
+		}
+		dst, mask, ones, err := decodeDestination(rt)
+		if err != nil {
+			continue
+		}
+		ipNet := net.IPNet{IP: dst, Mask: net.IPMask(mask)}
+		if !ipNet.Contains(ip) {
+			continue
+		}
+		if ones > bestLen || (ones == bestLen && rt.Metric < best.Metric) {
+			best, bestLen = rt, ones
+		}
+	}
+
+	return best, bestLen >= 0
+}
+
+// RoutesContaining returns every route whose prefix contains ip, from the most specific prefix to the least,
+// with the lowest metric first among equal prefixes. The first usable entry is the one LookupRoute selects;
+// the others, down to the default route, are the routes it shadows for ip.
+func RoutesContaining(routes []RoutingTable, ip net.IP) []RoutingTable {
+	var matches []RoutingTable
+	for _, rt := range routes {
+		dst, mask, _, err := decodeDestination(rt)
+		if err != nil {
+			continue
+		}
+		if (&net.IPNet{IP: dst, Mask: net.IPMask(mask)}).Contains(ip) {
+			matches = append(matches, rt)
+		}
+	}
+
+	SortByMetric(matches)
+	SortByPrefixLength(matches)
+
+	return matches
+}
@@ -0,0 +1,64 @@
+package routing
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestLookupRoute(t *testing.T) {
+	table, err := ParseIPRoute(strings.NewReader(`default via 192.168.1.1 dev eth0 metric 600
+default via 10.8.0.1 dev tun0 metric 50
+192.168.1.0/24 dev eth0 scope link
+10.8.0.0/24 dev tun0 scope link
+`))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	cases := map[string]string{
+		"192.168.1.20": "eth0",
+		"10.8.0.9":     "tun0",
+		"8.8.8.8":      "tun0",
+	}
+	for ip, iface := range cases {
+		rt, ok := LookupRoute(table, net.ParseIP(ip))
+		if !ok {
+			t.Errorf("No route found for %s", ip)
+			continue
+		}
+		if rt.Interface != iface {
+			t.Errorf("Route for %s uses %s, want %s", ip, rt.Interface, iface)
+		}
+	}
+
+	if _, ok := LookupRoute(table[2:], net.ParseIP("8.8.8.8")); ok {
+		t.Errorf("Expected no route without a default")
+	}
+}
+
+func TestRoutesContaining(t *testing.T) {
+	table, err := ParseIPRoute(strings.NewReader(`default via 192.168.1.1 dev eth0 metric 100
+default via 10.8.0.1 dev tun0 metric 50
+10.0.0.0/8 via 10.8.0.1 dev tun0
+10.1.0.0/16 via 192.168.1.1 dev eth0
+192.168.1.0/24 dev eth0 scope link
+`))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	var got []string
+	for _, rt := range RoutesContaining(table, net.ParseIP("10.1.2.3")) {
+		got = append(got, rt.String())
+	}
+	want := "10.1.0.0/16 via 192.168.1.1 dev eth0\n10.0.0.0/8 via 10.8.0.1 dev tun0\n" +
+		"default via 10.8.0.1 dev tun0 metric 50\ndefault via 192.168.1.1 dev eth0 metric 100"
+	if strings.Join(got, "\n") != want {
+		t.Errorf("Unexpected routes\n%s\nwant\n%s", strings.Join(got, "\n"), want)
+	}
+
+	if routes := RoutesContaining(table[2:], net.ParseIP("8.8.8.8")); len(routes) != 0 {
+		t.Errorf("Expected no routes without a default, got %v", routes)
+	}
+}
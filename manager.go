@@ -0,0 +1,200 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"time"
+)
+
+// ManagerConfig configures a Manager. The zero value reads /proc/net/route on every call.
+// A ManagerConfig is an Option, so calls to NewManager written for it keep working.
+//
+// Deprecated: Pass WithSource, WithProcPath and WithCacheTTL to NewManager instead.
+type ManagerConfig struct {
+	Source   RouteSource   // Where routes are read from; a ProcSource reading ProcPath when nil.
+	ProcPath string        // File read by the default source; /proc/net/route when empty.
+	CacheTTL time.Duration // How long a read is reused by later calls; zero disables caching.
+}
+
+// apply sets the options of the fields of cfg that are set, leaving the others as they are.
+func (cfg ManagerConfig) apply(o *options) {
+	if cfg.Source != nil {
+		o.source = cfg.Source
+	}
+	if cfg.ProcPath != "" {
+		o.procPath = cfg.ProcPath
+	}
+	if cfg.CacheTTL > 0 {
+		o.cacheTTL = cfg.CacheTTL
+	}
+}
+
+// Manager reads, looks up and watches routes through a configured RouteSource.
+// Its configuration is fixed at construction, so a Manager is safe for concurrent use
+// as long as its source is; every source in this package is.
+type Manager struct {
+	source RouteSource   // The configured source, read directly by watchers.
+	cache  *CachedSource // Wraps source when caching is enabled; nil otherwise.
+	logger *slog.Logger  // Receives diagnostics; nil discards them.
+	fsys   fs.FS         // Filesystem /proc files other than the route source are read from; nil for the host's.
+
+	ipv6     ipv6RouteSource // Reads the IPv6 routes matching source; nil reads ipv6Path.
+	ipv6Path string          // File IPv6 routes are read from, /proc/net/ipv6_route unless WithProcPath was given.
+
+	excludeCloned bool                   // Drop kernel-generated IPv6 routes; the route source filters IPv4 routes itself.
+	links         func() ([]Link, error) // Lists the interfaces to drop IPv6 routes through those down; nil keeps them.
+}
+
+// discardLogger is used when no logger was configured.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// defaultManager backs the package-level functions such as GetLinuxRoutingTable.
+var defaultManager = NewManager()
+
+// NewManager returns a Manager configured by opts. Without options it reads /proc/net/route on every call.
+// If /proc is unusable, as in scratch containers or with /proc mounted with subset=pid, it reads the main table over
+// netlink instead, and otherwise reports a ProcUnavailableError explaining the environment. On Android it reads
+// every network's table over netlink, the active network's first.
+func NewManager(opts ...Option) *Manager {
+	o := newOptions(opts)
+	m := &Manager{source: o.baseSource(), logger: o.logger, fsys: o.fsys, excludeCloned: o.noCloned}
+	m.ipv6, m.ipv6Path = o.ipv6Source()
+	if o.noDown {
+		m.links = Links
+	}
+	if o.cacheTTL > 0 {
+		m.cache = NewCachedSource(m.source, o.cacheTTL)
+	}
+
+	return m
+}
+
+// log returns the configured logger, or one that discards everything.
+func (m *Manager) log() *slog.Logger {
+	if m.logger == nil {
+		return discardLogger
+	}
+
+	return m.logger
+}
+
+// Routes returns the current routing table, from the cache if it is still fresh.
+// Manager implements RouteSource, so it can be passed to Query, ExpVar or the collector package.
+func (m *Manager) Routes(ctx context.Context) ([]RoutingTable, error) {
+	var routes []RoutingTable
+	var err error
+	if m.cache != nil {
+		routes, err = m.cache.Routes(ctx)
+	} else {
+		routes, err = m.source.Routes(ctx)
+	}
+	if err != nil {
+		m.log().Debug("reading routing table failed", "source", fmt.Sprintf("%T", m.source), "err", err)
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// Invalidate drops any cached routing table so the next read goes to the source.
+func (m *Manager) Invalidate() {
+	if m.cache != nil {
+		m.cache.Invalidate()
+	}
+}
+
+// DefaultRoute returns the usable default route, 0.0.0.0/0 with the "U" and "G" flags, with the lowest metric,
+// the first in table order among equal metrics, as DefaultRoutes marks preferred. The kernel only compares metrics
+// within a table, so when the routes span several tables only the defaults of the first table holding one compete.
+// It returns ErrNoDefaultGateway if there is none.
+func (m *Manager) DefaultRoute(ctx context.Context) (RoutingTable, error) {
+	routes, err := m.Routes(ctx)
+	if err != nil {
+		return RoutingTable{}, err
+	}
+
+	rt, ok := defaultRoute(routes)
+	if !ok {
+		return RoutingTable{}, ErrNoDefaultGateway
+	}
+
+	return rt, nil
+}
+
+// defaultRoute returns the default route DefaultRoute reports.
+func defaultRoute(routes []RoutingTable) (RoutingTable, bool) {
+	var best RoutingTable
+	found := false
+	for _, v := range routes {
+		if !flagContains(v.Flags, "U") || !flagContains(v.Flags, "G") || !isForwarding(v.Type, v.Flags) {
+			continue
+		}
+		if _, _, ones, err := decodeDestination(v); err != nil || ones != 0 {
+			continue
+		}
+		if !found || v.Table == best.Table && v.Metric < best.Metric {
+			best, found = v, true
+		}
+	}
+
+	return best, found
+}
+
+// DefaultGateway returns the address of the default gateway in dotted notation.
+func (m *Manager) DefaultGateway(ctx context.Context) (string, error) {
+	rt, err := m.DefaultRoute(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return rt.Gateway, nil
+}
+
+// DefaultInterface returns the name of the interface the default gateway is reached through.
+func (m *Manager) DefaultInterface(ctx context.Context) (string, error) {
+	rt, err := m.DefaultRoute(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return rt.Interface, nil
+}
+
+// Lookup returns the route that would be selected for ip, as LookupRoute does on the current routing table.
+func (m *Manager) Lookup(ctx context.Context, ip net.IP) (RoutingTable, bool, error) {
+	routes, err := m.Routes(ctx)
+	if err != nil {
+		return RoutingTable{}, false, err
+	}
+
+	rt, ok := LookupRoute(routes, ip)
+
+	return rt, ok, nil
+}
+
+// RoutesContaining returns every route of the current routing table covering ip, as RoutesContaining does.
+func (m *Manager) RoutesContaining(ctx context.Context, ip net.IP) ([]RoutingTable, error) {
+	routes, err := m.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return RoutesContaining(routes, ip), nil
+}
+
+// Query starts a RouteQuery over the manager's routes.
+func (m *Manager) Query() *RouteQuery {
+	return Query().From(m)
+}
+
+// Watch returns a Watcher polling the manager's source every interval.
+// It bypasses the cache, which would otherwise delay changes by up to the TTL.
+func (m *Manager) Watch(interval time.Duration) *Watcher {
+	w := NewWatcher(m.source, interval)
+	w.logger = m.log()
+
+	return w
+}
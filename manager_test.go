@@ -0,0 +1,150 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// procFS is a /proc tree for a host whose default gateway is 192.0.2.1 on eth0.
+var procFS = fstest.MapFS{
+	"proc/net/route": {Data: []byte(procRouteFixture)},
+	"proc/net/arp": {Data: []byte(`IP address       HW type     Flags       HW address            Mask     Device
+192.0.2.1        0x1         0x2         02:fc:00:00:00:05     *        eth0
+`)},
+	"proc/net/dev": {Data: []byte(netDevFixture)},
+}
+
+// useProcFS makes the package-level functions read procFS instead of the host's /proc until the test ends.
+func useProcFS(t *testing.T) {
+	t.Helper()
+	saved := defaultManager
+	defaultManager = NewManager(WithFS(procFS))
+	t.Cleanup(func() { defaultManager = saved })
+}
+
+func TestManagerProcPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "route")
+	if err := os.WriteFile(path, []byte(procRouteFixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(WithProcPath(path))
+	gw, err := m.DefaultGateway(context.Background())
+	if err != nil || gw != "192.0.2.1" {
+		t.Errorf("DefaultGateway = %s, %v", gw, err)
+	}
+	iface, err := m.DefaultInterface(context.Background())
+	if err != nil || iface != "eth0" {
+		t.Errorf("DefaultInterface = %s, %v", iface, err)
+	}
+	rt, ok, err := m.Lookup(context.Background(), net.ParseIP("10.0.0.1"))
+	if err != nil || !ok || rt.Interface != "tun0" {
+		t.Errorf("Lookup = %q, %t, %v", rt, ok, err)
+	}
+}
+
+func TestManagerConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "route")
+	if err := os.WriteFile(path, []byte(procRouteFixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(ManagerConfig{ProcPath: path, CacheTTL: time.Minute})
+	if gw, err := m.DefaultGateway(context.Background()); err != nil || gw != "192.0.2.1" {
+		t.Errorf("DefaultGateway = %s, %v", gw, err)
+	}
+	if m.cache == nil {
+		t.Error("Expected CacheTTL to enable caching")
+	}
+
+	// Fields left unset keep the options given before.
+	m = NewManager(WithSource(&memTable{routes: failoverRoutes(t)}), ManagerConfig{CacheTTL: time.Minute})
+	if gw, err := m.DefaultGateway(context.Background()); err != nil || gw != "192.0.2.1" {
+		t.Errorf("DefaultGateway = %s, %v", gw, err)
+	}
+	if _, ok := m.source.(*memTable); !ok {
+		t.Errorf("Expected the source given by WithSource, got %T", m.source)
+	}
+}
+
+func TestManagerFS(t *testing.T) {
+	m := NewManager(WithFS(procFS))
+
+	hw, state, err := m.GatewayHWAddr(context.Background())
+	if err != nil || hw.String() != "02:fc:00:00:00:05" || state != ARPComplete {
+		t.Errorf("GatewayHWAddr = %s, %s, %v", hw, state, err)
+	}
+	stats, err := m.DefaultInterfaceStats(context.Background())
+	if err != nil || stats.Interface != "eth0" || stats.RxBytes != 12345678901 {
+		t.Errorf("DefaultInterfaceStats = %+v, %v", stats, err)
+	}
+
+	_, err = NewManager(WithFS(fstest.MapFS{})).ARPTable(context.Background())
+	if !errors.Is(err, ErrProcUnavailable) || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected ErrProcUnavailable for a missing file, got %v", err)
+	}
+}
+
+func TestManagerCache(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("192.168.1.0/24 dev eth0\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	var reads atomic.Int32
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		reads.Add(1)
+		return routes, nil
+	})
+
+	m := NewManager(WithSource(src), WithCacheTTL(time.Hour))
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := m.Routes(context.Background()); err != nil {
+				t.Errorf("Routes failed %s", err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+	if reads.Load() != 1 {
+		t.Errorf("Expected a single read of the source, got %d", reads.Load())
+	}
+
+	m.Invalidate()
+	m.Routes(context.Background())
+	if reads.Load() != 2 {
+		t.Errorf("Expected Invalidate to force a read, got %d reads", reads.Load())
+	}
+
+	if _, err := m.DefaultRoute(context.Background()); !errors.Is(err, ErrNoDefaultGateway) {
+		t.Errorf("Expected ErrNoDefaultGateway, got %v", err)
+	}
+	if all, _ := m.Query().OnInterface("eth0").All(); len(all) != 1 {
+		t.Errorf("Expected the query to see the manager's routes, got %v", all)
+	}
+}
+
+func TestManagerDefaultRoute(t *testing.T) {
+	m := NewManager(WithSource(&memTable{routes: mustParseIPRoute(t, `10.0.0.0/8 via 192.0.2.9 dev eth0
+default via 192.0.2.1 dev eth0 metric 700
+default via 198.51.100.1 dev wlan0 metric 600
+default via 203.0.113.1 dev wwan0 metric 600`)}))
+
+	// Routes through a gateway to other prefixes are not default routes, whatever their position.
+	rt, err := m.DefaultRoute(context.Background())
+	if err != nil || rt.Gateway != "198.51.100.1" || rt.Interface != "wlan0" {
+		t.Errorf("DefaultRoute() = %s, %v, want the first default with metric 600", rt, err)
+	}
+}
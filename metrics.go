@@ -0,0 +1,115 @@
+package routing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RouteMetrics holds the per-route TCP and path metrics the kernel keeps in RTA_METRICS.
+// Zero fields are unset and the kernel falls back to its defaults for them.
+type RouteMetrics struct {
+	MTU        uint32        // Path MTU in bytes.
+	Window     uint32        // Maximum TCP window to advertise, in bytes.
+	RTT        time.Duration // Initial round trip time estimate.
+	RTTVar     time.Duration // Initial round trip time variance.
+	SSThresh   uint32        // Initial TCP slow start threshold, in packets.
+	CWnd       uint32        // Clamp for the TCP congestion window, in packets.
+	AdvMSS     uint32        // Maximum segment size to advertise for TCP, in bytes.
+	Reordering uint32        // Maximal reordering before TCP assumes loss.
+	HopLimit   uint32        // TTL or hop limit for outgoing packets.
+	InitCwnd   uint32        // Initial TCP congestion window, in packets.
+	RTOMin     time.Duration // Minimum TCP retransmission timeout.
+	InitRwnd   uint32        // Initial TCP receive window, in packets.
+	QuickAck   bool          // Whether delayed TCP acknowledgements are disabled.
+	CongCtl    string        // TCP congestion control algorithm, e.g. "bbr".
+}
+
+// IsZero reports whether no metric is set.
+func (m RouteMetrics) IsZero() bool {
+	return m == RouteMetrics{}
+}
+
+// String renders the set metrics the way `ip route` prints them, e.g. "mtu 1400 advmss 1360".
+func (m RouteMetrics) String() string {
+	var parts []string
+	add := func(name string, v uint32) {
+		if v != 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", name, v))
+		}
+	}
+	addDuration := func(name string, d time.Duration) {
+		if d != 0 {
+			parts = append(parts, fmt.Sprintf("%s %dms", name, d.Milliseconds()))
+		}
+	}
+
+	add("mtu", m.MTU)
+	add("window", m.Window)
+	addDuration("rtt", m.RTT)
+	addDuration("rttvar", m.RTTVar)
+	add("ssthresh", m.SSThresh)
+	add("cwnd", m.CWnd)
+	add("advmss", m.AdvMSS)
+	add("reordering", m.Reordering)
+	add("hoplimit", m.HopLimit)
+	add("initcwnd", m.InitCwnd)
+	addDuration("rto_min", m.RTOMin)
+	add("initrwnd", m.InitRwnd)
+	if m.QuickAck {
+		parts = append(parts, "quickack 1")
+	}
+	if m.CongCtl != "" {
+		parts = append(parts, "congctl "+m.CongCtl)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// setIPRouteMetric sets the metric named by an `ip route` keyword such as "advmss" from its printed value.
+// Times may carry a unit ("10ms", "1s") and default to milliseconds. It reports false for keywords that are not metrics.
+func (m *RouteMetrics) setIPRouteMetric(key, val string) (bool, error) {
+	if key == "congctl" {
+		m.CongCtl = val
+		return true, nil
+	}
+
+	targets := map[string]*uint32{
+		"mtu": &m.MTU, "window": &m.Window, "ssthresh": &m.SSThresh, "cwnd": &m.CWnd,
+		"advmss": &m.AdvMSS, "reordering": &m.Reordering, "hoplimit": &m.HopLimit,
+		"initcwnd": &m.InitCwnd, "initrwnd": &m.InitRwnd,
+	}
+	if p, ok := targets[key]; ok {
+		v, err := parseUint32(val)
+		*p = v
+		return true, err
+	}
+
+	durations := map[string]*time.Duration{"rtt": &m.RTT, "rttvar": &m.RTTVar, "rto_min": &m.RTOMin}
+	if p, ok := durations[key]; ok {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			var ms uint32
+			ms, err = parseUint32(val)
+			d = time.Duration(ms) * time.Millisecond
+		}
+		*p = d
+		return true, err
+	}
+
+	if key == "quickack" {
+		v, err := parseUint32(val)
+		m.QuickAck = v != 0
+		return true, err
+	}
+
+	return false, nil
+}
+
+// parseUint32 parses a decimal uint32, saturating out-of-range values like strconv does.
+func parseUint32(s string) (uint32, error) {
+	v, err := strconv.ParseUint(s, 10, 32)
+
+	return uint32(v), err
+}
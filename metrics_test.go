@@ -0,0 +1,49 @@
+package routing
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseIPRouteMetrics(t *testing.T) {
+	table, err := ParseIPRoute(strings.NewReader("10.0.0.0/8 dev eth0 mtu lock 9000 advmss 8960 rtt 1s hoplimit 32 initcwnd 10 congctl bbr\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	want := RouteMetrics{MTU: 9000, AdvMSS: 8960, RTT: time.Second, HopLimit: 32, InitCwnd: 10, CongCtl: "bbr"}
+	if table[0].Metrics != want {
+		t.Errorf("Unexpected metrics %+v", table[0].Metrics)
+	}
+	if table[0].String() != "10.0.0.0/8 dev eth0 scope link mtu 9000 rtt 1000ms advmss 8960 hoplimit 32 initcwnd 10 congctl bbr" {
+		t.Errorf("Unexpected route %q", table[0].String())
+	}
+
+	table, err = ParseIPRouteJSON(strings.NewReader(`[{"dst":"10.0.0.0/8","dev":"eth0","flags":[],"metrics":[{"mtu":["lock",9000],"advmss":8960,"rtt":5}]}]`))
+	if err != nil {
+		t.Fatalf("ParseIPRouteJSON failed %s", err.Error())
+	}
+	if m := table[0].Metrics; m.MTU != 9000 || m.AdvMSS != 8960 || m.RTT != 5*time.Millisecond {
+		t.Errorf("Unexpected metrics %+v", m)
+	}
+}
+
+func TestRouteMetricsJSONRoundTrip(t *testing.T) {
+	m := RouteMetrics{MTU: 1400, RTT: 20 * time.Millisecond, QuickAck: true, CongCtl: "cubic"}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal failed %s", err.Error())
+	}
+	if string(b) != `{"mtu":1400,"rtt_ms":20,"quickack":true,"congctl":"cubic"}` {
+		t.Errorf("Unexpected encoding %s", b)
+	}
+
+	var decoded RouteMetrics
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed %s", err.Error())
+	}
+	if decoded != m {
+		t.Errorf("Round trip mismatch %+v %+v", m, decoded)
+	}
+}
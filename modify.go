@@ -0,0 +1,129 @@
+package routing
+
+import (
+	"context"
+	"net"
+)
+
+// routeOp selects the change made to the kernel routing table by modifyRoute.
+type routeOp int
+
+const (
+	routeAdd     routeOp = iota // Create the route, failing if it already exists.
+	routeReplace                // Create the route or replace an existing one with the same key.
+	routeDelete                 // Remove the route.
+)
+
+// AddRoute installs rt in the kernel routing table over netlink.
+// The route's Type selects special routes such as blackhole or prohibit, which need no gateway or interface.
+// An unset Table adds to main, an unset Proto records the route as "boot" and an unset Scope is derived from the type
+// and gateway, as `ip route add` does. It fails with an error matching os.ErrExist if the route already exists.
+func AddRoute(rt RoutingTable) error {
+	return AddRouteContext(context.Background(), rt)
+}
+
+// AddRouteContext is like AddRoute but returns early if ctx is done.
+func AddRouteContext(ctx context.Context, rt RoutingTable) error {
+	return modifyRoute(ctx, routeAdd, rt)
+}
+
+// ReplaceRoute installs rt, replacing any route with the same destination, table and metric.
+func ReplaceRoute(rt RoutingTable) error {
+	return ReplaceRouteContext(context.Background(), rt)
+}
+
+// ReplaceRouteContext is like ReplaceRoute but returns early if ctx is done.
+func ReplaceRouteContext(ctx context.Context, rt RoutingTable) error {
+	return modifyRoute(ctx, routeReplace, rt)
+}
+
+// DeleteRoute removes rt from the kernel routing table.
+// Unset fields such as Proto, Scope or Gateway match any value, so the first route with the same destination is removed.
+func DeleteRoute(rt RoutingTable) error {
+	return DeleteRouteContext(context.Background(), rt)
+}
+
+// DeleteRouteContext is like DeleteRoute but returns early if ctx is done.
+func DeleteRouteContext(ctx context.Context, rt RoutingTable) error {
+	return modifyRoute(ctx, routeDelete, rt)
+}
+
+// InstallBlackhole null-routes prefix, a CIDR prefix such as "198.51.100.0/24" or a single address,
+// in the main table, so the kernel silently drops traffic to it, e.g. to mitigate an attack.
+// Installing a prefix that is already null-routed succeeds; a unicast route to the same prefix and metric is replaced.
+func InstallBlackhole(prefix string) error {
+	return InstallBlackholeContext(context.Background(), prefix)
+}
+
+// InstallBlackholeContext is like InstallBlackhole but returns early if ctx is done.
+func InstallBlackholeContext(ctx context.Context, prefix string) error {
+	rt, err := blackholeRoute(prefix)
+	if err != nil {
+		return err
+	}
+
+	return modifyRoute(ctx, routeReplace, rt)
+}
+
+// RemoveBlackhole removes the null route of prefix installed by InstallBlackhole.
+// Other routes to the prefix are left alone, and it fails if the prefix is not null-routed.
+func RemoveBlackhole(prefix string) error {
+	return RemoveBlackholeContext(context.Background(), prefix)
+}
+
+// RemoveBlackholeContext is like RemoveBlackhole but returns early if ctx is done.
+func RemoveBlackholeContext(ctx context.Context, prefix string) error {
+	rt, err := blackholeRoute(prefix)
+	if err != nil {
+		return err
+	}
+
+	return modifyRoute(ctx, routeDelete, rt)
+}
+
+// blackholeRoute returns the blackhole route of prefix in the main table.
+func blackholeRoute(prefix string) (RoutingTable, error) {
+	dst, err := parseIPRouteDst(prefix)
+	if err != nil {
+		return RoutingTable{}, err
+	}
+
+	return RoutingTable{
+		Destination: formatHexIP(dst.IP),
+		Mask:        formatHexIP(net.IP(dst.Mask)),
+		Table:       TableMain,
+		Type:        RouteTypeBlackhole,
+	}, nil
+}
+
+// RouteWriter changes a routing table. NetlinkSource writes to the kernel's, and routingtest.FakeSource
+// to an in-memory table for tests.
+type RouteWriter interface {
+	AddRoute(ctx context.Context, rt RoutingTable) error     // Adds rt, failing if a route with its key exists.
+	ReplaceRoute(ctx context.Context, rt RoutingTable) error // Adds rt or replaces the route with its key.
+	DeleteRoute(ctx context.Context, rt RoutingTable) error  // Removes the route with the key of rt.
+}
+
+// AddRoute installs rt in the kernel routing table like AddRouteContext, in the source's table if rt has none.
+func (s NetlinkSource) AddRoute(ctx context.Context, rt RoutingTable) error {
+	return modifyRoute(ctx, routeAdd, s.withTable(rt))
+}
+
+// ReplaceRoute installs rt like ReplaceRouteContext, in the source's table if rt has none.
+func (s NetlinkSource) ReplaceRoute(ctx context.Context, rt RoutingTable) error {
+	return modifyRoute(ctx, routeReplace, s.withTable(rt))
+}
+
+// DeleteRoute removes rt like DeleteRouteContext, from the source's table if rt has none.
+func (s NetlinkSource) DeleteRoute(ctx context.Context, rt RoutingTable) error {
+	return modifyRoute(ctx, routeDelete, s.withTable(rt))
+}
+
+// withTable returns rt in the source's table unless rt names one.
+func (s NetlinkSource) withTable(rt RoutingTable) RoutingTable {
+	if rt.Table == TableUnspec {
+		rt.Table = s.Table
+	}
+
+	return rt
+}
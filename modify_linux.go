@@ -0,0 +1,271 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+)
+
+// Route protocol and scope values used when the caller leaves them unset.
+const (
+	rtprotUnspec    = 0
+	rtprotBoot      = 3
+	rtScopeUniverse = 0
+	rtScopeLink     = 253
+	rtScopeHost     = 254
+	rtScopeNowhere  = 255
+)
+
+// modifyRoute sends an RTM_NEWROUTE or RTM_DELROUTE request for rt.
+func modifyRoute(ctx context.Context, op routeOp, rt RoutingTable) error {
+	msg, err := routeMsg(op, rt)
+	if err != nil {
+		return err
+	}
+
+	typ, flags, verb := uint16(syscall.RTM_NEWROUTE), uint16(syscall.NLM_F_CREATE|syscall.NLM_F_EXCL), "adding"
+	switch op {
+	case routeReplace:
+		flags, verb = syscall.NLM_F_CREATE|syscall.NLM_F_REPLACE, "replacing"
+	case routeDelete:
+		typ, flags, verb = syscall.RTM_DELROUTE, 0, "deleting"
+	}
+	if _, err := netlinkRequest(ctx, typ, flags, msg); err != nil {
+		return fmt.Errorf("%s route %s: %w", verb, rt, err)
+	}
+
+	return nil
+}
+
+// routeMsg encodes rt as the payload of a route request: a struct rtmsg followed by its attributes.
+// Deletions leave unset fields zero, which the kernel treats as wildcards.
+func routeMsg(op routeOp, rt RoutingTable) ([]byte, error) {
+	dst, _, ones, err := decodeDestination(rt)
+	if err != nil {
+		return nil, err
+	}
+	if rt.SRv6 != nil && op != routeDelete { // Deletions match the route without its encapsulation.
+		return nil, fmt.Errorf("SRv6 encapsulation: %w", ErrNotSupported)
+	}
+
+	typ := rt.Type
+	proto, scope := rtprotUnspec, rtScopeNowhere
+	if op != routeDelete {
+		if typ == RouteTypeUnspec {
+			typ = RouteTypeUnicast
+		}
+		proto, scope = rtprotBoot, defaultScope(rt, typ)
+	}
+	if rt.Proto != "" {
+		p, ok := numberOrName(protocolNames(), rt.Proto)
+		if !ok {
+			return nil, fmt.Errorf("unknown route protocol %q", rt.Proto)
+		}
+		proto = p
+	}
+	if rt.Scope != "" {
+		sc, ok := numberOrName(scopeNames(), rt.Scope)
+		if !ok {
+			return nil, fmt.Errorf("unknown route scope %q", rt.Scope)
+		}
+		scope = sc
+	}
+
+	b := make([]byte, sizeofRtMsg)
+	b[0] = syscall.AF_INET
+	b[1] = byte(ones)
+	b[3] = rt.TOS
+	if rt.Table < 256 {
+		b[4] = byte(rt.Table) // Larger IDs only fit in RTA_TABLE.
+	}
+	b[5], b[6], b[7] = byte(proto), byte(scope), byte(typ)
+	if rt.OnLink {
+		binary.NativeEndian.PutUint32(b[8:12], rtnhFOnlink)
+	}
+
+	if ones > 0 {
+		b = append(b, nlAttr(syscall.RTA_DST, dst.To4())...)
+	}
+	if rt.Table != TableUnspec {
+		b = append(b, nlAttr(rtaTableAttr, nlUint32Bytes(uint32(rt.Table)))...)
+	}
+	switch {
+	case rt.NexthopID != 0: // The kernel rejects paths given along with a nexthop object.
+		b = append(b, nlAttr(rtaNhID, nlUint32Bytes(rt.NexthopID))...)
+	case len(rt.Nexthops) == 0:
+		gw, err := routeGateway(rt.Gateway)
+		if err != nil {
+			return nil, err
+		}
+		if gw != nil {
+			b = append(b, nlAttr(syscall.RTA_GATEWAY, gw)...)
+		}
+		if rt.Interface != "" && rt.Interface != "*" {
+			index, err := interfaceIndex(rt.Interface)
+			if err != nil {
+				return nil, err
+			}
+			b = append(b, nlAttr(syscall.RTA_OIF, nlUint32Bytes(uint32(index)))...)
+		}
+	default:
+		mp, err := encodeMultipath(rt.Nexthops)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, nlAttr(syscall.RTA_MULTIPATH, mp)...)
+	}
+	if rt.PrefSrc != "" {
+		src := net.ParseIP(rt.PrefSrc).To4()
+		if src == nil {
+			return nil, fmt.Errorf("preferred source %q is not an IPv4 address", rt.PrefSrc)
+		}
+		b = append(b, nlAttr(syscall.RTA_PREFSRC, src)...)
+	}
+	if rt.Metric != 0 {
+		b = append(b, nlAttr(syscall.RTA_PRIORITY, nlUint32Bytes(rt.Metric))...)
+	}
+	if rt.Realm != "" || rt.FromRealm != "" {
+		flow, err := encodeRealms(rt.Realm, rt.FromRealm)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, nlAttr(syscall.RTA_FLOW, nlUint32Bytes(flow))...)
+	}
+	if !rt.Metrics.IsZero() {
+		b = append(b, nlAttr(syscall.RTA_METRICS, encodeRouteMetrics(rt.Metrics))...)
+	}
+
+	return b, nil
+}
+
+// encodeRealms builds the RTA_FLOW value of a route's realms, given as names or numbers.
+func encodeRealms(to, from string) (uint32, error) {
+	names := realmNames()
+	var flow uint32
+	for shift, realm := range map[int]string{0: to, 16: from} {
+		if realm == "" {
+			continue
+		}
+		id, ok := numberOrName(names, realm)
+		if !ok || id < 0 || id > 0xffff {
+			return 0, fmt.Errorf("unknown route realm %q", realm)
+		}
+		flow |= uint32(id) << shift
+	}
+
+	return flow, nil
+}
+
+// defaultScope picks the scope `ip route add` uses for a route of type typ when none is given.
+func defaultScope(rt RoutingTable, typ RouteType) int {
+	switch typ {
+	case RouteTypeLocal:
+		return rtScopeHost
+	case RouteTypeBroadcast, RouteTypeAnycast, RouteTypeMulticast:
+		return rtScopeLink
+	case RouteTypeUnicast:
+		gw := net.ParseIP(rt.Gateway)
+		if len(rt.Nexthops) == 0 && rt.NexthopID == 0 && (gw == nil || gw.IsUnspecified()) {
+			return rtScopeLink
+		}
+	}
+
+	return rtScopeUniverse
+}
+
+// routeGateway parses a gateway field, returning nil if it is unset or unspecified.
+func routeGateway(s string) (net.IP, error) {
+	if s == "" {
+		return nil, nil
+	}
+	gw := net.ParseIP(s).To4()
+	if gw == nil {
+		return nil, fmt.Errorf("gateway %q is not an IPv4 address", s)
+	}
+	if gw.IsUnspecified() {
+		return nil, nil
+	}
+
+	return gw, nil
+}
+
+// interfaceIndex resolves an interface name to its index.
+func interfaceIndex(name string) (int, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return iface.Index, nil
+}
+
+// encodeRouteMetrics encodes the set metrics as the attributes nested in RTA_METRICS.
+// It is the inverse of parseRouteMetrics.
+func encodeRouteMetrics(m RouteMetrics) []byte {
+	var b []byte
+	put := func(typ uint16, v uint32) {
+		if v != 0 {
+			b = append(b, nlAttr(typ, nlUint32Bytes(v))...)
+		}
+	}
+
+	put(rtaxMTU, m.MTU)
+	put(rtaxWindow, m.Window)
+	put(rtaxRTT, uint32(m.RTT*8/time.Millisecond))
+	put(rtaxRTTVar, uint32(m.RTTVar*4/time.Millisecond))
+	put(rtaxSSThresh, m.SSThresh)
+	put(rtaxCWnd, m.CWnd)
+	put(rtaxAdvMSS, m.AdvMSS)
+	put(rtaxReordering, m.Reordering)
+	put(rtaxHopLimit, m.HopLimit)
+	put(rtaxInitCwnd, m.InitCwnd)
+	put(rtaxRTOMin, uint32(m.RTOMin/time.Millisecond))
+	put(rtaxInitRwnd, m.InitRwnd)
+	if m.QuickAck {
+		put(rtaxQuickAck, 1)
+	}
+	if m.CongCtl != "" {
+		b = append(b, nlAttr(rtaxCCAlgo, append([]byte(m.CongCtl), 0))...)
+	}
+
+	return b
+}
+
+// encodeMultipath encodes nexthops as the struct rtnexthop entries of an RTA_MULTIPATH attribute.
+// It is the inverse of parseMultipath.
+func encodeMultipath(nexthops []Nexthop) ([]byte, error) {
+	var b []byte
+	for _, nh := range nexthops {
+		gw, err := routeGateway(nh.Gateway)
+		if err != nil {
+			return nil, err
+		}
+		var attrs []byte
+		if gw != nil {
+			attrs = nlAttr(syscall.RTA_GATEWAY, gw)
+		}
+
+		rtnh := make([]byte, syscall.SizeofRtNexthop)
+		binary.NativeEndian.PutUint16(rtnh[0:2], uint16(syscall.SizeofRtNexthop+len(attrs)))
+		if nh.OnLink {
+			rtnh[2] = rtnhFOnlink
+		}
+		if nh.Weight > 1 {
+			rtnh[3] = byte(nh.Weight - 1) // The kernel stores the weight minus one.
+		}
+		if nh.Interface != "" {
+			index, err := interfaceIndex(nh.Interface)
+			if err != nil {
+				return nil, err
+			}
+			binary.NativeEndian.PutUint32(rtnh[4:8], uint32(index))
+		}
+		b = append(b, rtnh...)
+		b = append(b, attrs...)
+	}
+
+	return b, nil
+}
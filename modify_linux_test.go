@@ -0,0 +1,285 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// inNewNetns reruns the calling test in a child process with its own network namespace and reports whether the
+// caller is that child, which may change routes without touching the host. The test is skipped without CAP_SYS_ADMIN.
+func inNewNetns(t *testing.T) bool {
+	t.Helper()
+	if os.Getenv("ROUTING_TEST_NETNS") != "" {
+		return true
+	}
+
+	var out bytes.Buffer
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "ROUTING_TEST_NETNS=1")
+	cmd.Stdout, cmd.Stderr = &out, &out
+	cmd.SysProcAttr = &syscall.SysProcAttr{Cloneflags: syscall.CLONE_NEWNET}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("Cannot create a network namespace: %s", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Errorf("Test failed in a new network namespace: %s\n%s", err, out.String())
+	}
+
+	return false
+}
+
+func TestAddDeleteSpecialRoutes(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+
+	routes, err := ParseIPRoute(strings.NewReader("blackhole 198.51.100.0/24 table 4242\nprohibit 203.0.113.0/24 table 4242 metric 7 mtu 1400 rtt 10ms\nunreachable 192.0.2.128/25 table 4242\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	for _, rt := range routes {
+		if err := AddRoute(rt); err != nil {
+			t.Errorf("AddRoute failed %s", err.Error())
+		}
+	}
+	if err := AddRoute(routes[0]); !errors.Is(err, os.ErrExist) {
+		t.Errorf("Expected adding a duplicate route to fail with ErrExist, got %v", err)
+	}
+
+	got, err := NetlinkSource{Table: 4242}.Routes(context.Background())
+	if err != nil {
+		t.Fatalf("NetlinkSource failed %s", err.Error())
+	}
+	expected := []string{
+		"unreachable 192.0.2.128/25 table 4242",
+		"blackhole 198.51.100.0/24 table 4242",
+		"prohibit 203.0.113.0/24 table 4242 metric 7 mtu 1400 rtt 10ms",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d routes, got %v", len(expected), got)
+	}
+	for i, rt := range got {
+		if rt.String() != expected[i] {
+			t.Errorf("Route %d = %q, want %q", i, rt.String(), expected[i])
+		}
+	}
+
+	for _, rt := range routes {
+		if err := DeleteRoute(rt); err != nil {
+			t.Errorf("DeleteRoute failed %s", err.Error())
+		}
+	}
+	if got, _ := (NetlinkSource{Table: 4242}).Routes(context.Background()); len(got) != 0 {
+		t.Errorf("Expected the table to be empty, got %v", got)
+	}
+}
+
+func TestRouteMsgDefaults(t *testing.T) {
+	rt := RoutingTable{Destination: "0000000A", Mask: "000000FF", Gateway: "0.0.0.0", Type: RouteTypeBlackhole}
+	b, err := routeMsg(routeAdd, rt)
+	if err != nil {
+		t.Fatalf("routeMsg failed %s", err.Error())
+	}
+	if b[1] != 8 || b[5] != rtprotBoot || b[6] != rtScopeUniverse || b[7] != byte(RouteTypeBlackhole) {
+		t.Errorf("Unexpected header % x", b[:sizeofRtMsg])
+	}
+
+	b, _ = routeMsg(routeAdd, RoutingTable{Destination: "0000000A", Mask: "000000FF"})
+	if b[6] != rtScopeLink || b[7] != byte(RouteTypeUnicast) {
+		t.Errorf("Expected a gateway-less unicast route to get link scope, got % x", b[:sizeofRtMsg])
+	}
+
+	b, _ = routeMsg(routeDelete, RoutingTable{Destination: "0000000A", Mask: "000000FF"})
+	if b[5] != rtprotUnspec || b[6] != rtScopeNowhere || b[7] != byte(RouteTypeUnspec) {
+		t.Errorf("Expected wildcard fields for a delete, got % x", b[:sizeofRtMsg])
+	}
+
+	if _, err := routeMsg(routeAdd, RoutingTable{Destination: "0000000A", Mask: "000000FF", Proto: "nonsense"}); err == nil {
+		t.Errorf("Expected an error for an unknown protocol")
+	}
+}
+
+func TestRouteMsgOnLink(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("10.0.0.0/8 via 203.0.113.1 dev lo onlink\n10.1.0.0/16\n\tnexthop via 203.0.113.1 dev lo weight 1 onlink\n\tnexthop via 203.0.113.2 dev lo weight 2\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	for _, rt := range routes {
+		b, err := routeMsg(routeAdd, rt)
+		if err != nil {
+			t.Fatalf("routeMsg failed %s", err.Error())
+		}
+		decoded, ok := parseRouteMsg(b, loadRouteNames())
+		if !ok {
+			t.Fatalf("parseRouteMsg rejected the message")
+		}
+		if decoded.OnLink != rt.OnLink || len(decoded.Nexthops) != len(rt.Nexthops) {
+			t.Errorf("Round trip of %q gave %q", rt, decoded)
+		}
+		for i := range rt.Nexthops {
+			if decoded.Nexthops[i] != rt.Nexthops[i] {
+				t.Errorf("Nexthop %d = %+v, want %+v", i, decoded.Nexthops[i], rt.Nexthops[i])
+			}
+		}
+	}
+	if !routes[0].OnLink || !routes[1].Nexthops[0].OnLink || routes[1].Nexthops[1].OnLink {
+		t.Errorf("Expected onlink flags to be parsed, got %+v", routes)
+	}
+}
+
+func TestRouteMetricsNetlinkRoundTrip(t *testing.T) {
+	m := RouteMetrics{MTU: 1400, RTT: 10 * time.Millisecond, RTTVar: 5 * time.Millisecond, RTOMin: 200 * time.Millisecond, AdvMSS: 1360, QuickAck: true, CongCtl: "bbr"}
+	if got := parseRouteMetrics(encodeRouteMetrics(m)); got != m {
+		t.Errorf("Round trip mismatch %+v %+v", m, got)
+	}
+}
+
+func TestInstallBlackhole(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+
+	for range 2 {
+		if err := InstallBlackhole("198.51.100.0/24"); err != nil {
+			t.Fatalf("InstallBlackhole failed %s", err.Error())
+		}
+	}
+	if err := InstallBlackhole("192.0.2.7"); err != nil {
+		t.Fatalf("InstallBlackhole failed %s", err.Error())
+	}
+
+	got, err := NetlinkSource{Table: TableMain}.Routes(context.Background())
+	if err != nil {
+		t.Fatalf("NetlinkSource failed %s", err.Error())
+	}
+	if len(got) != 2 || got[0].String() != "blackhole 192.0.2.7" || got[1].String() != "blackhole 198.51.100.0/24" {
+		t.Errorf("Unexpected routes %v", got)
+	}
+
+	if err := RemoveBlackhole("198.51.100.0/24"); err != nil {
+		t.Errorf("RemoveBlackhole failed %s", err.Error())
+	}
+	if err := RemoveBlackhole("198.51.100.0/24"); err == nil {
+		t.Error("Expected removing a missing blackhole to fail")
+	}
+}
+
+func TestBlackholeRoute(t *testing.T) {
+	rt, err := blackholeRoute("198.51.100.0/24")
+	if err != nil || rt.String() != "blackhole 198.51.100.0/24" {
+		t.Errorf("blackholeRoute = %q, %v", rt, err)
+	}
+	if _, err := blackholeRoute("2001:db8::/32"); !errors.Is(err, ErrParse) {
+		t.Errorf("Expected a parse error for an IPv6 prefix, got %v", err)
+	}
+}
+
+func TestRouteRealmsRoundTrip(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+
+	routes, err := ParseIPRoute(strings.NewReader("blackhole 198.51.100.0/24 table 4242 realm 5\nblackhole 203.0.113.0/24 table 4242 realms 3/7\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	for _, rt := range routes {
+		if err := AddRoute(rt); err != nil {
+			t.Fatalf("AddRoute failed %s", err.Error())
+		}
+	}
+
+	got, err := NetlinkSource{Table: 4242}.Routes(context.Background())
+	if err != nil {
+		t.Fatalf("NetlinkSource failed %s", err.Error())
+	}
+	expected := []string{
+		"blackhole 198.51.100.0/24 table 4242 realm 5",
+		"blackhole 203.0.113.0/24 table 4242 realms 3/7",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d routes, got %v", len(expected), got)
+	}
+	for i, rt := range got {
+		if rt.String() != expected[i] {
+			t.Errorf("Route %d = %q, want %q", i, rt.String(), expected[i])
+		}
+	}
+
+	if err := AddRoute(RoutingTable{Type: RouteTypeBlackhole, Destination: "000000C0", Mask: "00FFFFFF", Table: 4242, Realm: "nosuchrealm"}); err == nil {
+		t.Error("Expected an unknown realm to be rejected")
+	}
+}
+
+func TestRouteTOSRoundTrip(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+
+	routes, err := ParseIPRoute(strings.NewReader("blackhole 198.51.100.0/24 table 4242\nblackhole 198.51.100.0/24 tos 0x10 table 4242\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	for _, rt := range routes {
+		if err := AddRoute(rt); err != nil {
+			t.Fatalf("AddRoute failed %s", err.Error())
+		}
+	}
+
+	got, err := NetlinkSource{Table: 4242}.Routes(context.Background())
+	if err != nil {
+		t.Fatalf("NetlinkSource failed %s", err.Error())
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected routes differing by TOS to coexist, got %v", got)
+	}
+
+	if err := DeleteRoute(routes[1]); err != nil {
+		t.Fatalf("DeleteRoute failed %s", err.Error())
+	}
+	got, _ = NetlinkSource{Table: 4242}.Routes(context.Background())
+	if len(got) != 1 || got[0].TOS != 0 {
+		t.Errorf("Expected only the route without TOS to remain, got %v", got)
+	}
+}
+
+func TestRouteMetricRoundTrip(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+
+	routes, err := ParseIPRoute(strings.NewReader("blackhole 198.51.100.0/24 table 4242 metric 600\nblackhole 198.51.100.0/24 table 4242 metric 700\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	for _, rt := range routes {
+		if err := AddRoute(rt); err != nil {
+			t.Fatalf("AddRoute failed %s", err.Error())
+		}
+	}
+
+	got, err := NetlinkSource{Table: 4242}.Routes(context.Background())
+	if err != nil {
+		t.Fatalf("NetlinkSource failed %s", err.Error())
+	}
+	if len(got) != 2 || got[0].Metric != 600 || got[1].Metric != 700 {
+		t.Fatalf("Expected routes with metrics 600 and 700, got %v", got)
+	}
+
+	// A route read back deletes the route it was read from.
+	if err := DeleteRoute(got[0]); err != nil {
+		t.Fatalf("DeleteRoute failed %s", err.Error())
+	}
+	got, _ = NetlinkSource{Table: 4242}.Routes(context.Background())
+	if len(got) != 1 || got[0].Metric != 700 {
+		t.Errorf("Expected only the route with metric 700 to remain, got %v", got)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package routing
+
+import "context"
+
+// modifyRoute always returns ErrNotSupported, as netlink is only available on Linux.
+func modifyRoute(ctx context.Context, op routeOp, rt RoutingTable) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,66 @@
+package routing
+
+import (
+	"context"
+	"net"
+	"strings"
+)
+
+// NeighState is the reachability state of a neighbor entry, as reported by the kernel (NUD_* values).
+type NeighState uint16
+
+// Neighbor states, matching the kernel's NUD_* bits.
+const (
+	NeighIncomplete NeighState = 0x01
+	NeighReachable  NeighState = 0x02
+	NeighStale      NeighState = 0x04
+	NeighDelay      NeighState = 0x08
+	NeighProbe      NeighState = 0x10
+	NeighFailed     NeighState = 0x20
+	NeighNoARP      NeighState = 0x40
+	NeighPermanent  NeighState = 0x80
+)
+
+var neighStateNames = []struct {
+	state NeighState
+	name  string
+}{
+	{NeighIncomplete, "INCOMPLETE"},
+	{NeighReachable, "REACHABLE"},
+	{NeighStale, "STALE"},
+	{NeighDelay, "DELAY"},
+	{NeighProbe, "PROBE"},
+	{NeighFailed, "FAILED"},
+	{NeighNoARP, "NOARP"},
+	{NeighPermanent, "PERMANENT"},
+}
+
+// String returns the state names as printed by `ip neigh`, e.g. "STALE", joined by "|" if several bits are set.
+func (s NeighState) String() string {
+	var names []string
+	for _, n := range neighStateNames {
+		if s&n.state != 0 {
+			names = append(names, n.name)
+		}
+	}
+	if len(names) == 0 {
+		return "NONE"
+	}
+
+	return strings.Join(names, "|")
+}
+
+// Neighbor is an entry of the kernel neighbor table: an ARP entry for IPv4 or an NDP entry for IPv6.
+type Neighbor struct {
+	IP     net.IP           // The neighbor's address.
+	HWAddr net.HardwareAddr // The link-layer address; empty while unresolved.
+	Device string           // The network interface the neighbor is reachable through.
+	State  NeighState       // Reachability state.
+	Router bool             // Whether the neighbor advertised itself as an IPv6 router.
+}
+
+// Neighbors lists the IPv4 and IPv6 neighbor entries known to the kernel using netlink.
+// Unlike GetLinuxARPTable it includes IPv6 neighbors and exact reachability states.
+func Neighbors() ([]Neighbor, error) {
+	return NeighborsContext(context.Background())
+}
@@ -0,0 +1,64 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"syscall"
+)
+
+// Neighbor message layout (struct ndmsg) and attribute types from linux/neighbour.h.
+const (
+	sizeofNdMsg = 12
+	ndaDst      = 1
+	ndaLLAddr   = 2
+	ntfRouter   = 0x80
+)
+
+// NeighborsContext is like Neighbors but returns early if ctx is done.
+func NeighborsContext(ctx context.Context) ([]Neighbor, error) {
+	msgs, err := netlinkDump(ctx, syscall.RTM_GETNEIGH, syscall.AF_UNSPEC)
+	if err != nil {
+		return nil, err
+	}
+
+	var neighbors []Neighbor
+	for _, m := range msgs {
+		n, ok := parseNeighMsg(m.Data)
+		if ok {
+			neighbors = append(neighbors, n)
+		}
+	}
+
+	return neighbors, nil
+}
+
+// parseNeighMsg decodes the payload of an RTM_NEWNEIGH message.
+// Entries without a destination address, such as bridge FDB entries, are skipped.
+func parseNeighMsg(b []byte) (Neighbor, bool) {
+	if len(b) < sizeofNdMsg {
+		return Neighbor{}, false
+	}
+	family := b[0]
+	if family != syscall.AF_INET && family != syscall.AF_INET6 {
+		return Neighbor{}, false
+	}
+
+	attrs := netlinkAttrs(b[sizeofNdMsg:])
+	dst, ok := attrs[ndaDst]
+	if !ok {
+		return Neighbor{}, false
+	}
+
+	n := Neighbor{
+		IP:     net.IP(append([]byte(nil), dst...)),
+		Device: interfaceName(int(int32(binary.NativeEndian.Uint32(b[4:8])))),
+		State:  NeighState(binary.NativeEndian.Uint16(b[8:10])),
+		Router: b[10]&ntfRouter != 0,
+	}
+	if ll, ok := attrs[ndaLLAddr]; ok && len(ll) > 0 {
+		n.HWAddr = net.HardwareAddr(append([]byte(nil), ll...))
+	}
+
+	return n, true
+}
@@ -0,0 +1,41 @@
+package routing
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestParseNeighMsg(t *testing.T) {
+	msg := make([]byte, sizeofNdMsg)
+	msg[0] = syscall.AF_INET6
+	binary.NativeEndian.PutUint32(msg[4:8], 0)
+	binary.NativeEndian.PutUint16(msg[8:10], uint16(NeighStale))
+	msg[10] = ntfRouter
+	msg = append(msg, nlAttr(ndaDst, net.ParseIP("fe80::1"))...)
+	msg = append(msg, nlAttr(ndaLLAddr, []byte{0x02, 0, 0, 0, 0, 1})...)
+
+	n, ok := parseNeighMsg(msg)
+	if !ok {
+		t.Fatalf("parseNeighMsg rejected the message")
+	}
+	if n.IP.String() != "fe80::1" || n.HWAddr.String() != "02:00:00:00:00:01" || n.State != NeighStale || !n.Router {
+		t.Errorf("Unexpected neighbor %+v", n)
+	}
+	if n.State.String() != "STALE" {
+		t.Errorf("Unexpected state name %s", n.State)
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	neighbors, err := Neighbors()
+	if err != nil {
+		t.Fatalf("Calling routing library failed %s", err.Error())
+	}
+	for _, n := range neighbors {
+		if n.IP == nil || n.Device == "" {
+			t.Errorf("Incomplete neighbor %+v", n)
+		}
+	}
+}
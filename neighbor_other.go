@@ -0,0 +1,11 @@
+//go:build !linux
+
+package routing
+
+import "context"
+
+// NeighborsContext is like Neighbors but returns early if ctx is done.
+// Netlink is only available on Linux, so it always returns ErrNotSupported here.
+func NeighborsContext(ctx context.Context) ([]Neighbor, error) {
+	return nil, ErrNotSupported
+}
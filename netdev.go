@@ -0,0 +1,168 @@
+package routing
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// procNetDevPath is the location of the kernel's per-interface counters.
+const procNetDevPath = "/proc/net/dev"
+
+// InterfaceStats holds the traffic counters of a network interface from /proc/net/dev.
+type InterfaceStats struct {
+	Interface    string // The network interface name.
+	RxBytes      uint64 // Bytes received.
+	RxPackets    uint64 // Packets received.
+	RxErrors     uint64 // Receive errors.
+	RxDropped    uint64 // Received packets dropped.
+	RxFIFO       uint64 // Receive FIFO buffer errors.
+	RxFrame      uint64 // Receive framing errors.
+	RxCompressed uint64 // Compressed packets received.
+	RxMulticast  uint64 // Multicast frames received.
+	TxBytes      uint64 // Bytes transmitted.
+	TxPackets    uint64 // Packets transmitted.
+	TxErrors     uint64 // Transmit errors.
+	TxDropped    uint64 // Transmitted packets dropped.
+	TxFIFO       uint64 // Transmit FIFO buffer errors.
+	TxCollisions uint64 // Collisions detected while transmitting.
+	TxCarrier    uint64 // Carrier losses detected while transmitting.
+	TxCompressed uint64 // Compressed packets transmitted.
+}
+
+// netDevColumns names the counter columns of /proc/net/dev, in order, for error messages.
+var netDevColumns = []string{
+	"rx_bytes", "rx_packets", "rx_errs", "rx_drop", "rx_fifo", "rx_frame", "rx_compressed", "rx_multicast",
+	"tx_bytes", "tx_packets", "tx_errs", "tx_drop", "tx_fifo", "tx_colls", "tx_carrier", "tx_compressed",
+}
+
+// GetLinuxInterfaceStats reads /proc/net/dev and appends the counters of every interface to stats.
+func GetLinuxInterfaceStats(stats *[]InterfaceStats) error {
+	return GetLinuxInterfaceStatsContext(context.Background(), stats)
+}
+
+// GetLinuxInterfaceStatsContext is like GetLinuxInterfaceStats but returns early if ctx is done.
+func GetLinuxInterfaceStatsContext(ctx context.Context, stats *[]InterfaceStats) error {
+	parsed, err := defaultManager.InterfaceStats(ctx)
+	if err != nil {
+		return err
+	}
+	*stats = append(*stats, parsed...)
+
+	return nil
+}
+
+// InterfaceStats reads /proc/net/dev from the manager's filesystem, the host's unless WithFS was given.
+func (m *Manager) InterfaceStats(ctx context.Context) ([]InterfaceStats, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f, fErr := openProc(m.fsys, procNetDevPath)
+	if fErr != nil {
+		return nil, procUnavailable(m.fsys, procNetDevPath, fErr)
+	}
+	defer f.Close()
+
+	parsed, err := ParseNetDev(f)
+	if err != nil {
+		var pErr *ParseError
+		if errors.As(err, &pErr) {
+			pErr.File = procNetDevPath
+		}
+		return nil, err
+	}
+
+	return parsed, nil
+}
+
+// ParseNetDev parses interface counters in the format of /proc/net/dev read from r.
+// The two header lines are skipped.
+func ParseNetDev(r io.Reader) ([]InterfaceStats, error) {
+	var stats []InterfaceStats
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if line <= 2 || strings.TrimSpace(scanner.Text()) == "" {
+			continue // Skip the header rows and blank lines.
+		}
+
+		name, counters, found := strings.Cut(scanner.Text(), ":")
+		fields := strings.Fields(counters)
+		if !found || len(fields) < len(netDevColumns) {
+			return nil, &ParseError{Line: line, Value: scanner.Text(), Err: errTruncatedRow}
+		}
+
+		var values [16]uint64
+		for i := range values {
+			v, err := strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				return nil, &ParseError{Line: line, Column: netDevColumns[i], Value: fields[i], Err: err}
+			}
+			values[i] = v
+		}
+
+		stats = append(stats, InterfaceStats{
+			Interface:    strings.TrimSpace(name),
+			RxBytes:      values[0],
+			RxPackets:    values[1],
+			RxErrors:     values[2],
+			RxDropped:    values[3],
+			RxFIFO:       values[4],
+			RxFrame:      values[5],
+			RxCompressed: values[6],
+			RxMulticast:  values[7],
+			TxBytes:      values[8],
+			TxPackets:    values[9],
+			TxErrors:     values[10],
+			TxDropped:    values[11],
+			TxFIFO:       values[12],
+			TxCollisions: values[13],
+			TxCarrier:    values[14],
+			TxCompressed: values[15],
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// DefaultInterfaceStats returns the counters of the interface holding the default route.
+func DefaultInterfaceStats() (InterfaceStats, error) {
+	return DefaultInterfaceStatsContext(context.Background())
+}
+
+// DefaultInterfaceStatsContext is like DefaultInterfaceStats but returns early if ctx is done.
+func DefaultInterfaceStatsContext(ctx context.Context) (InterfaceStats, error) {
+	return defaultManager.DefaultInterfaceStats(ctx)
+}
+
+// DefaultInterfaceStats is like the package-level DefaultInterfaceStats but uses the manager's routes and counters.
+func (m *Manager) DefaultInterfaceStats(ctx context.Context) (InterfaceStats, error) {
+	iface, err := m.DefaultInterface(ctx)
+	if err != nil {
+		return InterfaceStats{}, err
+	}
+
+	stats, err := m.InterfaceStats(ctx)
+	if err != nil {
+		return InterfaceStats{}, err
+	}
+
+	for _, s := range stats {
+		if s.Interface == iface {
+			return s, nil
+		}
+	}
+
+	return InterfaceStats{}, fmt.Errorf("no counters for interface %s in %s", iface, procNetDevPath)
+}
@@ -0,0 +1,38 @@
+package routing
+
+import (
+	"strings"
+	"testing"
+)
+
+const netDevFixture = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:    1200      12    0    0    0     0          0         0     1200      12    0    0    0     0       0          0
+  eth0:12345678901 9876543 1 2 3 4 5 6 1098765432 123456 7 8 9 10 11 12
+`
+
+func TestParseNetDev(t *testing.T) {
+	stats, err := ParseNetDev(strings.NewReader(netDevFixture))
+	if err != nil {
+		t.Fatalf("ParseNetDev failed %s", err.Error())
+	}
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 interfaces, got %d", len(stats))
+	}
+
+	eth0 := stats[1]
+	if eth0.Interface != "eth0" || eth0.RxBytes != 12345678901 || eth0.RxDropped != 2 || eth0.TxBytes != 1098765432 || eth0.TxDropped != 8 || eth0.TxCompressed != 12 {
+		t.Errorf("Unexpected counters %+v", eth0)
+	}
+}
+
+func TestDefaultInterfaceStats(t *testing.T) {
+	useProcFS(t)
+	stats, err := DefaultInterfaceStats()
+	if err != nil {
+		t.Fatalf("Calling routing library failed %s", err.Error())
+	}
+	if stats.Interface == "" {
+		t.Errorf("Expected an interface name in %+v", stats)
+	}
+}
@@ -0,0 +1,163 @@
+package routing
+
+import (
+	"context"
+	"net"
+	"net/netip"
+)
+
+// The methods below give the addresses of the package's types as net/netip values, which are comparable and
+// allocation free, so they can key maps and be compared with ==. Missing and unspecified addresses, such as the
+// "0.0.0.0" gateway of a directly connected route, become the zero Addr, for which IsValid reports false.
+// IPv6 link-local addresses, which are only unique on their link, carry the interface of the route or neighbor as
+// their zone, e.g. fe80::1%eth0, so they can be dialed and tell apart neighbors on different links.
+
+// ParseHexAddr decodes an IPv4 address in the little-endian hex form of RoutingTable's Destination and Mask,
+// e.g. "0100A8C0" for 192.168.0.1.
+func ParseHexAddr(s string) (netip.Addr, error) {
+	ip, err := parseHexIP(s)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	return IPAddr(ip), nil
+}
+
+// IPAddr converts ip to an Addr, unmapping IPv4-mapped IPv6 addresses; the zero Addr if ip is nil or malformed.
+func IPAddr(ip net.IP) netip.Addr {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return netip.Addr{}
+	}
+
+	return addr.Unmap()
+}
+
+// AddrIP converts addr to a net.IP for the functions taking one; nil for the zero Addr.
+func AddrIP(addr netip.Addr) net.IP {
+	if !addr.IsValid() {
+		return nil
+	}
+
+	return addr.AsSlice()
+}
+
+// Prefix returns the destination of the route, masked.
+func (rt RoutingTable) Prefix() (netip.Prefix, error) {
+	dst, _, ones, err := decodeDestination(rt)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	return netip.PrefixFrom(IPAddr(dst), ones).Masked(), nil
+}
+
+// GatewayAddr returns the gateway of the route; the zero Addr if it is directly connected or the gateway is malformed.
+func (rt RoutingTable) GatewayAddr() netip.Addr {
+	return linkLocalZone(stringAddr(rt.Gateway), rt.Interface)
+}
+
+// PrefSrcAddr returns the preferred source address of the route; the zero Addr if it has none.
+func (rt RoutingTable) PrefSrcAddr() netip.Addr {
+	return stringAddr(rt.PrefSrc)
+}
+
+// GatewayAddr returns the gateway of the path; the zero Addr if it is directly connected.
+func (nh Nexthop) GatewayAddr() netip.Addr {
+	return linkLocalZone(stringAddr(nh.Gateway), nh.Interface)
+}
+
+// Prefix returns the destination of the route; the zero Prefix if it has none.
+func (r IPv6Route) Prefix() netip.Prefix {
+	return ipNetPrefix(r.Destination)
+}
+
+// SourcePrefix returns the source prefix of a source-specific route; the zero Prefix for other routes.
+func (r IPv6Route) SourcePrefix() netip.Prefix {
+	if src := ipNetPrefix(r.Source); src.IsValid() && src.Bits() > 0 {
+		return src
+	}
+
+	return netip.Prefix{}
+}
+
+// GatewayAddr returns the next hop of the route; the zero Addr if it is directly connected.
+func (r IPv6Route) GatewayAddr() netip.Addr {
+	if addr := IPAddr(r.Gateway); addr.IsValid() && !addr.IsUnspecified() {
+		return linkLocalZone(addr, r.Interface)
+	}
+
+	return netip.Addr{}
+}
+
+// Addr returns the address of the neighbor.
+func (e ARPEntry) Addr() netip.Addr {
+	return IPAddr(e.IP)
+}
+
+// Addr returns the address of the neighbor.
+func (n Neighbor) Addr() netip.Addr {
+	return linkLocalZone(IPAddr(n.IP), n.Device)
+}
+
+// SrcPrefix returns the source prefix the rule selects; the zero Prefix if it matches all sources.
+func (r Rule) SrcPrefix() netip.Prefix {
+	return ipNetPrefix(r.Src)
+}
+
+// DstPrefix returns the destination prefix the rule selects; the zero Prefix if it matches all destinations.
+func (r Rule) DstPrefix() netip.Prefix {
+	return ipNetPrefix(r.Dst)
+}
+
+// LookupRouteAddr is like LookupRoute but takes a netip.Addr.
+func LookupRouteAddr(routes []RoutingTable, addr netip.Addr) (RoutingTable, bool) {
+	return LookupRoute(routes, AddrIP(addr))
+}
+
+// LookupAddr is like Lookup but takes a netip.Addr.
+func (m *Manager) LookupAddr(ctx context.Context, addr netip.Addr) (RoutingTable, bool, error) {
+	return m.Lookup(ctx, AddrIP(addr))
+}
+
+// stringAddr parses an address of a route; the zero Addr if it is empty, malformed or unspecified.
+func stringAddr(s string) netip.Addr {
+	addr, err := netip.ParseAddr(s)
+	if err != nil || addr.IsUnspecified() {
+		return netip.Addr{}
+	}
+
+	return addr.Unmap()
+}
+
+// linkLocalZone returns addr with iface as its zone if it is an IPv6 link-local address, and addr unchanged otherwise,
+// also when it already has a zone.
+func linkLocalZone(addr netip.Addr, iface string) netip.Addr {
+	if !addr.Is6() || addr.Zone() != "" || iface == "" || !addr.IsLinkLocalUnicast() && !addr.IsLinkLocalMulticast() {
+		return addr
+	}
+
+	return addr.WithZone(iface)
+}
+
+// ipNetPrefix converts n to a Prefix, the zero Prefix if n is nil or malformed.
+func ipNetPrefix(n *net.IPNet) netip.Prefix {
+	if n == nil {
+		return netip.Prefix{}
+	}
+	addr, ok := netip.AddrFromSlice(n.IP)
+	ones, bits := n.Mask.Size()
+	if bits == 32 {
+		addr = addr.Unmap()
+	}
+	if !ok || bits != addr.BitLen() {
+		return netip.Prefix{}
+	}
+
+	return netip.PrefixFrom(addr, ones)
+}
+
+// prefixIPNet converts p to an IPNet.
+func prefixIPNet(p netip.Prefix) *net.IPNet {
+	return &net.IPNet{IP: p.Addr().AsSlice(), Mask: net.CIDRMask(p.Bits(), p.Addr().BitLen())}
+}
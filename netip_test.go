@@ -0,0 +1,153 @@
+package routing
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestParseHexAddr(t *testing.T) {
+	addr, err := ParseHexAddr("0100A8C0")
+	if err != nil || addr != netip.MustParseAddr("192.168.0.1") {
+		t.Errorf("ParseHexAddr() = %v, %v, want 192.168.0.1", addr, err)
+	}
+	if _, err := ParseHexAddr("zz"); err == nil {
+		t.Error("Expected an error for a malformed address")
+	}
+}
+
+func TestRoutingTableAddrs(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev eth0 src 192.0.2.10\n" +
+		"192.0.2.0/24 dev eth0\n" +
+		"198.51.100.0/24\n\tnexthop via 192.0.2.2 dev eth0\n\tnexthop dev wg0\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	// Addresses are comparable, so they can key maps.
+	byGateway := make(map[netip.Addr][]netip.Prefix)
+	for _, rt := range routes {
+		p, err := rt.Prefix()
+		if err != nil {
+			t.Fatalf("Prefix failed %s", err.Error())
+		}
+		byGateway[rt.GatewayAddr()] = append(byGateway[rt.GatewayAddr()], p)
+	}
+	if got := byGateway[netip.MustParseAddr("192.0.2.1")]; len(got) != 1 || got[0] != netip.MustParsePrefix("0.0.0.0/0") {
+		t.Errorf("Routes via 192.0.2.1 = %v, want the default route", got)
+	}
+	if got := byGateway[netip.Addr{}]; len(got) != 1 || got[0] != netip.MustParsePrefix("192.0.2.0/24") {
+		t.Errorf("Directly connected routes = %v, want 192.0.2.0/24", got)
+	}
+
+	if got := routes[0].PrefSrcAddr(); got != netip.MustParseAddr("192.0.2.10") {
+		t.Errorf("PrefSrcAddr() = %v, want 192.0.2.10", got)
+	}
+	if got := routes[1].PrefSrcAddr(); got.IsValid() {
+		t.Errorf("PrefSrcAddr() = %v, want the zero Addr", got)
+	}
+	nhs := routes[2].Nexthops
+	if len(nhs) != 2 || nhs[0].GatewayAddr() != netip.MustParseAddr("192.0.2.2") || nhs[1].GatewayAddr().IsValid() {
+		t.Errorf("Unexpected nexthop gateways in %+v", nhs)
+	}
+}
+
+func TestIPv6RouteAddrs(t *testing.T) {
+	routes, err := ParseIPv6Routes(strings.NewReader(ipv6RouteFixture))
+	if err != nil {
+		t.Fatalf("ParseIPv6Routes failed %s", err.Error())
+	}
+	if got := routes[0].Prefix(); got != netip.MustParsePrefix("::/0") {
+		t.Errorf("Prefix() = %v, want ::/0", got)
+	}
+	if got := routes[0].GatewayAddr(); got != netip.MustParseAddr("fe80::1%eth0") {
+		t.Errorf("GatewayAddr() = %v, want fe80::1%%eth0", got)
+	}
+	if got := routes[1].GatewayAddr(); got.IsValid() {
+		t.Errorf("GatewayAddr() = %v, want the zero Addr", got)
+	}
+}
+
+func TestIPAddr(t *testing.T) {
+	if got := IPAddr(net.ParseIP("192.0.2.1")); got != netip.MustParseAddr("192.0.2.1") {
+		t.Errorf("IPAddr() = %v, want the unmapped IPv4 address", got)
+	}
+	if got := IPAddr(nil); got.IsValid() {
+		t.Errorf("IPAddr(nil) = %v, want the zero Addr", got)
+	}
+	if got := AddrIP(netip.Addr{}); got != nil {
+		t.Errorf("AddrIP() = %v, want nil", got)
+	}
+	if got := AddrIP(netip.MustParseAddr("2001:db8::1")); !got.Equal(net.ParseIP("2001:db8::1")) {
+		t.Errorf("AddrIP() = %v, want 2001:db8::1", got)
+	}
+
+	_, src, _ := net.ParseCIDR("10.8.0.0/24")
+	r := Rule{Src: src}
+	if r.SrcPrefix() != netip.MustParsePrefix("10.8.0.0/24") || r.DstPrefix().IsValid() {
+		t.Errorf("SrcPrefix() = %v, DstPrefix() = %v", r.SrcPrefix(), r.DstPrefix())
+	}
+}
+
+func TestManagerLookupAddr(t *testing.T) {
+	m := NewManager(WithFS(procFS))
+	rt, ok, err := m.LookupAddr(context.Background(), netip.MustParseAddr("192.0.2.77"))
+	if err != nil || !ok || rt.Interface != "eth0" || rt.GatewayAddr().IsValid() {
+		t.Errorf("LookupAddr() = %s, %v, %v, want the eth0 subnet route", rt, ok, err)
+	}
+}
+
+func TestLinkLocalZones(t *testing.T) {
+	rt := RoutingTable{Gateway: "fe80::2", Interface: "wlan0", Nexthops: []Nexthop{
+		{Gateway: "fe80::3", Interface: "eth1"},
+		{Gateway: "2001:db8::1", Interface: "eth2"},
+		{Gateway: "fe80::4%eth3", Interface: "eth2"},
+	}}
+	want := []string{"fe80::2%wlan0", "fe80::3%eth1", "2001:db8::1", "fe80::4%eth3"}
+	got := []netip.Addr{rt.GatewayAddr()}
+	for _, nh := range rt.Nexthops {
+		got = append(got, nh.GatewayAddr())
+	}
+	for i, addr := range got {
+		if addr != netip.MustParseAddr(want[i]) {
+			t.Errorf("Gateway %d = %v, want %s", i, addr, want[i])
+		}
+	}
+
+	// The same link-local address on two links belongs to two different neighbors.
+	a := Neighbor{IP: net.ParseIP("fe80::1"), Device: "eth0"}
+	b := Neighbor{IP: net.ParseIP("fe80::1"), Device: "wlan0"}
+	if a.Addr() == b.Addr() || a.Addr().Zone() != "eth0" {
+		t.Errorf("Addr() = %v and %v, want fe80::1 zoned with each device", a.Addr(), b.Addr())
+	}
+	if got := (Neighbor{IP: net.ParseIP("2001:db8::7"), Device: "eth0"}).Addr(); got != netip.MustParseAddr("2001:db8::7") {
+		t.Errorf("Addr() = %v, want 2001:db8::7 without a zone", got)
+	}
+	if got := (ARPEntry{IP: net.ParseIP("192.0.2.1"), Device: "eth0"}).Addr(); got != netip.MustParseAddr("192.0.2.1") {
+		t.Errorf("Addr() = %v, want 192.0.2.1", got)
+	}
+
+	// Converting back to net.IP drops the zone, which net.IP cannot hold.
+	if ip := AddrIP(a.Addr()); !ip.Equal(net.ParseIP("fe80::1")) {
+		t.Errorf("AddrIP() = %v, want fe80::1", ip)
+	}
+}
+
+func TestIPv6RouteSourcePrefix(t *testing.T) {
+	_, src, _ := net.ParseCIDR("2001:db8:1::/48")
+	_, any, _ := net.ParseCIDR("::/0")
+	if got := (IPv6Route{Source: src}).SourcePrefix(); got != netip.MustParsePrefix("2001:db8:1::/48") {
+		t.Errorf("SourcePrefix() = %v, want 2001:db8:1::/48", got)
+	}
+	if got := (IPv6Route{Source: any}).SourcePrefix(); got.IsValid() {
+		t.Errorf("SourcePrefix() = %v, want the zero Prefix for a route from any source", got)
+	}
+
+	_, dst, _ := net.ParseCIDR("2001:db8:2::/64")
+	r := Rule{Family: FamilyIPv6, Dst: dst}
+	if r.DstPrefix() != netip.MustParsePrefix("2001:db8:2::/64") || r.SrcPrefix().IsValid() {
+		t.Errorf("DstPrefix() = %v, SrcPrefix() = %v", r.DstPrefix(), r.SrcPrefix())
+	}
+}
@@ -0,0 +1,314 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// nlAttrAlign rounds an attribute length up to the netlink alignment of four bytes.
+func nlAttrAlign(n int) int {
+	return (n + syscall.RTA_ALIGNTO - 1) &^ (syscall.RTA_ALIGNTO - 1)
+}
+
+// nlAttr encodes a single netlink attribute, padded to the netlink alignment.
+func nlAttr(typ uint16, data []byte) []byte {
+	b := make([]byte, nlAttrAlign(syscall.SizeofRtAttr+len(data)))
+	binary.NativeEndian.PutUint16(b[0:2], uint16(syscall.SizeofRtAttr+len(data)))
+	binary.NativeEndian.PutUint16(b[2:4], typ)
+	copy(b[syscall.SizeofRtAttr:], data)
+
+	return b
+}
+
+// netlinkAttrs splits a buffer of netlink attributes into a map keyed by attribute type.
+// Nested-attribute and byte-order flag bits are masked off the type. Truncated attributes end the scan.
+func netlinkAttrs(b []byte) map[uint16][]byte {
+	attrs := make(map[uint16][]byte)
+	for len(b) >= syscall.SizeofRtAttr {
+		l := int(binary.NativeEndian.Uint16(b[0:2]))
+		t := binary.NativeEndian.Uint16(b[2:4]) & 0x3fff
+		if l < syscall.SizeofRtAttr || l > len(b) {
+			break
+		}
+		attrs[t] = b[syscall.SizeofRtAttr:l]
+		if nlAttrAlign(l) > len(b) {
+			break
+		}
+		b = b[nlAttrAlign(l):]
+	}
+
+	return attrs
+}
+
+// nlmFDumpIntr is set on the messages of a dump that was inconsistent because the table changed meanwhile.
+const nlmFDumpIntr = 0x10
+
+// netlinkDump requests a dump of the given type and family from the kernel over NETLINK_ROUTE.
+// Only messages of the matching "new" type are returned. An inconsistent dump fails with errDumpInterrupted.
+func netlinkDump(ctx context.Context, typ, family int) ([]syscall.NetlinkMessage, error) {
+	msgs, err := netlinkRequest(ctx, uint16(typ), syscall.NLM_F_DUMP, []byte{byte(family)}) // A bare rtgenmsg.
+	if err != nil {
+		return nil, err
+	}
+
+	out := msgs[:0]
+	for _, m := range msgs {
+		if m.Header.Type == uint16(typ-2) { // RTM_GETx is RTM_NEWx + 2.
+			out = append(out, m)
+		}
+	}
+
+	return out, nil
+}
+
+// netlinkRequest sends a single request to the kernel over NETLINK_ROUTE and waits for its acknowledgement.
+// Messages the kernel sends before the acknowledgement, such as the answer to a get request, are returned.
+// A negative acknowledgement is returned as the syscall.Errno the kernel reported. Dump requests, made with
+// NLM_F_DUMP in flags, end with NLMSG_DONE instead. Waiting for the kernel's answer stops once ctx is done.
+func netlinkRequest(ctx context.Context, typ, flags uint16, data []byte) ([]syscall.NetlinkMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// The socket is non-blocking and registered with the runtime poller, as in netlinkSubscribe, so reads can be cancelled.
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC|syscall.SOCK_NONBLOCK, syscall.NETLINK_ROUTE)
+	if err != nil {
+		return nil, err
+	}
+	f := os.NewFile(uintptr(fd), "netlink")
+	defer f.Close()
+	raw, err := f.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	const seq = 1
+	msg := make([]byte, syscall.NLMSG_HDRLEN, syscall.NLMSG_HDRLEN+len(data))
+	binary.NativeEndian.PutUint32(msg[0:4], uint32(syscall.NLMSG_HDRLEN+len(data)))
+	binary.NativeEndian.PutUint16(msg[4:6], typ)
+	binary.NativeEndian.PutUint16(msg[6:8], syscall.NLM_F_REQUEST|syscall.NLM_F_ACK|flags)
+	binary.NativeEndian.PutUint32(msg[8:12], seq)
+	msg = append(msg, data...)
+	// The socket is not bound, as Android keeps apps from binding NETLINK_ROUTE sockets; the kernel assigns it a
+	// port on the first send.
+	if err := syscall.Sendto(fd, msg, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return nil, err
+	}
+
+	stop := context.AfterFunc(ctx, func() { f.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	var replies []syscall.NetlinkMessage
+	buf := make([]byte, syscall.Getpagesize())
+	for {
+		var n int
+		var recvErr error
+		err := raw.Read(func(fd uintptr) bool {
+			n, _, recvErr = syscall.Recvfrom(int(fd), buf, 0)
+			return recvErr != syscall.EAGAIN
+		})
+		if err == nil {
+			err = recvErr
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range msgs {
+			if m.Header.Seq != seq {
+				continue
+			}
+			if m.Header.Flags&nlmFDumpIntr != 0 {
+				return nil, errDumpInterrupted
+			}
+			if m.Header.Type == syscall.NLMSG_DONE {
+				return replies, nil
+			}
+			if m.Header.Type != syscall.NLMSG_ERROR {
+				m.Data = bytes.Clone(m.Data) // buf is reused for the next read.
+				replies = append(replies, m)
+				continue
+			}
+			if len(m.Data) < 4 {
+				continue
+			}
+			if errno := int32(binary.NativeEndian.Uint32(m.Data[0:4])); errno != 0 {
+				return nil, syscall.Errno(-errno)
+			}
+			return replies, nil
+		}
+	}
+}
+
+// errNotificationsLost is returned by nlSubscription.Receive when the kernel dropped notifications
+// the socket had no room for; callers must read the current state again.
+var errNotificationsLost = errors.New("netlink notifications lost")
+
+// nlSubscription is a NETLINK_ROUTE socket joined to multicast groups, receiving the kernel's change notifications.
+type nlSubscription struct {
+	f   *os.File
+	raw syscall.RawConn
+	buf []byte
+}
+
+// solNetlink is the socket option level of netlink sockets, which the syscall package does not define.
+const solNetlink = 270
+
+// netlinkSubscribe opens a socket receiving the notifications of the given RTNLGRP_* groups.
+// The socket is non-blocking and registered with the runtime poller, so Receive can be cancelled.
+// Android forbids apps to bind NETLINK_ROUTE sockets, so the socket gets its port from connecting to the kernel,
+// without which the kernel does not deliver notifications to it, and joins the groups with NETLINK_ADD_MEMBERSHIP.
+func netlinkSubscribe(groups ...uint32) (*nlSubscription, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC|syscall.SOCK_NONBLOCK, syscall.NETLINK_ROUTE)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Connect(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+	for _, g := range groups {
+		if err := syscall.SetsockoptInt(fd, solNetlink, syscall.NETLINK_ADD_MEMBERSHIP, int(g)); err != nil {
+			syscall.Close(fd)
+			return nil, err
+		}
+	}
+
+	f := os.NewFile(uintptr(fd), "netlink")
+	raw, err := f.SyscallConn()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &nlSubscription{f: f, raw: raw, buf: make([]byte, 1<<16)}, nil
+}
+
+// Receive blocks until notifications arrive and returns them. It returns ctx.Err() once ctx is done.
+// The returned messages are copies and stay valid after the next call.
+func (s *nlSubscription) Receive(ctx context.Context) ([]syscall.NetlinkMessage, error) {
+	stop := context.AfterFunc(ctx, func() { s.f.SetReadDeadline(time.Now()) })
+	defer stop()
+	defer s.f.SetReadDeadline(time.Time{})
+
+	for {
+		var n int
+		var recvErr error
+		err := s.raw.Read(func(fd uintptr) bool {
+			n, _, recvErr = syscall.Recvfrom(int(fd), s.buf, 0)
+			return recvErr != syscall.EAGAIN
+		})
+		if err == nil {
+			err = recvErr
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if errors.Is(err, syscall.ENOBUFS) {
+				return nil, errNotificationsLost
+			}
+			return nil, err
+		}
+
+		msgs, err := syscall.ParseNetlinkMessage(s.buf[:n])
+		if err != nil {
+			return nil, err
+		}
+		for i := range msgs {
+			msgs[i].Data = bytes.Clone(msgs[i].Data)
+		}
+		if len(msgs) > 0 {
+			return msgs, nil
+		}
+	}
+}
+
+// Drain discards the notifications received so far without blocking and reports whether there were any.
+// Lost notifications count as received.
+func (s *nlSubscription) Drain() (bool, error) {
+	received := false
+	for {
+		var recvErr error
+		err := s.raw.Read(func(fd uintptr) bool {
+			_, _, recvErr = syscall.Recvfrom(int(fd), s.buf, 0)
+			return true
+		})
+		if err == nil {
+			err = recvErr
+		}
+		switch {
+		case err == syscall.EAGAIN:
+			return received, nil
+		case err == nil, errors.Is(err, syscall.ENOBUFS):
+			received = true
+		default:
+			return received, err
+		}
+	}
+}
+
+// Close closes the socket.
+func (s *nlSubscription) Close() error {
+	return s.f.Close()
+}
+
+// nlUint32 decodes a native-endian 32-bit attribute value, returning zero if it is too short.
+func nlUint32(b []byte) uint32 {
+	if len(b) < 4 {
+		return 0
+	}
+
+	return binary.NativeEndian.Uint32(b)
+}
+
+// nlUint32Bytes encodes v as a native-endian 32-bit attribute value.
+func nlUint32Bytes(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.NativeEndian.PutUint32(b, v)
+
+	return b
+}
+
+// nlString decodes a NUL-terminated string attribute.
+func nlString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+
+	return string(b)
+}
+
+// familyFromAF maps a kernel address family to a Family.
+func familyFromAF(af uint8) Family {
+	switch af {
+	case syscall.AF_INET:
+		return FamilyIPv4
+	case syscall.AF_INET6:
+		return FamilyIPv6
+	}
+
+	return FamilyUnspec
+}
+
+// interfaceName resolves an interface index to its name, falling back to the empty string.
+func interfaceName(index int) string {
+	iface, err := net.InterfaceByIndex(index)
+	if err != nil {
+		return ""
+	}
+
+	return iface.Name
+}
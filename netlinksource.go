@@ -0,0 +1,32 @@
+package routing
+
+import (
+	"context"
+	"errors"
+)
+
+var errTableUnspec = errors.New("table 0 is not a routing table; use NetlinkSource to read every table")
+
+// NetlinkSource reads routes of every type from the kernel over netlink: IPv4 through Routes and IPv6 through IPv6Routes.
+// Unlike /proc/net/route it can read tables other than main; each route's Table field records where it came from.
+// It is only available on Linux and returns ErrNotSupported elsewhere.
+type NetlinkSource struct {
+	Table int // Table to read; TableUnspec (zero) reads every table.
+}
+
+// RoutesInTable reads the IPv4 routes of the kernel routing table with the given ID over netlink, such as
+// TableLocal or a table used by a VPN client for policy routing, which /proc/net/route never shows.
+// TableID resolves names from rt_tables. NetlinkSource{Table: tableID}.IPv6Routes reads the table's IPv6 routes.
+// It is only available on Linux and returns ErrNotSupported elsewhere.
+func RoutesInTable(tableID int) ([]RoutingTable, error) {
+	return RoutesInTableContext(context.Background(), tableID)
+}
+
+// RoutesInTableContext is like RoutesInTable but returns early if ctx is done.
+func RoutesInTableContext(ctx context.Context, tableID int) ([]RoutingTable, error) {
+	if tableID == TableUnspec {
+		return nil, errTableUnspec
+	}
+
+	return NetlinkSource{Table: tableID}.Routes(ctx)
+}
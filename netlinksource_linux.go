@@ -0,0 +1,317 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"net"
+	"syscall"
+	"time"
+)
+
+// Route message layout (struct rtmsg) and flags from linux/rtnetlink.h.
+const (
+	sizeofRtMsg   = 12
+	rtnhFDead     = 0x1
+	rtnhFLinkdown = 0x10
+	rtaTableAttr  = 15
+	rtnhFOnlink   = 0x4
+	rtmFCloned    = 0x200
+	rtprotRA      = 9
+	userHZ        = 100 // Ticks per second of the clock_t values the kernel reports, e.g. in struct rta_cacheinfo.
+)
+
+// Route metric attributes nested in RTA_METRICS, from linux/rtnetlink.h.
+const (
+	rtaxMTU        = 2
+	rtaxWindow     = 3
+	rtaxRTT        = 4
+	rtaxRTTVar     = 5
+	rtaxSSThresh   = 6
+	rtaxCWnd       = 7
+	rtaxAdvMSS     = 8
+	rtaxReordering = 9
+	rtaxHopLimit   = 10
+	rtaxInitCwnd   = 11
+	rtaxRTOMin     = 13
+	rtaxInitRwnd   = 14
+	rtaxQuickAck   = 15
+	rtaxCCAlgo     = 16
+)
+
+// Routes dumps the kernel routing tables and returns the IPv4 routes of the selected table.
+// Routes using a nexthop object the kernel reports without its paths are resolved; see ResolveNexthopObjects.
+func (s NetlinkSource) Routes(ctx context.Context) ([]RoutingTable, error) {
+	msgs, err := netlinkDump(ctx, syscall.RTM_GETROUTE, syscall.AF_INET)
+	if err != nil {
+		return nil, err
+	}
+
+	names := loadRouteNames()
+	var table []RoutingTable
+	for _, m := range msgs {
+		rt, ok := parseRouteMsg(m.Data, names)
+		if !ok || (s.Table != TableUnspec && rt.Table != s.Table) {
+			continue
+		}
+		table = append(table, rt)
+	}
+
+	return resolveNexthopIDs(ctx, table)
+}
+
+// parseRouteMsg decodes the payload of an RTM_NEWROUTE message into the package's route model.
+// Only IPv4 routes are decoded; false is returned for anything else.
+// Protocol and scope numbers are resolved to their iproute2 names.
+func parseRouteMsg(b []byte, names routeNames) (RoutingTable, bool) {
+	if len(b) < sizeofRtMsg || b[0] != syscall.AF_INET {
+		return RoutingTable{}, false
+	}
+	dstLen := int(b[1])
+	rtmFlags := binary.NativeEndian.Uint32(b[8:12])
+	attrs := netlinkAttrs(b[sizeofRtMsg:])
+
+	rt := RoutingTable{
+		Table:  int(b[4]),
+		Proto:  nameOrNumber(names.protos, int(b[5])),
+		Scope:  nameOrNumber(names.scopes, int(b[6])),
+		Type:   RouteType(b[7]),
+		OnLink: rtmFlags&rtnhFOnlink != 0,
+		TOS:    b[3],
+	}
+	if t, ok := attrs[rtaTableAttr]; ok {
+		rt.Table = int(nlUint32(t))
+	}
+
+	dst := net.IPv4zero.To4()
+	if v, ok := attrs[syscall.RTA_DST]; ok && len(v) == net.IPv4len {
+		dst = net.IP(v)
+	}
+	rt.Destination = formatHexIP(dst)
+	rt.Mask = formatHexIP(net.IP(net.CIDRMask(dstLen, 32)))
+
+	gw := net.IPv4zero
+	if v, ok := attrs[syscall.RTA_GATEWAY]; ok && len(v) == net.IPv4len {
+		gw = net.IP(v)
+	}
+	rt.Gateway = gw.String()
+
+	if v, ok := attrs[syscall.RTA_PREFSRC]; ok && len(v) == net.IPv4len {
+		rt.PrefSrc = net.IP(v).String()
+	}
+	if v, ok := attrs[syscall.RTA_OIF]; ok {
+		rt.Interface = interfaceName(int(nlUint32(v)))
+	}
+	if v, ok := attrs[syscall.RTA_MULTIPATH]; ok {
+		rt.Nexthops = parseMultipath(v)
+		if len(rt.Nexthops) > 0 {
+			gw = net.ParseIP(rt.Nexthops[0].Gateway) // Mirror the first path, as /proc/net/route does.
+			rt.Gateway, rt.Interface = rt.Nexthops[0].Gateway, rt.Nexthops[0].Interface
+		}
+	}
+	if v, ok := attrs[syscall.RTA_METRICS]; ok {
+		rt.Metrics = parseRouteMetrics(v)
+		rt.Window = clampInt8(int(min(rt.Metrics.Window, math.MaxInt32)))
+	}
+	if v, ok := attrs[syscall.RTA_PRIORITY]; ok {
+		rt.Metric = nlUint32(v)
+	}
+	if v, ok := attrs[syscall.RTA_FLOW]; ok {
+		rt.Realm, rt.FromRealm = decodeRealms(nlUint32(v), names.realms)
+	}
+	if v, ok := attrs[rtaNhID]; ok {
+		rt.NexthopID = nlUint32(v)
+	}
+	rt.SRv6 = parseRouteEncap(attrs)
+
+	var bits int16
+	if rtmFlags&(rtnhFDead|rtnhFLinkdown) == 0 {
+		bits |= FlagUp
+	}
+	if !gw.IsUnspecified() {
+		bits |= FlagGateway
+	}
+	if dstLen == 32 {
+		bits |= FlagHost
+	}
+	if rt.Type == RouteTypeUnreachable || rt.Type == RouteTypeProhibit {
+		bits |= FlagReject
+	}
+	rt.Flags = computeRouteFlag(bits)
+
+	return rt, true
+}
+
+// IPv6Routes dumps the kernel routing tables and returns the IPv6 routes of the selected table.
+// Unlike /proc/net/ipv6_route it reports the protocol that installed each route and the remaining lifetime of expiring ones.
+func (s NetlinkSource) IPv6Routes(ctx context.Context) ([]IPv6Route, error) {
+	msgs, err := netlinkDump(ctx, syscall.RTM_GETROUTE, syscall.AF_INET6)
+	if err != nil {
+		return nil, err
+	}
+
+	names := loadRouteNames()
+	var table []IPv6Route
+	for _, m := range msgs {
+		rt, tableID, ok := parseIPv6RouteMsg(m.Data, names)
+		if !ok || (s.Table != TableUnspec && tableID != s.Table) {
+			continue
+		}
+		table = append(table, rt)
+	}
+
+	return resolveIPv6NexthopIDs(ctx, table)
+}
+
+// parseIPv6RouteMsg decodes the payload of an RTM_NEWROUTE message for an IPv6 route, also returning its table.
+// The kernel does not report the RTF_* flags of IPv6 routes over netlink, so they are derived from the protocol,
+// the lifetime and the message flags; routes with the "ra" protocol are marked Addrconf as in /proc/net/ipv6_route.
+func parseIPv6RouteMsg(b []byte, names routeNames) (IPv6Route, int, bool) {
+	if len(b) < sizeofRtMsg || b[0] != syscall.AF_INET6 {
+		return IPv6Route{}, 0, false
+	}
+	dstLen, srcLen := int(b[1]), int(b[2])
+	rtmFlags := binary.NativeEndian.Uint32(b[8:12])
+	typ := RouteType(b[7])
+	attrs := netlinkAttrs(b[sizeofRtMsg:])
+
+	tableID := int(b[4])
+	if t, ok := attrs[rtaTableAttr]; ok {
+		tableID = int(nlUint32(t))
+	}
+
+	prefix := func(attr uint16, ones int) *net.IPNet {
+		ip := net.IPv6zero
+		if v, ok := attrs[attr]; ok && len(v) == net.IPv6len {
+			ip = net.IP(v)
+		}
+		mask := net.CIDRMask(ones, 8*net.IPv6len)
+		return &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+	}
+	rt := IPv6Route{
+		Destination: prefix(syscall.RTA_DST, dstLen),
+		Source:      prefix(syscall.RTA_SRC, srcLen),
+		Gateway:     net.IPv6zero,
+		Proto:       nameOrNumber(names.protos, int(b[5])),
+		Type:        typ,
+	}
+	if v, ok := attrs[syscall.RTA_GATEWAY]; ok && len(v) == net.IPv6len {
+		rt.Gateway = net.IP(v)
+	}
+	if v, ok := attrs[syscall.RTA_OIF]; ok {
+		rt.Interface = interfaceName(int(nlUint32(v)))
+	}
+	if v, ok := attrs[syscall.RTA_PRIORITY]; ok {
+		rt.Metric = nlUint32(v)
+	}
+	if v, ok := attrs[rtaNhID]; ok {
+		rt.NexthopID = nlUint32(v)
+	}
+	rt.SRv6 = parseRouteEncap(attrs)
+	if v, ok := attrs[syscall.RTA_CACHEINFO]; ok && len(v) >= 12 {
+		if expires := int32(binary.NativeEndian.Uint32(v[8:12])); expires > 0 {
+			rt.Expires = time.Duration(expires) * time.Second / userHZ
+		}
+	}
+
+	var bits int16
+	if rtmFlags&(rtnhFDead|rtnhFLinkdown) == 0 {
+		bits |= FlagUp
+	}
+	if !rt.Gateway.IsUnspecified() {
+		bits |= FlagGateway
+	}
+	if dstLen == 8*net.IPv6len {
+		bits |= FlagHost
+	}
+	if typ == RouteTypeUnreachable || typ == RouteTypeProhibit {
+		bits |= FlagReject
+	}
+	if typ == RouteTypeAnycast {
+		bits |= FlagAnycast
+	}
+	if b[5] == rtprotRA {
+		bits |= FlagAddrconf
+		if dstLen == 0 {
+			bits |= FlagDefaultRouter
+		}
+	}
+	if rt.Expires > 0 {
+		bits |= FlagExpires
+	}
+	if rtmFlags&rtmFCloned != 0 {
+		bits |= FlagCache
+	}
+	rt.Flags = computeIPv6RouteFlag(bits)
+
+	return rt, tableID, true
+}
+
+// decodeRealms splits an RTA_FLOW value into the names of its destination realm, in the low 16 bits,
+// and its source realm, in the high ones. Unset realms are returned as empty strings.
+func decodeRealms(flow uint32, names map[int]string) (string, string) {
+	var to, from string
+	if r := int(flow & 0xffff); r != 0 {
+		to = nameOrNumber(names, r)
+	}
+	if r := int(flow >> 16); r != 0 {
+		from = nameOrNumber(names, r)
+	}
+
+	return to, from
+}
+
+// parseRouteMetrics decodes the attributes nested in RTA_METRICS.
+// The kernel keeps RTT in units of 1/8 ms and its variance in units of 1/4 ms, as TCP does.
+func parseRouteMetrics(b []byte) RouteMetrics {
+	attrs := netlinkAttrs(b)
+	u := func(typ uint16) uint32 { return nlUint32(attrs[typ]) }
+	ms := func(v uint32) time.Duration { return time.Duration(v) * time.Millisecond }
+
+	return RouteMetrics{
+		MTU:        u(rtaxMTU),
+		Window:     u(rtaxWindow),
+		RTT:        ms(u(rtaxRTT)) / 8,
+		RTTVar:     ms(u(rtaxRTTVar)) / 4,
+		SSThresh:   u(rtaxSSThresh),
+		CWnd:       u(rtaxCWnd),
+		AdvMSS:     u(rtaxAdvMSS),
+		Reordering: u(rtaxReordering),
+		HopLimit:   u(rtaxHopLimit),
+		InitCwnd:   u(rtaxInitCwnd),
+		RTOMin:     ms(u(rtaxRTOMin)),
+		InitRwnd:   u(rtaxInitRwnd),
+		QuickAck:   u(rtaxQuickAck) != 0,
+		CongCtl:    nlString(attrs[rtaxCCAlgo]),
+	}
+}
+
+// parseMultipath decodes the struct rtnexthop entries of an RTA_MULTIPATH attribute.
+func parseMultipath(b []byte) []Nexthop {
+	var nexthops []Nexthop
+	for len(b) >= syscall.SizeofRtNexthop {
+		l := int(binary.NativeEndian.Uint16(b[0:2]))
+		if l < syscall.SizeofRtNexthop || l > len(b) {
+			break
+		}
+
+		nh := Nexthop{
+			Gateway:   net.IPv4zero.String(),
+			Interface: interfaceName(int(int32(binary.NativeEndian.Uint32(b[4:8])))),
+			Weight:    int(b[3]) + 1, // The kernel stores the weight minus one.
+			OnLink:    b[2]&rtnhFOnlink != 0,
+		}
+		attrs := netlinkAttrs(b[syscall.SizeofRtNexthop:l])
+		if v, ok := attrs[syscall.RTA_GATEWAY]; ok && len(v) == net.IPv4len {
+			nh.Gateway = net.IP(v).String()
+		}
+		nexthops = append(nexthops, nh)
+
+		if nlAttrAlign(l) > len(b) {
+			break
+		}
+		b = b[nlAttrAlign(l):]
+	}
+
+	return nexthops
+}
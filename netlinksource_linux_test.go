@@ -0,0 +1,153 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestNetlinkSourceMatchesProc(t *testing.T) {
+	proc, err := ProcSource{}.Routes(context.Background())
+	if err != nil {
+		t.Fatalf("Calling routing library failed %s", err.Error())
+	}
+	nl, err := NetlinkSource{Table: TableMain}.Routes(context.Background())
+	if err != nil {
+		t.Fatalf("Calling routing library failed %s", err.Error())
+	}
+
+	if len(proc) != len(nl) {
+		t.Fatalf("Expected %d routes from netlink, got %d", len(proc), len(nl))
+	}
+	for i := range proc {
+		p, n := proc[i], nl[i]
+		if p.Destination != n.Destination || p.Mask != n.Mask || p.Gateway != n.Gateway || p.Interface != n.Interface || p.Table != n.Table || flagLetters(p.Flags) != flagLetters(n.Flags) {
+			t.Errorf("Route %d differs: proc %q netlink %q", i, p, n)
+		}
+		if n.Proto == "" || n.Scope == "" {
+			t.Errorf("Expected protocol and scope on %+v", n)
+		}
+	}
+}
+
+func TestNetlinkSourceDualStackRoutes(t *testing.T) {
+	src := NetlinkSource{Table: TableMain}
+	v4, err := src.Routes(context.Background())
+	if err != nil {
+		t.Fatalf("Routes failed %s", err.Error())
+	}
+	v6, err := src.IPv6Routes(context.Background())
+	if err != nil {
+		t.Fatalf("IPv6Routes failed %s", err.Error())
+	}
+	routes, err := src.DualStackRoutes(context.Background())
+	if err != nil {
+		t.Fatalf("DualStackRoutes failed %s", err.Error())
+	}
+
+	if len(routes) != len(v4)+len(v6) {
+		t.Fatalf("Expected %d routes, got %v", len(v4)+len(v6), routes)
+	}
+	for i, r := range routes {
+		if (r.Family == FamilyIPv6) != (i >= len(v4)) {
+			t.Errorf("Route %d %s has family %v, want IPv4 routes first", i, r, r.Family)
+		}
+	}
+}
+
+func TestParseRouteMsgMultipath(t *testing.T) {
+	nexthop := func(gw net.IP, weight byte) []byte {
+		attr := nlAttr(syscall.RTA_GATEWAY, gw.To4())
+		b := make([]byte, syscall.SizeofRtNexthop)
+		binary.NativeEndian.PutUint16(b[0:2], uint16(syscall.SizeofRtNexthop+len(attr)))
+		b[3] = weight - 1
+		return append(b, attr...)
+	}
+
+	msg := make([]byte, sizeofRtMsg)
+	msg[0], msg[1], msg[4], msg[7] = syscall.AF_INET, 24, TableMain, byte(RouteTypeUnicast)
+	msg = append(msg, nlAttr(syscall.RTA_DST, net.ParseIP("198.51.100.0").To4())...)
+	msg = append(msg, nlAttr(syscall.RTA_MULTIPATH, append(nexthop(net.ParseIP("192.0.2.1"), 1), nexthop(net.ParseIP("192.0.2.3"), 3)...))...)
+
+	rt, ok := parseRouteMsg(msg, loadRouteNames())
+	if !ok {
+		t.Fatalf("parseRouteMsg rejected the message")
+	}
+	if len(rt.Nexthops) != 2 || rt.Nexthops[1].Gateway != "192.0.2.3" || rt.Nexthops[1].Weight != 3 {
+		t.Errorf("Unexpected nexthops %+v", rt.Nexthops)
+	}
+	if rt.Gateway != "192.0.2.1" || !flagContains(rt.Flags, "G") {
+		t.Errorf("Expected the route to mirror its first nexthop, got %+v", rt)
+	}
+	if rt.String() != "198.51.100.0/24 proto unspec\n\tnexthop via 192.0.2.1 weight 1\n\tnexthop via 192.0.2.3 weight 3" {
+		t.Errorf("Unexpected route %q", rt.String())
+	}
+}
+
+func TestParseIPv6RouteMsg(t *testing.T) {
+	cacheinfo := make([]byte, 20)
+	binary.NativeEndian.PutUint32(cacheinfo[8:12], 179850) // 1798.5 seconds in USER_HZ ticks.
+
+	msg := make([]byte, sizeofRtMsg)
+	msg[0], msg[4], msg[5], msg[7] = syscall.AF_INET6, TableMain, rtprotRA, byte(RouteTypeUnicast)
+	msg = append(msg, nlAttr(syscall.RTA_GATEWAY, net.ParseIP("fe80::1"))...)
+	msg = append(msg, nlAttr(syscall.RTA_PRIORITY, nlUint32Bytes(1024))...)
+	msg = append(msg, nlAttr(syscall.RTA_CACHEINFO, cacheinfo)...)
+
+	rt, table, ok := parseIPv6RouteMsg(msg, loadRouteNames())
+	if !ok || table != TableMain {
+		t.Fatalf("parseIPv6RouteMsg rejected the message")
+	}
+	if rt.String() != "default via fe80::1 proto ra metric 1024 expires 1798sec" {
+		t.Errorf("Unexpected route %q", rt.String())
+	}
+	if !rt.LearnedFromRA() || flagLetters(rt.Flags) != "UGAed" {
+		t.Errorf("Expected an RA-learned default route, got flags %s", flagLetters(rt.Flags))
+	}
+
+	if _, _, ok := parseIPv6RouteMsg(msg[:4], loadRouteNames()); ok {
+		t.Error("Expected a truncated message to be rejected")
+	}
+}
+
+func TestRoutesInTable(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+	setLoopbackUp(t)
+	routes, err := ParseIPRoute(strings.NewReader("blackhole 198.51.100.0/24 table 100\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	if err := AddRoute(routes[0]); err != nil {
+		t.Fatalf("AddRoute failed %s", err.Error())
+	}
+
+	got, err := RoutesInTable(100)
+	if err != nil {
+		t.Fatalf("RoutesInTable failed %s", err.Error())
+	}
+	if len(got) != 1 || got[0].String() != "blackhole 198.51.100.0/24 table 100" {
+		t.Errorf("Expected the route of table 100, got %v", got)
+	}
+
+	local, err := RoutesInTable(TableLocal)
+	if err != nil {
+		t.Fatalf("RoutesInTable failed %s", err.Error())
+	}
+	found := false
+	for _, rt := range local {
+		found = found || rt.Type == RouteTypeLocal && rt.Table == TableLocal
+	}
+	if !found {
+		t.Errorf("Expected the local routes of lo, got %v", local)
+	}
+
+	if _, err := RoutesInTable(TableUnspec); !errors.Is(err, errTableUnspec) {
+		t.Errorf("Expected table 0 to be rejected, got %v", err)
+	}
+}
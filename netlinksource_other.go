@@ -0,0 +1,15 @@
+//go:build !linux
+
+package routing
+
+import "context"
+
+// Routes always returns ErrNotSupported, as netlink is only available on Linux.
+func (s NetlinkSource) Routes(ctx context.Context) ([]RoutingTable, error) {
+	return nil, ErrNotSupported
+}
+
+// IPv6Routes always returns ErrNotSupported, as netlink is only available on Linux.
+func (s NetlinkSource) IPv6Routes(ctx context.Context) ([]IPv6Route, error) {
+	return nil, ErrNotSupported
+}
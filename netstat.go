@@ -0,0 +1,143 @@
+package routing
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+var errBadPrefixLength = errors.New("invalid prefix length")
+
+// netstatFlagBits maps the BSD route flag letters printed by `netstat -rn` to the package's flag bits.
+// BSD uses "R" for reject routes, where Linux uses "!".
+var netstatFlagBits = map[rune]int16{
+	'U': FlagUp, 'G': FlagGateway, 'H': FlagHost, 'D': FlagDynamic, 'M': FlagModified, 'R': FlagReject,
+}
+
+// ParseNetstat decodes the IPv4 routes from the output of macOS or BSD `netstat -rn` read from r,
+// e.g. as captured in macOS diagnostics. The "Internet6" section is skipped.
+// Abbreviated destinations such as "192.168.1" take their prefix length from the number of octets.
+// Interface-scoped gateways ("link#6") and link-layer gateways of ARP entries ("a0:b1:c2:d3:e4:f5")
+// become "0.0.0.0"; the printed values of every column are kept in Raw.
+func ParseNetstat(r io.Reader) ([]RoutingTable, error) {
+	var table []RoutingTable
+	var header []string // Columns of the current IPv4 section; nil outside it.
+	inIPv4 := false
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 0:
+			continue
+		case len(fields) == 1 && strings.HasSuffix(fields[0], ":"):
+			inIPv4, header = fields[0] == "Internet:", nil
+			continue
+		case !inIPv4:
+			continue
+		case header == nil:
+			if fields[0] == "Destination" {
+				header = fields
+			}
+			continue
+		case len(fields) != len(header) && len(fields) != len(header)-1:
+			continue // Columns such as Expire may be blank.
+		}
+
+		raw := make(map[string]string, len(fields))
+		for i, v := range fields {
+			raw[header[i]] = v
+		}
+		rt, err := parseNetstatRow(raw)
+		if err != nil {
+			err.Line = n
+			return nil, err
+		}
+		rt.Raw = rawColumns(header, fields)
+		table = append(table, rt)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// parseNetstatRow converts the columns of a netstat route, keyed by header name, into a RoutingTable.
+func parseNetstatRow(raw map[string]string) (RoutingTable, *ParseError) {
+	var bits int16
+	typ := RouteTypeUnicast
+	for _, c := range raw["Flags"] {
+		bits |= netstatFlagBits[c]
+		switch c {
+		case 'B':
+			typ = RouteTypeBlackhole
+		case 'b':
+			typ = RouteTypeBroadcast
+		case 'm':
+			typ = RouteTypeMulticast
+		}
+	}
+	if bits&FlagReject != 0 {
+		typ = RouteTypeUnreachable
+	}
+
+	dst, err := parseNetstatDst(raw["Destination"], bits&FlagHost != 0)
+	if err != nil {
+		return RoutingTable{}, &ParseError{Column: "Destination", Value: raw["Destination"], Err: err}
+	}
+
+	gw := net.IPv4zero
+	if ip := net.ParseIP(raw["Gateway"]).To4(); ip != nil && bits&FlagGateway != 0 {
+		gw = ip
+	}
+
+	return RoutingTable{
+		Interface:   raw["Netif"],
+		Destination: formatHexIP(dst.IP),
+		Gateway:     gw.String(),
+		Flags:       computeRouteFlag(bits),
+		Mask:        formatHexIP(net.IP(dst.Mask)),
+		Type:        typ,
+	}, nil
+}
+
+// parseNetstatDst parses a netstat destination: "default", an address with an optional "/len",
+// or an abbreviated network such as "10" or "172.16" whose missing octets are zero.
+// Full addresses without a length are hosts.
+func parseNetstatDst(s string, host bool) (*net.IPNet, error) {
+	if s == "default" {
+		return &net.IPNet{IP: net.IPv4zero.To4(), Mask: net.CIDRMask(0, 32)}, nil
+	}
+
+	addr, length, hasLength := strings.Cut(s, "/")
+	octets := strings.Split(addr, ".")
+	if len(octets) > 4 {
+		return nil, errNotIPv4
+	}
+	for len(octets) < 4 {
+		octets = append(octets, "0")
+	}
+	ip := net.ParseIP(strings.Join(octets, ".")).To4()
+	if ip == nil {
+		return nil, errNotIPv4
+	}
+
+	ones := 8*strings.Count(addr, ".") + 8
+	switch {
+	case hasLength:
+		v, err := strconv.Atoi(length)
+		if err != nil || v < 0 || v > 32 {
+			return nil, errBadPrefixLength
+		}
+		ones = v
+	case host:
+		ones = 32
+	}
+	mask := net.CIDRMask(ones, 32)
+
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}, nil
+}
@@ -0,0 +1,64 @@
+package routing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const netstatFixture = `Routing tables
+
+Internet:
+Destination        Gateway            Flags           Netif Expire
+default            192.168.1.1        UGScg             en0
+127                127.0.0.1          UCS               lo0
+127.0.0.1          127.0.0.1          UH                lo0
+169.254            link#6             UCS               en0      !
+192.168.1          link#6             UCS               en0      !
+192.168.1.1/32     link#6             UCS               en0      !
+192.168.1.1        a0:b1:c2:d3:e4:f5  UHLWIir           en0   1184
+192.168.1.255      ff:ff:ff:ff:ff:ff  UHLWbI            en0      !
+198.51.100/24      127.0.0.1          UGSB              lo0
+224.0.0/4          link#6             UmCS              en0      !
+
+Internet6:
+Destination                             Gateway                                 Flags           Netif Expire
+default                                 fe80::%utun0                            UGcIg           utun0
+::1                                     ::1                                     UHL               lo0
+`
+
+func TestParseNetstat(t *testing.T) {
+	table, err := ParseNetstat(strings.NewReader(netstatFixture))
+	if err != nil {
+		t.Fatalf("ParseNetstat failed %s", err.Error())
+	}
+
+	var got []string
+	for _, rt := range table {
+		got = append(got, rt.String()+" "+flagLetters(rt.Flags))
+	}
+	want := []string{
+		"default via 192.168.1.1 dev en0 UG",
+		"127.0.0.0/8 dev lo0 scope link U",
+		"127.0.0.1 dev lo0 scope link UH",
+		"169.254.0.0/16 dev en0 scope link U",
+		"192.168.1.0/24 dev en0 scope link U",
+		"192.168.1.1 dev en0 scope link U",
+		"192.168.1.1 dev en0 scope link UH",
+		"broadcast 192.168.1.255 dev en0 UH",
+		"blackhole 198.51.100.0/24 via 127.0.0.1 dev lo0 UG",
+		"multicast 224.0.0.0/4 dev en0 U",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected routes\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if table[6].Raw.Get("Gateway") != "a0:b1:c2:d3:e4:f5" || table[6].Raw.Get("Expire") != "1184" {
+		t.Errorf("Expected the link-layer gateway in Raw, got %v", table[6].Raw)
+	}
+
+	bad := strings.Replace(netstatFixture, "224.0.0/4", "224.0.0/40", 1)
+	var pErr *ParseError
+	if _, err := ParseNetstat(strings.NewReader(bad)); !errors.As(err, &pErr) || pErr.Line != 14 || pErr.Column != "Destination" {
+		t.Errorf("Expected a Destination error on line 14, got %v", err)
+	}
+}
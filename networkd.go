@@ -0,0 +1,154 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+var errNotNetworkd = errors.New("not expressible in systemd-networkd")
+
+// networkdProtocols are the route protocols systemd-networkd accepts by name; others are written as numbers.
+var networkdProtocols = map[string]bool{"kernel": true, "boot": true, "static": true, "ra": true, "dhcp": true}
+
+// WriteNetworkd writes the routes to w as systemd-networkd [Route] sections, one per route, e.g.
+//
+//	[Route]
+//	Destination=10.1.0.0/16
+//	Gateway=192.168.1.254
+//	Metric=100
+//
+// A .network file applies its routes to the links it matches, so the routes should all go through the same link,
+// and are best written to a drop-in such as /etc/systemd/network/10-eth0.network.d/routes.conf, which adds them to
+// the link's existing configuration. Multipath routes become MultiPathRoute= entries, and a route using a nexthop
+// object refers to it with NextHop=, which needs a [NextHop] section with that Id. It fails without writing the
+// route for settings networkd has no key for, such as TOS, realms, SRv6 encapsulation or the rtt metric.
+func WriteNetworkd(w io.Writer, routes []RoutingTable) error {
+	for i, rt := range routes {
+		section, err := networkdRoute(rt)
+		if err != nil {
+			return fmt.Errorf("route %s: %w", strings.ReplaceAll(rt.String(), "\n\t", " "), err)
+		}
+		if i > 0 {
+			section = "\n" + section
+		}
+		if _, err := io.WriteString(w, section); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// networkdRoute renders rt as a [Route] section.
+func networkdRoute(rt RoutingTable) (string, error) {
+	switch {
+	case rt.TOS != 0:
+		return "", fmt.Errorf("tos: %w", errNotNetworkd)
+	case rt.Realm != "" || rt.FromRealm != "":
+		return "", fmt.Errorf("realms: %w", errNotNetworkd)
+	case rt.SRv6 != nil:
+		return "", fmt.Errorf("SRv6 encapsulation: %w", errNotNetworkd)
+	}
+	dst, _, ones, err := decodeDestination(rt)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("[Route]\n")
+	set := func(key, value string) { fmt.Fprintf(&b, "%s=%s\n", key, value) }
+
+	set("Destination", fmt.Sprintf("%s/%d", dst, ones))
+	if !rt.Type.isUnicast() {
+		set("Type", rt.Type.String())
+	}
+	if rt.NexthopID != 0 {
+		set("NextHop", strconv.FormatUint(uint64(rt.NexthopID), 10))
+	}
+	if gw := net.ParseIP(rt.Gateway); gw != nil && !gw.IsUnspecified() && len(rt.Nexthops) == 0 {
+		set("Gateway", gw.String())
+		if rt.OnLink {
+			set("GatewayOnLink", "yes")
+		}
+	}
+	for _, nh := range rt.Nexthops {
+		gw := net.ParseIP(nh.Gateway)
+		if gw == nil || gw.IsUnspecified() {
+			return "", fmt.Errorf("nexthop without a gateway: %w", errNotNetworkd)
+		}
+		path := gw.String()
+		if nh.Interface != "" {
+			path += "@" + nh.Interface
+		}
+		if nh.Weight > 0 {
+			path += " " + strconv.Itoa(nh.Weight)
+		}
+		set("MultiPathRoute", path)
+	}
+	if rt.PrefSrc != "" {
+		set("PreferredSource", rt.PrefSrc)
+	}
+	if rt.Metric != 0 {
+		set("Metric", strconv.FormatUint(uint64(rt.Metric), 10))
+	}
+	if rt.Scope != "" {
+		set("Scope", rt.Scope)
+	}
+	if rt.Proto != "" {
+		proto := rt.Proto
+		if !networkdProtocols[proto] {
+			p, ok := numberOrName(protocolNames(), proto)
+			if !ok {
+				return "", fmt.Errorf("unknown route protocol %q", proto)
+			}
+			proto = strconv.Itoa(p)
+		}
+		set("Protocol", proto)
+	}
+	switch rt.Table {
+	case TableUnspec, TableMain:
+	case TableLocal, TableDefault:
+		set("Table", TableName(rt.Table))
+	default:
+		set("Table", strconv.Itoa(rt.Table))
+	}
+	if err := networkdMetrics(rt.Metrics, set); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// networkdMetrics sets the keys of the route metrics m, failing for metrics networkd cannot set.
+func networkdMetrics(m RouteMetrics, set func(key, value string)) error {
+	switch {
+	case m.Window != 0, m.RTT != 0, m.RTTVar != 0, m.SSThresh != 0, m.CWnd != 0, m.Reordering != 0:
+		return fmt.Errorf("metrics %s: %w", m, errNotNetworkd)
+	}
+	add := func(key string, v uint32) {
+		if v != 0 {
+			set(key, strconv.FormatUint(uint64(v), 10))
+		}
+	}
+
+	add("MTUBytes", m.MTU)
+	add("TCPAdvertisedMaximumSegmentSize", m.AdvMSS)
+	add("HopLimit", m.HopLimit)
+	add("InitialCongestionWindow", m.InitCwnd)
+	add("InitialAdvertisedReceiveWindow", m.InitRwnd)
+	if m.RTOMin != 0 {
+		set("TCPRetransmissionTimeoutSec", fmt.Sprintf("%dms", m.RTOMin.Milliseconds()))
+	}
+	if m.QuickAck {
+		set("QuickAck", "yes")
+	}
+	if m.CongCtl != "" {
+		set("TCPCongestionControlAlgorithm", m.CongCtl)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package routing
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteNetworkd(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("10.1.0.0/16 via 192.168.1.254 dev eth0 proto static metric 1024 mtu 1400 quickack 1\n" +
+		"blackhole 203.0.113.0/24 table 100\n" +
+		"default proto bird\n\tnexthop via 192.168.1.1 dev eth0 weight 1\n\tnexthop via 192.168.2.1 dev eth1 weight 2\n" +
+		"198.51.100.0/24 via 10.0.0.1 dev eth0 scope global onlink\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	var buf bytes.Buffer
+	if err := WriteNetworkd(&buf, routes); err != nil {
+		t.Fatalf("WriteNetworkd failed %s", err.Error())
+	}
+
+	expected := "[Route]\nDestination=10.1.0.0/16\nGateway=192.168.1.254\nMetric=1024\nProtocol=static\nMTUBytes=1400\nQuickAck=yes\n" +
+		"\n[Route]\nDestination=203.0.113.0/24\nType=blackhole\nTable=100\n" +
+		"\n[Route]\nDestination=0.0.0.0/0\nMultiPathRoute=192.168.1.1@eth0 1\nMultiPathRoute=192.168.2.1@eth1 2\nProtocol=12\n" +
+		"\n[Route]\nDestination=198.51.100.0/24\nGateway=10.0.0.1\nGatewayOnLink=yes\nScope=global\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected networkd output\n%s\nwant\n%s", buf.String(), expected)
+	}
+}
+
+func TestWriteNetworkdNotExpressible(t *testing.T) {
+	for _, line := range []string{
+		"10.1.0.0/16 tos 0x10 dev eth0",
+		"10.1.0.0/16 dev eth0 realm 5",
+		"10.1.0.0/16 dev eth0 rtt 20ms",
+		"default\n\tnexthop dev eth0 weight 1\n\tnexthop dev eth1 weight 1",
+	} {
+		routes, err := ParseIPRoute(strings.NewReader(line + "\n"))
+		if err != nil {
+			t.Fatalf("ParseIPRoute(%q) failed %s", line, err.Error())
+		}
+		var buf bytes.Buffer
+		if err := WriteNetworkd(&buf, routes); !errors.Is(err, errNotNetworkd) {
+			t.Errorf("WriteNetworkd(%q) = %v, want errNotNetworkd", line, err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("WriteNetworkd(%q) wrote %q", line, buf.String())
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+)
+
+// NetworkManager D-Bus names.
+const (
+	nmService           = "org.freedesktop.NetworkManager"
+	nmPath              = "/org/freedesktop/NetworkManager"
+	nmIface             = "org.freedesktop.NetworkManager"
+	nmActiveIface       = "org.freedesktop.NetworkManager.Connection.Active"
+	nmDeviceIface       = "org.freedesktop.NetworkManager.Device"
+	nmIP4ConfigIface    = "org.freedesktop.NetworkManager.IP4Config"
+	nmSettingsConnIface = "org.freedesktop.NetworkManager.Settings.Connection"
+)
+
+// NetworkManagerConnection is an active NetworkManager connection, its profile's routes and the kernel routes
+// through its devices.
+type NetworkManagerConnection struct {
+	ID         string         // Name of the connection profile, e.g. "Wired connection 1".
+	UUID       string         // UUID of the connection profile.
+	Type       string         // Type of the connection, e.g. "802-3-ethernet", "wireguard" or "vpn".
+	Devices    []string       // Interfaces the connection is active on.
+	Default    bool           // The connection holds the IPv4 default route.
+	Gateway    string         // IPv4 gateway of the connection; empty if it has none.
+	Routes     []RoutingTable // IPv4 routes NetworkManager reports for the connection, from the profile or DHCP.
+	Configured []RoutingTable // Static IPv4 routes of the connection profile, without an interface.
+	Kernel     []RoutingTable // Kernel routes through the connection's devices, in any table.
+	Missing    []RoutingTable // Configured routes with no matching kernel route.
+}
+
+// NetworkManagerSource queries NetworkManager over D-Bus for the routes of its active connections, for diagnosing
+// desktops and laptops. D-Bus is reached with busctl, which ships with systemd, on the system bus.
+type NetworkManagerSource struct {
+	Busctl string // Path to the busctl binary; "busctl" is looked up in $PATH when empty.
+}
+
+// busCall runs busctl with args, returning its JSON output.
+type busCall func(ctx context.Context, args ...string) ([]byte, error)
+
+// busVariant is a D-Bus value as busctl prints it in JSON.
+type busVariant struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Routes returns the IPv4 routes NetworkManager reports for its active connections.
+func (s NetworkManagerSource) Routes(ctx context.Context) ([]RoutingTable, error) {
+	conns, err := networkManagerConnections(ctx, s.call)
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []RoutingTable
+	for _, c := range conns {
+		routes = append(routes, c.Routes...)
+	}
+
+	return routes, nil
+}
+
+// Connections returns the active connections with their profile routes correlated with the kernel routes, so
+// routes NetworkManager was told to install but are not in the kernel stand out in Missing.
+// Reading the kernel routes is only available on Linux and returns ErrNotSupported elsewhere.
+func (s NetworkManagerSource) Connections(ctx context.Context) ([]NetworkManagerConnection, error) {
+	conns, err := networkManagerConnections(ctx, s.call)
+	if err != nil {
+		return nil, err
+	}
+	kernel, err := NetlinkSource{}.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	correlateNetworkManager(conns, kernel)
+
+	return conns, nil
+}
+
+// call runs busctl on the system bus with JSON output.
+func (s NetworkManagerSource) call(ctx context.Context, args ...string) ([]byte, error) {
+	bin := s.Busctl
+	if bin == "" {
+		bin = "busctl"
+	}
+
+	out, err := exec.CommandContext(ctx, bin, append([]string{"--system", "--json=short"}, args...)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("running %s: %w", bin, err)
+	}
+
+	return out, nil
+}
+
+// networkManagerConnections reads the active connections, their devices, IPv4 configuration and profile with call.
+func networkManagerConnections(ctx context.Context, call busCall) ([]NetworkManagerConnection, error) {
+	var active []string
+	if err := busProperties(ctx, call, nmPath, nmIface, []string{"ActiveConnections"}, &active); err != nil {
+		return nil, err
+	}
+
+	conns := make([]NetworkManagerConnection, 0, len(active))
+	for _, path := range active {
+		var c NetworkManagerConnection
+		var devices []string
+		var ip4Config, profile string
+		err := busProperties(ctx, call, path, nmActiveIface,
+			[]string{"Id", "Uuid", "Type", "Default", "Devices", "Ip4Config", "Connection"},
+			&c.ID, &c.UUID, &c.Type, &c.Default, &devices, &ip4Config, &profile)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dev := range devices {
+			var name string
+			if err := busProperties(ctx, call, dev, nmDeviceIface, []string{"IpInterface"}, &name); err != nil {
+				return nil, err
+			}
+			c.Devices = append(c.Devices, name)
+		}
+
+		if ip4Config != "" && ip4Config != "/" {
+			var routeData []map[string]busVariant
+			if err := busProperties(ctx, call, ip4Config, nmIP4ConfigIface, []string{"Gateway", "RouteData"}, &c.Gateway, &routeData); err != nil {
+				return nil, err
+			}
+			if c.Routes, err = nmRoutes(routeData, c.Devices, 0); err != nil {
+				return nil, fmt.Errorf("connection %s: %w", c.ID, err)
+			}
+		}
+
+		if profile != "" && profile != "/" {
+			if c.Configured, err = nmProfileRoutes(ctx, call, profile); err != nil {
+				return nil, fmt.Errorf("connection %s: %w", c.ID, err)
+			}
+		}
+		conns = append(conns, c)
+	}
+
+	return conns, nil
+}
+
+// busProperties reads the properties names of the object path into values, in order.
+func busProperties(ctx context.Context, call busCall, path, iface string, names []string, values ...any) error {
+	out, err := call(ctx, append([]string{"get-property", nmService, path, iface}, names...)...)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for i, v := range values {
+		var variant busVariant
+		if err := dec.Decode(&variant); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return fmt.Errorf("decoding %s of %s: %w", names[i], path, err)
+		}
+		if err := json.Unmarshal(variant.Data, v); err != nil {
+			return fmt.Errorf("decoding %s of %s: %w", names[i], path, err)
+		}
+	}
+
+	return nil
+}
+
+// nmProfileRoutes returns the static IPv4 routes of the connection profile at path.
+func nmProfileRoutes(ctx context.Context, call busCall, path string) ([]RoutingTable, error) {
+	out, err := call(ctx, "call", nmService, path, nmSettingsConnIface, "GetSettings")
+	if err != nil {
+		return nil, err
+	}
+
+	var reply struct {
+		Data []map[string]map[string]busVariant `json:"data"`
+	}
+	if err := json.Unmarshal(out, &reply); err != nil {
+		return nil, fmt.Errorf("decoding settings of %s: %w", path, err)
+	}
+	if len(reply.Data) == 0 {
+		return nil, nil
+	}
+	ipv4 := reply.Data[0]["ipv4"]
+
+	var table int
+	if v, ok := ipv4["route-table"]; ok {
+		if err := json.Unmarshal(v.Data, &table); err != nil {
+			return nil, fmt.Errorf("decoding route-table of %s: %w", path, err)
+		}
+	}
+	var routeData []map[string]busVariant
+	if v, ok := ipv4["route-data"]; ok {
+		if err := json.Unmarshal(v.Data, &routeData); err != nil {
+			return nil, fmt.Errorf("decoding route-data of %s: %w", path, err)
+		}
+	}
+
+	return nmRoutes(routeData, nil, table)
+}
+
+// nmRoutes converts NetworkManager route data, dictionaries with keys such as "dest", "prefix" and "next-hop", to
+// routes. Routes go through the first of devices, if any, and into table unless they name their own.
+func nmRoutes(data []map[string]busVariant, devices []string, table int) ([]RoutingTable, error) {
+	var iface string
+	if len(devices) > 0 {
+		iface = devices[0]
+	}
+
+	routes := make([]RoutingTable, 0, len(data))
+	for _, d := range data {
+		var dest, nextHop string
+		var prefix, rtTable int
+		var metric uint32
+		for key, v := range map[string]any{"dest": &dest, "prefix": &prefix, "next-hop": &nextHop, "metric": &metric, "table": &rtTable} {
+			if variant, ok := d[key]; ok {
+				if err := json.Unmarshal(variant.Data, v); err != nil {
+					return nil, fmt.Errorf("decoding route %s: %w", key, err)
+				}
+			}
+		}
+
+		dst := net.ParseIP(dest).To4()
+		if dst == nil || prefix < 0 || prefix > 32 {
+			return nil, &ParseError{Column: "dest", Value: fmt.Sprintf("%s/%d", dest, prefix), Err: errNotIPv4}
+		}
+		mask := net.CIDRMask(prefix, 32)
+		if rtTable == 0 {
+			rtTable = table
+		}
+
+		rt := unicastRoute(&net.IPNet{IP: dst.Mask(mask), Mask: mask}, net.ParseIP(nextHop).To4(), iface, rtTable)
+		rt.Metric = metric
+		routes = append(routes, rt)
+	}
+
+	return routes, nil
+}
+
+// correlateNetworkManager fills in the Kernel and Missing routes of the connections from the kernel routes.
+func correlateNetworkManager(conns []NetworkManagerConnection, kernel []RoutingTable) {
+	for i := range conns {
+		c := &conns[i]
+		for _, rt := range kernel {
+			for _, dev := range c.Devices {
+				if routesThrough(rt, dev) {
+					c.Kernel = append(c.Kernel, rt)
+					break
+				}
+			}
+		}
+		for _, want := range c.Configured {
+			if !nmRouteInstalled(want, c.Kernel) {
+				c.Missing = append(c.Missing, want)
+			}
+		}
+	}
+}
+
+// nmRouteInstalled reports whether one of the kernel routes has the destination, table and gateway of want.
+// Metrics are not compared, as NetworkManager adds the device's metric to routes without one.
+func nmRouteInstalled(want RoutingTable, kernel []RoutingTable) bool {
+	wantTable := want.Table
+	if wantTable == TableUnspec {
+		wantTable = TableMain
+	}
+	wantGW := net.ParseIP(want.Gateway)
+	wantDst, _, wantOnes, err := decodeDestination(want)
+	if err != nil {
+		return false
+	}
+
+	for _, rt := range kernel {
+		table := rt.Table
+		if table == TableUnspec {
+			table = TableMain
+		}
+		dst, _, ones, err := decodeDestination(rt)
+		if err != nil || !dst.Equal(wantDst) || ones != wantOnes || table != wantTable {
+			continue
+		}
+		if wantGW == nil || wantGW.IsUnspecified() || wantGW.Equal(net.ParseIP(rt.Gateway)) {
+			return true
+		}
+		for _, nh := range rt.Nexthops {
+			if wantGW.Equal(net.ParseIP(nh.Gateway)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,110 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeBus answers busctl invocations from canned JSON output keyed by their arguments.
+type fakeBus map[string]string
+
+func (b fakeBus) call(_ context.Context, args ...string) ([]byte, error) {
+	out, ok := b[strings.Join(args, " ")]
+	if !ok {
+		return nil, fmt.Errorf("unexpected busctl %s", strings.Join(args, " "))
+	}
+
+	return []byte(out), nil
+}
+
+var networkManagerFixture = fakeBus{
+	"get-property " + nmService + " " + nmPath + " " + nmIface + " ActiveConnections": `{"type":"ao","data":["/org/freedesktop/NetworkManager/ActiveConnection/1"]}`,
+	"get-property " + nmService + " /org/freedesktop/NetworkManager/ActiveConnection/1 " + nmActiveIface +
+		" Id Uuid Type Default Devices Ip4Config Connection": `{"type":"s","data":"Wired connection 1"}
+{"type":"s","data":"4d1c5bf0-2a4e-3c9b-8b7a-0f1e2d3c4b5a"}
+{"type":"s","data":"802-3-ethernet"}
+{"type":"b","data":true}
+{"type":"ao","data":["/org/freedesktop/NetworkManager/Devices/2"]}
+{"type":"o","data":"/org/freedesktop/NetworkManager/IP4Config/3"}
+{"type":"o","data":"/org/freedesktop/NetworkManager/Settings/1"}
+`,
+	"get-property " + nmService + " /org/freedesktop/NetworkManager/Devices/2 " + nmDeviceIface + " IpInterface": `{"type":"s","data":"enp0s31f6"}`,
+	"get-property " + nmService + " /org/freedesktop/NetworkManager/IP4Config/3 " + nmIP4ConfigIface + " Gateway RouteData": `{"type":"s","data":"192.168.1.1"}
+{"type":"aa{sv}","data":[{"dest":{"type":"s","data":"0.0.0.0"},"prefix":{"type":"u","data":0},"next-hop":{"type":"s","data":"192.168.1.1"},"metric":{"type":"u","data":600}},` +
+		`{"dest":{"type":"s","data":"192.168.1.0"},"prefix":{"type":"u","data":24},"metric":{"type":"u","data":600}}]}
+`,
+	"call " + nmService + " /org/freedesktop/NetworkManager/Settings/1 " + nmSettingsConnIface + " GetSettings": `{"type":"a{sa{sv}}","data":[{` +
+		`"connection":{"id":{"type":"s","data":"Wired connection 1"}},` +
+		`"ipv4":{"method":{"type":"s","data":"auto"},"route-data":{"type":"aa{sv}","data":[` +
+		`{"dest":{"type":"s","data":"10.20.0.0"},"prefix":{"type":"u","data":16},"next-hop":{"type":"s","data":"192.168.1.254"}},` +
+		`{"dest":{"type":"s","data":"10.30.0.0"},"prefix":{"type":"u","data":16},"next-hop":{"type":"s","data":"192.168.1.254"},"table":{"type":"u","data":100}}]}}}]}`,
+}
+
+func TestNetworkManagerConnections(t *testing.T) {
+	conns, err := networkManagerConnections(context.Background(), networkManagerFixture.call)
+	if err != nil {
+		t.Fatalf("networkManagerConnections failed %s", err.Error())
+	}
+	if len(conns) != 1 {
+		t.Fatalf("Expected one connection, got %v", conns)
+	}
+	c := conns[0]
+	if c.ID != "Wired connection 1" || c.Type != "802-3-ethernet" || !c.Default || c.Gateway != "192.168.1.1" ||
+		len(c.Devices) != 1 || c.Devices[0] != "enp0s31f6" {
+		t.Errorf("Unexpected connection %+v", c)
+	}
+
+	want := []string{"default via 192.168.1.1 dev enp0s31f6 metric 600", "192.168.1.0/24 dev enp0s31f6 scope link metric 600"}
+	if len(c.Routes) != len(want) {
+		t.Fatalf("Expected %d routes, got %v", len(want), c.Routes)
+	}
+	for i, rt := range c.Routes {
+		if rt.String() != want[i] {
+			t.Errorf("Route %d = %q, want %q", i, rt.String(), want[i])
+		}
+	}
+
+	want = []string{"10.20.0.0/16 via 192.168.1.254", "10.30.0.0/16 via 192.168.1.254 table 100"}
+	if len(c.Configured) != len(want) {
+		t.Fatalf("Expected %d configured routes, got %v", len(want), c.Configured)
+	}
+	for i, rt := range c.Configured {
+		if rt.String() != want[i] {
+			t.Errorf("Configured route %d = %q, want %q", i, rt.String(), want[i])
+		}
+	}
+}
+
+func TestCorrelateNetworkManager(t *testing.T) {
+	conns, err := networkManagerConnections(context.Background(), networkManagerFixture.call)
+	if err != nil {
+		t.Fatalf("networkManagerConnections failed %s", err.Error())
+	}
+	kernel, err := ParseIPRoute(strings.NewReader("default via 192.168.1.1 dev enp0s31f6 proto dhcp metric 600\n" +
+		"10.20.0.0/16 via 192.168.1.254 dev enp0s31f6 proto static metric 100\n" +
+		"10.30.0.0/16 via 192.168.1.254 dev enp0s31f6 proto static metric 100\n" +
+		"default dev wg0 table 51820\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	correlateNetworkManager(conns, kernel)
+	c := conns[0]
+	if len(c.Kernel) != 3 {
+		t.Errorf("Expected the kernel routes through enp0s31f6, got %v", c.Kernel)
+	}
+	if len(c.Missing) != 1 || c.Missing[0].String() != "10.30.0.0/16 via 192.168.1.254 table 100" {
+		t.Errorf("Expected the route in table 100 to be missing, got %v", c.Missing)
+	}
+}
+
+func TestNetworkManagerSourceError(t *testing.T) {
+	if _, err := (NetworkManagerSource{Busctl: "/nonexistent/busctl"}).Routes(context.Background()); err == nil {
+		t.Error("Expected a missing busctl to fail")
+	}
+	if _, err := networkManagerConnections(context.Background(), fakeBus{}.call); err == nil {
+		t.Error("Expected an unanswered call to fail")
+	}
+}
@@ -0,0 +1,126 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// NexthopObject is a nexthop object of the kernel (Linux 5.3 and later). Routes reference it by ID, through
+// RoutingTable.NexthopID, instead of carrying their own gateway. It is either a single path or a group of them.
+type NexthopObject struct {
+	ID        uint32               // The nexthop's ID, unique within the network namespace.
+	Gateway   string               // The gateway IP address; empty if directly connected, for groups and blackholes.
+	Interface string               // The network interface; empty for groups and blackholes.
+	Proto     string               // Protocol that installed the nexthop (e.g. "boot", "zebra"), when known.
+	Blackhole bool                 // Traffic through the nexthop is dropped.
+	OnLink    bool                 // Gateway is reachable through Interface even though it is outside its subnets.
+	Group     []NexthopGroupMember // Members of a group nexthop; nil for single paths.
+}
+
+// NexthopGroupMember is a member of a group nexthop.
+type NexthopGroupMember struct {
+	ID     uint32 // ID of the member nexthop, which is always a single path.
+	Weight int    // Relative weight of the member when balancing traffic, from 1 to 256.
+}
+
+// String formats the nexthop like `ip nexthop`, e.g. "id 3 group 1/2,3" or "id 1 via 192.0.2.1 dev eth0".
+func (nh NexthopObject) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "id %d", nh.ID)
+	if len(nh.Group) > 0 {
+		members := make([]string, len(nh.Group))
+		for i, m := range nh.Group {
+			members[i] = fmt.Sprint(m.ID)
+			if m.Weight > 1 {
+				members[i] += fmt.Sprintf(",%d", m.Weight)
+			}
+		}
+		fmt.Fprintf(&b, " group %s", strings.Join(members, "/"))
+	}
+	if nh.Blackhole {
+		b.WriteString(" blackhole")
+	}
+	if nh.Gateway != "" {
+		fmt.Fprintf(&b, " via %s", nh.Gateway)
+	}
+	if nh.Interface != "" {
+		fmt.Fprintf(&b, " dev %s", nh.Interface)
+	}
+	if nh.Proto != "" && nh.Proto != "boot" {
+		fmt.Fprintf(&b, " proto %s", nh.Proto)
+	}
+	if nh.OnLink {
+		b.WriteString(" onlink")
+	}
+
+	return b.String()
+}
+
+// NexthopObjects lists the nexthop objects and groups of the kernel using netlink (RTM_GETNEXTHOP).
+// Kernels older than 5.3 do not have them and fail the request.
+func NexthopObjects() ([]NexthopObject, error) {
+	return NexthopObjectsContext(context.Background())
+}
+
+// ResolveNexthopObjects fills in the gateway and interface of routes that reference a nexthop object by ID but
+// carry no path of their own, as the kernel reports them with net.ipv4.nexthop_compat_mode disabled.
+// Routes through a group get one Nexthop per member, their Gateway and Interface mirroring the first one, and routes
+// through a blackhole nexthop become blackhole routes. Routes referencing unknown IDs are returned unchanged.
+func ResolveNexthopObjects(routes []RoutingTable, objects []NexthopObject) []RoutingTable {
+	byID := make(map[uint32]NexthopObject, len(objects))
+	for _, nh := range objects {
+		byID[nh.ID] = nh
+	}
+
+	out := make([]RoutingTable, len(routes))
+	for i, rt := range routes {
+		nh, ok := byID[rt.NexthopID]
+		if ok && rt.NexthopID != 0 && rt.Interface == "" && len(rt.Nexthops) == 0 {
+			rt = resolveNexthopObject(rt, nh, byID)
+		}
+		out[i] = rt
+	}
+
+	return out
+}
+
+// resolveNexthopObject returns rt routed through the nexthop object nh, whose members are looked up in byID.
+func resolveNexthopObject(rt RoutingTable, nh NexthopObject, byID map[uint32]NexthopObject) RoutingTable {
+	if nh.Blackhole {
+		rt.Type = RouteTypeBlackhole
+		return rt
+	}
+
+	for _, m := range nh.Group {
+		member := byID[m.ID]
+		rt.Nexthops = append(rt.Nexthops, Nexthop{
+			Gateway:   nexthopGateway(member.Gateway),
+			Interface: member.Interface,
+			Weight:    m.Weight,
+			OnLink:    member.OnLink,
+		})
+	}
+	if len(rt.Nexthops) > 0 {
+		nh = byID[nh.Group[0].ID] // Mirror the first path, as /proc/net/route does.
+	} else {
+		rt.OnLink = nh.OnLink // Paths of groups record it themselves.
+	}
+
+	rt.Gateway, rt.Interface = nexthopGateway(nh.Gateway), nh.Interface
+	if rt.Gateway != net.IPv4zero.String() {
+		rt.Flags = computeRouteFlag(flagBits(rt.Flags) | FlagGateway)
+	}
+
+	return rt
+}
+
+// nexthopGateway returns the gateway of a nexthop object in the form RoutingTable uses, "0.0.0.0" when unset.
+func nexthopGateway(gw string) string {
+	if gw == "" {
+		return net.IPv4zero.String()
+	}
+
+	return gw
+}
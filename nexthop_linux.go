@@ -0,0 +1,126 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"syscall"
+)
+
+// Nexthop message layout (struct nhmsg) and attribute types from linux/nexthop.h.
+const (
+	rtmNewNexthop    = 104
+	rtmGetNexthop    = 106
+	sizeofNhMsg      = 8
+	sizeofNexthopGrp = 8
+	nhaID            = 1
+	nhaGroup         = 2
+	nhaBlackhole     = 4
+	nhaOIF           = 5
+	nhaGateway       = 6
+	rtaNhID          = 30 // RTA_NH_ID, the nexthop object a route uses.
+)
+
+// NexthopObjectsContext is like NexthopObjects but returns early if ctx is done.
+func NexthopObjectsContext(ctx context.Context) ([]NexthopObject, error) {
+	msgs, err := netlinkRequest(ctx, rtmGetNexthop, syscall.NLM_F_DUMP, make([]byte, sizeofNhMsg))
+	if err != nil {
+		return nil, err
+	}
+
+	protos := protocolNames()
+	var objects []NexthopObject
+	for _, m := range msgs {
+		if m.Header.Type != rtmNewNexthop {
+			continue
+		}
+		if nh, ok := parseNexthopMsg(m.Data, protos); ok {
+			objects = append(objects, nh)
+		}
+	}
+
+	return objects, nil
+}
+
+// parseNexthopMsg decodes the payload of an RTM_NEWNEXTHOP message.
+func parseNexthopMsg(b []byte, protos map[int]string) (NexthopObject, bool) {
+	if len(b) < sizeofNhMsg {
+		return NexthopObject{}, false
+	}
+	attrs := netlinkAttrs(b[sizeofNhMsg:])
+	id, ok := attrs[nhaID]
+	if !ok {
+		return NexthopObject{}, false
+	}
+
+	nh := NexthopObject{
+		ID:     nlUint32(id),
+		Proto:  nameOrNumber(protos, int(b[2])),
+		OnLink: binary.NativeEndian.Uint32(b[4:8])&rtnhFOnlink != 0,
+	}
+	_, nh.Blackhole = attrs[nhaBlackhole]
+	if v, ok := attrs[nhaOIF]; ok {
+		nh.Interface = interfaceName(int(nlUint32(v)))
+	}
+	if v, ok := attrs[nhaGateway]; ok && (len(v) == net.IPv4len || len(v) == net.IPv6len) {
+		nh.Gateway = net.IP(v).String()
+	}
+	for v := attrs[nhaGroup]; len(v) >= sizeofNexthopGrp; v = v[sizeofNexthopGrp:] {
+		nh.Group = append(nh.Group, NexthopGroupMember{
+			ID:     binary.NativeEndian.Uint32(v[0:4]),
+			Weight: int(v[4]) + 1, // The kernel stores the weight minus one.
+		})
+	}
+
+	return nh, true
+}
+
+// resolveNexthopIDs resolves routes that reference nexthop objects without carrying their paths,
+// listing the objects only if there are such routes.
+func resolveNexthopIDs(ctx context.Context, routes []RoutingTable) ([]RoutingTable, error) {
+	for _, rt := range routes {
+		if rt.NexthopID == 0 || rt.Interface != "" || len(rt.Nexthops) > 0 {
+			continue
+		}
+		objects, err := NexthopObjectsContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return ResolveNexthopObjects(routes, objects), nil
+	}
+
+	return routes, nil
+}
+
+// resolveIPv6NexthopIDs is like resolveNexthopIDs for IPv6 routes, which take the gateway and interface of
+// the first member of a group.
+func resolveIPv6NexthopIDs(ctx context.Context, routes []IPv6Route) ([]IPv6Route, error) {
+	var byID map[uint32]NexthopObject
+	for i, rt := range routes {
+		if rt.NexthopID == 0 || rt.Interface != "" {
+			continue
+		}
+		if byID == nil {
+			objects, err := NexthopObjectsContext(ctx)
+			if err != nil {
+				return nil, err
+			}
+			byID = make(map[uint32]NexthopObject, len(objects))
+			for _, nh := range objects {
+				byID[nh.ID] = nh
+			}
+		}
+
+		nh := byID[rt.NexthopID]
+		if len(nh.Group) > 0 {
+			nh = byID[nh.Group[0].ID]
+		}
+		if gw := net.ParseIP(nh.Gateway); gw != nil {
+			routes[i].Gateway = gw
+			routes[i].Flags = computeIPv6RouteFlag(flagBits(rt.Flags) | FlagGateway)
+		}
+		routes[i].Interface = nh.Interface
+	}
+
+	return routes, nil
+}
@@ -0,0 +1,94 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// addNexthopObject creates a nexthop object through lo, or a group of the given members with weight 1.
+func addNexthopObject(t *testing.T, id uint32, gw string, group ...uint32) {
+	t.Helper()
+	msg := make([]byte, sizeofNhMsg)
+	msg[2] = rtprotBoot
+	msg = append(msg, nlAttr(nhaID, nlUint32Bytes(id))...)
+	if len(group) > 0 {
+		var grp []byte
+		for _, m := range group {
+			grp = binary.NativeEndian.AppendUint32(grp, m)
+			grp = append(grp, 0, 0, 0, 0)
+		}
+		msg = append(msg, nlAttr(nhaGroup, grp)...)
+	} else {
+		msg[0] = syscall.AF_INET
+		binary.NativeEndian.PutUint32(msg[4:8], rtnhFOnlink)
+		msg = append(msg, nlAttr(nhaOIF, nlUint32Bytes(1))...)
+		msg = append(msg, nlAttr(nhaGateway, net.ParseIP(gw).To4())...)
+	}
+	if _, err := netlinkRequest(context.Background(), rtmNewNexthop, syscall.NLM_F_CREATE|syscall.NLM_F_EXCL, msg); err != nil {
+		t.Skipf("Cannot create nexthop objects: %s", err)
+	}
+}
+
+func TestNexthopObjects(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+	setLoopbackUp(t)
+	addNexthopObject(t, 1, "192.0.2.1")
+	addNexthopObject(t, 2, "192.0.2.2")
+	addNexthopObject(t, 3, "", 1, 2)
+
+	objects, err := NexthopObjects()
+	if err != nil {
+		t.Fatalf("NexthopObjects failed %s", err.Error())
+	}
+	expected := []string{
+		"id 1 via 192.0.2.1 dev lo onlink",
+		"id 2 via 192.0.2.2 dev lo onlink",
+		"id 3 group 1/2",
+	}
+	if len(objects) != len(expected) {
+		t.Fatalf("Expected %d nexthops, got %v", len(expected), objects)
+	}
+	for i, nh := range objects {
+		if nh.String() != expected[i] {
+			t.Errorf("Nexthop %d = %q, want %q", i, nh.String(), expected[i])
+		}
+	}
+
+	// Without compat mode the kernel reports only the nexthop IDs of routes, which NetlinkSource resolves.
+	if err := os.WriteFile("/proc/sys/net/ipv4/nexthop_compat_mode", []byte("0"), 0o644); err != nil {
+		t.Skipf("Cannot disable nexthop compat mode: %s", err)
+	}
+	routes, err := ParseIPRoute(strings.NewReader("198.51.100.0/24 nhid 1 table 4242\n203.0.113.0/24 nhid 3 table 4242\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	for _, rt := range routes {
+		if err := AddRoute(rt); err != nil {
+			t.Fatalf("AddRoute failed %s", err.Error())
+		}
+	}
+
+	got, err := NetlinkSource{Table: 4242}.Routes(context.Background())
+	if err != nil {
+		t.Fatalf("NetlinkSource failed %s", err.Error())
+	}
+	expected = []string{
+		"198.51.100.0/24 nhid 1 via 192.0.2.1 dev lo table 4242 onlink",
+		"203.0.113.0/24 nhid 3 table 4242\n\tnexthop via 192.0.2.1 dev lo weight 1 onlink\n\tnexthop via 192.0.2.2 dev lo weight 1 onlink",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d routes, got %v", len(expected), got)
+	}
+	for i, rt := range got {
+		if rt.String() != expected[i] {
+			t.Errorf("Route %d = %q, want %q", i, rt.String(), expected[i])
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package routing
+
+import "context"
+
+// NexthopObjectsContext is like NexthopObjects but returns early if ctx is done.
+// Netlink is only available on Linux, so it always returns ErrNotSupported here.
+func NexthopObjectsContext(ctx context.Context) ([]NexthopObject, error) {
+	return nil, ErrNotSupported
+}
@@ -0,0 +1,60 @@
+package routing
+
+import (
+	"strings"
+	"testing"
+)
+
+var nexthopObjectsFixture = []NexthopObject{
+	{ID: 1, Gateway: "192.0.2.1", Interface: "eth0"},
+	{ID: 2, Gateway: "192.0.2.2", Interface: "eth1", OnLink: true},
+	{ID: 3, Group: []NexthopGroupMember{{ID: 1, Weight: 1}, {ID: 2, Weight: 3}}},
+	{ID: 4, Blackhole: true},
+	{ID: 5, Interface: "eth0"},
+}
+
+func TestNexthopObjectString(t *testing.T) {
+	expected := []string{
+		"id 1 via 192.0.2.1 dev eth0",
+		"id 2 via 192.0.2.2 dev eth1 onlink",
+		"id 3 group 1/2,3",
+		"id 4 blackhole",
+		"id 5 dev eth0",
+	}
+	for i, nh := range nexthopObjectsFixture {
+		if nh.String() != expected[i] {
+			t.Errorf("Nexthop %d = %q, want %q", i, nh.String(), expected[i])
+		}
+	}
+}
+
+func TestResolveNexthopObjects(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("198.51.100.0/24 nhid 1\n203.0.113.0/24 nhid 3\n192.0.2.128/25 nhid 4\n10.0.0.0/8 nhid 5\n10.1.0.0/16 nhid 42\n10.2.0.0/16 nhid 1 via 192.0.2.1 dev eth0\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	if routes[0].NexthopID != 1 {
+		t.Fatalf("Expected nhid 1, got %d", routes[0].NexthopID)
+	}
+
+	got := ResolveNexthopObjects(routes, nexthopObjectsFixture)
+	expected := []string{
+		"198.51.100.0/24 nhid 1 via 192.0.2.1 dev eth0",
+		"203.0.113.0/24 nhid 3\n\tnexthop via 192.0.2.1 dev eth0 weight 1\n\tnexthop via 192.0.2.2 dev eth1 weight 3 onlink",
+		"blackhole 192.0.2.128/25 nhid 4",
+		"10.0.0.0/8 nhid 5 dev eth0",
+		"10.1.0.0/16 nhid 42",
+		"10.2.0.0/16 nhid 1 via 192.0.2.1 dev eth0",
+	}
+	for i, rt := range got {
+		if rt.String() != expected[i] {
+			t.Errorf("Route %d = %q, want %q", i, rt.String(), expected[i])
+		}
+	}
+	if !flagContains(got[0].Flags, "G") || flagContains(got[3].Flags, "G") {
+		t.Errorf("Unexpected gateway flags %v %v", got[0].Flags, got[3].Flags)
+	}
+	if routes[0].Interface != "" {
+		t.Error("Expected ResolveNexthopObjects to leave its input unchanged")
+	}
+}
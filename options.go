@@ -0,0 +1,283 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"time"
+)
+
+// Option configures a Manager or a source built by NewSource.
+type Option interface {
+	apply(o *options)
+}
+
+// optionFunc is an Option setting the options it is called with.
+type optionFunc func(*options)
+
+// apply calls f with o.
+func (f optionFunc) apply(o *options) { f(o) }
+
+// options collects the settings applied by Option values.
+type options struct {
+	source   RouteSource   // Explicit source; takes precedence over netlink and procPath.
+	procPath string        // File read by the default ProcSource.
+	fsys     fs.FS         // Filesystem /proc files are read from; nil for the host's.
+	netlink  bool          // Read the main table over netlink instead of /proc/net/route.
+	cacheTTL time.Duration // Reuse reads for this long; zero disables caching.
+	logger   *slog.Logger  // Receives diagnostics; nil discards them.
+	family   Family        // Only return routes of this family; FamilyUnspec returns all.
+	retry    RetryPolicy   // Retries transient read failures; the zero value does not retry.
+	noCloned bool          // Drop kernel-generated routes such as cached entries.
+	noDown   bool          // Drop routes through interfaces that are down or without carrier.
+	kinds    bool          // Set the DeviceKind of routes.
+}
+
+// WithSource reads routes from src, overriding WithProcPath and WithNetlink.
+func WithSource(src RouteSource) Option {
+	return optionFunc(func(o *options) { o.source = src })
+}
+
+// WithProcPath reads routes from the file at path instead of /proc/net/route.
+func WithProcPath(path string) Option {
+	return optionFunc(func(o *options) { o.procPath = path })
+}
+
+// WithNetlink reads the main routing table over netlink, which also provides protocol, scope and route types.
+func WithNetlink() Option {
+	return optionFunc(func(o *options) { o.netlink = true })
+}
+
+// WithCacheTTL reuses each read of the routing table for ttl. Zero, the default, disables caching.
+func WithCacheTTL(ttl time.Duration) Option {
+	return optionFunc(func(o *options) { o.cacheTTL = ttl })
+}
+
+// WithFS reads /proc files such as /proc/net/route and /proc/net/arp from fsys instead of the host,
+// e.g. an fstest.MapFS with a "proc/net/route" entry. Paths given to WithProcPath are then looked up in fsys too.
+func WithFS(fsys fs.FS) Option {
+	return optionFunc(func(o *options) { o.fsys = fsys })
+}
+
+// WithLogger sends diagnostics to logger. Without it nothing is logged.
+func WithLogger(logger *slog.Logger) Option {
+	return optionFunc(func(o *options) { o.logger = logger })
+}
+
+// WithFamily only returns routes whose destination belongs to family.
+func WithFamily(family Family) Option {
+	return optionFunc(func(o *options) { o.family = family })
+}
+
+// WithRetry retries reads of the routing table that fail with a transient error, as IsTransient reports,
+// following policy. By default a failed read is returned at once.
+func WithRetry(policy RetryPolicy) Option {
+	return optionFunc(func(o *options) { o.retry = policy })
+}
+
+// WithoutCloned drops kernel-generated routes, such as cached entries and routes created by redirects,
+// from the routes read, IPv6 routes included; see RoutingTable.Cloned.
+func WithoutCloned() Option {
+	return optionFunc(func(o *options) { o.noCloned = true })
+}
+
+// WithoutDownInterfaces drops routes whose interface is administratively down or has no carrier, as they cannot
+// carry traffic, IPv6 routes included. The interfaces' state is read along with every read of the routing table;
+// see ExcludeDownLinks.
+func WithoutDownInterfaces() Option {
+	return optionFunc(func(o *options) { o.noDown = true })
+}
+
+// WithDeviceKinds sets the DeviceKind of every route read from the kind of its interface, read along with the
+// routing table. It is only available on Linux; elsewhere reads fail with ErrNotSupported.
+func WithDeviceKinds() Option {
+	return optionFunc(func(o *options) { o.kinds = true })
+}
+
+// newOptions applies opts over the defaults.
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return o
+}
+
+// baseSource returns the configured source without caching.
+func (o options) baseSource() RouteSource {
+	src := o.source
+	switch {
+	case src != nil:
+	case o.netlink:
+		src = NetlinkSource{Table: TableMain}
+	case o.procPath == "" && o.fsys == nil:
+		src = o.hostSource()
+	default:
+		src = ProcSource{Path: o.procPath, FS: o.fsys, OnWarning: logWarnings(o.logger)}
+	}
+	if o.retry.Attempts > 1 {
+		r := NewRetrySource(src, o.retry)
+		if o.logger != nil {
+			r.logger = o.logger
+		}
+		src = r
+	}
+	if o.family != FamilyUnspec {
+		src = familySource{source: src, family: o.family}
+	}
+	if o.noCloned {
+		src = filterSource{source: src, keep: func(rt RoutingTable) bool { return !rt.Cloned() }}
+	}
+	if o.noDown {
+		src = upLinkSource{source: src, links: Links}
+	}
+	if o.kinds {
+		src = deviceKindSource{source: src, kinds: DeviceKindsContext}
+	}
+
+	return src
+}
+
+// NewSource returns the RouteSource described by opts: /proc/net/route by default, or the main table over netlink
+// when the host's /proc is unusable, optionally filtered by family and cached. WithLogger logs anomalies found while parsing /proc/net/route.
+func NewSource(opts ...Option) RouteSource {
+	o := newOptions(opts)
+	src := o.baseSource()
+	if o.cacheTTL > 0 {
+		return NewCachedSource(src, o.cacheTTL)
+	}
+
+	return src
+}
+
+// logWarnings returns a ProcSource.OnWarning callback logging each anomaly as a warning, or nil without a logger.
+func logWarnings(logger *slog.Logger) func(ParseWarning) {
+	if logger == nil {
+		return nil
+	}
+
+	return func(w ParseWarning) {
+		logger.Warn("routing table parse anomaly", "file", w.File, "line", w.Line, "column", w.Column, "value", w.Value, "err", w.Err)
+	}
+}
+
+// familySource drops routes of other address families from a source.
+type familySource struct {
+	source RouteSource
+	family Family
+}
+
+// Routes returns the routes of the wrapped source whose destination belongs to the configured family.
+func (s familySource) Routes(ctx context.Context) ([]RoutingTable, error) {
+	routes, err := s.source.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return FilterRoutes(routes, func(rt RoutingTable) bool { return routeFamily(rt) == s.family }), nil
+}
+
+// filterSource drops the routes of a source for which keep returns false.
+type filterSource struct {
+	source RouteSource
+	keep   func(RoutingTable) bool
+}
+
+// Routes returns the routes of the wrapped source that keep accepts.
+func (s filterSource) Routes(ctx context.Context) ([]RoutingTable, error) {
+	routes, err := s.source.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return FilterRoutes(routes, s.keep), nil
+}
+
+// upLinkSource drops the routes of a source whose interface is not operational.
+type upLinkSource struct {
+	source RouteSource
+	links  func() ([]Link, error) // Lists the interfaces, replaceable in tests.
+}
+
+// Routes returns the routes of the wrapped source, without those through interfaces that are down.
+func (s upLinkSource) Routes(ctx context.Context) ([]RoutingTable, error) {
+	routes, err := s.source.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	links, err := s.links()
+	if err != nil {
+		return nil, err
+	}
+
+	return ExcludeDownLinks(routes, links), nil
+}
+
+// deviceKindSource sets the DeviceKind of the routes of a source.
+type deviceKindSource struct {
+	source RouteSource
+	kinds  func(context.Context) (map[string]DeviceKind, error) // Lists the interfaces' kinds, replaceable in tests.
+}
+
+// Routes returns the routes of the wrapped source classified by their interface.
+func (s deviceKindSource) Routes(ctx context.Context) ([]RoutingTable, error) {
+	routes, err := s.source.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	kinds, err := s.kinds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ClassifyDevices(routes, kinds), nil
+}
+
+// routeFamily returns the address family of a route's destination.
+func routeFamily(rt RoutingTable) Family {
+	dst, _, _, err := decodeDestination(rt)
+	switch {
+	case err != nil:
+		return FamilyUnspec
+	case dst.To4() != nil:
+		return FamilyIPv4
+	case len(dst) == net.IPv6len:
+		return FamilyIPv6
+	}
+
+	return FamilyUnspec
+}
+
+// procFallbackSource reads the host's /proc/net/route, falling back to the main table over netlink when /proc is
+// unusable, as in scratch containers. The ProcUnavailableError is returned if netlink is not available either.
+type procFallbackSource struct {
+	proc     ProcSource
+	fallback RouteSource
+	logger   *slog.Logger // Receives the fallbacks; nil discards them.
+}
+
+// Routes reads /proc/net/route, or the fallback if /proc is unusable.
+func (s procFallbackSource) Routes(ctx context.Context) ([]RoutingTable, error) {
+	routes, err := s.proc.Routes(ctx)
+	var pErr *ProcUnavailableError
+	if !errors.As(err, &pErr) || !pErr.procUnusable {
+		return routes, err
+	}
+
+	routes, fErr := s.fallback.Routes(ctx)
+	switch {
+	case errors.Is(fErr, ErrNotSupported):
+		return nil, err
+	case fErr != nil:
+		return nil, fmt.Errorf("%w (netlink fallback failed: %v)", err, fErr)
+	}
+	if s.logger != nil {
+		s.logger.Debug("reading routes over netlink", "reason", pErr.Reason)
+	}
+
+	return routes, nil
+}
@@ -0,0 +1,101 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestNewSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "route")
+	if err := os.WriteFile(path, []byte(procRouteFixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := NewSource(WithProcPath(path), WithFamily(FamilyIPv4)).Routes(context.Background())
+	if err != nil || len(routes) != 3 {
+		t.Errorf("Expected 3 IPv4 routes, got %v, %v", routes, err)
+	}
+	routes, err = NewSource(WithProcPath(path), WithFamily(FamilyIPv6)).Routes(context.Background())
+	if err != nil || len(routes) != 0 {
+		t.Errorf("Expected no IPv6 routes, got %v, %v", routes, err)
+	}
+
+	if _, ok := NewSource(WithNetlink()).(NetlinkSource); !ok {
+		t.Errorf("Expected WithNetlink to select a NetlinkSource")
+	}
+	if _, ok := NewSource(WithNetlink(), WithCacheTTL(time.Second)).(*CachedSource); !ok {
+		t.Errorf("Expected WithCacheTTL to wrap the source in a cache")
+	}
+	if src, ok := NewSource(WithSource(IPRouteSource{}), WithNetlink()).(IPRouteSource); !ok {
+		t.Errorf("Expected WithSource to take precedence, got %T", src)
+	}
+}
+
+func TestWithLoggerParseWarnings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "route")
+	table := "Iface\tDestination\tGateway\tFlags\tRefCnt\tUse\tMetric\tMask\tMTU\tWindow\tIRTT\n" +
+		"eth0\t00000000\t0100A8C0\t0003\t0\t0\tbogus\t00000000\t0\t0\t0\n"
+	if err := os.WriteFile(path, []byte(table), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	if _, err := NewSource(WithProcPath(path), WithLogger(logger)).Routes(context.Background()); err != nil {
+		t.Fatalf("Routes failed %s", err.Error())
+	}
+	if out := buf.String(); !strings.Contains(out, "level=WARN") || !strings.Contains(out, "column=Metric") {
+		t.Errorf("Expected a warning for the Metric column, got %q", out)
+	}
+}
+
+func TestProcFallbackSource(t *testing.T) {
+	netlinkRoutes, err := ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev eth0 proto dhcp\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	fallback := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) { return netlinkRoutes, nil })
+	var logs bytes.Buffer
+	src := procFallbackSource{
+		proc:     ProcSource{FS: fstest.MapFS{}},
+		fallback: fallback,
+		logger:   slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+
+	routes, err := src.Routes(context.Background())
+	if err != nil || len(routes) != 1 || routes[0].Proto != "dhcp" {
+		t.Errorf("Expected the netlink routes without /proc, got %v, %v", routes, err)
+	}
+	if !strings.Contains(logs.String(), "not mounted") {
+		t.Errorf("Expected the fallback to be logged, got %q", logs.String())
+	}
+
+	src.proc.FS = procFS
+	if routes, err := src.Routes(context.Background()); err != nil || len(routes) != 3 {
+		t.Errorf("Expected /proc/net/route to be read when available, got %v, %v", routes, err)
+	}
+
+	src.proc.FS = fstest.MapFS{"proc/self/status": {}, "proc/net/dev": {}}
+	if _, err := src.Routes(context.Background()); !errors.Is(err, ErrProcUnavailable) {
+		t.Errorf("Expected no fallback when only the file is missing, got %v", err)
+	}
+
+	src.proc.FS = fstest.MapFS{}
+	src.fallback = routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) { return nil, ErrNotSupported })
+	if _, err := src.Routes(context.Background()); !errors.Is(err, ErrProcUnavailable) || errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected the /proc error without netlink, got %v", err)
+	}
+	src.fallback = routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) { return nil, syscall.EPERM })
+	if _, err := src.Routes(context.Background()); !errors.Is(err, ErrProcUnavailable) || !strings.Contains(err.Error(), "netlink fallback failed") {
+		t.Errorf("Expected both failures to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package routing
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"net"
+	"os"
+	"time"
+)
+
+// Limits of the path MTU search. 68 bytes is the smallest MTU an IPv4 link may have (RFC 791).
+const (
+	minPathMTU         = 68
+	defaultLinkMTU     = 1500
+	ipv4HeaderLen      = 20
+	mtuProbeTimeout    = time.Second
+	mtuProbesPerSize   = 2 // A lost probe is retried once before the size is taken to be too large.
+	echoHeaderOverhead = ipv4HeaderLen + icmpHeaderLen
+)
+
+// mtuProbeResult is the outcome of sending one probe of a given size.
+type mtuProbeResult int
+
+const (
+	mtuProbeOK     mtuProbeResult = iota // The destination answered.
+	mtuProbeTooBig                       // The kernel refused the size, as a router reported a smaller MTU.
+	mtuProbeLost                         // Nothing came back.
+)
+
+// PathMTUCheck is the result of probing the path MTU toward a destination.
+type PathMTUCheck struct {
+	Destination  net.IP       // The address that was probed.
+	Route        RoutingTable // The route the probes took.
+	PathMTU      int          // The largest packet that reached the destination; zero if no probe was answered.
+	InterfaceMTU int          // The MTU of the route's interface; zero if unknown.
+	RouteMTU     int          // The MTU set on the route; zero if none.
+	Signalled    bool         // A router reported a smaller MTU with an ICMP "fragmentation needed" message.
+}
+
+// LocalMTU returns the MTU the host sends with toward the destination: the route MTU if set,
+// the interface MTU otherwise, and 1500 if neither is known.
+func (c PathMTUCheck) LocalMTU() int {
+	switch {
+	case c.RouteMTU > 0:
+		return c.RouteMTU
+	case c.InterfaceMTU > 0:
+		return c.InterfaceMTU
+	}
+
+	return defaultLinkMTU
+}
+
+// Blackhole reports whether packets of the local MTU are silently dropped on the path:
+// smaller packets get through but no router reported the smaller MTU, so TCP connections will stall.
+func (c PathMTUCheck) Blackhole() bool {
+	return c.PathMTU > 0 && c.PathMTU < c.LocalMTU() && !c.Signalled
+}
+
+// CheckPathMTU probes the path MTU toward dst with ICMP echo requests that must not be fragmented,
+// searching sizes between 68 bytes and the local MTU. It needs the same privileges as CheckGateway
+// and is only supported on Linux; elsewhere it returns ErrNotSupported.
+func CheckPathMTU(ctx context.Context, dst net.IP) (PathMTUCheck, error) {
+	return defaultManager.CheckPathMTU(ctx, dst)
+}
+
+// CheckPathMTU is like the package-level CheckPathMTU but takes the route and its MTU from the manager's routes.
+func (m *Manager) CheckPathMTU(ctx context.Context, dst net.IP) (PathMTUCheck, error) {
+	rt, ok, err := m.Lookup(ctx, dst)
+	if err != nil {
+		return PathMTUCheck{}, err
+	}
+	if !ok {
+		return PathMTUCheck{}, ErrNoDefaultGateway // Only a table without a default route leaves dst unrouted.
+	}
+
+	check := PathMTUCheck{Destination: dst, Route: rt, RouteMTU: int(rt.Metrics.MTU)}
+	if ifi, err := net.InterfaceByName(rt.Interface); err == nil {
+		check.InterfaceMTU = ifi.MTU
+	}
+
+	conn, addr, err := listenICMP(dst, m.log())
+	if err != nil {
+		return check, err
+	}
+	defer conn.Close()
+	if err := setDontFragment(conn); err != nil {
+		return check, err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	token := make([]byte, 8)
+	rand.Read(token)
+	id, seq := uint16(os.Getpid()), uint16(0)
+	probe := func(ctx context.Context, size int) (mtuProbeResult, error) {
+		payload := make([]byte, size-echoHeaderOverhead)
+		copy(payload, token)
+		seq++
+		return sendMTUProbe(ctx, conn, addr, icmpEcho(id, seq, payload), seq, payload)
+	}
+
+	check.PathMTU, check.Signalled, err = searchPathMTU(ctx, check.LocalMTU(), probe)
+
+	return check, err
+}
+
+// sendMTUProbe sends one echo request and waits for its reply.
+func sendMTUProbe(ctx context.Context, conn net.PacketConn, addr net.Addr, msg []byte, seq uint16, payload []byte) (mtuProbeResult, error) {
+	if _, err := conn.WriteTo(msg, addr); err != nil {
+		if isMessageTooLong(err) {
+			return mtuProbeTooBig, nil
+		}
+		return mtuProbeLost, err
+	}
+
+	deadline := time.Now().Add(mtuProbeTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetReadDeadline(deadline)
+	buf := make([]byte, len(msg)+ipv4HeaderLen+64) // Room for the IP header raw sockets deliver.
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return mtuProbeLost, ctx.Err()
+		}
+		if err != nil {
+			if isMessageTooLong(err) {
+				return mtuProbeTooBig, nil
+			}
+			return mtuProbeLost, err
+		}
+		reply := buf[:n]
+		if len(reply) > ipv4HeaderLen && reply[0]>>4 == 4 {
+			reply = reply[int(reply[0]&0x0f)*4:] // Raw sockets include the IP header.
+		}
+		if isEchoReply(reply, seq, payload) {
+			return mtuProbeOK, nil
+		}
+	}
+}
+
+// searchPathMTU finds the largest size up to localMTU for which probe succeeds, by binary search.
+// It also reports whether any size was refused because a router signalled a smaller MTU.
+// The result is zero if even the smallest probe is lost.
+func searchPathMTU(ctx context.Context, localMTU int, probe func(ctx context.Context, size int) (mtuProbeResult, error)) (int, bool, error) {
+	signalled := false
+	fits := func(size int) (bool, error) {
+		for range mtuProbesPerSize {
+			res, err := probe(ctx, size)
+			if err != nil {
+				return false, err
+			}
+			switch res {
+			case mtuProbeOK:
+				return true, nil
+			case mtuProbeTooBig:
+				signalled = true
+				return false, nil
+			}
+		}
+		return false, nil
+	}
+
+	if ok, err := fits(localMTU); err != nil {
+		return 0, signalled, err
+	} else if ok {
+		return localMTU, signalled, nil
+	}
+	if ok, err := fits(minPathMTU); !ok || err != nil {
+		return 0, signalled, err
+	}
+
+	good, bad := minPathMTU, localMTU
+	for bad-good > 1 {
+		mid := (good + bad) / 2
+		ok, err := fits(mid)
+		if err != nil {
+			return good, signalled, err
+		}
+		if ok {
+			good = mid
+		} else {
+			bad = mid
+		}
+	}
+
+	return good, signalled, nil
+}
@@ -0,0 +1,34 @@
+package routing
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+// setDontFragment sets IP_PMTUDISC_DO on conn, so its packets carry the DF bit and the kernel refuses to send
+// packets larger than a path MTU a router has reported.
+func setDontFragment(conn net.PacketConn) error {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return ErrNotSupported
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var sockErr error
+	if err := raw.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_MTU_DISCOVER, syscall.IP_PMTUDISC_DO)
+	}); err != nil {
+		return err
+	}
+
+	return sockErr
+}
+
+// isMessageTooLong reports whether err is EMSGSIZE, returned for packets larger than the known path MTU.
+func isMessageTooLong(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE)
+}
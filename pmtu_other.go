@@ -0,0 +1,15 @@
+//go:build !linux
+
+package routing
+
+import "net"
+
+// setDontFragment always returns ErrNotSupported, as path MTU probes are only implemented on Linux.
+func setDontFragment(conn net.PacketConn) error {
+	return ErrNotSupported
+}
+
+// isMessageTooLong always reports false, as path MTU probes are only implemented on Linux.
+func isMessageTooLong(err error) bool {
+	return false
+}
@@ -0,0 +1,62 @@
+package routing
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSearchPathMTU(t *testing.T) {
+	tests := []struct {
+		name      string
+		pathMTU   int  // Largest size the simulated path carries.
+		signal    bool // Whether the path reports smaller MTUs.
+		want      int
+		signalled bool
+	}{
+		{"clean path", 1500, false, 1500, false},
+		{"tunnel with ICMP", 1420, true, 1420, true},
+		{"blackhole", 1400, false, 1400, false},
+		{"unreachable", 0, false, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probes := 0
+			probe := func(ctx context.Context, size int) (mtuProbeResult, error) {
+				probes++
+				switch {
+				case size <= tt.pathMTU:
+					return mtuProbeOK, nil
+				case tt.signal:
+					return mtuProbeTooBig, nil
+				}
+				return mtuProbeLost, nil
+			}
+
+			got, signalled, err := searchPathMTU(context.Background(), 1500, probe)
+			if err != nil || got != tt.want || signalled != tt.signalled {
+				t.Errorf("searchPathMTU = %d, %t, %v; want %d, %t", got, signalled, err, tt.want, tt.signalled)
+			}
+			if probes > 2*12 {
+				t.Errorf("Expected a binary search, got %d probes", probes)
+			}
+		})
+	}
+}
+
+func TestPathMTUCheckBlackhole(t *testing.T) {
+	tests := []struct {
+		check PathMTUCheck
+		want  bool
+	}{
+		{PathMTUCheck{PathMTU: 1400, InterfaceMTU: 1500}, true},
+		{PathMTUCheck{PathMTU: 1400, InterfaceMTU: 1500, Signalled: true}, false},
+		{PathMTUCheck{PathMTU: 1400, InterfaceMTU: 9000, RouteMTU: 1400}, false},
+		{PathMTUCheck{PathMTU: 1500}, false},
+		{PathMTUCheck{}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.check.Blackhole(); got != tt.want {
+			t.Errorf("%+v: Blackhole() = %t, want %t", tt.check, got, tt.want)
+		}
+	}
+}
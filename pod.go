@@ -0,0 +1,173 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+var errPodNotFound = errors.New("pod sandbox not found")
+
+// NewProcessManager returns a Manager reading the routing tables of the network namespace of process pid through
+// /proc/<pid>/net, e.g. those of a container, without entering the namespace. Other files, such as
+// /etc/resolv.conf, are read from the process's root directory. WithNetlink must not be given, as netlink
+// always answers for the caller's namespace.
+func NewProcessManager(pid int, opts ...Option) *Manager {
+	return NewManager(append([]Option{WithFS(processFS(pid))}, opts...)...)
+}
+
+// processFS is the filesystem as seen by a process: /proc files from /proc/<pid>, others from its root directory.
+type processFS int
+
+// Open opens the named file, e.g. "proc/net/route" from /proc/<pid>/net/route.
+func (pid processFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	dir := fmt.Sprintf("/proc/%d", int(pid))
+	if rest, ok := strings.CutPrefix(name, "proc/"); ok {
+		return os.Open(path.Join(dir, rest))
+	}
+
+	return os.Open(path.Join(dir, "root", name))
+}
+
+// PodNetwork is the routing state of a Kubernetes pod.
+type PodNetwork struct {
+	Namespace      string         // The pod's namespace.
+	Name           string         // The pod's name.
+	SandboxID      string         // ID of the pod sandbox in the container runtime.
+	PID            int            // PID of the sandbox process, whose network namespace the pod's containers share.
+	Routes         []RoutingTable // The pod's IPv4 routes.
+	IPv6Routes     []IPv6Route    // The pod's IPv6 routes; nil if IPv6 is disabled in the pod.
+	DefaultGateway string         // The pod's default gateway; empty if it has no default route.
+}
+
+// PodInspector reads the routing tables of Kubernetes pods on the local node. It finds the pod sandbox through the
+// container runtime (CRI) with crictl, then reads the tables of the sandbox's network namespace from /proc.
+// It needs root privileges and a runtime, such as containerd, that reports the sandbox PID.
+type PodInspector struct {
+	Crictl   string // Path to the crictl binary; "crictl" is looked up in $PATH when empty.
+	Endpoint string // CRI endpoint, e.g. "unix:///run/containerd/containerd.sock"; crictl's configured one when empty.
+}
+
+// InspectPod returns the routing tables and default gateway of the pod name in namespace, running on this node,
+// using crictl with its configured runtime endpoint; see PodInspector.
+func InspectPod(namespace, name string) (PodNetwork, error) {
+	return InspectPodContext(context.Background(), namespace, name)
+}
+
+// InspectPodContext is like InspectPod but returns early if ctx is done.
+func InspectPodContext(ctx context.Context, namespace, name string) (PodNetwork, error) {
+	return PodInspector{}.Inspect(ctx, namespace, name)
+}
+
+// Inspect returns the routing tables and default gateway of the pod name in namespace.
+func (p PodInspector) Inspect(ctx context.Context, namespace, name string) (PodNetwork, error) {
+	id, pid, err := p.sandbox(ctx, namespace, name)
+	if err != nil {
+		return PodNetwork{}, err
+	}
+
+	m := NewProcessManager(pid)
+	pn := PodNetwork{Namespace: namespace, Name: name, SandboxID: id, PID: pid}
+	if pn.Routes, err = m.Routes(ctx); err != nil {
+		return PodNetwork{}, err
+	}
+	if pn.IPv6Routes, err = m.IPv6Routes(ctx); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return PodNetwork{}, err
+	}
+	if rt, ok := defaultRoute(pn.Routes); ok {
+		pn.DefaultGateway = rt.Gateway
+	}
+
+	return pn, nil
+}
+
+// sandbox returns the ID and PID of the ready sandbox of the pod name in namespace.
+func (p PodInspector) sandbox(ctx context.Context, namespace, name string) (string, int, error) {
+	out, err := p.crictl(ctx, "pods", "--namespace", namespace, "--name", name, "--state", "ready", "-o", "json")
+	if err != nil {
+		return "", 0, err
+	}
+	id, err := parseCrictlPods(out, namespace, name)
+	if err != nil {
+		return "", 0, err
+	}
+
+	out, err = p.crictl(ctx, "inspectp", id)
+	if err != nil {
+		return "", 0, err
+	}
+	pid, err := parseCrictlInspect(out)
+	if err != nil {
+		return "", 0, fmt.Errorf("pod %s/%s: %w", namespace, name, err)
+	}
+
+	return id, pid, nil
+}
+
+// crictl runs crictl with args and returns its output.
+func (p PodInspector) crictl(ctx context.Context, args ...string) ([]byte, error) {
+	bin := p.Crictl
+	if bin == "" {
+		bin = "crictl"
+	}
+	if p.Endpoint != "" {
+		args = append([]string{"--runtime-endpoint", p.Endpoint}, args...)
+	}
+
+	out, err := exec.CommandContext(ctx, bin, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("running %s: %w", bin, err)
+	}
+
+	return out, nil
+}
+
+// parseCrictlPods returns the ID of the pod sandbox with exactly the given namespace and name in the output of
+// `crictl pods -o json`, whose name filter is a regular expression.
+func parseCrictlPods(data []byte, namespace, name string) (string, error) {
+	var pods struct {
+		Items []struct {
+			ID       string `json:"id"`
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &pods); err != nil {
+		return "", fmt.Errorf("decoding crictl pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		if pod.Metadata.Namespace == namespace && pod.Metadata.Name == name {
+			return pod.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("pod %s/%s: %w", namespace, name, errPodNotFound)
+}
+
+// parseCrictlInspect returns the PID of the sandbox in the output of `crictl inspectp`.
+func parseCrictlInspect(data []byte) (int, error) {
+	var sandbox struct {
+		Info struct {
+			PID int `json:"pid"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(data, &sandbox); err != nil {
+		return 0, fmt.Errorf("decoding crictl inspectp: %w", err)
+	}
+	if sandbox.Info.PID <= 0 {
+		return 0, errors.New("the container runtime does not report the sandbox PID")
+	}
+
+	return sandbox.Info.PID, nil
+}
@@ -0,0 +1,52 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNewProcessManager(t *testing.T) {
+	host, err := NewManager().Routes(context.Background())
+	if err != nil {
+		t.Skipf("Cannot read /proc/net/route: %s", err)
+	}
+	routes, err := NewProcessManager(os.Getpid()).Routes(context.Background())
+	if err != nil {
+		t.Fatalf("Routes failed %s", err.Error())
+	}
+	if !reflect.DeepEqual(routes, host) {
+		t.Errorf("Expected the routes of this process's namespace, got %v, want %v", routes, host)
+	}
+	if _, err := NewProcessManager(-1).Routes(context.Background()); err == nil {
+		t.Error("Expected reading the routes of a missing process to fail")
+	}
+}
+
+func TestPodInspector(t *testing.T) {
+	dir := t.TempDir()
+	crictl := filepath.Join(dir, "crictl")
+	script := fmt.Sprintf("#!/bin/sh\ncase \"$1\" in\npods) echo '%s' ;;\ninspectp) echo '{\"info\":{\"pid\":%d}}' ;;\nesac\n",
+		crictlPodsFixture, os.Getpid())
+	if err := os.WriteFile(crictl, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	pn, err := PodInspector{Crictl: crictl}.Inspect(context.Background(), "default", "web")
+	if err != nil {
+		t.Skipf("Cannot inspect the fake pod: %s", err)
+	}
+	if pn.SandboxID != "0c3a7e5d9f21b" || pn.PID != os.Getpid() {
+		t.Errorf("Unexpected pod %+v", pn)
+	}
+	host, _ := NewManager().Routes(context.Background())
+	if len(pn.Routes) != len(host) {
+		t.Errorf("Expected the routes of this process's namespace, got %v", pn.Routes)
+	}
+	if rt, ok := defaultRoute(host); ok && pn.DefaultGateway != rt.Gateway {
+		t.Errorf("DefaultGateway = %q, want %q", pn.DefaultGateway, rt.Gateway)
+	}
+}
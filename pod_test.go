@@ -0,0 +1,30 @@
+package routing
+
+import (
+	"errors"
+	"testing"
+)
+
+const crictlPodsFixture = `{"items":[` +
+	`{"id":"4dccb216c4adb","metadata":{"name":"web-7d4b9c","uid":"a1","namespace":"default","attempt":0},"state":"SANDBOX_READY"},` +
+	`{"id":"0c3a7e5d9f21b","metadata":{"name":"web","uid":"b2","namespace":"default","attempt":0},"state":"SANDBOX_READY"}]}`
+
+func TestParseCrictlPods(t *testing.T) {
+	id, err := parseCrictlPods([]byte(crictlPodsFixture), "default", "web")
+	if err != nil || id != "0c3a7e5d9f21b" {
+		t.Errorf("parseCrictlPods = %q, %v, want the exact match", id, err)
+	}
+	if _, err := parseCrictlPods([]byte(crictlPodsFixture), "kube-system", "web"); !errors.Is(err, errPodNotFound) {
+		t.Errorf("Expected errPodNotFound, got %v", err)
+	}
+}
+
+func TestParseCrictlInspect(t *testing.T) {
+	pid, err := parseCrictlInspect([]byte(`{"status":{"id":"0c3a7e5d9f21b","state":"SANDBOX_READY"},"info":{"pid":4242,"processStatus":"running"}}`))
+	if err != nil || pid != 4242 {
+		t.Errorf("parseCrictlInspect = %d, %v, want 4242", pid, err)
+	}
+	if _, err := parseCrictlInspect([]byte(`{"status":{"id":"0c3a7e5d9f21b"}}`)); err == nil {
+		t.Error("Expected a missing PID to fail")
+	}
+}
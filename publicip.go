@@ -0,0 +1,290 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// STUN message constants from RFC 5389.
+const (
+	stunBindingRequest  = 0x0001
+	stunBindingSuccess  = 0x0101
+	stunMagicCookie     = 0x2112A442
+	stunHeaderLen       = 20
+	stunMappedAddress   = 0x0001
+	stunXORMappedAddr   = 0x0020
+	stunDefaultPort     = "3478"
+	stunRetransmitAfter = 500 * time.Millisecond
+)
+
+// publicIPTimeout bounds PublicIP when the context has no deadline.
+const publicIPTimeout = 5 * time.Second
+
+// maxEchoResponseSize limits how much of an echo service's response is read.
+const maxEchoResponseSize = 256
+
+var (
+	errUnknownEndpoint = errors.New("endpoint must be a stun:, http: or https: URL")
+	errNoMappedAddress = errors.New("STUN response has no mapped address")
+	errNotAnIPAddress  = errors.New("echo response is not an IP address")
+	errBadStunResponse = errors.New("malformed STUN response")
+)
+
+// PublicAddr is the address traffic leaving through the default route appears to come from.
+type PublicAddr struct {
+	Public    net.IP // The address the endpoint saw the request come from.
+	Local     net.IP // The local source address of the request.
+	Interface string // The interface of the default route.
+}
+
+// BehindNAT reports whether the endpoint saw another address than the local source address,
+// i.e. whether the traffic was translated on the way.
+func (a PublicAddr) BehindNAT() bool {
+	return !a.Public.Equal(a.Local)
+}
+
+// PublicIP asks endpoint which address the request came from. The endpoint is either a STUN server,
+// e.g. "stun:stun.example.net:3478", or an HTTP(S) echo service answering with the address as plain text,
+// e.g. "https://ifconfig.example.net".
+func PublicIP(endpoint string) (PublicAddr, error) {
+	return PublicIPContext(context.Background(), endpoint)
+}
+
+// PublicIPContext is like PublicIP but returns early if ctx is done.
+func PublicIPContext(ctx context.Context, endpoint string) (PublicAddr, error) {
+	return defaultManager.PublicIP(ctx, endpoint)
+}
+
+// PublicIP asks endpoint which address a request through the default route came from, as PublicIP does.
+// The request leaves from the route's preferred source address, or else the first IPv4 address of its interface,
+// and on Linux is bound to that interface, so that multi-homed and policy-routed hosts report the address of the
+// default route's uplink. Without a deadline on ctx the request gives up after five seconds.
+func (m *Manager) PublicIP(ctx context.Context, endpoint string) (PublicAddr, error) {
+	rt, err := m.DefaultRoute(ctx)
+	if err != nil {
+		return PublicAddr{}, err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, publicIPTimeout)
+		defer cancel()
+	}
+
+	addr := PublicAddr{Interface: rt.Interface}
+	switch {
+	case strings.HasPrefix(endpoint, "stun:"):
+		addr.Public, addr.Local, err = stunPublicIP(ctx, egressDialer(rt, "udp"), strings.TrimPrefix(endpoint, "stun:"))
+	case strings.HasPrefix(endpoint, "http://"), strings.HasPrefix(endpoint, "https://"):
+		addr.Public, addr.Local, err = httpPublicIP(ctx, egressDialer(rt, "tcp"), endpoint)
+	default:
+		err = errUnknownEndpoint
+	}
+	if err != nil {
+		return PublicAddr{}, fmt.Errorf("%s: %w", endpoint, err)
+	}
+
+	return addr, nil
+}
+
+// egressDialer returns a dialer for network whose connections leave through the interface of rt: bound to it
+// where the platform allows, and from its preferred source address or the interface's first IPv4 address.
+func egressDialer(rt RoutingTable, network string) *net.Dialer {
+	d := &net.Dialer{}
+	if rt.Interface == "" {
+		return d
+	}
+	d.Control = bindToDevice(rt.Interface)
+
+	src := net.ParseIP(rt.PrefSrc)
+	if src == nil {
+		src = interfaceSourceIP(rt.Interface)
+	}
+	if src != nil {
+		if strings.HasPrefix(network, "udp") {
+			d.LocalAddr = &net.UDPAddr{IP: src}
+		} else {
+			d.LocalAddr = &net.TCPAddr{IP: src}
+		}
+	}
+
+	return d
+}
+
+// interfaceSourceIP returns the first IPv4 address of the named interface, or nil if it has none.
+func interfaceSourceIP(name string) net.IP {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil
+	}
+	addrs, _ := iface.Addrs()
+	for _, a := range addrs {
+		if ipNet, ok := a.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+			return ipNet.IP.To4()
+		}
+	}
+
+	return nil
+}
+
+// httpPublicIP fetches url through d and parses the body as an IP address, returning it and the local address of
+// the connection.
+func httpPublicIP(ctx context.Context, d *net.Dialer, url string) (net.IP, net.IP, error) {
+	var local net.IP
+	transport := &http.Transport{
+		Proxy: nil, // A proxy would report its own address.
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := d.DialContext(ctx, network, addr)
+			if err == nil {
+				local = conn.LocalAddr().(*net.TCPAddr).IP
+			}
+			return conn, err
+		},
+	}
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxEchoResponseSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	ip := net.ParseIP(strings.TrimSpace(string(body)))
+	if ip == nil {
+		return nil, nil, errNotAnIPAddress
+	}
+
+	return ip, local, nil
+}
+
+// stunPublicIP sends a STUN binding request to hostport through d, retransmitting until ctx is done,
+// and returns the mapped address from the response and the local address of the socket.
+func stunPublicIP(ctx context.Context, d *net.Dialer, hostport string) (net.IP, net.IP, error) {
+	if _, _, err := net.SplitHostPort(hostport); err != nil {
+		hostport = net.JoinHostPort(hostport, stunDefaultPort)
+	}
+	conn, err := d.DialContext(ctx, "udp", hostport)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer conn.Close()
+
+	req := make([]byte, stunHeaderLen)
+	binary.BigEndian.PutUint16(req[0:2], stunBindingRequest)
+	binary.BigEndian.PutUint32(req[4:8], stunMagicCookie)
+	rand.Read(req[8:20])
+
+	buf := make([]byte, 1500)
+	for {
+		if _, err := conn.Write(req); err != nil {
+			return nil, nil, err
+		}
+		deadline := time.Now().Add(stunRetransmitAfter)
+		if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+			deadline = d
+		}
+		conn.SetReadDeadline(deadline)
+
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, nil, ctx.Err()
+				}
+				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+					break // Retransmit.
+				}
+				return nil, nil, err
+			}
+			if n < stunHeaderLen || !bytes.Equal(buf[8:20], req[8:20]) {
+				continue // Not a response to this request.
+			}
+			ip, err := parseStunResponse(buf[:n])
+			if err != nil {
+				return nil, nil, err
+			}
+			return ip, conn.LocalAddr().(*net.UDPAddr).IP, nil
+		}
+	}
+}
+
+// parseStunResponse returns the address of a STUN binding response, preferring XOR-MAPPED-ADDRESS
+// over the MAPPED-ADDRESS of servers implementing only RFC 3489.
+func parseStunResponse(b []byte) (net.IP, error) {
+	if binary.BigEndian.Uint16(b[0:2]) != stunBindingSuccess {
+		return nil, fmt.Errorf("%w: message type %#04x", errBadStunResponse, binary.BigEndian.Uint16(b[0:2]))
+	}
+	length := int(binary.BigEndian.Uint16(b[2:4]))
+	if stunHeaderLen+length > len(b) {
+		return nil, errBadStunResponse
+	}
+
+	var mapped net.IP
+	attrs := b[stunHeaderLen : stunHeaderLen+length]
+	for len(attrs) >= 4 {
+		typ := binary.BigEndian.Uint16(attrs[0:2])
+		l := int(binary.BigEndian.Uint16(attrs[2:4]))
+		if 4+l > len(attrs) {
+			return nil, errBadStunResponse
+		}
+		value := attrs[4 : 4+l]
+		switch typ {
+		case stunXORMappedAddr:
+			if ip := stunAddress(value, b[4:20]); ip != nil {
+				return ip, nil
+			}
+		case stunMappedAddress:
+			mapped = stunAddress(value, nil)
+		}
+		attrs = attrs[min(len(attrs), 4+(l+3)&^3):] // Attributes are padded to four bytes.
+	}
+	if mapped == nil {
+		return nil, errNoMappedAddress
+	}
+
+	return mapped, nil
+}
+
+// stunAddress decodes the address of a (XOR-)MAPPED-ADDRESS attribute value. For XOR-MAPPED-ADDRESS, key holds
+// the magic cookie and transaction ID the address is XORed with; it is nil for MAPPED-ADDRESS.
+func stunAddress(value, key []byte) net.IP {
+	if len(value) < 4 {
+		return nil
+	}
+	size := net.IPv4len
+	if value[1] == 0x02 {
+		size = net.IPv6len
+	}
+	if len(value) < 4+size {
+		return nil
+	}
+
+	ip := make(net.IP, size)
+	copy(ip, value[4:4+size])
+	if key != nil {
+		for i := range ip {
+			ip[i] ^= key[i]
+		}
+	}
+
+	return ip
+}
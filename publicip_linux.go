@@ -0,0 +1,26 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// bindToDevice returns a Dialer.Control function binding sockets to iface with SO_BINDTODEVICE, so their packets
+// leave through it whatever the policy rules say. Kernels before 5.7 only allow this with CAP_NET_RAW; without it
+// the socket is left unbound and its source address alone selects the route.
+func bindToDevice(iface string) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, c syscall.RawConn) error {
+		var sockErr error
+		if err := c.Control(func(fd uintptr) {
+			sockErr = syscall.SetsockoptString(int(fd), syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, iface)
+		}); err != nil {
+			return err
+		}
+		if sockErr != nil && !errors.Is(sockErr, syscall.EPERM) {
+			return fmt.Errorf("binding to %s: %w", iface, sockErr)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,21 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+)
+
+func TestPublicIPBindsInterface(t *testing.T) {
+	if !hasCapability(capNetRaw) {
+		t.Skip("binding to an interface may need CAP_NET_RAW")
+	}
+	conn := stunServer(t)
+
+	// Bound to the default route's interface, the request cannot take the loopback route to the server.
+	m := NewManager(WithSource(&memTable{routes: mustParseIPRoute(t, "default via 127.0.0.1 dev nosuch0")}))
+	if _, err := m.PublicIP(context.Background(), "stun:"+conn.LocalAddr().String()); !errors.Is(err, syscall.ENODEV) {
+		t.Errorf("Expected ENODEV for a missing interface, got %v", err)
+	}
+}
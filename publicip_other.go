@@ -0,0 +1,11 @@
+//go:build !linux
+
+package routing
+
+import "syscall"
+
+// bindToDevice returns nil, as sockets are only bound to an interface on Linux; elsewhere the source address
+// alone selects the route.
+func bindToDevice(iface string) func(network, address string, c syscall.RawConn) error {
+	return nil
+}
@@ -0,0 +1,126 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// stunResponse builds a binding response to req carrying ip in an attribute of the given type.
+func stunResponse(req []byte, typ uint16, ip net.IP) []byte {
+	value := make([]byte, 8)
+	value[1] = 0x01
+	copy(value[4:], ip.To4())
+	if typ == stunXORMappedAddr {
+		for i := range 4 {
+			value[4+i] ^= req[4+i]
+		}
+	}
+
+	resp := make([]byte, stunHeaderLen, stunHeaderLen+12)
+	copy(resp, req[:stunHeaderLen])
+	binary.BigEndian.PutUint16(resp[0:2], stunBindingSuccess)
+	binary.BigEndian.PutUint16(resp[2:4], 12)
+	resp = binary.BigEndian.AppendUint16(resp, typ)
+	resp = binary.BigEndian.AppendUint16(resp, uint16(len(value)))
+
+	return append(resp, value...)
+}
+
+// loopbackManager returns a manager whose default route leaves through the loopback interface, from src if set.
+func loopbackManager(t *testing.T, src string) *Manager {
+	t.Helper()
+	route := "default via 127.0.0.1 dev lo"
+	if src != "" {
+		route += " src " + src
+	}
+
+	return NewManager(WithSource(&memTable{routes: mustParseIPRoute(t, route)}))
+}
+
+// stunServer answers the first binding request on a local UDP socket with the address the request came from.
+func stunServer(t *testing.T) net.PacketConn {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, 1500)
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil || n < stunHeaderLen {
+			return
+		}
+		conn.WriteTo(stunResponse(buf[:n], stunXORMappedAddr, from.(*net.UDPAddr).IP), from)
+	}()
+
+	return conn
+}
+
+func TestPublicIPHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "203.0.113.7")
+	}))
+	defer srv.Close()
+
+	addr, err := loopbackManager(t, "").PublicIP(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("PublicIP failed %s", err.Error())
+	}
+	if addr.Public.String() != "203.0.113.7" || !addr.Local.IsLoopback() || addr.Interface != "lo" || !addr.BehindNAT() {
+		t.Errorf("Unexpected address %+v", addr)
+	}
+}
+
+func TestPublicIPSTUN(t *testing.T) {
+	conn := stunServer(t)
+
+	addr, err := loopbackManager(t, "").PublicIP(context.Background(), "stun:"+conn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("PublicIP failed %s", err.Error())
+	}
+	if addr.Public.String() != "127.0.0.1" || addr.Local.String() != "127.0.0.1" || addr.BehindNAT() {
+		t.Errorf("Unexpected address %+v", addr)
+	}
+}
+
+func TestPublicIPPrefSrc(t *testing.T) {
+	conn := stunServer(t)
+
+	// The request must leave from the default route's preferred source, not the address the kernel would pick.
+	addr, err := loopbackManager(t, "127.0.0.2").PublicIP(context.Background(), "stun:"+conn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("PublicIP failed %s", err.Error())
+	}
+	if addr.Public.String() != "127.0.0.2" || addr.Local.String() != "127.0.0.2" {
+		t.Errorf("Unexpected address %+v", addr)
+	}
+}
+
+func TestParseStunResponseMappedAddress(t *testing.T) {
+	req := make([]byte, stunHeaderLen)
+	binary.BigEndian.PutUint32(req[4:8], stunMagicCookie)
+	ip, err := parseStunResponse(stunResponse(req, stunMappedAddress, net.ParseIP("192.0.2.77")))
+	if err != nil || ip.String() != "192.0.2.77" {
+		t.Errorf("parseStunResponse = %s, %v", ip, err)
+	}
+
+	resp := stunResponse(req, stunMappedAddress, net.ParseIP("192.0.2.77"))
+	binary.BigEndian.PutUint16(resp[0:2], 0x0111) // Binding error response.
+	if _, err := parseStunResponse(resp); !errors.Is(err, errBadStunResponse) {
+		t.Errorf("Expected errBadStunResponse, got %v", err)
+	}
+}
+
+func TestPublicIPUnknownEndpoint(t *testing.T) {
+	_, err := NewManager(WithFS(procFS)).PublicIP(context.Background(), "ftp://example.net")
+	if !errors.Is(err, errUnknownEndpoint) {
+		t.Errorf("Expected errUnknownEndpoint, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package routing
+
+import (
+	"context"
+	"net"
+)
+
+// RouteQuery is a chainable query over the routing table.
+// Conditions are combined with AND; results are ordered most specific prefix first, then by lowest metric.
+type RouteQuery struct {
+	source     RouteSource
+	conditions []func(RoutingTable) bool
+}
+
+// Query starts a new query over the routes read from /proc/net/route.
+func Query() *RouteQuery {
+	return &RouteQuery{source: ProcSource{}}
+}
+
+// From makes the query read routes from source instead of /proc/net/route.
+func (q *RouteQuery) From(source RouteSource) *RouteQuery {
+	q.source = source
+	return q
+}
+
+// Where keeps only the routes for which keep returns true.
+func (q *RouteQuery) Where(keep func(RoutingTable) bool) *RouteQuery {
+	q.conditions = append(q.conditions, keep)
+	return q
+}
+
+// OnInterface keeps only the routes using the network interface iface.
+func (q *RouteQuery) OnInterface(iface string) *RouteQuery {
+	return q.Where(func(rt RoutingTable) bool { return rt.Interface == iface })
+}
+
+// WithFlags keeps only the routes carrying all of the given flag bits, e.g. FlagUp|FlagGateway.
+func (q *RouteQuery) WithFlags(bits int16) *RouteQuery {
+	return q.Where(func(rt RoutingTable) bool { return flagBits(rt.Flags)&bits == bits })
+}
+
+// MetricBelow keeps only the routes with a metric lower than metric.
+func (q *RouteQuery) MetricBelow(metric int) *RouteQuery {
+	return q.Where(func(rt RoutingTable) bool { return int(rt.Metric) < metric })
+}
+
+// Within keeps only the routes whose destination lies within prefix, like RoutesMatching.
+func (q *RouteQuery) Within(prefix net.IPNet) *RouteQuery {
+	prefixLen, _ := prefix.Mask.Size()
+	return q.Where(func(rt RoutingTable) bool {
+		dst, _, ones, err := decodeDestination(rt)
+		return err == nil && ones >= prefixLen && prefix.Contains(dst)
+	})
+}
+
+// ExcludeCloned drops kernel-generated routes such as cached entries, like ExcludeCloned.
+func (q *RouteQuery) ExcludeCloned() *RouteQuery {
+	return q.Where(func(rt RoutingTable) bool { return !rt.Cloned() })
+}
+
+// All runs the query and returns every matching route.
+func (q *RouteQuery) All() ([]RoutingTable, error) {
+	return q.AllContext(context.Background())
+}
+
+// AllContext is like All but passes ctx to the route source.
+func (q *RouteQuery) AllContext(ctx context.Context) ([]RoutingTable, error) {
+	routes, err := q.source.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := FilterRoutes(routes, func(rt RoutingTable) bool {
+		for _, keep := range q.conditions {
+			if !keep(rt) {
+				return false
+			}
+		}
+		return true
+	})
+
+	SortByMetric(out)
+	SortByPrefixLength(out)
+
+	return out, nil
+}
+
+// First runs the query and returns the best matching route, or false if none matched.
+func (q *RouteQuery) First() (RoutingTable, bool, error) {
+	routes, err := q.All()
+	if err != nil || len(routes) == 0 {
+		return RoutingTable{}, false, err
+	}
+
+	return routes[0], true, nil
+}
@@ -0,0 +1,37 @@
+package routing
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader(`default via 192.168.1.1 dev eth0 metric 100
+default via 192.168.1.2 dev eth0 metric 50
+10.0.0.0/8 via 192.168.1.3 dev eth0 metric 120
+192.168.1.0/24 dev eth0 scope link
+10.8.0.0/24 via 10.8.0.1 dev tun0
+`))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) { return routes, nil })
+
+	got, err := Query().From(src).OnInterface("eth0").WithFlags(FlagUp | FlagGateway).MetricBelow(110).All()
+	if err != nil {
+		t.Fatalf("All failed %s", err.Error())
+	}
+	if len(got) != 2 || got[0].Gateway != "192.168.1.2" || got[1].Gateway != "192.168.1.1" {
+		t.Errorf("Unexpected query result %v", got)
+	}
+
+	got, _ = Query().From(src).WithFlags(FlagGateway).All()
+	if len(got) != 4 || got[0].Interface != "tun0" || got[1].Gateway != "192.168.1.3" {
+		t.Errorf("Expected most specific routes first, got %v", got)
+	}
+
+	if _, ok, _ := Query().From(src).OnInterface("wg0").First(); ok {
+		t.Errorf("Expected no route on wg0")
+	}
+}
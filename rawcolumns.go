@@ -0,0 +1,119 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// RawColumns are the column values of a route exactly as its source printed them, keyed by column name.
+// The values stay in the row they were read from and the names are shared by every row of a file, so keeping
+// them costs no allocation per route. The zero value has no columns.
+type RawColumns struct {
+	names []string // Column names, in the order of the values.
+	row   string   // Tab-separated values.
+}
+
+// rawColumns returns the RawColumns holding values under names, in that order.
+// The values must not contain tabs, as is the case for fields split at white space.
+func rawColumns(names, values []string) RawColumns {
+	return RawColumns{names: names, row: strings.Join(values, "\t")}
+}
+
+// rawColumnsFromMap returns the RawColumns holding the values of m, ordered by column name.
+func rawColumnsFromMap(m map[string]string) RawColumns {
+	names := slices.Sorted(maps.Keys(m))
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = m[name]
+	}
+
+	return rawColumns(names, values)
+}
+
+// All returns an iterator over the column names and values, in the order the source printed them.
+// Values beyond the names of the header, as in a row with extra columns, are left out.
+func (r RawColumns) All() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		if len(r.names) == 0 {
+			return
+		}
+		rest, more := r.row, true
+		for _, name := range r.names {
+			if !more {
+				return
+			}
+			var v string
+			v, rest, more = strings.Cut(rest, "\t")
+			if !yield(name, v) {
+				return
+			}
+		}
+	}
+}
+
+// Get returns the value of column, or "" if the route has no such column.
+func (r RawColumns) Get(column string) string {
+	for name, v := range r.All() {
+		if name == column {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// Len returns the number of columns with a value.
+func (r RawColumns) Len() int {
+	n := 0
+	for range r.All() {
+		n++
+	}
+
+	return n
+}
+
+// Map returns the columns as a map keyed by column name, or nil if there are none.
+func (r RawColumns) Map() map[string]string {
+	var m map[string]string
+	for name, v := range r.All() {
+		if m == nil {
+			m = make(map[string]string, len(r.names))
+		}
+		m[name] = v
+	}
+
+	return m
+}
+
+// MarshalBinary encodes the names and the values, so that routes keep their columns in gob snapshots.
+func (r RawColumns) MarshalBinary() ([]byte, error) {
+	if len(r.names) == 0 {
+		return nil, nil
+	}
+
+	return []byte(strings.Join(r.names, "\t") + "\n" + r.row), nil
+}
+
+// UnmarshalBinary decodes columns encoded by MarshalBinary.
+func (r *RawColumns) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		*r = RawColumns{}
+		return nil
+	}
+	names, row, ok := strings.Cut(string(data), "\n")
+	if !ok {
+		return errors.New("raw columns without a line of values")
+	}
+	*r = RawColumns{names: strings.Split(names, "\t"), row: row}
+
+	return nil
+}
+
+// String formats the columns like a map.
+func (r RawColumns) String() string {
+	return fmt.Sprint(r.Map())
+}
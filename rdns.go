@@ -0,0 +1,130 @@
+package routing
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults of GatewayNames.
+const (
+	defaultGatewayNameTimeout = 2 * time.Second
+	defaultGatewayNameTTL     = 5 * time.Minute
+)
+
+// GatewayNames resolves gateway addresses to host names through reverse DNS (PTR) lookups, caching the answers.
+// The zero value is ready to use and it is safe for concurrent use.
+type GatewayNames struct {
+	Resolver *net.Resolver // Resolver for the lookups; nil uses net.DefaultResolver.
+	Timeout  time.Duration // Limit for each lookup; zero means two seconds.
+	TTL      time.Duration // How long answers, including failed lookups, are cached; zero means five minutes.
+
+	lookup func(ctx context.Context, addr string) ([]string, error) // Replaces the resolver in tests.
+	now    func() time.Time                                         // Clock used for expiry, replaceable in tests.
+
+	mu    sync.Mutex
+	cache map[string]cachedName
+}
+
+// cachedName is a cached reverse lookup.
+type cachedName struct {
+	name    string // Empty if the address has no PTR record or the lookup failed.
+	expires time.Time
+}
+
+// Lookup returns the host name of ip without the trailing dot, or "" if it has none or the lookup fails or times out.
+func (g *GatewayNames) Lookup(ctx context.Context, ip net.IP) string {
+	key := ip.String()
+	now := g.clock()
+	g.mu.Lock()
+	if c, ok := g.cache[key]; ok && now.Before(c.expires) {
+		g.mu.Unlock()
+		return c.name
+	}
+	g.mu.Unlock()
+
+	timeout := g.Timeout
+	if timeout <= 0 {
+		timeout = defaultGatewayNameTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	lookup := g.lookup
+	if lookup == nil {
+		r := g.Resolver
+		if r == nil {
+			r = net.DefaultResolver
+		}
+		lookup = r.LookupAddr
+	}
+	var name string
+	names, err := lookup(ctx, key)
+	if err == nil && len(names) > 0 {
+		name = strings.TrimSuffix(names[0], ".")
+	}
+	if err != nil && ctx.Err() != nil {
+		return "" // Do not cache lookups cut short by the caller or the timeout.
+	}
+
+	ttl := g.TTL
+	if ttl <= 0 {
+		ttl = defaultGatewayNameTTL
+	}
+	g.mu.Lock()
+	if g.cache == nil {
+		g.cache = make(map[string]cachedName)
+	}
+	g.cache[key] = cachedName{name: name, expires: g.clock().Add(ttl)}
+	g.mu.Unlock()
+
+	return name
+}
+
+// Label returns a copy of routes with GatewayName set on every route with a gateway.
+// Distinct gateways are looked up concurrently, each at most once.
+func (g *GatewayNames) Label(ctx context.Context, routes []RoutingTable) []RoutingTable {
+	var gateways []string
+	seen := make(map[string]bool)
+	for _, rt := range routes {
+		if gw := net.ParseIP(rt.Gateway); gw != nil && !gw.IsUnspecified() && !seen[rt.Gateway] {
+			seen[rt.Gateway] = true
+			gateways = append(gateways, rt.Gateway)
+		}
+	}
+
+	resolved := make([]string, len(gateways))
+	var wg sync.WaitGroup
+	for i, gw := range gateways {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resolved[i] = g.Lookup(ctx, net.ParseIP(gw))
+		}()
+	}
+	wg.Wait()
+
+	names := make(map[string]string, len(gateways))
+	for i, gw := range gateways {
+		names[gw] = resolved[i]
+	}
+
+	labelled := make([]RoutingTable, len(routes))
+	for i, rt := range routes {
+		rt.GatewayName = names[rt.Gateway]
+		labelled[i] = rt
+	}
+
+	return labelled
+}
+
+// clock returns the current time from the configured clock.
+func (g *GatewayNames) clock() time.Time {
+	if g.now == nil {
+		return time.Now()
+	}
+
+	return g.now()
+}
@@ -0,0 +1,73 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGatewayNamesLabel(t *testing.T) {
+	var mu sync.Mutex
+	lookups := 0
+	now := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	g := &GatewayNames{
+		lookup: func(ctx context.Context, addr string) ([]string, error) {
+			mu.Lock()
+			lookups++
+			mu.Unlock()
+			if addr == "192.0.2.1" {
+				return []string{"router.example.net."}, nil
+			}
+			return nil, errors.New("no such host")
+		},
+		now: func() time.Time { return now },
+	}
+
+	routes, err := ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev eth0\n198.51.100.0/24 via 192.0.2.1 dev eth0\n203.0.113.0/24 via 192.0.2.2 dev eth0\n192.0.2.0/24 dev eth0"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	labelled := g.Label(context.Background(), routes)
+	want := []string{"router.example.net", "router.example.net", "", ""}
+	for i, rt := range labelled {
+		if rt.GatewayName != want[i] {
+			t.Errorf("Route %d: expected gateway name %q, got %q", i, want[i], rt.GatewayName)
+		}
+	}
+	if routes[0].GatewayName != "" {
+		t.Error("Label modified its input")
+	}
+	if lookups != 2 {
+		t.Errorf("Expected one lookup per gateway, got %d", lookups)
+	}
+
+	g.Label(context.Background(), routes)
+	if lookups != 2 {
+		t.Errorf("Expected cached answers to be reused, got %d lookups", lookups)
+	}
+	now = now.Add(defaultGatewayNameTTL)
+	g.Label(context.Background(), routes)
+	if lookups != 4 {
+		t.Errorf("Expected expired answers to be looked up again, got %d lookups", lookups)
+	}
+}
+
+func TestGatewayNamesTimeout(t *testing.T) {
+	g := &GatewayNames{
+		Timeout: time.Millisecond,
+		lookup: func(ctx context.Context, addr string) ([]string, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	if name := g.Lookup(context.Background(), []byte{192, 0, 2, 1}); name != "" {
+		t.Errorf("Expected no name after a timeout, got %q", name)
+	}
+	if len(g.cache) != 0 {
+		t.Errorf("Expected timed out lookups not to be cached, got %v", g.cache)
+	}
+}
@@ -0,0 +1,145 @@
+package routing
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// Locations of the resolver configuration. systemd-resolved points /etc/resolv.conf at its local stub
+// and lists the upstream servers it forwards to in resolvedUpstreamPath.
+const (
+	resolvConfPath       = "/etc/resolv.conf"
+	resolvedUpstreamPath = "/run/systemd/resolve/resolv.conf"
+)
+
+// resolvedStub is the address of the systemd-resolved stub listener.
+var resolvedStub = net.IPv4(127, 0, 0, 53)
+
+// ResolverConfig is the DNS configuration of the host, as found in resolv.conf.
+type ResolverConfig struct {
+	Nameservers []net.IP // DNS servers in the order they are queried.
+	Search      []string // Domains appended to names that are not fully qualified.
+	Options     []string // Resolver options such as "edns0" or "timeout:2".
+	File        string   // The file the configuration was read from.
+}
+
+// ParseResolvConf parses resolver configuration in the format of resolv.conf read from r.
+// Comments, unknown keywords and nameservers that are not IP addresses are ignored;
+// as in glibc, the last "search" or "domain" line wins.
+func ParseResolvConf(r io.Reader) (ResolverConfig, error) {
+	var conf ResolverConfig
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			host, _, _ := strings.Cut(fields[1], "%") // Drop the zone of link-local IPv6 servers.
+			if ip := net.ParseIP(host); ip != nil {
+				conf.Nameservers = append(conf.Nameservers, ip)
+			}
+		case "search", "domain":
+			conf.Search = fields[1:]
+		case "options":
+			conf.Options = append(conf.Options, fields[1:]...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ResolverConfig{}, err
+	}
+
+	return conf, nil
+}
+
+// Resolver returns the DNS configuration of the host from /etc/resolv.conf.
+func Resolver() (ResolverConfig, error) {
+	return ResolverContext(context.Background())
+}
+
+// ResolverContext is like Resolver but returns early if ctx is done.
+func ResolverContext(ctx context.Context) (ResolverConfig, error) {
+	return defaultManager.Resolver(ctx)
+}
+
+// Resolver reads /etc/resolv.conf from the manager's filesystem, the host's unless WithFS was given.
+// When it only lists the systemd-resolved stub, the upstream servers systemd-resolved uses are returned instead.
+func (m *Manager) Resolver(ctx context.Context) (ResolverConfig, error) {
+	if err := ctx.Err(); err != nil {
+		return ResolverConfig{}, err
+	}
+
+	conf, err := m.readResolvConf(resolvConfPath)
+	if err != nil {
+		return ResolverConfig{}, err
+	}
+	if len(conf.Nameservers) == 1 && conf.Nameservers[0].Equal(resolvedStub) {
+		upstream, err := m.readResolvConf(resolvedUpstreamPath)
+		if err != nil {
+			m.log().Debug("reading systemd-resolved upstream servers failed", "file", resolvedUpstreamPath, "err", err)
+			return conf, nil
+		}
+		return upstream, nil
+	}
+
+	return conf, nil
+}
+
+// readResolvConf opens and parses the resolv.conf file at path.
+func (m *Manager) readResolvConf(path string) (ResolverConfig, error) {
+	f, err := openProc(m.fsys, path)
+	if err != nil {
+		return ResolverConfig{}, err
+	}
+	defer f.Close()
+
+	conf, err := ParseResolvConf(f)
+	if err != nil {
+		return ResolverConfig{}, fmt.Errorf("%s: %w", path, err)
+	}
+	conf.File = path
+
+	return conf, nil
+}
+
+// HostNetwork is the network configuration a connectivity check usually starts from.
+type HostNetwork struct {
+	Gateway   string   // The default gateway.
+	Interface string   // The interface of the default route, which traffic leaves through.
+	DNS       []net.IP // The DNS servers; empty if the resolver configuration could not be read.
+}
+
+// NetworkInfo returns the default gateway, the egress interface and the DNS servers of the host.
+func NetworkInfo() (HostNetwork, error) {
+	return NetworkInfoContext(context.Background())
+}
+
+// NetworkInfoContext is like NetworkInfo but returns early if ctx is done.
+func NetworkInfoContext(ctx context.Context) (HostNetwork, error) {
+	return defaultManager.NetworkInfo(ctx)
+}
+
+// NetworkInfo returns the default gateway and interface from the manager's routes and its resolver configuration.
+// It fails if there is no default route; a missing resolver configuration only leaves DNS empty.
+func (m *Manager) NetworkInfo(ctx context.Context) (HostNetwork, error) {
+	rt, err := m.DefaultRoute(ctx)
+	if err != nil {
+		return HostNetwork{}, err
+	}
+	info := HostNetwork{Gateway: rt.Gateway, Interface: rt.Interface}
+
+	conf, err := m.Resolver(ctx)
+	if err != nil {
+		m.log().Warn("reading resolver configuration failed", "err", err)
+		return info, nil
+	}
+	info.DNS = conf.Nameservers
+
+	return info, nil
+}
@@ -0,0 +1,74 @@
+package routing
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseResolvConf(t *testing.T) {
+	conf, err := ParseResolvConf(strings.NewReader(`# Generated by NetworkManager
+domain example.org
+search corp.example.com example.com
+nameserver 192.0.2.53
+nameserver fe80::1%eth0
+; nameserver 192.0.2.99
+nameserver not-an-address
+options edns0 timeout:2
+`))
+	if err != nil {
+		t.Fatalf("ParseResolvConf failed %s", err.Error())
+	}
+
+	if len(conf.Nameservers) != 2 || conf.Nameservers[0].String() != "192.0.2.53" || conf.Nameservers[1].String() != "fe80::1" {
+		t.Errorf("Unexpected nameservers %v", conf.Nameservers)
+	}
+	if !slices.Equal(conf.Search, []string{"corp.example.com", "example.com"}) {
+		t.Errorf("Expected the last search line to win, got %v", conf.Search)
+	}
+	if !slices.Equal(conf.Options, []string{"edns0", "timeout:2"}) {
+		t.Errorf("Unexpected options %v", conf.Options)
+	}
+}
+
+func TestResolverSystemdStub(t *testing.T) {
+	fsys := fstest.MapFS{
+		"etc/resolv.conf":                 {Data: []byte("nameserver 127.0.0.53\noptions edns0 trust-ad\n")},
+		"run/systemd/resolve/resolv.conf": {Data: []byte("nameserver 192.0.2.53\nnameserver 198.51.100.53\n")},
+	}
+
+	conf, err := NewManager(WithFS(fsys)).Resolver(context.Background())
+	if err != nil {
+		t.Fatalf("Resolver failed %s", err.Error())
+	}
+	if len(conf.Nameservers) != 2 || conf.File != resolvedUpstreamPath {
+		t.Errorf("Expected the upstream servers of systemd-resolved, got %+v", conf)
+	}
+
+	delete(fsys, "run/systemd/resolve/resolv.conf")
+	conf, err = NewManager(WithFS(fsys)).Resolver(context.Background())
+	if err != nil || len(conf.Nameservers) != 1 || conf.File != resolvConfPath {
+		t.Errorf("Expected the stub configuration, got %+v, %v", conf, err)
+	}
+}
+
+func TestNetworkInfo(t *testing.T) {
+	fsys := maps.Clone(procFS)
+	fsys["etc/resolv.conf"] = &fstest.MapFile{Data: []byte("nameserver 192.0.2.53\n")}
+
+	info, err := NewManager(WithFS(fsys)).NetworkInfo(context.Background())
+	if err != nil {
+		t.Fatalf("NetworkInfo failed %s", err.Error())
+	}
+	if info.Gateway != "192.0.2.1" || info.Interface != "eth0" || len(info.DNS) != 1 || info.DNS[0].String() != "192.0.2.53" {
+		t.Errorf("Unexpected network info %+v", info)
+	}
+
+	info, err = NewManager(WithFS(procFS)).NetworkInfo(context.Background())
+	if err != nil || info.Gateway != "192.0.2.1" || info.DNS != nil {
+		t.Errorf("Expected network info without DNS servers, got %+v, %v", info, err)
+	}
+}
@@ -0,0 +1,93 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"syscall"
+	"time"
+)
+
+// defaultRetryBackoff is the first delay of a RetryPolicy without a Backoff.
+const defaultRetryBackoff = 50 * time.Millisecond
+
+// errDumpInterrupted is returned when the kernel reports that a netlink dump was inconsistent
+// because the table changed while it was being dumped.
+var errDumpInterrupted = errors.New("netlink dump interrupted by a concurrent change")
+
+// RetryPolicy describes how often and how fast a transient read failure is retried.
+type RetryPolicy struct {
+	Attempts   int           // Reads in total, including the first; values below two disable retrying.
+	Backoff    time.Duration // Delay before the first retry, doubled for each further one; zero means 50ms.
+	MaxBackoff time.Duration // Upper limit of the delay; zero means no limit.
+}
+
+// delay returns how long to wait before the given retry, counting from one.
+func (p RetryPolicy) delay(retry int) time.Duration {
+	d := p.Backoff
+	if d <= 0 {
+		d = defaultRetryBackoff
+	}
+	for range retry - 1 {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 {
+		d = min(d, p.MaxBackoff)
+	}
+
+	return d
+}
+
+// IsTransient reports whether err is a read failure worth retrying: an interrupted system call, a temporarily
+// unavailable or exhausted resource, or a netlink dump the kernel flagged as interrupted by a concurrent change.
+// Missing files, permission errors and parse errors are not transient.
+func IsTransient(err error) bool {
+	return errors.Is(err, syscall.EINTR) || errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.ENOBUFS) ||
+		errors.Is(err, syscall.EBUSY) || errors.Is(err, errDumpInterrupted)
+}
+
+// RetrySource wraps a RouteSource and retries reads that fail with a transient error, such as during boot
+// when the network is still being configured. It is safe for concurrent use if the wrapped source is.
+type RetrySource struct {
+	source RouteSource
+	policy RetryPolicy
+	logger *slog.Logger                                     // Receives a debug message for every retry; set by WithRetry.
+	sleep  func(ctx context.Context, d time.Duration) error // Waits between attempts, replaceable in tests.
+}
+
+// NewRetrySource returns a RetrySource reading from source and retrying as policy describes.
+func NewRetrySource(source RouteSource, policy RetryPolicy) *RetrySource {
+	return &RetrySource{source: source, policy: policy, logger: discardLogger, sleep: sleepContext}
+}
+
+// Routes reads the routing table, retrying transient failures. It returns the last error once the attempts
+// are used up, or ctx.Err() if ctx is done while waiting.
+func (s *RetrySource) Routes(ctx context.Context) ([]RoutingTable, error) {
+	for attempt := 1; ; attempt++ {
+		routes, err := s.source.Routes(ctx)
+		if err == nil || attempt >= s.policy.Attempts || !IsTransient(err) || ctx.Err() != nil {
+			return routes, err
+		}
+
+		d := s.policy.delay(attempt)
+		s.logger.Debug("retrying routing table read", "attempt", attempt, "delay", d, "err", err)
+		if err := s.sleep(ctx, d); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleepContext waits for d or until ctx is done, returning ctx.Err() in that case.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
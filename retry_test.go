@@ -0,0 +1,103 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetrySource(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  []error
+		attempts  int
+		wantErr   error
+		wantReads int
+	}{
+		{"transient", []error{syscall.EINTR, fmt.Errorf("dump: %w", syscall.ENOBUFS)}, 3, nil, 3},
+		{"interrupted dump", []error{errDumpInterrupted}, 3, nil, 2},
+		{"attempts used up", []error{syscall.EAGAIN, syscall.EAGAIN, syscall.EAGAIN}, 3, syscall.EAGAIN, 3},
+		{"permanent", []error{fmt.Errorf("%w: %w", ErrProcUnavailable, fs.ErrNotExist)}, 3, fs.ErrNotExist, 1},
+		{"disabled", []error{syscall.EINTR}, 1, syscall.EINTR, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reads := 0
+			src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+				reads++
+				if reads <= len(test.failures) {
+					return nil, test.failures[reads-1]
+				}
+				return ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev eth0\n"))
+			})
+			var delays []time.Duration
+			r := NewRetrySource(src, RetryPolicy{Attempts: test.attempts, Backoff: time.Millisecond, MaxBackoff: 3 * time.Millisecond})
+			r.sleep = func(ctx context.Context, d time.Duration) error {
+				delays = append(delays, d)
+				return nil
+			}
+
+			routes, err := r.Routes(context.Background())
+			if !errors.Is(err, test.wantErr) || (test.wantErr == nil && err != nil) {
+				t.Errorf("Routes error = %v, want %v", err, test.wantErr)
+			}
+			if test.wantErr == nil && len(routes) != 1 {
+				t.Errorf("Expected the route of the successful read, got %v", routes)
+			}
+			if reads != test.wantReads {
+				t.Errorf("Expected %d reads, got %d", test.wantReads, reads)
+			}
+			if len(delays) != test.wantReads-1 {
+				t.Errorf("Expected a delay before every retry, got %v", delays)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{Backoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	expected := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond}
+	for i, want := range expected {
+		if got := p.delay(i + 1); got != want {
+			t.Errorf("delay(%d) = %s, want %s", i+1, got, want)
+		}
+	}
+	if got := (RetryPolicy{}).delay(1); got != defaultRetryBackoff {
+		t.Errorf("Expected the default backoff, got %s", got)
+	}
+}
+
+func TestRetrySourceCancelled(t *testing.T) {
+	reads := 0
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		reads++
+		return nil, syscall.EINTR
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewRetrySource(src, RetryPolicy{Attempts: 5, Backoff: time.Hour}).Routes(ctx); !errors.Is(err, syscall.EINTR) || reads != 1 {
+		t.Errorf("Expected the first failure once ctx is done, got %v after %d reads", err, reads)
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	reads := 0
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		if reads++; reads == 1 {
+			return nil, syscall.EINTR
+		}
+		return ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev eth0\n"))
+	})
+
+	m := NewManager(WithSource(src), WithRetry(RetryPolicy{Attempts: 2, Backoff: time.Microsecond}))
+	if gw, err := m.DefaultGateway(context.Background()); err != nil || gw != "192.0.2.1" {
+		t.Errorf("DefaultGateway = %s, %v after a transient failure", gw, err)
+	}
+}
@@ -0,0 +1,300 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/netip"
+	"time"
+)
+
+// Route is a route of either address family, with netip addresses, so that one code path handles IPv4 and IPv6
+// routes. Sources implementing DualStackSource, such as ProcSource, NetlinkSource and Manager, report the routes
+// of both families as Routes. RouteSource keeps reporting the family-specific RoutingTable, and IPv6Routes the
+// IPv6Route, for compatibility; RoutingTable.Route and IPv6Route.Route convert them and UnifyRoutes converts the
+// output of any source.
+type Route struct {
+	Family      Family               // FamilyIPv4 or FamilyIPv6, the family of every address of the route.
+	Destination netip.Prefix         // The destination prefix, masked.
+	Source      netip.Prefix         // Source prefix of a source-specific IPv6 route; the zero Prefix otherwise.
+	Gateway     netip.Addr           // The next hop, zoned with Interface if link-local; the zero Addr if directly connected.
+	Interface   string               // The network interface associated with the route.
+	PrefSrc     netip.Addr           // Preferred source address; the zero Addr when unknown.
+	Metric      uint32               // Metric for the route, used in route selection.
+	Table       int                  // ID of the kernel routing table holding the route; TableUnspec when unknown.
+	Type        RouteType            // Kind of route, e.g. RouteTypeBlackhole; the zero value means unicast.
+	Proto       string               // Protocol that installed the route (e.g. "kernel", "ra"), when known.
+	Scope       string               // Scope of the destination (e.g. "link", "host"), when known.
+	Flags       map[string]RouteFlag // Flags of the route, from RouteFlags or IPv6RouteFlags depending on Family.
+	Nexthops    []RouteNexthop       // Paths of a multipath (ECMP) route; nil for single-path routes.
+	NexthopID   uint32               // ID of the NexthopObject the route uses; zero if it carries its own paths.
+	SRv6        *SRv6Encap           // Segment routing encapsulation or behaviour; nil for other routes.
+	OnLink      bool                 // Gateway is reachable through Interface even though it is outside its subnets.
+	Metrics     RouteMetrics         // Per-route metrics such as the path MTU; IPv4 routes only.
+	Expires     time.Duration        // Remaining lifetime of an expiring IPv6 route; zero if it does not expire.
+}
+
+// RouteNexthop is one path of a multipath Route.
+type RouteNexthop struct {
+	Gateway   netip.Addr // The gateway of the path, zoned with Interface if link-local; the zero Addr if directly connected.
+	Interface string     // The network interface of the path.
+	Weight    int        // Relative weight of the path when balancing traffic.
+	OnLink    bool       // Gateway is reachable through Interface even though it is outside its subnets.
+}
+
+// IsDefault reports whether the route is a default route, 0.0.0.0/0 or ::/0.
+func (r Route) IsDefault() bool {
+	return r.Destination.IsValid() && r.Destination.Bits() == 0
+}
+
+// String formats the route like `ip route` or `ip -6 route`, depending on its family.
+func (r Route) String() string {
+	if r.Family == FamilyIPv6 {
+		v6, err := r.IPv6Route()
+		if err != nil {
+			return fmt.Sprintf("invalid route to %s: %v", r.Destination, err)
+		}
+		return v6.String()
+	}
+	rt, err := r.RoutingTable()
+	if err != nil {
+		return fmt.Sprintf("invalid route to %s: %v", r.Destination, err)
+	}
+
+	return rt.String()
+}
+
+// Route converts rt to a Route. It fails if the destination or an address of rt is malformed.
+func (rt RoutingTable) Route() (Route, error) {
+	dst, err := rt.Prefix()
+	if err != nil {
+		return Route{}, err
+	}
+	family := FamilyIPv4
+	if dst.Addr().Is6() {
+		family = FamilyIPv6
+	}
+
+	r := Route{
+		Family:      family,
+		Destination: dst,
+		Interface:   rt.Interface,
+		Metric:      rt.Metric,
+		Table:       rt.Table,
+		Type:        rt.Type,
+		Proto:       rt.Proto,
+		Scope:       rt.Scope,
+		Flags:       rt.Flags,
+		NexthopID:   rt.NexthopID,
+		SRv6:        rt.SRv6,
+		OnLink:      rt.OnLink,
+		Metrics:     rt.Metrics,
+	}
+	if r.Gateway, err = routeAddr("Gateway", rt.Gateway); err != nil {
+		return Route{}, err
+	}
+	r.Gateway = linkLocalZone(r.Gateway, rt.Interface)
+	if r.PrefSrc, err = routeAddr("PrefSrc", rt.PrefSrc); err != nil {
+		return Route{}, err
+	}
+	for _, nh := range rt.Nexthops {
+		gw, err := routeAddr("Gateway", nh.Gateway)
+		if err != nil {
+			return Route{}, err
+		}
+		r.Nexthops = append(r.Nexthops, RouteNexthop{Gateway: linkLocalZone(gw, nh.Interface), Interface: nh.Interface, Weight: nh.Weight, OnLink: nh.OnLink})
+	}
+
+	return r, nil
+}
+
+// Route converts r to a Route. Routes from /proc/net/ipv6_route do not tell their table, which is left unspecified.
+func (r IPv6Route) Route() Route {
+	route := Route{
+		Family:      FamilyIPv6,
+		Destination: r.Prefix(),
+		Gateway:     r.GatewayAddr(),
+		Interface:   r.Interface,
+		Metric:      r.Metric,
+		Type:        r.Type,
+		Proto:       r.Proto,
+		Flags:       r.Flags,
+		NexthopID:   r.NexthopID,
+		SRv6:        r.SRv6,
+		Expires:     r.Expires,
+		Source:      r.SourcePrefix(),
+	}
+
+	return route
+}
+
+// RoutingTable converts an IPv4 route back to a RoutingTable, e.g. to pass it to AddRoute.
+// Flags are derived from the route when it has none.
+func (r Route) RoutingTable() (RoutingTable, error) {
+	if r.Family != FamilyIPv4 || !r.Destination.Addr().Is4() {
+		return RoutingTable{}, fmt.Errorf("route to %s: %w", r.Destination, errNotIPv4)
+	}
+	dst := r.Destination.Masked()
+
+	var gw net.IP
+	if r.Gateway.IsValid() {
+		gw = net.IP(r.Gateway.AsSlice())
+	}
+	rt := unicastRoute(&net.IPNet{IP: dst.Addr().AsSlice(), Mask: net.CIDRMask(dst.Bits(), 32)}, gw, r.Interface, r.Table)
+	if r.Flags != nil {
+		rt.Flags = r.Flags
+	}
+	rt.Metric = r.Metric
+	rt.Type = r.Type
+	rt.Proto = r.Proto
+	rt.Scope = r.Scope
+	rt.NexthopID = r.NexthopID
+	rt.SRv6 = r.SRv6
+	rt.OnLink = r.OnLink
+	rt.Metrics = r.Metrics
+	if r.PrefSrc.IsValid() {
+		rt.PrefSrc = r.PrefSrc.String()
+	}
+	for _, nh := range r.Nexthops {
+		gw := net.IPv4zero
+		if nh.Gateway.IsValid() {
+			gw = net.IP(nh.Gateway.AsSlice())
+		}
+		rt.Nexthops = append(rt.Nexthops, Nexthop{Gateway: gw.String(), Interface: nh.Interface, Weight: nh.Weight, OnLink: nh.OnLink})
+	}
+
+	return rt, nil
+}
+
+// IPv6Route converts an IPv6 route back to an IPv6Route. It fails for multipath routes, which IPv6Route lists as
+// one route per path.
+func (r Route) IPv6Route() (IPv6Route, error) {
+	if r.Family != FamilyIPv6 || !r.Destination.Addr().Is6() {
+		return IPv6Route{}, fmt.Errorf("route to %s is not IPv6", r.Destination)
+	}
+	if len(r.Nexthops) > 0 {
+		return IPv6Route{}, fmt.Errorf("route to %s has %d nexthops", r.Destination, len(r.Nexthops))
+	}
+
+	src := r.Source
+	if !src.IsValid() {
+		src = netip.PrefixFrom(netip.IPv6Unspecified(), 0)
+	}
+	gw := net.IPv6unspecified
+	if r.Gateway.IsValid() {
+		gw = net.IP(r.Gateway.AsSlice())
+	}
+
+	return IPv6Route{
+		Destination: prefixIPNet(r.Destination.Masked()),
+		Source:      prefixIPNet(src.Masked()),
+		Gateway:     gw,
+		Interface:   r.Interface,
+		Metric:      r.Metric,
+		Flags:       r.Flags,
+		Proto:       r.Proto,
+		Expires:     r.Expires,
+		Type:        r.Type,
+		NexthopID:   r.NexthopID,
+		SRv6:        r.SRv6,
+	}, nil
+}
+
+// UnifyRoutes converts the IPv4 and IPv6 routes of a source, e.g. NetlinkSource's Routes and IPv6Routes, to
+// Routes, IPv4 first.
+func UnifyRoutes(v4 []RoutingTable, v6 []IPv6Route) ([]Route, error) {
+	routes := make([]Route, 0, len(v4)+len(v6))
+	for _, rt := range v4 {
+		r, err := rt.Route()
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, r)
+	}
+	for _, rt := range v6 {
+		routes = append(routes, rt.Route())
+	}
+
+	return routes, nil
+}
+
+// DualStackSource is implemented by route sources that report the routes of both families as Routes.
+type DualStackSource interface {
+	DualStackRoutes(ctx context.Context) ([]Route, error)
+}
+
+// DualStackRoutes returns the routes of /proc/net/route and of ipv6_route next to it as Routes, IPv4 first.
+// Hosts without IPv6 only report IPv4 routes.
+func (s ProcSource) DualStackRoutes(ctx context.Context) ([]Route, error) {
+	return dualStackRoutes(ctx, s)
+}
+
+// DualStackRoutes returns the IPv4 and IPv6 routes of the selected table as Routes, IPv4 first.
+// It is only available on Linux and returns ErrNotSupported elsewhere.
+func (s NetlinkSource) DualStackRoutes(ctx context.Context) ([]Route, error) {
+	return dualStackRoutes(ctx, s)
+}
+
+// dualStackRoutes reads the routes of both families of src as Routes, IPv4 first. A missing IPv6 table, as on
+// hosts without IPv6, leaves the IPv4 routes.
+func dualStackRoutes(ctx context.Context, src interface {
+	RouteSource
+	ipv6RouteSource
+}) ([]Route, error) {
+	v4, err := src.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v6, err := src.IPv6Routes(ctx)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return UnifyRoutes(v4, v6)
+}
+
+// DualStackRoutes returns the IPv4 and IPv6 routes of the default manager as Routes, IPv4 first.
+// Hosts without IPv6 only report IPv4 routes.
+func DualStackRoutes() ([]Route, error) {
+	return DualStackRoutesContext(context.Background())
+}
+
+// DualStackRoutesContext is like DualStackRoutes but returns early if ctx is done.
+func DualStackRoutesContext(ctx context.Context) ([]Route, error) {
+	return defaultManager.DualStackRoutes(ctx)
+}
+
+// DualStackRoutes returns the manager's routes and its IPv6 routes as Routes, IPv4 first. Sources given to
+// WithSource that report no IPv6 routes only report IPv4 routes.
+func (m *Manager) DualStackRoutes(ctx context.Context) ([]Route, error) {
+	v4, err := m.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v6, err := m.IPv6Routes(ctx)
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, ErrNoIPv6Routes) {
+		m.log().Debug("IPv6 routing table unavailable", "err", err)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return UnifyRoutes(v4, v6)
+}
+
+// routeAddr parses the address of column s, returning the zero Addr for an empty or unspecified address.
+func routeAddr(column, s string) (netip.Addr, error) {
+	if s == "" {
+		return netip.Addr{}, nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, &ParseError{Column: column, Value: s, Err: err}
+	}
+	if addr.IsUnspecified() {
+		return netip.Addr{}, nil
+	}
+
+	return addr.Unmap(), nil
+}
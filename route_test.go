@@ -0,0 +1,102 @@
+package routing
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDualStackRoutes(t *testing.T) {
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		return ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev eth0 proto dhcp src 192.0.2.10 metric 600\n" +
+			"198.51.100.0/24 table 100 proto static\n\tnexthop via 192.0.2.2 dev eth0 weight 1\n\tnexthop via 192.0.2.3 dev eth0 weight 2\n"))
+	})
+	m := NewManager(WithSource(src), WithFS(fstest.MapFS{"proc/net/ipv6_route": {Data: []byte(ipv6RouteFixture)}}))
+
+	routes, err := m.DualStackRoutes(context.Background())
+	if err != nil {
+		t.Fatalf("DualStackRoutes failed %s", err.Error())
+	}
+	if len(routes) != 5 {
+		t.Fatalf("Expected 5 routes, got %v", routes)
+	}
+
+	def := routes[0]
+	if def.Family != FamilyIPv4 || !def.IsDefault() || def.Gateway != netip.MustParseAddr("192.0.2.1") ||
+		def.PrefSrc != netip.MustParseAddr("192.0.2.10") || def.Metric != 600 || def.Proto != "dhcp" {
+		t.Errorf("Unexpected IPv4 default route %+v", def)
+	}
+	multi := routes[1]
+	if multi.Destination != netip.MustParsePrefix("198.51.100.0/24") || multi.Table != 100 || multi.Gateway != netip.MustParseAddr("192.0.2.2") {
+		t.Errorf("Unexpected multipath route %+v", multi)
+	}
+	if len(multi.Nexthops) != 2 || multi.Nexthops[1].Gateway != netip.MustParseAddr("192.0.2.3") || multi.Nexthops[1].Weight != 2 {
+		t.Errorf("Unexpected nexthops %+v", multi.Nexthops)
+	}
+
+	v6 := routes[2]
+	if v6.Family != FamilyIPv6 || !v6.IsDefault() || v6.Gateway != netip.MustParseAddr("fe80::1%eth0") || v6.Source.IsValid() || v6.Metric != 1024 {
+		t.Errorf("Unexpected IPv6 default route %+v", v6)
+	}
+	if routes[3].Gateway.IsValid() || routes[3].Destination != netip.MustParsePrefix("2001:db8:0:1::/64") {
+		t.Errorf("Unexpected IPv6 prefix route %+v", routes[3])
+	}
+}
+
+func TestProcSourceDualStackRoutes(t *testing.T) {
+	var src DualStackSource = ProcSource{Path: "snapshot/route", FS: fstest.MapFS{
+		"snapshot/route":      {Data: []byte(procRouteFixture)},
+		"snapshot/ipv6_route": {Data: []byte(ipv6RouteFixture)},
+	}}
+	routes, err := src.DualStackRoutes(context.Background())
+	if err != nil {
+		t.Fatalf("DualStackRoutes failed %s", err.Error())
+	}
+	if len(routes) != 6 || routes[0].Family != FamilyIPv4 || routes[0].Gateway != netip.MustParseAddr("192.0.2.1") ||
+		routes[3].Family != FamilyIPv6 || routes[3].Gateway != netip.MustParseAddr("fe80::1%eth0") {
+		t.Errorf("Unexpected routes %v", routes)
+	}
+
+	v4Only := ProcSource{FS: fstest.MapFS{"proc/net/route": {Data: []byte(procRouteFixture)}}}
+	if routes, err := v4Only.DualStackRoutes(context.Background()); err != nil || len(routes) != 3 {
+		t.Errorf("Expected the IPv4 routes without an IPv6 table, got %v, %v", routes, err)
+	}
+}
+
+func TestRouteRoundTrip(t *testing.T) {
+	v4, err := ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev eth0 proto dhcp src 192.0.2.10 metric 600\n" +
+		"198.51.100.0/24 table 100 proto static\n\tnexthop via 192.0.2.2 dev eth0 weight 1\n\tnexthop via 192.0.2.3 dev eth0 weight 2\n" +
+		"blackhole 203.0.113.0/24 metric 7 mtu 1400\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	v6, err := ParseIPv6Routes(strings.NewReader(ipv6RouteFixture))
+	if err != nil {
+		t.Fatalf("ParseIPv6Routes failed %s", err.Error())
+	}
+	routes, err := UnifyRoutes(v4, v6)
+	if err != nil {
+		t.Fatalf("UnifyRoutes failed %s", err.Error())
+	}
+
+	for i, r := range routes {
+		var want string
+		if i < len(v4) {
+			want = v4[i].String()
+		} else {
+			want = v6[i-len(v4)].String()
+		}
+		if got := r.String(); got != want {
+			t.Errorf("Route %d = %q, want %q", i, got, want)
+		}
+	}
+
+	if _, err := routes[0].IPv6Route(); err == nil {
+		t.Error("Expected converting an IPv4 route to an IPv6Route to fail")
+	}
+	if _, err := routes[len(v4)].RoutingTable(); err == nil {
+		t.Error("Expected converting an IPv6 route to a RoutingTable to fail")
+	}
+}
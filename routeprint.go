@@ -0,0 +1,107 @@
+package routing
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"strings"
+)
+
+var errNonContiguousMask = errors.New("netmask is not contiguous")
+
+// routePrintColumns are the columns of the IPv4 "Active Routes" section printed by Windows `route print`,
+// used as the names of each route's Raw columns.
+var routePrintColumns = []string{"Network Destination", "Netmask", "Gateway", "Interface", "Metric"}
+
+// ParseRoutePrint decodes the active IPv4 routes from the output of Windows `route print` read from r,
+// e.g. as captured in a support bundle. Other sections, including persistent and IPv6 routes, are skipped.
+// Windows identifies interfaces by address, so Interface holds the interface's IPv4 address rather than a name,
+// and the printed values are kept in Raw.
+func ParseRoutePrint(r io.Reader) ([]RoutingTable, error) {
+	var table []RoutingTable
+	inIPv4, inActive := false, false
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "IPv4 Route Table":
+			inIPv4 = true
+			continue
+		case strings.HasSuffix(line, "Route Table"):
+			inIPv4 = false
+			continue
+		case line == "Active Routes:":
+			inActive = inIPv4
+			continue
+		case strings.HasPrefix(line, "==="), strings.HasSuffix(line, ":"):
+			inActive = false
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if !inActive || len(fields) != len(routePrintColumns) || fields[0] == "Network" {
+			continue
+		}
+		rt, err := parseRoutePrintRow(fields)
+		if err != nil {
+			err.Line = n
+			return nil, err
+		}
+		table = append(table, rt)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// parseRoutePrintRow converts the columns of an active route into a RoutingTable.
+func parseRoutePrintRow(fields []string) (RoutingTable, *ParseError) {
+	dst := net.ParseIP(fields[0]).To4()
+	if dst == nil {
+		return RoutingTable{}, &ParseError{Column: "Network Destination", Value: fields[0], Err: errNotIPv4}
+	}
+	mask := net.ParseIP(fields[1]).To4()
+	if mask == nil {
+		return RoutingTable{}, &ParseError{Column: "Netmask", Value: fields[1], Err: errNotIPv4}
+	}
+	ones, bits := net.IPMask(mask).Size()
+	if bits == 0 {
+		return RoutingTable{}, &ParseError{Column: "Netmask", Value: fields[1], Err: errNonContiguousMask}
+	}
+
+	gw := net.IPv4zero
+	flags := FlagUp
+	if fields[2] != "On-link" {
+		gw = net.ParseIP(fields[2]).To4()
+		if gw == nil {
+			return RoutingTable{}, &ParseError{Column: "Gateway", Value: fields[2], Err: errNotIPv4}
+		}
+		flags |= FlagGateway
+	}
+	if ones == 32 {
+		flags |= FlagHost
+	}
+
+	if net.ParseIP(fields[3]).To4() == nil {
+		return RoutingTable{}, &ParseError{Column: "Interface", Value: fields[3], Err: errNotIPv4}
+	}
+	metric, err := parseUint32(fields[4])
+	if err != nil {
+		return RoutingTable{}, &ParseError{Column: "Metric", Value: fields[4], Err: err}
+	}
+
+	return RoutingTable{
+		Interface:   fields[3],
+		Destination: formatHexIP(dst.Mask(net.IPMask(mask))),
+		Gateway:     gw.String(),
+		Flags:       computeRouteFlag(flags),
+		Metric:      metric,
+		Mask:        formatHexIP(mask),
+		Type:        RouteTypeUnicast,
+		Raw:         rawColumns(routePrintColumns, fields[:len(routePrintColumns)]),
+	}, nil
+}
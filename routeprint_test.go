@@ -0,0 +1,71 @@
+package routing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const routePrintFixture = `===========================================================================
+Interface List
+ 12...00 15 5d 01 02 03 ......Microsoft Hyper-V Network Adapter
+  1...........................Software Loopback Interface 1
+===========================================================================
+
+IPv4 Route Table
+===========================================================================
+Active Routes:
+Network Destination        Netmask          Gateway       Interface  Metric
+          0.0.0.0          0.0.0.0      192.168.1.1    192.168.1.100     25
+        127.0.0.0        255.0.0.0         On-link         127.0.0.1    331
+      192.168.1.0    255.255.255.0         On-link     192.168.1.100    281
+    192.168.1.100  255.255.255.255         On-link     192.168.1.100    281
+===========================================================================
+Persistent Routes:
+  Network Address          Netmask  Gateway Address  Metric
+         10.0.0.0        255.0.0.0      192.168.1.2       1
+===========================================================================
+
+IPv6 Route Table
+===========================================================================
+Active Routes:
+ If Metric Network Destination      Gateway
+  1    331 ::1/128                  On-link
+===========================================================================
+Persistent Routes:
+  None
+`
+
+func TestParseRoutePrint(t *testing.T) {
+	table, err := ParseRoutePrint(strings.NewReader(routePrintFixture))
+	if err != nil {
+		t.Fatalf("ParseRoutePrint failed %s", err.Error())
+	}
+
+	var got []string
+	for _, rt := range table {
+		got = append(got, rt.String()+" "+flagLetters(rt.Flags))
+	}
+	want := []string{
+		"default via 192.168.1.1 dev 192.168.1.100 metric 25 UG",
+		"127.0.0.0/8 dev 127.0.0.1 scope link metric 331 U",
+		"192.168.1.0/24 dev 192.168.1.100 scope link metric 281 U",
+		"192.168.1.100 dev 192.168.1.100 scope link metric 281 UH",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected routes\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if table[1].Raw.Get("Metric") != "331" {
+		t.Errorf("Expected the printed metric in Raw, got %v", table[1].Raw)
+	}
+
+	bad := strings.Replace(routePrintFixture, "255.0.0.0 ", "255.0.255.0 ", 1)
+	var pErr *ParseError
+	if _, err := ParseRoutePrint(strings.NewReader(bad)); !errors.As(err, &pErr) || pErr.Line != 12 || pErr.Column != "Netmask" {
+		t.Errorf("Expected a Netmask error on line 12, got %v", err)
+	}
+	bad = strings.Replace(routePrintFixture, "127.0.0.1    331", "127.0.0.1    4294967296", 1)
+	if _, err := ParseRoutePrint(strings.NewReader(bad)); !errors.As(err, &pErr) || pErr.Line != 12 || pErr.Column != "Metric" {
+		t.Errorf("Expected a Metric error on line 12, got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+var errPruneNeedsProto = errors.New("removing extra routes needs a route set protocol")
+
+// RouteSet is a set of routes a service keeps installed, such as a handful of static routes,
+// saved to a file and applied at startup. Routes are identified by destination, table and metric, as the kernel does.
+type RouteSet struct {
+	Proto  string         `json:"proto,omitempty"` // Protocol the set's routes are installed with; it marks them as owned by the set.
+	Routes []RoutingTable `json:"routes"`          // The routes to install.
+}
+
+// RouteSetResult lists the changes RouteSet.Apply made.
+type RouteSetResult struct {
+	Added    []RoutingTable // Routes that were missing.
+	Replaced []RoutingTable // Routes that differed from the set, as installed now.
+	Removed  []RoutingTable // Routes of the set's protocol that the set no longer holds.
+}
+
+// Changed reports whether Apply changed the routing table.
+func (r RouteSetResult) Changed() bool {
+	return len(r.Added)+len(r.Replaced)+len(r.Removed) > 0
+}
+
+// LoadRouteSet reads a route set saved with Save.
+func LoadRouteSet(path string) (RouteSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return RouteSet{}, err
+	}
+	defer f.Close()
+
+	return ReadRouteSet(f)
+}
+
+// ReadRouteSet reads a route set written by WriteJSON.
+func ReadRouteSet(r io.Reader) (RouteSet, error) {
+	var s RouteSet
+	err := json.NewDecoder(r).Decode(&s)
+
+	return s, err
+}
+
+// WriteJSON writes the route set to w as indented JSON.
+func (s RouteSet) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(s)
+}
+
+// Save writes the route set to path, replacing the file atomically so a crash never leaves half a set behind.
+func (s RouteSet) Save(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // Fails harmlessly once the file has been renamed.
+
+	if err := s.WriteJSON(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// Apply makes the routing table read from src hold the set's routes, changing it through w: missing routes are
+// added and routes that differ are replaced, so applying an unchanged set again does nothing. With prune, routes
+// installed with the set's Proto that the set does not hold are removed; this needs a Proto no other program uses
+// and a source reporting protocols, such as NetlinkSource. Apply stops at the first failed change and returns
+// the changes made so far.
+func (s RouteSet) Apply(ctx context.Context, src RouteSource, w RouteWriter, prune bool) (RouteSetResult, error) {
+	var result RouteSetResult
+	if prune && s.Proto == "" {
+		return result, errPruneNeedsProto
+	}
+
+	current, err := src.Routes(ctx)
+	if err != nil {
+		return result, err
+	}
+	installed := make(map[string]RoutingTable, len(current))
+	for _, rt := range current {
+		installed[routeSetKey(rt)] = rt
+	}
+
+	wanted := make(map[string]bool, len(s.Routes))
+	for _, rt := range s.Routes {
+		if rt.Proto == "" {
+			rt.Proto = s.Proto
+		}
+		key := routeSetKey(rt)
+		wanted[key] = true
+
+		have, ok := installed[key]
+		switch {
+		case !ok:
+			if err := w.AddRoute(ctx, rt); err != nil {
+				return result, err
+			}
+			result.Added = append(result.Added, rt)
+		case !routeSetMatches(rt, have):
+			if err := w.ReplaceRoute(ctx, rt); err != nil {
+				return result, err
+			}
+			result.Replaced = append(result.Replaced, rt)
+		}
+	}
+
+	if prune {
+		for _, rt := range current {
+			if rt.Proto != s.Proto || wanted[routeSetKey(rt)] {
+				continue
+			}
+			if err := w.DeleteRoute(ctx, rt); err != nil {
+				return result, err
+			}
+			result.Removed = append(result.Removed, rt)
+		}
+	}
+
+	return result, nil
+}
+
+// routeSetKey identifies a route by destination, TOS, table and metric, as the kernel does; an unset table is main.
+func routeSetKey(rt RoutingTable) string {
+	table := rt.Table
+	if table == TableUnspec {
+		table = TableMain
+	}
+	key := fmt.Sprintf("%s/%s table %d metric %d", rt.Destination, rt.Mask, table, rt.Metric)
+	if rt.TOS != 0 {
+		key += fmt.Sprintf(" tos %d", rt.TOS)
+	}
+
+	return key
+}
+
+// routeSetMatches reports whether the installed route have already is the route want.
+// Fields left unset in want, and the protocol when the source does not report it, are not compared.
+func routeSetMatches(want, have RoutingTable) bool {
+	gwWant, gwHave := net.ParseIP(want.Gateway), net.ParseIP(have.Gateway)
+	sameGateway := gwWant.Equal(gwHave) || (gwWant == nil || gwWant.IsUnspecified()) && (gwHave == nil || gwHave.IsUnspecified())
+	typWant, typHave := want.Type, have.Type
+	if typWant == RouteTypeUnspec {
+		typWant = RouteTypeUnicast
+	}
+	if typHave == RouteTypeUnspec {
+		typHave = RouteTypeUnicast
+	}
+
+	return sameGateway && typWant == typHave && want.OnLink == have.OnLink &&
+		(want.Interface == "" || want.Interface == have.Interface) &&
+		(want.PrefSrc == "" || want.PrefSrc == have.PrefSrc) &&
+		(want.Proto == "" || have.Proto == "" || want.Proto == have.Proto)
+}
@@ -0,0 +1,136 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// memTable is an in-memory routing table serving as both RouteSource and RouteWriter.
+type memTable struct {
+	routes []RoutingTable
+	ops    []string
+}
+
+func (m *memTable) Routes(ctx context.Context) ([]RoutingTable, error) {
+	return append([]RoutingTable(nil), m.routes...), nil
+}
+
+func (m *memTable) find(rt RoutingTable) int {
+	for i, v := range m.routes {
+		if routeSetKey(v) == routeSetKey(rt) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (m *memTable) AddRoute(ctx context.Context, rt RoutingTable) error {
+	m.ops = append(m.ops, "add "+rt.String())
+	m.routes = append(m.routes, rt)
+	return nil
+}
+
+func (m *memTable) ReplaceRoute(ctx context.Context, rt RoutingTable) error {
+	m.ops = append(m.ops, "replace "+rt.String())
+	m.routes[m.find(rt)] = rt
+	return nil
+}
+
+func (m *memTable) DeleteRoute(ctx context.Context, rt RoutingTable) error {
+	m.ops = append(m.ops, "delete "+rt.String())
+	i := m.find(rt)
+	m.routes = append(m.routes[:i], m.routes[i+1:]...)
+	return nil
+}
+
+func mustParseIPRoute(t *testing.T, s string) []RoutingTable {
+	t.Helper()
+	routes, err := ParseIPRoute(strings.NewReader(s))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	return routes
+}
+
+func TestRouteSetApply(t *testing.T) {
+	table := &memTable{routes: mustParseIPRoute(t, `default via 192.0.2.1 dev eth0 proto dhcp
+10.0.0.0/8 via 192.0.2.2 dev eth0 proto static
+172.16.0.0/12 via 192.0.2.2 dev eth0 proto static`)}
+	set := RouteSet{Proto: "static", Routes: mustParseIPRoute(t, `10.0.0.0/8 via 192.0.2.3 dev eth0
+198.51.100.0/24 via 192.0.2.1 dev eth0`)}
+
+	result, err := set.Apply(context.Background(), table, table, true)
+	if err != nil {
+		t.Fatalf("Apply failed %s", err.Error())
+	}
+	want := []string{
+		"replace 10.0.0.0/8 via 192.0.2.3 dev eth0 proto static",
+		"add 198.51.100.0/24 via 192.0.2.1 dev eth0 proto static",
+		"delete 172.16.0.0/12 via 192.0.2.2 dev eth0 proto static",
+	}
+	if strings.Join(table.ops, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected changes:\n%s", strings.Join(table.ops, "\n"))
+	}
+	if len(result.Added) != 1 || len(result.Replaced) != 1 || len(result.Removed) != 1 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	table.ops = nil
+	result, err = set.Apply(context.Background(), table, table, true)
+	if err != nil || result.Changed() || len(table.ops) != 0 {
+		t.Errorf("Expected applying the set again to change nothing, got %v, %v", table.ops, err)
+	}
+}
+
+func TestRouteSetApplyMetrics(t *testing.T) {
+	table := &memTable{routes: mustParseIPRoute(t, "10.0.0.0/8 via 192.0.2.2 dev eth0 proto static metric 600")}
+	set := RouteSet{Proto: "static", Routes: mustParseIPRoute(t, "10.0.0.0/8 via 192.0.2.2 dev eth0 metric 1024")}
+
+	if _, err := set.Apply(context.Background(), table, table, true); err != nil {
+		t.Fatalf("Apply failed %s", err.Error())
+	}
+	want := []string{
+		"add 10.0.0.0/8 via 192.0.2.2 dev eth0 proto static metric 1024",
+		"delete 10.0.0.0/8 via 192.0.2.2 dev eth0 proto static metric 600",
+	}
+	if strings.Join(table.ops, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected routes differing by metric to be distinct, got:\n%s", strings.Join(table.ops, "\n"))
+	}
+}
+
+func TestRouteSetApplyPruneNeedsProto(t *testing.T) {
+	table := &memTable{}
+	_, err := RouteSet{}.Apply(context.Background(), table, table, true)
+	if !errors.Is(err, errPruneNeedsProto) {
+		t.Errorf("Expected errPruneNeedsProto, got %v", err)
+	}
+}
+
+func TestRouteSetSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	set := RouteSet{Proto: "static", Routes: mustParseIPRoute(t, "blackhole 203.0.113.0/24\n10.0.0.0/8 via 192.0.2.3 dev eth0 metric 20")}
+	if err := set.Save(path); err != nil {
+		t.Fatalf("Save failed %s", err.Error())
+	}
+
+	loaded, err := LoadRouteSet(path)
+	if err != nil {
+		t.Fatalf("LoadRouteSet failed %s", err.Error())
+	}
+	if loaded.Proto != set.Proto || len(loaded.Routes) != 2 {
+		t.Fatalf("Unexpected route set %+v", loaded)
+	}
+	for i := range set.Routes {
+		if loaded.Routes[i].String() != set.Routes[i].String() {
+			t.Errorf("Route %d: expected %q, got %q", i, set.Routes[i], loaded.Routes[i])
+		}
+	}
+	if matches, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".*")); len(matches) != 0 {
+		t.Errorf("Save left temporary files behind: %v", matches)
+	}
+}
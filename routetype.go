@@ -0,0 +1,54 @@
+package routing
+
+import "strconv"
+
+// RouteType is the kind of a route, matching the kernel's rtm_type values.
+// Special types such as blackhole or unreachable drop traffic instead of forwarding it.
+type RouteType uint8
+
+// Route types from linux/rtnetlink.h. The zero value is treated as RouteTypeUnicast.
+const (
+	RouteTypeUnspec      RouteType = 0
+	RouteTypeUnicast     RouteType = 1  // Forwarded to a gateway or directly connected destination.
+	RouteTypeLocal       RouteType = 2  // Destination is an address of this host.
+	RouteTypeBroadcast   RouteType = 3  // Destination is a broadcast address.
+	RouteTypeAnycast     RouteType = 4  // Destination is an anycast address of this host.
+	RouteTypeMulticast   RouteType = 5  // Multicast route.
+	RouteTypeBlackhole   RouteType = 6  // Packets are silently discarded.
+	RouteTypeUnreachable RouteType = 7  // Packets are discarded with an ICMP host unreachable error.
+	RouteTypeProhibit    RouteType = 8  // Packets are discarded with an ICMP administratively prohibited error.
+	RouteTypeThrow       RouteType = 9  // Lookup continues with the next policy rule.
+	RouteTypeNAT         RouteType = 10 // Obsolete stateless NAT route.
+)
+
+// routeTypeNames are the iproute2 keywords of the route types, indexed by value.
+var routeTypeNames = [...]string{
+	"unspec", "unicast", "local", "broadcast", "anycast", "multicast",
+	"blackhole", "unreachable", "prohibit", "throw", "nat",
+}
+
+// String returns the iproute2 keyword of the type, e.g. "blackhole".
+// Unknown types are printed as their number.
+func (t RouteType) String() string {
+	if int(t) < len(routeTypeNames) {
+		return routeTypeNames[t]
+	}
+
+	return strconv.Itoa(int(t))
+}
+
+// ParseRouteType returns the route type named by an iproute2 keyword such as "prohibit".
+func ParseRouteType(s string) (RouteType, bool) {
+	for i, name := range routeTypeNames {
+		if name == s {
+			return RouteType(i), true
+		}
+	}
+
+	return RouteTypeUnspec, false
+}
+
+// isUnicast reports whether the type forwards packets normally.
+func (t RouteType) isUnicast() bool {
+	return t == RouteTypeUnspec || t == RouteTypeUnicast
+}
@@ -0,0 +1,56 @@
+package routing
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRouteTypeNames(t *testing.T) {
+	for _, typ := range []RouteType{RouteTypeUnicast, RouteTypeLocal, RouteTypeBlackhole, RouteTypeUnreachable, RouteTypeProhibit, RouteTypeThrow} {
+		parsed, ok := ParseRouteType(typ.String())
+		if !ok || parsed != typ {
+			t.Errorf("ParseRouteType(%q) = %v, %t", typ.String(), parsed, ok)
+		}
+	}
+	if RouteType(42).String() != "42" {
+		t.Errorf("Unexpected name for an unknown type %s", RouteType(42))
+	}
+	if _, ok := ParseRouteType("bogus"); ok {
+		t.Errorf("Expected an unknown type name to be rejected")
+	}
+}
+
+func TestProcRouteType(t *testing.T) {
+	header := newProcHeader(splitHeader("Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT"))
+	cases := map[string]RouteType{
+		"eth0\t0000000A\t00000000\t0001\t0\t0\t0\t000000FF\t0\t0\t0": RouteTypeUnicast,
+		"*\t0000000A\t00000000\t0001\t0\t0\t0\t000000FF\t0\t0\t0":    RouteTypeBlackhole,
+		"*\t0000000A\t00000000\t0201\t0\t0\t0\t000000FF\t0\t0\t0":    RouteTypeUnreachable,
+	}
+	for row, want := range cases {
+		rt, err := parseRouteRow(header, row, nil)
+		if err != nil {
+			t.Fatalf("parseRouteRow failed %s", err.Error())
+		}
+		if rt.Type != want {
+			t.Errorf("Row %q has type %v, want %v", row, rt.Type, want)
+		}
+	}
+}
+
+func TestParseIPRouteJSONType(t *testing.T) {
+	table, err := ParseIPRouteJSON(strings.NewReader(`[{"type":"unreachable","dst":"10.0.0.0/8","flags":[]},{"dst":"default","gateway":"192.0.2.1","dev":"eth0","flags":[]}]`))
+	if err != nil {
+		t.Fatalf("ParseIPRouteJSON failed %s", err.Error())
+	}
+	if table[0].Type != RouteTypeUnreachable || table[0].String() != "unreachable 10.0.0.0/8" || !flagContains(table[0].Flags, "!") {
+		t.Errorf("Unexpected unreachable route %q %+v", table[0].String(), table[0])
+	}
+	if table[1].Type != RouteTypeUnicast {
+		t.Errorf("Expected an untyped route to be unicast, got %v", table[1].Type)
+	}
+
+	if _, err := ParseIPRouteJSON(strings.NewReader(`[{"type":"bogus","dst":"10.0.0.0/8","flags":[]}]`)); err == nil {
+		t.Errorf("Expected an error for an unknown route type")
+	}
+}
@@ -4,10 +4,13 @@
 package routing
 
 import (
-	"errors"
-	"io"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"iter"
 	"net"
-	"os"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -16,16 +19,40 @@ import (
 // It contains details about network routes, including the interface, destination, and gateway.
 type RoutingTable struct {
 	Interface   string               // The network interface associated with the route.
-	Destination string               // The destination IP address for the route.
+	DeviceKind  DeviceKind           // Kind of Interface, e.g. DeviceWireGuard; only set by WithDeviceKinds or ClassifyDevices.
+	Destination string               // The destination IP address for the route, as little-endian hex (see parseHexIP).
 	Gateway     string               // The gateway IP address for the route.
+	GatewayName string               // Host name of the gateway from a reverse DNS lookup; empty unless labelled by GatewayNames.
 	Flags       map[string]RouteFlag // Flags associated with the route.
 	RefCnt      int8                 // Reference count for the route.
 	Use         int8                 // Usage count of the route.
-	Metric      int8                 // Metric for the route, used in route selection.
-	Mask        string               // The subnet mask for the route.
-	MTU         int8                 // Maximum transmission unit for the route.
-	Window      int8                 // Window size for the route.
+	Metric      uint32               // Metric for the route, used in route selection; the kernel calls it priority.
+	Mask        string               // The subnet mask for the route, in the same format as Destination.
+	Window      int8                 // Window size for the route; see Metrics.Window for values above 127.
 	IRTT        int8                 // Initial round trip time for the route.
+	Proto       string               // Protocol that installed the route (e.g. "kernel", "dhcp"), when known.
+	Scope       string               // Scope of the destination (e.g. "link", "host"), when known.
+	PrefSrc     string               // Preferred source address for the route, when known.
+	Realm       string               // Realm of the destination for traffic accounting (e.g. "isp1"), when set.
+	FromRealm   string               // Realm of the source, when set; see Realm.
+	TOS         uint8                // TOS selector matched against the DS field of packets; zero matches any.
+	Table       int                  // ID of the kernel routing table holding the route, e.g. TableMain.
+	Nexthops    []Nexthop            // Paths of a multipath (ECMP) route; nil for single-path routes.
+	NexthopID   uint32               // ID of the NexthopObject the route uses; zero if it carries its own paths.
+	SRv6        *SRv6Encap           // Segment routing encapsulation of seg6 routes; nil for others. Only read over netlink.
+	Type        RouteType            // Kind of route, e.g. RouteTypeBlackhole; the zero value means unicast.
+	OnLink      bool                 // Gateway is reachable through Interface even though it is outside its subnets.
+	Metrics     RouteMetrics         // Per-route metrics such as the path MTU; /proc/net/route only provides MTU and Window.
+	Raw         RawColumns           // Column values exactly as /proc/net/route printed them; empty for other sources.
+}
+
+// Nexthop is one path of a multipath route.
+// The Gateway and Interface of a multipath RoutingTable mirror its first nexthop, as /proc/net/route does.
+type Nexthop struct {
+	Gateway   string // The gateway IP address of the path; "0.0.0.0" if directly connected.
+	Interface string // The network interface of the path.
+	Weight    int    // Relative weight of the path when balancing traffic.
+	OnLink    bool   // Gateway is reachable through Interface even though it is outside its subnets.
 }
 
 // RouteFlag represents a flag used in routing, indicating specific route characteristics.
@@ -36,19 +63,106 @@ type RouteFlag struct {
 	Desc   string // Description of what the flag indicates.
 }
 
+// Bit values of the route flags, as found in the Flags column of /proc/net/route.
+const (
+	FlagUp        int16 = 0x1
+	FlagGateway   int16 = 0x2
+	FlagHost      int16 = 0x4
+	FlagReinstate int16 = 0x8
+	FlagDynamic   int16 = 0x10
+	FlagModified  int16 = 0x20
+	FlagAddrconf  int16 = 0x40
+	FlagCache     int16 = 0x80
+	FlagReject    int16 = 0x200
+)
+
 var routeFlags = []RouteFlag{
-	{"U", 0x1, "Up", "Route is usable (interface is up)"},
-	{"G", 0x2, "Gateway", "Destination is a gateway"},
-	{"H", 0x4, "Host", "Target is a host (not a network)"},
-	{"R", 0x8, "Reinstate", "Route was reinstated for dynamic routing"},
-	{"D", 0x10, "Dynamic", "Route was dynamically created by daemon or redirect"},
-	{"M", 0x20, "Modified", "Route was modified by redirect"},
-	{"A", 0x40, "Addrconf", "Route created by address autoconf"},
-	{"C", 0x80, "Cache", "Route is in cache"},
+	{"U", FlagUp, "Up", "Route is usable (interface is up)"},
+	{"G", FlagGateway, "Gateway", "Destination is a gateway"},
+	{"H", FlagHost, "Host", "Target is a host (not a network)"},
+	{"R", FlagReinstate, "Reinstate", "Route was reinstated for dynamic routing"},
+	{"D", FlagDynamic, "Dynamic", "Route was dynamically created by daemon or redirect"},
+	{"M", FlagModified, "Modified", "Route was modified by redirect"},
+	{"A", FlagAddrconf, "Addrconf", "Route created by address autoconf"},
+	{"C", FlagCache, "Cache", "Route is in cache"},
+	{"!", FlagReject, "Reject", "Route rejects traffic (unreachable or prohibit)"},
+}
+
+// Bit values of the flags only IPv6 routes carry. They use bits the IPv4 table leaves free,
+// since the kernel's IPv6 flags do not fit the Flags column of /proc/net/route; see IPv6RouteFlags.
+const (
+	FlagAnycast       int16 = 0x100
+	FlagExpires       int16 = 0x400
+	FlagNonexthop     int16 = 0x800
+	FlagDefaultRouter int16 = 0x1000
+	FlagRouteInfo     int16 = 0x2000
+	FlagPrefixRoute   int16 = 0x4000
+)
+
+// ipv6RouteFlags describes the flags of IPv6 routes. Addrconf and Cache share letters and bits with the
+// IPv4 table but mean something else: IPv6 routes are autoconfigured from router advertisements,
+// and cached routes are exceptions cloned from a route, e.g. to record a path MTU.
+var ipv6RouteFlags = []RouteFlag{
+	{"U", FlagUp, "Up", "Route is usable (interface is up)"},
+	{"G", FlagGateway, "Gateway", "Destination is a gateway"},
+	{"H", FlagHost, "Host", "Target is a host (not a network)"},
+	{"D", FlagDynamic, "Dynamic", "Route was created by an ICMPv6 redirect"},
+	{"M", FlagModified, "Modified", "Route was modified by an ICMPv6 redirect"},
+	{"A", FlagAddrconf, "Addrconf", "Route learned from a router advertisement"},
+	{"C", FlagCache, "Cache", "Exception cloned from a route, e.g. for a path MTU or redirect"},
+	{"a", FlagAnycast, "Anycast", "Destination is an anycast address"},
+	{"!", FlagReject, "Reject", "Route rejects traffic (unreachable or prohibit)"},
+	{"e", FlagExpires, "Expires", "Route expires when its advertised lifetime ends"},
+	{"n", FlagNonexthop, "Nonexthop", "Route has no next hop"},
+	{"d", FlagDefaultRouter, "DefaultRouter", "Default route through a router learned from a router advertisement"},
+	{"i", FlagRouteInfo, "RouteInfo", "Route learned from a route information option of a router advertisement"},
+	{"p", FlagPrefixRoute, "PrefixRoute", "Route to an on-link prefix of an address"},
+}
+
+// ipv6FlagBits maps the kernel's RTF_* flags of IPv6 routes, as printed in /proc/net/ipv6_route, to the package's flag bits.
+var ipv6FlagBits = map[uint32]int16{
+	0x00000001: FlagUp,
+	0x00000002: FlagGateway,
+	0x00000004: FlagHost,
+	0x00000010: FlagDynamic,
+	0x00000020: FlagModified,
+	0x00000200: FlagReject,
+	0x00010000: FlagDefaultRouter,
+	0x00040000: FlagAddrconf,
+	0x00080000: FlagPrefixRoute,
+	0x00100000: FlagAnycast,
+	0x00200000: FlagNonexthop,
+	0x00400000: FlagExpires,
+	0x00800000: FlagRouteInfo,
+	0x01000000: FlagCache,
+}
+
+// RouteFlags returns the flags known for routes of the given family, ordered by bit.
+// FamilyIPv6 selects the IPv6 names and descriptions; any other family the IPv4 ones.
+func RouteFlags(f Family) []RouteFlag {
+	if f == FamilyIPv6 {
+		return sortedFlags(computeIPv6RouteFlag(-1))
+	}
+
+	return slices.Clone(ipv4Flags())
+}
+
+// IPv6RouteFlags decodes the kernel's flags of an IPv6 route, e.g. the Flags column of /proc/net/ipv6_route,
+// into flags with IPv6 names and descriptions. Kernel flags without a RouteFlag, such as RTF_PCPU, are ignored.
+func IPv6RouteFlags(rtf uint32) map[string]RouteFlag {
+	var bits int16
+	for k, b := range ipv6FlagBits {
+		if rtf&k != 0 {
+			bits |= b
+		}
+	}
+
+	return computeIPv6RouteFlag(bits)
 }
 
 // DecimalToIP converts a decimal integer into its equivalent IPv4 address format.
 // It takes a decimal integer and converts it to a human-readable IP address string.
+// The integer is taken to hold the address in little-endian byte order; use HexToIP to choose the order explicitly.
 func DecimalToIP(decimal int64) string {
 	ip := net.IPv4(
 		byte(decimal),
@@ -60,89 +174,192 @@ func DecimalToIP(decimal int64) string {
 	return ip.String() // Returns the IP address as a string.
 }
 
+// parseHexIP decodes the Destination or Mask of a RoutingTable into a net.IP.
+// Routes keep the little-endian form /proc/net/route has on common hosts whatever the local byte order,
+// so values are portable between machines; procHexToLE converts the columns of big-endian kernels.
+func parseHexIP(s string) (net.IP, error) {
+	return HexToIP(s, binary.LittleEndian)
+}
+
+// formatHexIP encodes an IPv4 address in the /proc/net/route column format.
+// It is the inverse of parseHexIP.
+func formatHexIP(ip net.IP) string {
+	s, err := IPToHex(ip, binary.LittleEndian)
+	if err != nil {
+		return ""
+	}
+
+	return s
+}
+
+// procHexToLE converts an address column of the local /proc/net/route, printed in host byte order,
+// to the little-endian form stored in RoutingTable. Invalid values are kept as they are for decodeDestination to report.
+// Values already in canonical form, as on little-endian hosts, are returned without allocating.
+func procHexToLE(v string) string {
+	val, err := strconv.ParseUint(strings.TrimSpace(v), 16, 32)
+	if err != nil {
+		return v
+	}
+
+	var ip [net.IPv4len]byte
+	binary.NativeEndian.PutUint32(ip[:], uint32(val))
+	le := binary.LittleEndian.Uint32(ip[:])
+	if le == uint32(val) && len(v) == 8 && strings.ToUpper(v) == v {
+		return v
+	}
+
+	const digits = "0123456789ABCDEF"
+	var buf [8]byte
+	for i := range buf {
+		buf[i] = digits[le>>(28-4*i)&0xf]
+	}
+
+	return string(buf[:])
+}
+
+// procHexToDotted decodes an address column of the local /proc/net/route, printed in host byte order,
+// into dotted notation. It is equivalent to HexToIP with binary.NativeEndian followed by String, with one allocation.
+func procHexToDotted(v string) (string, error) {
+	val, err := strconv.ParseUint(strings.TrimSpace(v), 16, 32)
+	if err != nil {
+		return "", err
+	}
+
+	var ip [net.IPv4len]byte
+	binary.NativeEndian.PutUint32(ip[:], uint32(val))
+	buf := make([]byte, 0, len("255.255.255.255"))
+	for i, b := range ip {
+		if i > 0 {
+			buf = append(buf, '.')
+		}
+		buf = strconv.AppendUint(buf, uint64(b), 10)
+	}
+
+	return string(buf), nil
+}
+
 // computeRouteFlag takes a bitmask and generates a list of RouteFlags based on it.
 // It takes a bitmask as input and returns the corresponding RouteFlags.
 func computeRouteFlag(bits int16) map[string]RouteFlag {
-	rf := make(map[string]RouteFlag)
+	return computeFlags(ipv4Flags(), bits)
+}
+
+// computeIPv6RouteFlag is computeRouteFlag for IPv6 routes, using the IPv6 names and descriptions.
+func computeIPv6RouteFlag(bits int16) map[string]RouteFlag {
+	return computeFlags(ipv6RouteFlags, bits)
+}
 
-	for i := range make([]int16, bits) {
-		if bits&routeFlags[i].Bit != 0 {
-			rf[routeFlags[i].Letter] = routeFlags[i]
+// computeFlags returns the flags of table whose bits are set in bits.
+func computeFlags(table []RouteFlag, bits int16) map[string]RouteFlag {
+	size := 0
+	for _, f := range table {
+		if bits&f.Bit != 0 {
+			size++
+		}
+	}
+	rf := make(map[string]RouteFlag, size)
+
+	for _, f := range table {
+		if bits&f.Bit != 0 {
+			rf[f.Letter] = f
 		}
 	}
 
 	return rf
 }
 
+// knownFlagBits returns the union of the bits of all known route flags, registered ones included.
+func knownFlagBits() int16 {
+	var bits int16
+	for _, f := range ipv4Flags() {
+		bits |= f.Bit
+	}
+
+	return bits
+}
+
+// flagBits returns the bitmask of a route's flags.
+func flagBits(rf map[string]RouteFlag) int16 {
+	var bits int16
+	for _, f := range rf {
+		bits |= f.Bit
+	}
+
+	return bits
+}
+
+// sortedFlags returns the flags of a route ordered by their bit value.
+// Map iteration order is random, so callers producing output use this for stable results.
+func sortedFlags(rf map[string]RouteFlag) []RouteFlag {
+	flags := make([]RouteFlag, 0, len(rf))
+	for _, f := range rf {
+		flags = append(flags, f)
+	}
+	sort.Slice(flags, func(i, j int) bool { return uint16(flags[i].Bit) < uint16(flags[j].Bit) })
+
+	return flags
+}
+
+// flagLetters returns the letters of the given flags ordered by bit, e.g. "UG".
+func flagLetters(rf map[string]RouteFlag) string {
+	var letters strings.Builder
+	for _, f := range sortedFlags(rf) {
+		letters.WriteString(f.Letter)
+	}
+
+	return letters.String()
+}
+
+// decodeDestination converts the hex Destination and Mask columns of a route.
+// It returns the destination address, the netmask and the prefix length.
+func decodeDestination(rt RoutingTable) (net.IP, net.IP, int, error) {
+	dst, dstErr := parseHexIP(rt.Destination)
+	if dstErr != nil {
+		return nil, nil, 0, &ParseError{Column: "Destination", Value: rt.Destination, Err: dstErr}
+	}
+	mask, maskErr := parseHexIP(rt.Mask)
+	if maskErr != nil {
+		return nil, nil, 0, &ParseError{Column: "Mask", Value: rt.Mask, Err: maskErr}
+	}
+	ones, _ := net.IPMask(mask).Size()
+
+	return dst, mask, ones, nil
+}
+
+// PrefixLen returns the prefix length of the route's destination, e.g. 24 for a Mask of "00FFFFFF"; see MaskToPrefixLen.
+func (rt RoutingTable) PrefixLen() (int, error) {
+	return MaskToPrefixLen(rt.Mask)
+}
+
 // GetLinuxRoutingTable retrieves the current routing table from the Linux operating system.
 // It reads the routing information from /proc/net/route and populates a slice of RoutingTable structs.
 func GetLinuxRoutingTable(table *[]RoutingTable) error {
-	f, fErr := os.Open("/proc/net/route")
-	if fErr != nil {
-		return errors.New(fErr.Error()) // Returns an error if the file cannot be opened.
-	}
+	return GetLinuxRoutingTableContext(context.Background(), table)
+}
 
-	defer f.Close() // Ensures the file is closed when the function exits.
+// GetLinuxRoutingTableContext is like GetLinuxRoutingTable but returns early if ctx is done.
+func GetLinuxRoutingTableContext(ctx context.Context, table *[]RoutingTable) error {
+	if err := ctx.Err(); err != nil {
+		return err // Returns the context error if it is already cancelled.
+	}
 
-	b, bErr := io.ReadAll(f)
-	if bErr != nil {
-		return errors.New(bErr.Error()) // Returns an error if reading the file fails.
+	routes, err := defaultManager.Routes(ctx)
+	if err != nil {
+		return err
 	}
+	*table = append(*table, routes...) // Append the routes to the caller's slice.
 
-	fTable := string(b)
-	fRows := strings.Split(fTable, "\n")         // Splits the file content into rows.
-	description := strings.Split(fRows[0], "\t") // Gets the header for routing table entries.
+	return nil
+}
 
-	for _, v := range fRows {
-		if strings.Contains(v, "Iface") {
-			continue // Skip the header row.
+// appendRoutes drains seq into table, stopping at the first error or once ctx is done.
+func appendRoutes(ctx context.Context, seq iter.Seq2[RoutingTable, error], table *[]RoutingTable) error {
+	for rtRow, err := range seq {
+		if err != nil {
+			return err // Returns an error if the file cannot be read or a row cannot be parsed.
 		}
-		fColumn := strings.Split(v, "\t")
-		rtRow := RoutingTable{}
-		for n, v := range fColumn {
-			d := strings.TrimSpace(description[n])
-			switch d {
-			case "Iface":
-				rtRow.Interface = v
-			case "Destination":
-				rtRow.Destination = v
-			case "Gateway":
-				val, valErr := strconv.ParseInt(v, 16, 64)
-				if valErr != nil {
-					return errors.New(valErr.Error()) // Returns an error if converting the gateway address fails.
-				}
-				rtRow.Gateway = DecimalToIP(val)
-			case "Flags":
-				var flag int64
-				flag, _ = strconv.ParseInt(v, 10, 16)
-				rtRow.Flags = computeRouteFlag(int16(flag))
-			case "RefCnt":
-				var refcnt int64
-				refcnt, _ = strconv.ParseInt(v, 10, 8)
-				rtRow.RefCnt = int8(refcnt)
-			case "Use":
-				var use int64
-				use, _ = strconv.ParseInt(v, 10, 8)
-				rtRow.Use = int8(use)
-			case "Metric":
-				var metric int64
-				metric, _ = strconv.ParseInt(v, 10, 8)
-				rtRow.Metric = int8(metric)
-			case "Mask":
-				rtRow.Mask = v
-			case "MTU":
-				var mtu int64
-				mtu, _ = strconv.ParseInt(v, 10, 8)
-				rtRow.MTU = int8(mtu)
-			case "Window":
-				var window int64
-				window, _ = strconv.ParseInt(v, 10, 8)
-				rtRow.Window = int8(window)
-			case "IRTT":
-				var irtt int64
-				irtt, _ = strconv.ParseInt(v, 10, 8)
-				rtRow.IRTT = int8(irtt)
-			}
+		if err := ctx.Err(); err != nil {
+			return err // Returns the context error if it was cancelled while reading.
 		}
 		*table = append(*table, rtRow) // Append the populated RoutingTable struct to the slice.
 	}
@@ -150,6 +367,201 @@ func GetLinuxRoutingTable(table *[]RoutingTable) error {
 	return nil // Return nil if the operation completes successfully.
 }
 
+// procColumn identifies a column of /proc/net/route understood by parseRouteRow.
+type procColumn uint8
+
+// Columns of /proc/net/route.
+const (
+	colUnknown procColumn = iota
+	colIface
+	colDestination
+	colGateway
+	colFlags
+	colRefCnt
+	colUse
+	colMetric
+	colMask
+	colMTU
+	colWindow
+	colIRTT
+)
+
+// procColumns maps the header names of /proc/net/route to their columns.
+var procColumns = map[string]procColumn{
+	"Iface": colIface, "Destination": colDestination, "Gateway": colGateway, "Flags": colFlags,
+	"RefCnt": colRefCnt, "Use": colUse, "Metric": colMetric, "Mask": colMask,
+	"MTU": colMTU, "Window": colWindow, "IRTT": colIRTT,
+}
+
+// requiredProcColumns are the columns without which a row cannot describe a route.
+var requiredProcColumns = []string{"Iface", "Destination", "Gateway", "Flags", "Mask"}
+
+// procHeader is the parsed header of /proc/net/route, mapping each position in a row to its column.
+// It is built once per file so rows can be decoded without looking up names.
+type procHeader struct {
+	names   []string     // Column names in file order.
+	columns []procColumn // Column at each position, colUnknown for names parseRouteRow does not understand.
+}
+
+// newProcHeader indexes the column names of a header, as returned by splitHeader.
+func newProcHeader(names []string) procHeader {
+	h := procHeader{names: names, columns: make([]procColumn, len(names))}
+	for i, name := range names {
+		h.columns[i] = procColumns[name]
+	}
+
+	return h
+}
+
+// validate returns a *ParseError naming the first required column the header lacks.
+// The columns may appear in any order.
+func (h procHeader) validate() error {
+	for _, name := range requiredProcColumns {
+		if !slices.Contains(h.columns, procColumns[name]) {
+			return &ParseError{Column: name, Err: errMissingColumn}
+		}
+	}
+
+	return nil
+}
+
+// splitHeader returns the column names of the /proc/net/route header line.
+// The kernel separates Mask and MTU with two tabs, so empty names are dropped to keep the header aligned with the rows.
+func splitHeader(line string) []string {
+	var description []string
+	for _, d := range strings.Split(line, "\t") {
+		if d = strings.TrimSpace(d); d != "" {
+			description = append(description, d)
+		}
+	}
+
+	return description
+}
+
+// ParseRouteHeader returns the column names of a /proc/net/route header line, for use with ParseRouteLine.
+func ParseRouteHeader(line string) []string {
+	return splitHeader(line)
+}
+
+// ParseRouteLine decodes one row of /proc/net/route given the column names of its header, as returned by
+// ParseRouteHeader, so streams of rows can be decoded one at a time. The columns may appear in any order but the
+// header must include Iface, Destination, Gateway, Flags and Mask. Recoverable anomalies, such as out-of-range
+// values, are ignored as they are when reading the file; use ProcSource.OnWarning to see them.
+func ParseRouteLine(header []string, line string) (RoutingTable, error) {
+	h := newProcHeader(header)
+	if err := h.validate(); err != nil {
+		return RoutingTable{}, err
+	}
+	if strings.TrimSpace(line) == "" {
+		return RoutingTable{}, &ParseError{Err: errTruncatedRow}
+	}
+
+	return parseRouteRow(h, line, nil)
+}
+
+// parseRouteRow parses a single tab-separated row of /proc/net/route.
+// The header determines how each value is interpreted; rows shorter or longer than it are reported to warn.
+// Recoverable anomalies are reported to warn, which may be nil.
+func parseRouteRow(h procHeader, row string, warn func(ParseWarning)) (RoutingTable, error) {
+	if warn == nil {
+		warn = func(ParseWarning) {}
+	}
+
+	// Fields are cut from the row one at a time rather than split into a slice, so they share its memory.
+	rtRow := RoutingTable{Table: TableMain, Raw: RawColumns{names: h.names, row: row}} // The proc file only ever shows the main table.
+	n := 0
+	for rest, more := row, true; more; n++ {
+		if n == len(h.names) {
+			columns := n + strings.Count(rest, "\t") + 1
+			warn(ParseWarning{Value: row, Err: fmt.Errorf("%w: %d columns but header has %d", errExtraColumns, columns, len(h.names))})
+			break
+		}
+		var v string
+		v, rest, more = strings.Cut(rest, "\t")
+
+		d := h.names[n]
+		switch h.columns[n] {
+		case colIface:
+			rtRow.Interface = v
+		case colDestination:
+			rtRow.Destination = procHexToLE(v)
+		case colGateway:
+			gw, valErr := procHexToDotted(v)
+			if valErr != nil {
+				return rtRow, &ParseError{Column: d, Value: v, Err: valErr} // Returns an error if converting the gateway address fails.
+			}
+			rtRow.Gateway = gw
+		case colFlags:
+			flag := parseProcInt(warn, d, v, 16, 16) // The kernel prints the flags in hexadecimal.
+			rtRow.Flags = computeRouteFlag(int16(flag))
+			if unknown := int16(flag) &^ knownFlagBits(); unknown != 0 {
+				warn(ParseWarning{Column: d, Value: v, Err: fmt.Errorf("%w: %#x", errUnknownFlags, unknown)})
+			}
+		case colRefCnt:
+			rtRow.RefCnt = int8(parseProcInt(warn, d, v, 10, 8))
+		case colUse:
+			rtRow.Use = int8(parseProcInt(warn, d, v, 10, 8))
+		case colMetric:
+			rtRow.Metric = uint32(parseProcInt(warn, d, v, 10, 32)) // The kernel prints the unsigned metric as signed.
+		case colMask:
+			rtRow.Mask = procHexToLE(v)
+		case colMTU:
+			mtu, err := parseUint32(strings.TrimSpace(v))
+			if err != nil {
+				warn(ParseWarning{Column: d, Value: v, Err: err})
+			}
+			rtRow.Metrics.MTU = mtu
+		case colWindow:
+			rtRow.Window = int8(parseProcInt(warn, d, v, 10, 8))
+			rtRow.Metrics.Window, _ = parseUint32(strings.TrimSpace(v)) // Out-of-range values were reported above.
+		case colIRTT:
+			rtRow.IRTT = int8(parseProcInt(warn, d, v, 10, 8))
+		}
+	}
+	if n < len(h.names) {
+		warn(ParseWarning{Value: row, Err: fmt.Errorf("%w: %d of %d columns", errTruncatedRow, n, len(h.names))})
+	}
+
+	rtRow.Type = procRouteType(rtRow)
+
+	return rtRow, nil
+}
+
+// parseProcInt decodes a numeric column, saturating out-of-range values and reporting failures to warn.
+func parseProcInt(warn func(ParseWarning), column, v string, base, bitSize int) int64 {
+	val, err := strconv.ParseInt(strings.TrimSpace(v), base, bitSize)
+	if err != nil {
+		warn(ParseWarning{Column: column, Value: v, Err: err})
+	}
+
+	return val
+}
+
+// procRouteType infers the type of a /proc/net/route row, which has no type column.
+// The kernel marks unreachable and prohibit routes with the reject flag and prints "*" for routes
+// without a device; neither distinguishes unreachable from prohibit nor blackhole from throw.
+func procRouteType(rt RoutingTable) RouteType {
+	switch {
+	case flagContains(rt.Flags, "!"):
+		return RouteTypeUnreachable
+	case rt.Interface == "*":
+		return RouteTypeBlackhole
+	}
+
+	return RouteTypeUnicast
+}
+
+// Cloned reports whether the kernel generated the route rather than an administrator or routing daemon:
+// a cached entry ("C") or a route created by an ICMP redirect ("D").
+func (rt RoutingTable) Cloned() bool {
+	return isCloned(rt.Flags)
+}
+
+// isCloned reports whether flags mark a cached or redirect-created route.
+func isCloned(flags map[string]RouteFlag) bool {
+	return flagContains(flags, "C") || flagContains(flags, "D")
+}
+
 // flagContains checks if a slice of RouteFlags contains a specific flag letter.
 // It returns true if the flag is found, otherwise false.
 func flagContains(rf map[string]RouteFlag, letter string) bool {
@@ -159,33 +571,21 @@ func flagContains(rf map[string]RouteFlag, letter string) bool {
 
 // getDefaultGW returns the RoutingTable entry that contains the default gateway.
 // It searches the routing table for an entry marked with the "U" (up) and "G" (gateway) flags.
-func getDefaultGW() (RoutingTable, error) {
-	rt := new([]RoutingTable)
-
-	err := GetLinuxRoutingTable(rt)
-	if err != nil {
-		if len(*rt) > 0 {
-			return (*rt)[0], nil // Return the first entry if error occurs but entries are present.
-		}
-		return RoutingTable{}, errors.New(err.Error()) // Return error if no entries are present.
-	}
-
-	for _, v := range *rt {
-		up := flagContains(v.Flags, "U")
-		gateway := flagContains(v.Flags, "G")
-		if up && gateway {
-			return v, nil // Return the entry with both "U" and "G" flags.
-		}
-	}
-	return RoutingTable{}, errors.New("could not locate default GW") // Error if default GW not found.
+func getDefaultGW(ctx context.Context) (RoutingTable, error) {
+	return defaultManager.DefaultRoute(ctx)
 }
 
 // FindLinuxDefaultGW retrieves the default gateway address by reading the routing table.
 // It returns the default gateway IP address in standard string format.
 func FindLinuxDefaultGW() (string, error) {
-	tr, err := getDefaultGW()
+	return FindLinuxDefaultGWContext(context.Background())
+}
+
+// FindLinuxDefaultGWContext is like FindLinuxDefaultGW but returns early if ctx is done.
+func FindLinuxDefaultGWContext(ctx context.Context) (string, error) {
+	tr, err := getDefaultGW(ctx)
 	if err != nil {
-		return "", errors.New(err.Error()) // Return error if default GW not found.
+		return "", err // Return error if default GW not found.
 	}
 
 	return tr.Gateway, nil // Return the default gateway IP address.
@@ -194,9 +594,14 @@ func FindLinuxDefaultGW() (string, error) {
 // FindLinuxDefaultGWInterface returns the network interface name of the default gateway.
 // It reads the routing table to find the interface associated with the default gateway.
 func FindLinuxDefaultGWInterface() (string, error) {
-	tr, err := getDefaultGW()
+	return FindLinuxDefaultGWInterfaceContext(context.Background())
+}
+
+// FindLinuxDefaultGWInterfaceContext is like FindLinuxDefaultGWInterface but returns early if ctx is done.
+func FindLinuxDefaultGWInterfaceContext(ctx context.Context) (string, error) {
+	tr, err := getDefaultGW(ctx)
 	if err != nil {
-		return "", errors.New(err.Error()) // Return error if default GW interface not found.
+		return "", err // Return error if default GW interface not found.
 	}
 
 	return tr.Interface, nil // Return the network interface name of the default gateway.
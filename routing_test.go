@@ -1,11 +1,19 @@
 package routing
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"maps"
+	"math"
 	"regexp"
+	"strings"
 	"testing"
 )
 
 func TestGetDefaultRouteLinux(t *testing.T) {
+	useProcFS(t)
 	result, err := FindLinuxDefaultGW()
 	if err != nil {
 		t.Errorf("Calling routing library failed %s %s", result, err.Error())
@@ -21,6 +29,7 @@ func TestGetDefaultRouteLinux(t *testing.T) {
 }
 
 func TestGetLinuxRoutingTable(t *testing.T) {
+	useProcFS(t)
 	table := new([]RoutingTable)
 	err := GetLinuxRoutingTable(table)
 	if err != nil {
@@ -36,3 +45,189 @@ func TestGetLinuxRoutingTable(t *testing.T) {
 		t.Errorf("Did not match IP address %s %s", (*table)[0].Gateway, "xxx.xxx.xxx.xxx")
 	}
 }
+
+func TestParseRouteRowHostByteOrder(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(procRouteFixture), "\n")
+	header := newProcHeader(splitHeader(lines[0]))
+
+	expected := []string{
+		"default via 192.0.2.1 dev eth0 metric 100",
+		"192.0.2.0/24 dev eth0 scope link metric 100",
+		"10.0.0.1 via 10.0.8.1 dev tun0",
+	}
+	for i, line := range lines[1:] {
+		rt, err := parseRouteRow(header, line, nil)
+		if err != nil {
+			t.Fatalf("parseRouteRow failed %s", err.Error())
+		}
+		if rt.String() != expected[i] {
+			t.Errorf("Row %d = %q, want %q", i, rt.String(), expected[i])
+		}
+	}
+}
+
+func TestParseRouteLine(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(procRouteFixture), "\n")
+	header := ParseRouteHeader(lines[0])
+
+	rt, err := ParseRouteLine(header, lines[2])
+	if err != nil {
+		t.Fatalf("ParseRouteLine failed %s", err.Error())
+	}
+	if rt.String() != "192.0.2.0/24 dev eth0 scope link metric 100" {
+		t.Errorf("Unexpected route %q", rt.String())
+	}
+
+	// The kernel prints metrics as signed 32-bit numbers.
+	for metric, want := range map[string]uint32{"600": 600, "-1": math.MaxUint32} {
+		rt, err := ParseRouteLine(header, "eth0\t00000000\t0100A8C0\t0003\t0\t0\t"+metric+"\t00000000\t0\t0\t0")
+		if err != nil || rt.Metric != want {
+			t.Errorf("ParseRouteLine() with metric %s = %d, %v, want %d", metric, rt.Metric, err, want)
+		}
+	}
+
+	if _, err := ParseRouteLine(header, "  "); !errors.Is(err, ErrParse) {
+		t.Errorf("Expected a blank line to fail with ErrParse, got %v", err)
+	}
+	if _, err := ParseRouteLine(header[:2], lines[1]); !errors.Is(err, errMissingColumn) {
+		t.Errorf("Expected a header without Gateway to be rejected, got %v", err)
+	}
+	if _, err := ParseRouteLine(header, "eth0\t00000000\tzz\t0003\t0\t0\t100\t00000000\t0\t0\t0"); !errors.Is(err, ErrParse) {
+		t.Errorf("Expected an invalid gateway to fail with ErrParse, got %v", err)
+	}
+}
+
+func TestParseRouteRowAligned(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(procRouteFixture), "\n")
+	header := newProcHeader(splitHeader(lines[0]))
+
+	// The header has an empty name between Mask and MTU that has no value in the rows.
+	var warnings []ParseWarning
+	row := strings.Replace(lines[1], "\t0\t0\t0", "\t1500\t0\t0", 1)
+	rt, err := parseRouteRow(header, row, CollectWarnings(&warnings))
+	if err != nil {
+		t.Fatalf("parseRouteRow failed %s", err.Error())
+	}
+	if rt.Metrics.MTU != 1500 || rt.Raw.Get("MTU") != "1500" || len(warnings) != 0 {
+		t.Errorf("Expected MTU 1500 without warnings, got %d and %v", rt.Metrics.MTU, warnings)
+	}
+}
+
+func TestParseRouteRowHexFlags(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(procRouteFixture), "\n")
+	header := newProcHeader(splitHeader(lines[0]))
+
+	// 0x0013 is Up, Gateway and Dynamic; read as decimal it would be Up and Dynamic alone.
+	var warnings []ParseWarning
+	rt, err := parseRouteRow(header, strings.Replace(lines[1], "\t0003\t", "\t0013\t", 1), CollectWarnings(&warnings))
+	if err != nil {
+		t.Fatalf("parseRouteRow failed %s", err.Error())
+	}
+	if got := flagLetters(rt.Flags); got != "UGD" || len(warnings) != 0 {
+		t.Errorf("Expected flags UGD without warnings, got %s and %v", got, warnings)
+	}
+}
+
+func TestParseRouteRowRaw(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(procRouteFixture), "\n")
+	rt, err := parseRouteRow(newProcHeader(splitHeader(lines[0])), lines[1], nil)
+	if err != nil {
+		t.Fatalf("parseRouteRow failed %s", err.Error())
+	}
+
+	fields := strings.Split(lines[1], "\t")
+	if rt.Raw.Get("Iface") != "eth0" || rt.Raw.Get("Gateway") != fields[2] || rt.Raw.Get("Metric") != "100" || rt.Raw.Len() != len(fields) {
+		t.Errorf("Unexpected raw values %v", rt.Raw)
+	}
+}
+
+func TestRawColumnsEncoding(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(procRouteFixture), "\n")
+	rt, err := parseRouteRow(newProcHeader(splitHeader(lines[0])), lines[1], nil)
+	if err != nil {
+		t.Fatalf("parseRouteRow failed %s", err.Error())
+	}
+
+	b, err := json.Marshal(rt)
+	if err != nil {
+		t.Fatalf("Marshal failed %s", err.Error())
+	}
+	var fromJSON RoutingTable
+	if err := json.Unmarshal(b, &fromJSON); err != nil {
+		t.Fatalf("Unmarshal failed %s", err.Error())
+	}
+	if !maps.Equal(fromJSON.Raw.Map(), rt.Raw.Map()) {
+		t.Errorf("JSON round trip changed the raw values to %v", fromJSON.Raw)
+	}
+
+	var buf bytes.Buffer
+	var fromGob RoutingTable
+	if err := gob.NewEncoder(&buf).Encode(rt); err != nil {
+		t.Fatalf("Encode failed %s", err.Error())
+	}
+	if err := gob.NewDecoder(&buf).Decode(&fromGob); err != nil {
+		t.Fatalf("Decode failed %s", err.Error())
+	}
+	if fromGob.Raw.String() != rt.Raw.String() {
+		t.Errorf("Gob round trip changed the raw values to %v", fromGob.Raw)
+	}
+}
+
+func BenchmarkParseRouteRow(b *testing.B) {
+	lines := strings.Split(strings.TrimSpace(procRouteFixture), "\n")
+	header := newProcHeader(splitHeader(lines[0]))
+
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, line := range lines[1:] {
+			if _, err := parseRouteRow(header, line, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestParseRouteRowAllocs(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(procRouteFixture), "\n")
+	header := newProcHeader(splitHeader(lines[0]))
+
+	// The Flags map and the gateway string are the only allocations left per row; Raw shares the row's memory.
+	for _, line := range lines[1:] {
+		allocs := testing.AllocsPerRun(100, func() {
+			parseRouteRow(header, line, nil)
+		})
+		if allocs > 3 {
+			t.Errorf("parseRouteRow made %.0f allocations for row %q, want at most 3", allocs, line)
+		}
+	}
+}
+
+func TestIPv6RouteFlags(t *testing.T) {
+	// An RA-learned default route as /proc/net/ipv6_route prints it, with RTF_PCPU set.
+	rf := IPv6RouteFlags(0x40450003)
+	if got := flagLetters(rf); got != "UGAed" {
+		t.Errorf("Expected flags UGAed, got %s", got)
+	}
+	if rf["A"].Desc != "Route learned from a router advertisement" {
+		t.Errorf("Expected the IPv6 description of Addrconf, got %q", rf["A"].Desc)
+	}
+	if got := IPv6RouteFlags(0x01000001)["C"]; got.Bit != FlagCache || got.Desc == computeRouteFlag(FlagCache)["C"].Desc {
+		t.Errorf("Expected the IPv6 meaning of Cache, got %+v", got)
+	}
+}
+
+func TestRouteFlagsFamily(t *testing.T) {
+	v4, v6 := RouteFlags(FamilyIPv4), RouteFlags(FamilyIPv6)
+	if len(v4) != len(routeFlags) || len(v6) != len(ipv6RouteFlags) {
+		t.Fatalf("Unexpected flag counts %d and %d", len(v4), len(v6))
+	}
+	for i := 1; i < len(v6); i++ {
+		if v6[i-1].Bit >= v6[i].Bit {
+			t.Errorf("IPv6 flags are not ordered by bit: %v", v6)
+		}
+	}
+	v4[0].Name = "changed"
+	if routeFlags[0].Name != "Up" {
+		t.Error("RouteFlags returned the package's table")
+	}
+}
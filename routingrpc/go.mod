@@ -0,0 +1,16 @@
+module github.com/noopduck/routing/routingrpc
+
+go 1.24.6
+
+require (
+	github.com/noopduck/routing v0.0.0-20261015084752-1123f968a789
+	google.golang.org/grpc v1.75.1
+	google.golang.org/protobuf v1.36.8
+)
+
+require (
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+)
@@ -0,0 +1,574 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: routing.proto
+
+package routingrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Route is a single routing table entry.
+type Route struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Interface     string                 `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"` // Network interface, e.g. "eth0".
+	Prefix        string                 `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`       // Destination in CIDR notation, e.g. "10.0.0.0/8".
+	Gateway       string                 `protobuf:"bytes,3,opt,name=gateway,proto3" json:"gateway,omitempty"`     // Gateway address; "0.0.0.0" if directly connected.
+	Flags         string                 `protobuf:"bytes,4,opt,name=flags,proto3" json:"flags,omitempty"`         // Flag letters as printed by route(8), e.g. "UG".
+	Metric        uint32                 `protobuf:"varint,5,opt,name=metric,proto3" json:"metric,omitempty"`      // Metric used in route selection.
+	Type          string                 `protobuf:"bytes,6,opt,name=type,proto3" json:"type,omitempty"`           // Route type, e.g. "unicast" or "blackhole".
+	Proto         string                 `protobuf:"bytes,7,opt,name=proto,proto3" json:"proto,omitempty"`         // Protocol that installed the route, e.g. "dhcp".
+	Scope         string                 `protobuf:"bytes,8,opt,name=scope,proto3" json:"scope,omitempty"`         // Scope of the route, e.g. "link".
+	Prefsrc       string                 `protobuf:"bytes,9,opt,name=prefsrc,proto3" json:"prefsrc,omitempty"`     // Preferred source address.
+	Table         uint32                 `protobuf:"varint,10,opt,name=table,proto3" json:"table,omitempty"`       // ID of the kernel routing table.
+	Nexthops      []*Nexthop             `protobuf:"bytes,11,rep,name=nexthops,proto3" json:"nexthops,omitempty"`  // Paths of a multipath route.
+	Onlink        bool                   `protobuf:"varint,12,opt,name=onlink,proto3" json:"onlink,omitempty"`     // Gateway is reachable even though it is outside the interface's subnets.
+	Mtu           uint32                 `protobuf:"varint,13,opt,name=mtu,proto3" json:"mtu,omitempty"`           // Path MTU; zero if unset.
+	Text          string                 `protobuf:"bytes,14,opt,name=text,proto3" json:"text,omitempty"`          // The route in `ip route` notation.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Route) Reset() {
+	*x = Route{}
+	mi := &file_routing_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Route) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Route) ProtoMessage() {}
+
+func (x *Route) ProtoReflect() protoreflect.Message {
+	mi := &file_routing_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Route.ProtoReflect.Descriptor instead.
+func (*Route) Descriptor() ([]byte, []int) {
+	return file_routing_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Route) GetInterface() string {
+	if x != nil {
+		return x.Interface
+	}
+	return ""
+}
+
+func (x *Route) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *Route) GetGateway() string {
+	if x != nil {
+		return x.Gateway
+	}
+	return ""
+}
+
+func (x *Route) GetFlags() string {
+	if x != nil {
+		return x.Flags
+	}
+	return ""
+}
+
+func (x *Route) GetMetric() uint32 {
+	if x != nil {
+		return x.Metric
+	}
+	return 0
+}
+
+func (x *Route) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Route) GetProto() string {
+	if x != nil {
+		return x.Proto
+	}
+	return ""
+}
+
+func (x *Route) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *Route) GetPrefsrc() string {
+	if x != nil {
+		return x.Prefsrc
+	}
+	return ""
+}
+
+func (x *Route) GetTable() uint32 {
+	if x != nil {
+		return x.Table
+	}
+	return 0
+}
+
+func (x *Route) GetNexthops() []*Nexthop {
+	if x != nil {
+		return x.Nexthops
+	}
+	return nil
+}
+
+func (x *Route) GetOnlink() bool {
+	if x != nil {
+		return x.Onlink
+	}
+	return false
+}
+
+func (x *Route) GetMtu() uint32 {
+	if x != nil {
+		return x.Mtu
+	}
+	return 0
+}
+
+func (x *Route) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// Nexthop is one path of a multipath route.
+type Nexthop struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Gateway       string                 `protobuf:"bytes,1,opt,name=gateway,proto3" json:"gateway,omitempty"`
+	Interface     string                 `protobuf:"bytes,2,opt,name=interface,proto3" json:"interface,omitempty"`
+	Weight        int32                  `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	Onlink        bool                   `protobuf:"varint,4,opt,name=onlink,proto3" json:"onlink,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Nexthop) Reset() {
+	*x = Nexthop{}
+	mi := &file_routing_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Nexthop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Nexthop) ProtoMessage() {}
+
+func (x *Nexthop) ProtoReflect() protoreflect.Message {
+	mi := &file_routing_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Nexthop.ProtoReflect.Descriptor instead.
+func (*Nexthop) Descriptor() ([]byte, []int) {
+	return file_routing_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Nexthop) GetGateway() string {
+	if x != nil {
+		return x.Gateway
+	}
+	return ""
+}
+
+func (x *Nexthop) GetInterface() string {
+	if x != nil {
+		return x.Interface
+	}
+	return ""
+}
+
+func (x *Nexthop) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Nexthop) GetOnlink() bool {
+	if x != nil {
+		return x.Onlink
+	}
+	return false
+}
+
+type ListRoutesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoutesRequest) Reset() {
+	*x = ListRoutesRequest{}
+	mi := &file_routing_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoutesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoutesRequest) ProtoMessage() {}
+
+func (x *ListRoutesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routing_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoutesRequest.ProtoReflect.Descriptor instead.
+func (*ListRoutesRequest) Descriptor() ([]byte, []int) {
+	return file_routing_proto_rawDescGZIP(), []int{2}
+}
+
+type ListRoutesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Routes        []*Route               `protobuf:"bytes,1,rep,name=routes,proto3" json:"routes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoutesResponse) Reset() {
+	*x = ListRoutesResponse{}
+	mi := &file_routing_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoutesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoutesResponse) ProtoMessage() {}
+
+func (x *ListRoutesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routing_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoutesResponse.ProtoReflect.Descriptor instead.
+func (*ListRoutesResponse) Descriptor() ([]byte, []int) {
+	return file_routing_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListRoutesResponse) GetRoutes() []*Route {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+type GetDefaultRouteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDefaultRouteRequest) Reset() {
+	*x = GetDefaultRouteRequest{}
+	mi := &file_routing_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDefaultRouteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDefaultRouteRequest) ProtoMessage() {}
+
+func (x *GetDefaultRouteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routing_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDefaultRouteRequest.ProtoReflect.Descriptor instead.
+func (*GetDefaultRouteRequest) Descriptor() ([]byte, []int) {
+	return file_routing_proto_rawDescGZIP(), []int{4}
+}
+
+type WatchRoutesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Interval      *durationpb.Duration   `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"` // How often to poll the table; one second if unset.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRoutesRequest) Reset() {
+	*x = WatchRoutesRequest{}
+	mi := &file_routing_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRoutesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRoutesRequest) ProtoMessage() {}
+
+func (x *WatchRoutesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routing_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRoutesRequest.ProtoReflect.Descriptor instead.
+func (*WatchRoutesRequest) Descriptor() ([]byte, []int) {
+	return file_routing_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *WatchRoutesRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+// RouteChange lists the routes added and removed since the previous change.
+type RouteChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Added         []*Route               `protobuf:"bytes,2,rep,name=added,proto3" json:"added,omitempty"`
+	Removed       []*Route               `protobuf:"bytes,3,rep,name=removed,proto3" json:"removed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RouteChange) Reset() {
+	*x = RouteChange{}
+	mi := &file_routing_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteChange) ProtoMessage() {}
+
+func (x *RouteChange) ProtoReflect() protoreflect.Message {
+	mi := &file_routing_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteChange.ProtoReflect.Descriptor instead.
+func (*RouteChange) Descriptor() ([]byte, []int) {
+	return file_routing_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RouteChange) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *RouteChange) GetAdded() []*Route {
+	if x != nil {
+		return x.Added
+	}
+	return nil
+}
+
+func (x *RouteChange) GetRemoved() []*Route {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+var File_routing_proto protoreflect.FileDescriptor
+
+const file_routing_proto_rawDesc = "" +
+	"\n" +
+	"\rrouting.proto\x12\n" +
+	"routing.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe4\x02\n" +
+	"\x05Route\x12\x1c\n" +
+	"\tinterface\x18\x01 \x01(\tR\tinterface\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\tR\x06prefix\x12\x18\n" +
+	"\agateway\x18\x03 \x01(\tR\agateway\x12\x14\n" +
+	"\x05flags\x18\x04 \x01(\tR\x05flags\x12\x16\n" +
+	"\x06metric\x18\x05 \x01(\rR\x06metric\x12\x12\n" +
+	"\x04type\x18\x06 \x01(\tR\x04type\x12\x14\n" +
+	"\x05proto\x18\a \x01(\tR\x05proto\x12\x14\n" +
+	"\x05scope\x18\b \x01(\tR\x05scope\x12\x18\n" +
+	"\aprefsrc\x18\t \x01(\tR\aprefsrc\x12\x14\n" +
+	"\x05table\x18\n" +
+	" \x01(\rR\x05table\x12/\n" +
+	"\bnexthops\x18\v \x03(\v2\x13.routing.v1.NexthopR\bnexthops\x12\x16\n" +
+	"\x06onlink\x18\f \x01(\bR\x06onlink\x12\x10\n" +
+	"\x03mtu\x18\r \x01(\rR\x03mtu\x12\x12\n" +
+	"\x04text\x18\x0e \x01(\tR\x04text\"q\n" +
+	"\aNexthop\x12\x18\n" +
+	"\agateway\x18\x01 \x01(\tR\agateway\x12\x1c\n" +
+	"\tinterface\x18\x02 \x01(\tR\tinterface\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x05R\x06weight\x12\x16\n" +
+	"\x06onlink\x18\x04 \x01(\bR\x06onlink\"\x13\n" +
+	"\x11ListRoutesRequest\"?\n" +
+	"\x12ListRoutesResponse\x12)\n" +
+	"\x06routes\x18\x01 \x03(\v2\x11.routing.v1.RouteR\x06routes\"\x18\n" +
+	"\x16GetDefaultRouteRequest\"K\n" +
+	"\x12WatchRoutesRequest\x125\n" +
+	"\binterval\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\binterval\"\x93\x01\n" +
+	"\vRouteChange\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12'\n" +
+	"\x05added\x18\x02 \x03(\v2\x11.routing.v1.RouteR\x05added\x12+\n" +
+	"\aremoved\x18\x03 \x03(\v2\x11.routing.v1.RouteR\aremoved2\xea\x01\n" +
+	"\aRouting\x12K\n" +
+	"\n" +
+	"ListRoutes\x12\x1d.routing.v1.ListRoutesRequest\x1a\x1e.routing.v1.ListRoutesResponse\x12H\n" +
+	"\x0fGetDefaultRoute\x12\".routing.v1.GetDefaultRouteRequest\x1a\x11.routing.v1.Route\x12H\n" +
+	"\vWatchRoutes\x12\x1e.routing.v1.WatchRoutesRequest\x1a\x17.routing.v1.RouteChange0\x01B(Z&github.com/noopduck/routing/routingrpcb\x06proto3"
+
+var (
+	file_routing_proto_rawDescOnce sync.Once
+	file_routing_proto_rawDescData []byte
+)
+
+func file_routing_proto_rawDescGZIP() []byte {
+	file_routing_proto_rawDescOnce.Do(func() {
+		file_routing_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_routing_proto_rawDesc), len(file_routing_proto_rawDesc)))
+	})
+	return file_routing_proto_rawDescData
+}
+
+var file_routing_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_routing_proto_goTypes = []any{
+	(*Route)(nil),                  // 0: routing.v1.Route
+	(*Nexthop)(nil),                // 1: routing.v1.Nexthop
+	(*ListRoutesRequest)(nil),      // 2: routing.v1.ListRoutesRequest
+	(*ListRoutesResponse)(nil),     // 3: routing.v1.ListRoutesResponse
+	(*GetDefaultRouteRequest)(nil), // 4: routing.v1.GetDefaultRouteRequest
+	(*WatchRoutesRequest)(nil),     // 5: routing.v1.WatchRoutesRequest
+	(*RouteChange)(nil),            // 6: routing.v1.RouteChange
+	(*durationpb.Duration)(nil),    // 7: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),  // 8: google.protobuf.Timestamp
+}
+var file_routing_proto_depIdxs = []int32{
+	1, // 0: routing.v1.Route.nexthops:type_name -> routing.v1.Nexthop
+	0, // 1: routing.v1.ListRoutesResponse.routes:type_name -> routing.v1.Route
+	7, // 2: routing.v1.WatchRoutesRequest.interval:type_name -> google.protobuf.Duration
+	8, // 3: routing.v1.RouteChange.time:type_name -> google.protobuf.Timestamp
+	0, // 4: routing.v1.RouteChange.added:type_name -> routing.v1.Route
+	0, // 5: routing.v1.RouteChange.removed:type_name -> routing.v1.Route
+	2, // 6: routing.v1.Routing.ListRoutes:input_type -> routing.v1.ListRoutesRequest
+	4, // 7: routing.v1.Routing.GetDefaultRoute:input_type -> routing.v1.GetDefaultRouteRequest
+	5, // 8: routing.v1.Routing.WatchRoutes:input_type -> routing.v1.WatchRoutesRequest
+	3, // 9: routing.v1.Routing.ListRoutes:output_type -> routing.v1.ListRoutesResponse
+	0, // 10: routing.v1.Routing.GetDefaultRoute:output_type -> routing.v1.Route
+	6, // 11: routing.v1.Routing.WatchRoutes:output_type -> routing.v1.RouteChange
+	9, // [9:12] is the sub-list for method output_type
+	6, // [6:9] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_routing_proto_init() }
+func file_routing_proto_init() {
+	if File_routing_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_routing_proto_rawDesc), len(file_routing_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_routing_proto_goTypes,
+		DependencyIndexes: file_routing_proto_depIdxs,
+		MessageInfos:      file_routing_proto_msgTypes,
+	}.Build()
+	File_routing_proto = out.File
+	file_routing_proto_goTypes = nil
+	file_routing_proto_depIdxs = nil
+}
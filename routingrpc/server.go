@@ -0,0 +1,155 @@
+// Package routingrpc serves routing table state over gRPC.
+// The service is defined in routing.proto; routing.pb.go and routing_grpc.pb.go are generated from it
+// with protoc-gen-go and protoc-gen-go-grpc.
+package routingrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative routing.proto
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/noopduck/routing"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements RoutingServer by reading routes from a routing.RouteSource.
+type Server struct {
+	UnimplementedRoutingServer
+
+	manager *routing.Manager
+}
+
+// NewServer returns a Server reading routes from source, e.g. routing.NewSource() for the local host.
+func NewServer(source routing.RouteSource) *Server {
+	return &Server{manager: routing.NewManager(routing.WithSource(source))}
+}
+
+// ListRoutes returns the current routing table.
+func (s *Server) ListRoutes(ctx context.Context, req *ListRoutesRequest) (*ListRoutesResponse, error) {
+	routes, err := s.manager.Routes(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "reading routes: %v", err)
+	}
+
+	return &ListRoutesResponse{Routes: toProto(routes)}, nil
+}
+
+// GetDefaultRoute returns the default route, or a NotFound error if there is none.
+func (s *Server) GetDefaultRoute(ctx context.Context, req *GetDefaultRouteRequest) (*Route, error) {
+	rt, err := s.manager.DefaultRoute(ctx)
+	if errors.Is(err, routing.ErrNoDefaultGateway) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "reading routes: %v", err)
+	}
+
+	return routeToProto(rt), nil
+}
+
+// WatchRoutes sends the current table as the first change and then every change until the client goes away.
+func (s *Server) WatchRoutes(req *WatchRoutesRequest, stream Routing_WatchRoutesServer) error {
+	var interval time.Duration
+	if req.GetInterval() != nil {
+		interval = req.GetInterval().AsDuration()
+	}
+	w := s.manager.Watch(interval)
+
+	for {
+		change, err := w.Next(stream.Context())
+		if err != nil {
+			if stream.Context().Err() != nil {
+				return status.FromContextError(err).Err()
+			}
+			return status.Errorf(codes.Unavailable, "reading routes: %v", err)
+		}
+		err = stream.Send(&RouteChange{
+			Time:    timestamppb.Now(),
+			Added:   toProto(change.Added),
+			Removed: toProto(change.Removed),
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// toProto converts routes to their protobuf messages.
+func toProto(routes []routing.RoutingTable) []*Route {
+	msgs := make([]*Route, len(routes))
+	for i, rt := range routes {
+		msgs[i] = routeToProto(rt)
+	}
+
+	return msgs
+}
+
+// routeToProto converts a route to its protobuf message.
+func routeToProto(rt routing.RoutingTable) *Route {
+	msg := &Route{
+		Interface: rt.Interface,
+		Prefix:    routePrefix(rt),
+		Gateway:   rt.Gateway,
+		Flags:     flagLetters(rt.Flags),
+		Metric:    rt.Metric,
+		Type:      rt.Type.String(),
+		Proto:     rt.Proto,
+		Scope:     rt.Scope,
+		Prefsrc:   rt.PrefSrc,
+		Table:     uint32(rt.Table),
+		Onlink:    rt.OnLink,
+		Mtu:       rt.Metrics.MTU,
+		Text:      rt.String(),
+	}
+	if rt.Type == routing.RouteTypeUnspec {
+		msg.Type = routing.RouteTypeUnicast.String()
+	}
+	for _, nh := range rt.Nexthops {
+		msg.Nexthops = append(msg.Nexthops, &Nexthop{
+			Gateway:   nh.Gateway,
+			Interface: nh.Interface,
+			Weight:    int32(nh.Weight),
+			Onlink:    nh.OnLink,
+		})
+	}
+
+	return msg
+}
+
+// routePrefix returns the destination of rt in CIDR notation, or the raw column if it cannot be decoded.
+func routePrefix(rt routing.RoutingTable) string {
+	dst, err := routing.HexToIP(rt.Destination, binary.LittleEndian)
+	if err != nil {
+		return rt.Destination
+	}
+	ones, err := rt.PrefixLen()
+	if err != nil {
+		return rt.Destination
+	}
+
+	return fmt.Sprintf("%s/%d", dst, ones)
+}
+
+// flagLetters returns the letters of flags ordered by bit, e.g. "UG".
+func flagLetters(flags map[string]routing.RouteFlag) string {
+	sorted := make([]routing.RouteFlag, 0, len(flags))
+	for _, f := range flags {
+		sorted = append(sorted, f)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Bit < sorted[j].Bit })
+
+	var b strings.Builder
+	for _, f := range sorted {
+		b.WriteString(f.Letter)
+	}
+
+	return b.String()
+}
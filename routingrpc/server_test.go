@@ -0,0 +1,105 @@
+package routingrpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/noopduck/routing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// staticSource returns whatever routes it currently holds.
+type staticSource struct {
+	routes []routing.RoutingTable
+}
+
+func (s *staticSource) Routes(ctx context.Context) ([]routing.RoutingTable, error) {
+	return s.routes, nil
+}
+
+func mustParse(t *testing.T, text string) []routing.RoutingTable {
+	t.Helper()
+	routes, err := routing.ParseIPRoute(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	return routes
+}
+
+// dial starts a server for src on an in-memory listener and returns a client connected to it.
+func dial(t *testing.T, src routing.RouteSource) RoutingClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	RegisterRoutingServer(srv, NewServer(src))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return NewRoutingClient(conn)
+}
+
+func TestServer(t *testing.T) {
+	src := &staticSource{routes: mustParse(t, "default via 192.168.1.1 dev eth0 proto dhcp metric 3000000000\n192.168.1.0/24 dev eth0\n")}
+	client := dial(t, src)
+	ctx := context.Background()
+
+	list, err := client.ListRoutes(ctx, &ListRoutesRequest{})
+	if err != nil {
+		t.Fatalf("ListRoutes failed %s", err.Error())
+	}
+	if len(list.Routes) != 2 || list.Routes[1].Prefix != "192.168.1.0/24" || list.Routes[1].Text != "192.168.1.0/24 dev eth0 scope link" {
+		t.Errorf("Unexpected routes %v", list.Routes)
+	}
+
+	def, err := client.GetDefaultRoute(ctx, &GetDefaultRouteRequest{})
+	if err != nil {
+		t.Fatalf("GetDefaultRoute failed %s", err.Error())
+	}
+	if def.Gateway != "192.168.1.1" || def.Flags != "UG" || def.Prefix != "0.0.0.0/0" || def.Type != "unicast" || def.Proto != "dhcp" || def.Metric != 3000000000 {
+		t.Errorf("Unexpected default route %v", def)
+	}
+
+	empty := dial(t, &staticSource{routes: src.routes[1:]})
+	if _, err := empty.GetDefaultRoute(ctx, &GetDefaultRouteRequest{}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound without a default route, got %v", err)
+	}
+}
+
+func TestWatchRoutes(t *testing.T) {
+	src := routing.Snapshot{Table: mustParse(t, "default via 192.168.1.1 dev eth0\n")}
+	client := dial(t, src)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.WatchRoutes(ctx, &WatchRoutesRequest{Interval: durationpb.New(1)})
+	if err != nil {
+		t.Fatalf("WatchRoutes failed %s", err.Error())
+	}
+	change, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed %s", err.Error())
+	}
+	if len(change.Added) != 1 || change.Added[0].Gateway != "192.168.1.1" || len(change.Removed) != 0 {
+		t.Errorf("Expected the initial table as added, got %v", change)
+	}
+
+	cancel()
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Errorf("Expected the stream to end with Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package routingtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/noopduck/routing"
+)
+
+var (
+	// ErrRouteExists is returned by FakeSource.AddRoute for a route that is already in the table.
+	ErrRouteExists = errors.New("route already exists")
+
+	// ErrRouteNotFound is returned by FakeSource.DeleteRoute for a route that is not in the table.
+	ErrRouteNotFound = errors.New("no such route")
+)
+
+var _ routing.RouteWriter = (*FakeSource)(nil)
+
+// MutationOp is the kind of change recorded by a FakeSource.
+type MutationOp int
+
+// Changes a FakeSource records.
+const (
+	MutationAdd MutationOp = iota
+	MutationReplace
+	MutationDelete
+)
+
+// String returns "add", "replace" or "delete".
+func (op MutationOp) String() string {
+	switch op {
+	case MutationAdd:
+		return "add"
+	case MutationReplace:
+		return "replace"
+	case MutationDelete:
+		return "delete"
+	}
+
+	return fmt.Sprintf("MutationOp(%d)", int(op))
+}
+
+// Mutation is a change made through a FakeSource, successful or not.
+type Mutation struct {
+	Op    MutationOp           // What was requested.
+	Route routing.RoutingTable // The route passed in.
+	Err   error                // The error returned to the caller, if any.
+}
+
+// FakeSource is a RouteSource serving a programmable routing table.
+// It implements routing.RouteWriter, so code writing routes through that interface, such as RouteSet.Apply,
+// can be tested against it; every call is recorded.
+// A FakeSource is safe for concurrent use.
+type FakeSource struct {
+	mu        sync.Mutex
+	routes    []routing.RoutingTable
+	err       error // Returned by Routes when set.
+	reads     int
+	mutations []Mutation
+}
+
+// NewFakeSource returns a FakeSource serving routes.
+func NewFakeSource(routes ...routing.RoutingTable) *FakeSource {
+	return &FakeSource{routes: append([]routing.RoutingTable(nil), routes...)}
+}
+
+// Routes returns a copy of the current table, or the error set by SetError.
+func (s *FakeSource) Routes(ctx context.Context) ([]routing.RoutingTable, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reads++
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return append([]routing.RoutingTable(nil), s.routes...), nil
+}
+
+// SetRoutes replaces the table without recording a mutation, e.g. to simulate a change made by another program.
+func (s *FakeSource) SetRoutes(routes ...routing.RoutingTable) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.routes = append([]routing.RoutingTable(nil), routes...)
+}
+
+// SetError makes Routes fail with err until it is called again with nil.
+func (s *FakeSource) SetError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+// Reads returns the number of times Routes has been called.
+func (s *FakeSource) Reads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.reads
+}
+
+// Mutations returns the changes requested so far, in order.
+func (s *FakeSource) Mutations() []Mutation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Mutation(nil), s.mutations...)
+}
+
+// AddRoute adds rt to the table. It fails with ErrRouteExists if a route with the same destination,
+// table and metric is present, as the kernel does.
+func (s *FakeSource) AddRoute(ctx context.Context, rt routing.RoutingTable) error {
+	return s.mutate(ctx, MutationAdd, rt)
+}
+
+// ReplaceRoute adds rt to the table, replacing a route with the same destination, table and metric.
+func (s *FakeSource) ReplaceRoute(ctx context.Context, rt routing.RoutingTable) error {
+	return s.mutate(ctx, MutationReplace, rt)
+}
+
+// DeleteRoute removes the first route with the destination, table and metric of rt.
+// It fails with ErrRouteNotFound if there is none.
+func (s *FakeSource) DeleteRoute(ctx context.Context, rt routing.RoutingTable) error {
+	return s.mutate(ctx, MutationDelete, rt)
+}
+
+// mutate applies and records a change.
+func (s *FakeSource) mutate(ctx context.Context, op MutationOp, rt routing.RoutingTable) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(rt)
+	var err error
+	switch {
+	case op == MutationAdd && i >= 0:
+		err = fmt.Errorf("adding route %s: %w", rt, ErrRouteExists)
+	case op == MutationDelete && i < 0:
+		err = fmt.Errorf("deleting route %s: %w", rt, ErrRouteNotFound)
+	case op == MutationDelete:
+		s.routes = append(s.routes[:i], s.routes[i+1:]...)
+	case i >= 0:
+		s.routes[i] = rt
+	default:
+		s.routes = append(s.routes, rt)
+	}
+	s.mutations = append(s.mutations, Mutation{Op: op, Route: rt, Err: err})
+
+	return err
+}
+
+// find returns the index of the route with the same destination, table and metric as rt, or -1.
+func (s *FakeSource) find(rt routing.RoutingTable) int {
+	for i, v := range s.routes {
+		if v.Destination == rt.Destination && v.Mask == rt.Mask && v.Table == rt.Table && v.Metric == rt.Metric {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,112 @@
+// Package routingtest provides routing table fixtures and a fake RouteSource
+// for testing code that depends on the routing package.
+package routingtest
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing/fstest"
+
+	"github.com/noopduck/routing"
+)
+
+// procRouteHeader is the header line of /proc/net/route.
+const procRouteHeader = "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n"
+
+// Fixture is a captured routing table of a typical host.
+type Fixture struct {
+	Name    string // Short name, e.g. "vpn".
+	Proc    string // /proc/net/route as a little-endian host prints it.
+	IPRoute string // The same table as printed by `ip -4 route show`, including paths /proc/net/route omits.
+}
+
+var (
+	// MultiHomed is a laptop connected by both Ethernet and Wi-Fi, preferring Ethernet by metric.
+	MultiHomed = Fixture{
+		Name: "multihomed",
+		Proc: procRouteHeader +
+			"eth0\t00000000\t0101A8C0\t0003\t0\t0\t100\t00000000\t0\t0\t0\n" +
+			"wlan0\t00000000\t0100000A\t0003\t0\t0\t120\t00000000\t0\t0\t0\n" +
+			"wlan0\t0000000A\t00000000\t0001\t0\t0\t120\t00FFFFFF\t0\t0\t0\n" +
+			"eth0\t0001A8C0\t00000000\t0001\t0\t0\t100\t00FFFFFF\t0\t0\t0\n",
+		IPRoute: "default via 192.168.1.1 dev eth0 proto dhcp src 192.168.1.20 metric 100\n" +
+			"default via 10.0.0.1 dev wlan0 proto dhcp src 10.0.0.57 metric 120\n" +
+			"10.0.0.0/24 dev wlan0 proto kernel scope link src 10.0.0.57 metric 120\n" +
+			"192.168.1.0/24 dev eth0 proto kernel scope link src 192.168.1.20 metric 100\n",
+	}
+
+	// VPN is a host with a full-tunnel VPN: two /1 routes through tun0 override the default route,
+	// and a host route keeps the VPN server reachable through the physical gateway.
+	VPN = Fixture{
+		Name: "vpn",
+		Proc: procRouteHeader +
+			"tun0\t00000000\t0100080A\t0003\t0\t0\t0\t00000080\t0\t0\t0\n" +
+			"eth0\t00000000\t0101A8C0\t0003\t0\t0\t100\t00000000\t0\t0\t0\n" +
+			"tun0\t0000080A\t00000000\t0001\t0\t0\t0\t00FFFFFF\t0\t0\t0\n" +
+			"tun0\t00000080\t0100080A\t0003\t0\t0\t0\t00000080\t0\t0\t0\n" +
+			"eth0\t0001A8C0\t00000000\t0001\t0\t0\t100\t00FFFFFF\t0\t0\t0\n" +
+			"eth0\t076433C6\t0101A8C0\t0007\t0\t0\t0\tFFFFFFFF\t0\t0\t0\n",
+		IPRoute: "0.0.0.0/1 via 10.8.0.1 dev tun0\n" +
+			"default via 192.168.1.1 dev eth0 proto dhcp src 192.168.1.20 metric 100\n" +
+			"10.8.0.0/24 dev tun0 proto kernel scope link src 10.8.0.2\n" +
+			"128.0.0.0/1 via 10.8.0.1 dev tun0\n" +
+			"192.168.1.0/24 dev eth0 proto kernel scope link src 192.168.1.20 metric 100\n" +
+			"198.51.100.7 via 192.168.1.1 dev eth0\n",
+	}
+
+	// ECMP is a router balancing its default route over two uplinks.
+	// /proc/net/route only shows the first path of the multipath route; IPRoute has both.
+	ECMP = Fixture{
+		Name: "ecmp",
+		Proc: procRouteHeader +
+			"eth0\t00000000\t0101A8C0\t0003\t0\t0\t100\t00000000\t0\t0\t0\n" +
+			"eth0\t0001A8C0\t00000000\t0001\t0\t0\t0\t00FFFFFF\t0\t0\t0\n" +
+			"eth1\t0002A8C0\t00000000\t0001\t0\t0\t0\t00FFFFFF\t0\t0\t0\n",
+		IPRoute: "default proto static metric 100\n" +
+			"\tnexthop via 192.168.1.1 dev eth0 weight 1\n" +
+			"\tnexthop via 192.168.2.1 dev eth1 weight 1\n" +
+			"192.168.1.0/24 dev eth0 proto kernel scope link src 192.168.1.2\n" +
+			"192.168.2.0/24 dev eth1 proto kernel scope link src 192.168.2.2\n",
+	}
+
+	// Empty is a routing table without routes, as in a fresh network namespace.
+	Empty = Fixture{
+		Name: "empty",
+		Proc: procRouteHeader,
+	}
+)
+
+// Fixtures lists every fixture, for table-driven tests.
+var Fixtures = []Fixture{MultiHomed, VPN, ECMP, Empty}
+
+// FS returns a filesystem holding the fixture as proc/net/route, in the byte order of the host,
+// for use with routing.WithFS.
+func (f Fixture) FS() fstest.MapFS {
+	return fstest.MapFS{"proc/net/route": {Data: []byte(nativeProc(f.Proc))}}
+}
+
+// Source returns a RouteSource reading the fixture's /proc/net/route.
+func (f Fixture) Source() routing.RouteSource {
+	return routing.NewSource(routing.WithFS(f.FS()))
+}
+
+// nativeProc rewrites the address columns of little-endian /proc/net/route text in the host's byte order.
+func nativeProc(text string) string {
+	if binary.NativeEndian.Uint16([]byte{1, 0}) == 1 {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		fields := strings.Split(lines[i], "\t")
+		for _, col := range []int{1, 2, 7} { // Destination, Gateway and Mask.
+			if col < len(fields) && len(fields[col]) == 8 {
+				v := fields[col]
+				fields[col] = v[6:8] + v[4:6] + v[2:4] + v[0:2]
+			}
+		}
+		lines[i] = strings.Join(fields, "\t")
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,84 @@
+package routingtest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/noopduck/routing"
+)
+
+func TestFixtures(t *testing.T) {
+	want := map[string]struct {
+		routes  int
+		gateway string
+	}{
+		"multihomed": {4, "192.168.1.1"},
+		"vpn":        {6, "192.168.1.1"}, // The /1 routes through tun0 are not default routes.
+		"ecmp":       {3, "192.168.1.1"},
+		"empty":      {0, ""},
+	}
+
+	for _, f := range Fixtures {
+		m := routing.NewManager(routing.WithSource(f.Source()))
+		routes, err := m.Routes(context.Background())
+		if err != nil {
+			t.Fatalf("%s: Routes failed %s", f.Name, err.Error())
+		}
+		if len(routes) != want[f.Name].routes {
+			t.Errorf("%s: got %d routes, want %d", f.Name, len(routes), want[f.Name].routes)
+		}
+		gw, _ := m.DefaultGateway(context.Background())
+		if gw != want[f.Name].gateway {
+			t.Errorf("%s: default gateway %q, want %q", f.Name, gw, want[f.Name].gateway)
+		}
+
+		parsed, err := routing.ParseIPRoute(strings.NewReader(f.IPRoute))
+		if err != nil {
+			t.Fatalf("%s: ParseIPRoute failed %s", f.Name, err.Error())
+		}
+		if len(parsed) != len(routes) {
+			t.Errorf("%s: IPRoute has %d routes, Proc %d", f.Name, len(parsed), len(routes))
+		}
+	}
+}
+
+func TestFakeSource(t *testing.T) {
+	routes, err := routing.ParseIPRoute(strings.NewReader(MultiHomed.IPRoute))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	ctx := context.Background()
+	src := NewFakeSource(routes[2:]...)
+
+	if err := src.AddRoute(ctx, routes[0]); err != nil {
+		t.Fatalf("AddRoute failed %s", err.Error())
+	}
+	if err := src.AddRoute(ctx, routes[0]); !errors.Is(err, ErrRouteExists) {
+		t.Errorf("Expected ErrRouteExists, got %v", err)
+	}
+	if err := src.DeleteRoute(ctx, routes[1]); !errors.Is(err, ErrRouteNotFound) {
+		t.Errorf("Expected ErrRouteNotFound, got %v", err)
+	}
+	if err := src.DeleteRoute(ctx, routes[2]); err != nil {
+		t.Errorf("DeleteRoute failed %s", err.Error())
+	}
+
+	got, _ := src.Routes(ctx)
+	if len(got) != 2 || got[1].Gateway != "192.168.1.1" || src.Reads() != 1 {
+		t.Errorf("Unexpected table %v after %d reads", got, src.Reads())
+	}
+	var ops []string
+	for _, m := range src.Mutations() {
+		ops = append(ops, m.Op.String())
+	}
+	if strings.Join(ops, " ") != "add add delete delete" {
+		t.Errorf("Unexpected mutations %v", ops)
+	}
+
+	src.SetError(errors.New("unavailable"))
+	if _, err := src.Routes(ctx); err == nil {
+		t.Errorf("Expected the programmed error")
+	}
+}
@@ -0,0 +1,112 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// RuleAction is what a routing policy rule does when it matches.
+type RuleAction uint8
+
+// Rule actions, matching the kernel's FR_ACT_* values.
+const (
+	RuleActionUnspec      RuleAction = 0
+	RuleActionLookup      RuleAction = 1 // Look the destination up in the rule's table.
+	RuleActionGoto        RuleAction = 2 // Jump to the rule with the given priority.
+	RuleActionNop         RuleAction = 3 // Do nothing.
+	RuleActionBlackhole   RuleAction = 6 // Silently drop the packet.
+	RuleActionUnreachable RuleAction = 7 // Reject the packet with "network unreachable".
+	RuleActionProhibit    RuleAction = 8 // Reject the packet with "administratively prohibited".
+)
+
+// String returns the keyword iproute2 uses for the action.
+func (a RuleAction) String() string {
+	switch a {
+	case RuleActionLookup:
+		return "lookup"
+	case RuleActionGoto:
+		return "goto"
+	case RuleActionNop:
+		return "nop"
+	case RuleActionBlackhole:
+		return "blackhole"
+	case RuleActionUnreachable:
+		return "unreachable"
+	case RuleActionProhibit:
+		return "prohibit"
+	}
+
+	return fmt.Sprintf("action %d", uint8(a))
+}
+
+// Rule is an entry of the routing policy database, as listed by `ip rule`.
+type Rule struct {
+	Family            Family     // Address family the rule applies to.
+	Priority          int        // Rule priority; lower values are evaluated first.
+	Src               *net.IPNet // Source prefix selector; nil matches all sources.
+	Dst               *net.IPNet // Destination prefix selector; nil matches all destinations.
+	Invert            bool       // Whether the selector is negated ("not").
+	TOS               uint8      // TOS selector; zero matches any.
+	FwMark            uint32     // Firewall mark selector; zero matches any.
+	FwMask            uint32     // Mask applied to the packet mark before comparing with FwMark.
+	IIF               string     // Incoming interface selector; empty matches any.
+	OIF               string     // Outgoing interface selector; empty matches any.
+	Action            RuleAction // What to do when the rule matches.
+	Table             int        // Table consulted by RuleActionLookup.
+	Goto              int        // Target priority of RuleActionGoto.
+	SuppressPrefixLen int        // Reject lookup results with a prefix this short or shorter; -1 if unset.
+}
+
+// String renders the rule the way `ip rule` prints it, e.g. "32766:	from all lookup main".
+func (r Rule) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%d:\t", r.Priority)
+	if r.Invert {
+		b.WriteString("not ")
+	}
+	if r.Src == nil {
+		b.WriteString("from all")
+	} else {
+		fmt.Fprintf(&b, "from %s", r.Src)
+	}
+	if r.Dst != nil {
+		fmt.Fprintf(&b, " to %s", r.Dst)
+	}
+	if r.TOS != 0 {
+		fmt.Fprintf(&b, " tos %#x", r.TOS)
+	}
+	if r.FwMark != 0 || r.FwMask != 0 {
+		fmt.Fprintf(&b, " fwmark %#x", r.FwMark)
+		if r.FwMask != 0xffffffff {
+			fmt.Fprintf(&b, "/%#x", r.FwMask)
+		}
+	}
+	if r.IIF != "" {
+		fmt.Fprintf(&b, " iif %s", r.IIF)
+	}
+	if r.OIF != "" {
+		fmt.Fprintf(&b, " oif %s", r.OIF)
+	}
+
+	switch r.Action {
+	case RuleActionLookup:
+		fmt.Fprintf(&b, " lookup %s", TableName(r.Table))
+	case RuleActionGoto:
+		fmt.Fprintf(&b, " goto %d", r.Goto)
+	default:
+		fmt.Fprintf(&b, " %s", r.Action)
+	}
+	if r.SuppressPrefixLen >= 0 {
+		fmt.Fprintf(&b, " suppress_prefixlength %d", r.SuppressPrefixLen)
+	}
+
+	return b.String()
+}
+
+// Rules lists the IPv4 and IPv6 routing policy rules using netlink, ordered as the kernel reports them.
+func Rules() ([]Rule, error) {
+	return RulesContext(context.Background())
+}
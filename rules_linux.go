@@ -0,0 +1,101 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"syscall"
+)
+
+// Rule message layout (struct fib_rule_hdr), attributes and flags from linux/fib_rules.h.
+const (
+	sizeofFibRuleHdr      = 12
+	fraDst                = 1
+	fraSrc                = 2
+	fraIIFName            = 3
+	fraGoto               = 4
+	fraPriority           = 6
+	fraFwMark             = 10
+	fraSuppressPrefixLen  = 14
+	fraTable              = 15
+	fraFwMask             = 16
+	fraOIFName            = 17
+	fibRuleInvert         = 0x2
+	rtmGetRule            = 34
+	suppressPrefixLenNone = 0xffffffff
+)
+
+// RulesContext is like Rules but returns early if ctx is done.
+func RulesContext(ctx context.Context) ([]Rule, error) {
+	var rules []Rule
+	for _, family := range []int{syscall.AF_INET, syscall.AF_INET6} {
+		msgs, err := netlinkDump(ctx, rtmGetRule, family)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range msgs {
+			if r, ok := parseRuleMsg(m.Data); ok {
+				rules = append(rules, r)
+			}
+		}
+	}
+
+	return rules, nil
+}
+
+// parseRuleMsg decodes the payload of an RTM_NEWRULE message.
+func parseRuleMsg(b []byte) (Rule, bool) {
+	if len(b) < sizeofFibRuleHdr {
+		return Rule{}, false
+	}
+
+	r := Rule{
+		Family:            familyFromAF(b[0]),
+		TOS:               b[3],
+		Table:             int(b[4]),
+		Action:            RuleAction(b[7]),
+		Invert:            binary.NativeEndian.Uint32(b[8:12])&fibRuleInvert != 0,
+		SuppressPrefixLen: -1,
+	}
+	if r.Family == FamilyUnspec {
+		return Rule{}, false
+	}
+	bits := 32
+	if r.Family == FamilyIPv6 {
+		bits = 128
+	}
+
+	attrs := netlinkAttrs(b[sizeofFibRuleHdr:])
+	if v, ok := attrs[fraSrc]; ok {
+		r.Src = &net.IPNet{IP: net.IP(append([]byte(nil), v...)), Mask: net.CIDRMask(int(b[2]), bits)}
+	}
+	if v, ok := attrs[fraDst]; ok {
+		r.Dst = &net.IPNet{IP: net.IP(append([]byte(nil), v...)), Mask: net.CIDRMask(int(b[1]), bits)}
+	}
+	if v, ok := attrs[fraPriority]; ok {
+		r.Priority = int(nlUint32(v))
+	}
+	if v, ok := attrs[fraTable]; ok {
+		r.Table = int(nlUint32(v))
+	}
+	if v, ok := attrs[fraGoto]; ok {
+		r.Goto = int(nlUint32(v))
+	}
+	if v, ok := attrs[fraFwMark]; ok {
+		r.FwMark = nlUint32(v)
+	}
+	if v, ok := attrs[fraFwMask]; ok {
+		r.FwMask = nlUint32(v)
+	}
+	if v, ok := attrs[fraIIFName]; ok {
+		r.IIF = nlString(v)
+	}
+	if v, ok := attrs[fraOIFName]; ok {
+		r.OIF = nlString(v)
+	}
+	if v, ok := attrs[fraSuppressPrefixLen]; ok && nlUint32(v) != suppressPrefixLenNone {
+		r.SuppressPrefixLen = int(nlUint32(v))
+	}
+
+	return r, true
+}
@@ -0,0 +1,20 @@
+package routing
+
+import "testing"
+
+func TestRules(t *testing.T) {
+	rules, err := Rules()
+	if err != nil {
+		t.Fatalf("Calling routing library failed %s", err.Error())
+	}
+
+	found := false
+	for _, r := range rules {
+		if r.Family == FamilyIPv4 && r.String() == "32766:\tfrom all lookup main" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the default main table rule in %v", rules)
+	}
+}
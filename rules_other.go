@@ -0,0 +1,11 @@
+//go:build !linux
+
+package routing
+
+import "context"
+
+// RulesContext is like Rules but returns early if ctx is done.
+// Netlink is only available on Linux, so it always returns ErrNotSupported here.
+func RulesContext(ctx context.Context) ([]Rule, error) {
+	return nil, ErrNotSupported
+}
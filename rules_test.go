@@ -0,0 +1,25 @@
+package routing
+
+import (
+	"net"
+	"testing"
+)
+
+func TestRuleString(t *testing.T) {
+	_, src, _ := net.ParseCIDR("10.8.0.0/24")
+	cases := []struct {
+		rule     Rule
+		expected string
+	}{
+		{Rule{Priority: 32766, Action: RuleActionLookup, Table: TableMain, SuppressPrefixLen: -1}, "32766:\tfrom all lookup main"},
+		{Rule{Priority: 100, Src: src, FwMark: 0x1, FwMask: 0xff, Action: RuleActionLookup, Table: 100, SuppressPrefixLen: -1}, "100:\tfrom 10.8.0.0/24 fwmark 0x1/0xff lookup 100"},
+		{Rule{Priority: 200, Invert: true, IIF: "eth0", Action: RuleActionProhibit, SuppressPrefixLen: -1}, "200:\tnot from all iif eth0 prohibit"},
+		{Rule{Priority: 300, Action: RuleActionLookup, Table: TableMain, SuppressPrefixLen: 0}, "300:\tfrom all lookup main suppress_prefixlength 0"},
+	}
+
+	for _, c := range cases {
+		if got := c.rule.String(); got != c.expected {
+			t.Errorf("String() = %q, want %q", got, c.expected)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package routing
+
+import (
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+)
+
+// Snapshot is a routing table captured on a host at a point in time.
+// A Snapshot is itself a RouteSource, so a loaded snapshot can be analysed with the same APIs as a live host.
+type Snapshot struct {
+	Taken    time.Time      `json:"taken"`    // When the routes were read.
+	Hostname string         `json:"hostname"` // Host the routes were read on.
+	Table    []RoutingTable `json:"routes"`   // The captured routing table.
+}
+
+// TakeSnapshot reads the routing table from src and records it with the current time and hostname.
+func TakeSnapshot(ctx context.Context, src RouteSource) (Snapshot, error) {
+	routes, err := src.Routes(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	return Snapshot{Taken: time.Now(), Hostname: hostname, Table: routes}, nil
+}
+
+// Routes returns a copy of the captured routes, making a Snapshot usable as a RouteSource.
+func (s Snapshot) Routes(ctx context.Context) ([]RoutingTable, error) {
+	return append([]RoutingTable(nil), s.Table...), nil
+}
+
+// WriteJSON writes the snapshot to w as indented JSON.
+func (s Snapshot) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(s)
+}
+
+// ReadSnapshotJSON reads a snapshot written by WriteJSON.
+func ReadSnapshotJSON(r io.Reader) (Snapshot, error) {
+	var s Snapshot
+	err := json.NewDecoder(r).Decode(&s)
+
+	return s, err
+}
+
+// WriteGob writes the snapshot to w in gob encoding.
+func (s Snapshot) WriteGob(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(s)
+}
+
+// ReadSnapshotGob reads a snapshot written by WriteGob.
+func ReadSnapshotGob(r io.Reader) (Snapshot, error) {
+	var s Snapshot
+	err := gob.NewDecoder(r).Decode(&s)
+
+	return s, err
+}
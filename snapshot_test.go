@@ -0,0 +1,51 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		return ParseIPRoute(strings.NewReader("default via 192.168.1.1 dev eth0 proto dhcp metric 100\n192.168.1.0/24 dev eth0 proto kernel scope link src 192.168.1.5\n"))
+	})
+
+	snap, err := TakeSnapshot(context.Background(), src)
+	if err != nil {
+		t.Fatalf("TakeSnapshot failed %s", err.Error())
+	}
+	if snap.Hostname == "" || snap.Taken.IsZero() || len(snap.Table) != 2 {
+		t.Fatalf("Unexpected snapshot %+v", snap)
+	}
+
+	var jsonBuf, gobBuf bytes.Buffer
+	if err := snap.WriteJSON(&jsonBuf); err != nil {
+		t.Fatalf("WriteJSON failed %s", err.Error())
+	}
+	if err := snap.WriteGob(&gobBuf); err != nil {
+		t.Fatalf("WriteGob failed %s", err.Error())
+	}
+
+	fromJSON, err := ReadSnapshotJSON(&jsonBuf)
+	if err != nil {
+		t.Fatalf("ReadSnapshotJSON failed %s", err.Error())
+	}
+	fromGob, err := ReadSnapshotGob(&gobBuf)
+	if err != nil {
+		t.Fatalf("ReadSnapshotGob failed %s", err.Error())
+	}
+
+	for name, got := range map[string]Snapshot{"json": fromJSON, "gob": fromGob} {
+		if !got.Taken.Equal(snap.Taken) || got.Hostname != snap.Hostname || !reflect.DeepEqual(got.Table, snap.Table) {
+			t.Errorf("%s round trip mismatch %+v", name, got)
+		}
+	}
+
+	rt, _, _ := Query().From(fromJSON).WithFlags(FlagGateway).First()
+	if rt.Gateway != "192.168.1.1" {
+		t.Errorf("Expected the snapshot to be queryable, got %+v", rt)
+	}
+}
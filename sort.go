@@ -0,0 +1,48 @@
+package routing
+
+import "sort"
+
+// SortByMetric sorts routes in place by ascending metric.
+// The sort is stable, so routes with equal metrics keep their relative order.
+func SortByMetric(routes []RoutingTable) {
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].Metric < routes[j].Metric
+	})
+}
+
+// SortByPrefixLength sorts routes in place from the most specific prefix to the least specific.
+// The sort is stable, so calling SortByMetric first yields the kernel's selection order.
+// Routes whose mask cannot be decoded are placed last.
+func SortByPrefixLength(routes []RoutingTable) {
+	sort.SliceStable(routes, func(i, j int) bool {
+		return prefixLen(routes[i]) > prefixLen(routes[j])
+	})
+}
+
+// SortKernelOrder sorts routes in place in the order the kernel FIB tries them for a destination they all cover:
+// the most specific prefix first, then among equal prefixes routes with a TOS selector before those matching any
+// TOS, higher TOS values first, then the lowest metric. The sort is stable, so routes of different tables, which
+// the kernel only compares through policy rules, keep their relative order when they tie.
+func SortKernelOrder(routes []RoutingTable) {
+	sort.SliceStable(routes, func(i, j int) bool {
+		a, b := routes[i], routes[j]
+		if la, lb := prefixLen(a), prefixLen(b); la != lb {
+			return la > lb
+		}
+		if a.TOS != b.TOS {
+			return a.TOS > b.TOS
+		}
+
+		return a.Metric < b.Metric
+	})
+}
+
+// prefixLen returns the number of leading ones in a route's mask, or -1 if the mask cannot be decoded.
+func prefixLen(rt RoutingTable) int {
+	ones, err := rt.PrefixLen()
+	if err != nil {
+		return -1
+	}
+
+	return ones
+}
@@ -0,0 +1,60 @@
+package routing
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSortHelpers(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader(`default via 192.168.1.1 dev eth0 metric 100
+10.0.0.0/8 via 192.168.1.3 dev eth0 metric 20
+default via 192.168.1.2 dev wlan0 metric 50
+10.1.2.3 via 192.168.1.4 dev eth0 metric 90
+10.1.0.0/16 via 192.168.1.5 dev eth0 metric 10
+`))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	SortByMetric(routes)
+	var gateways []string
+	for _, rt := range routes {
+		gateways = append(gateways, rt.Gateway)
+	}
+	if got := strings.Join(gateways, ","); got != "192.168.1.5,192.168.1.3,192.168.1.2,192.168.1.4,192.168.1.1" {
+		t.Errorf("Unexpected metric order %s", got)
+	}
+
+	SortByPrefixLength(routes)
+	gateways = gateways[:0]
+	for _, rt := range routes {
+		gateways = append(gateways, rt.Gateway)
+	}
+	if got := strings.Join(gateways, ","); got != "192.168.1.4,192.168.1.5,192.168.1.3,192.168.1.2,192.168.1.1" {
+		t.Errorf("Unexpected prefix order %s", got)
+	}
+}
+
+func TestSortKernelOrder(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader(`default via 192.168.1.1 dev eth0 metric 1024
+10.0.0.0/8 via 192.168.1.2 dev eth0 metric 700
+10.0.0.0/8 tos 0x10 via 192.168.1.3 dev eth0 metric 50
+default via 192.168.1.4 dev wlan0 metric 600
+10.0.0.0/8 tos 0x08 via 192.168.1.5 dev eth0 metric 10
+10.1.0.0/16 via 192.168.1.6 dev eth0 metric 90
+10.0.0.0/8 via 192.168.1.7 dev wlan0 metric 600
+`))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	SortKernelOrder(routes)
+	var gateways []string
+	for _, rt := range routes {
+		gateways = append(gateways, rt.Gateway)
+	}
+	want := "192.168.1.6,192.168.1.3,192.168.1.5,192.168.1.7,192.168.1.2,192.168.1.4,192.168.1.1"
+	if got := strings.Join(gateways, ","); got != want {
+		t.Errorf("Unexpected kernel order %s, want %s", got, want)
+	}
+}
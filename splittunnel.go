@@ -0,0 +1,146 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"slices"
+	"syscall"
+)
+
+// SplitTunnelEntry tells how traffic to one destination is routed relative to a VPN interface.
+type SplitTunnelEntry struct {
+	Destination *net.IPNet // The destination; a /32 prefix for a single address.
+	Interfaces  []string   // Interfaces the traffic leaves through, in address order; several if the prefix is split.
+	Tunneled    bool       // All of the traffic goes through the VPN interface.
+	Bypasses    bool       // Some of the traffic leaves through another interface, bypassing the VPN.
+	Dropped     bool       // Some of the traffic has no usable route, e.g. because of a blackhole or unreachable route.
+}
+
+// AnalyzeSplitTunnel reports for each destination, an IPv4 address or prefix such as "10.0.0.0/8", whether its
+// traffic would go through the VPN interface vpnIface or bypass it. The kernel is asked like `ip route get` does, so
+// policy rules and every routing table are applied. Prefixes are checked at both ends and at every boundary of a route
+// or rule inside them, so a prefix only partly routed through the VPN is reported as bypassing it.
+// It is only available on Linux and returns ErrNotSupported elsewhere.
+func AnalyzeSplitTunnel(vpnIface string, destinations []string) ([]SplitTunnelEntry, error) {
+	return AnalyzeSplitTunnelContext(context.Background(), vpnIface, destinations)
+}
+
+// AnalyzeSplitTunnelContext is like AnalyzeSplitTunnel but returns early if ctx is done.
+func AnalyzeSplitTunnelContext(ctx context.Context, vpnIface string, destinations []string) ([]SplitTunnelEntry, error) {
+	routes, err := NetlinkSource{}.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := RulesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var boundaries []*net.IPNet
+	for _, rt := range routes {
+		if dst, mask, _, err := decodeDestination(rt); err == nil {
+			boundaries = append(boundaries, &net.IPNet{IP: dst, Mask: net.IPMask(mask.To4())})
+		}
+	}
+	for _, r := range rules {
+		if r.Family == FamilyIPv4 && r.Dst != nil {
+			boundaries = append(boundaries, r.Dst)
+		}
+	}
+
+	lookup := func(ctx context.Context, ip net.IP) (RoutingTable, error) { return KernelRouteToContext(ctx, ip, nil) }
+
+	return analyzeSplitTunnel(ctx, vpnIface, destinations, boundaries, lookup)
+}
+
+// analyzeSplitTunnel implements AnalyzeSplitTunnel, looking up the route of each checked address with lookup.
+func analyzeSplitTunnel(ctx context.Context, vpnIface string, destinations []string, boundaries []*net.IPNet,
+	lookup func(context.Context, net.IP) (RoutingTable, error)) ([]SplitTunnelEntry, error) {
+	entries := make([]SplitTunnelEntry, 0, len(destinations))
+	for _, d := range destinations {
+		dst, err := parseIPv4Destination(d)
+		if err != nil {
+			return nil, err
+		}
+
+		e := SplitTunnelEntry{Destination: dst, Tunneled: true}
+		for _, addr := range boundaryAddrs(dst, boundaries) {
+			ip := make(net.IP, net.IPv4len)
+			binary.BigEndian.PutUint32(ip, addr)
+			rt, err := lookup(ctx, ip)
+			var errno syscall.Errno
+			switch {
+			case errors.As(err, &errno): // The kernel has no usable route, e.g. a blackhole.
+				e.Dropped, e.Tunneled = true, false
+				continue
+			case err != nil:
+				return nil, err
+			case !rt.Type.isUnicast():
+				e.Dropped, e.Tunneled = true, false
+				continue
+			}
+			if rt.Interface != vpnIface {
+				e.Bypasses, e.Tunneled = true, false
+			}
+			if !slices.Contains(e.Interfaces, rt.Interface) {
+				e.Interfaces = append(e.Interfaces, rt.Interface)
+			}
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// parseIPv4Destination parses an IPv4 address or prefix, returning a /32 prefix for an address.
+func parseIPv4Destination(s string) (*net.IPNet, error) {
+	if ip := net.ParseIP(s); ip != nil {
+		if ip.To4() == nil {
+			return nil, &ParseError{Column: "destination", Value: s, Err: errNotIPv4}
+		}
+		return &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}, nil
+	}
+	_, dst, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, &ParseError{Column: "destination", Value: s, Err: err}
+	}
+	if dst.IP.To4() == nil {
+		return nil, &ParseError{Column: "destination", Value: s, Err: errNotIPv4}
+	}
+
+	return dst, nil
+}
+
+// boundaryAddrs returns, in order, the addresses of dst at which routing may change: its first and last address
+// and the addresses on both sides of the edges of every boundary prefix overlapping it.
+func boundaryAddrs(dst *net.IPNet, boundaries []*net.IPNet) []uint32 {
+	first, last := ipv4Range(dst)
+	addrs := []uint32{first, last}
+	for _, b := range boundaries {
+		if b.IP.To4() == nil || len(b.Mask) != net.IPv4len {
+			continue
+		}
+		bFirst, bLast := ipv4Range(b)
+		if bLast < first || bFirst > last {
+			continue
+		}
+		for _, a := range []uint32{bFirst - 1, bFirst, bLast, bLast + 1} {
+			if a >= first && a <= last {
+				addrs = append(addrs, a)
+			}
+		}
+	}
+	slices.Sort(addrs)
+
+	return slices.Compact(addrs)
+}
+
+// ipv4Range returns the first and last address of an IPv4 prefix as integers.
+func ipv4Range(p *net.IPNet) (uint32, uint32) {
+	first := binary.BigEndian.Uint32(p.IP.To4().Mask(p.Mask))
+	last := first | ^binary.BigEndian.Uint32(p.Mask)
+
+	return first, last
+}
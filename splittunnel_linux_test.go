@@ -0,0 +1,45 @@
+package routing
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeSplitTunnelKernel(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+	setLoopbackUp(t)
+
+	// Creating veth pairs and tun devices and adding rules is left to the ip command.
+	for _, args := range [][]string{
+		{"link", "add", "veth0", "type", "veth", "peer", "name", "veth1"},
+		{"link", "set", "veth0", "up"},
+		{"link", "set", "veth1", "up"},
+		{"tuntap", "add", "tun0", "mode", "tun"},
+		{"link", "set", "tun0", "up"},
+		{"rule", "add", "to", "10.20.0.0/16", "lookup", "100", "priority", "100"},
+	} {
+		if out, err := exec.Command("ip", args...).CombinedOutput(); err != nil {
+			t.Skipf("Running ip %s failed: %s: %s", strings.Join(args, " "), err, out)
+		}
+	}
+	routes, err := ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev veth0 onlink\n10.0.0.0/8 dev tun0\n" +
+		"10.20.0.0/16 dev veth0 table 100\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	for _, rt := range routes {
+		if err := AddRoute(rt); err != nil {
+			t.Fatalf("AddRoute failed %s", err.Error())
+		}
+	}
+	entries, err := AnalyzeSplitTunnel("tun0", []string{"10.1.2.3", "10.0.0.0/8", "203.0.113.1"})
+	if err != nil {
+		t.Fatalf("AnalyzeSplitTunnel failed %s", err.Error())
+	}
+	if len(entries) != 3 || !entries[0].Tunneled || !entries[1].Bypasses || !entries[2].Bypasses || entries[2].Interfaces[0] != "veth0" {
+		t.Errorf("Unexpected analysis %+v", entries)
+	}
+}
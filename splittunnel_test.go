@@ -0,0 +1,68 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestAnalyzeSplitTunnel(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("0.0.0.0/1 dev tun0\n128.0.0.0/1 dev tun0\n10.0.0.0/8 dev tun0\n" +
+		"10.20.0.0/16 via 192.168.1.1 dev eth0\n192.168.1.0/24 dev eth0\nblackhole 198.51.100.0/24\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	var boundaries []*net.IPNet
+	for _, rt := range routes {
+		dst, mask, _, _ := decodeDestination(rt)
+		boundaries = append(boundaries, &net.IPNet{IP: dst, Mask: net.IPMask(mask.To4())})
+	}
+	lookup := func(ctx context.Context, ip net.IP) (RoutingTable, error) {
+		rt, ok := LookupRoute(routes, ip)
+		if !ok {
+			return RoutingTable{}, syscall.ENETUNREACH
+		}
+		return rt, nil
+	}
+
+	entries, err := analyzeSplitTunnel(context.Background(), "tun0",
+		[]string{"203.0.113.7", "10.1.0.0/16", "10.0.0.0/8", "192.168.1.10", "198.51.100.0/25"}, boundaries, lookup)
+	if err != nil {
+		t.Fatalf("analyzeSplitTunnel failed %s", err.Error())
+	}
+	expected := []string{
+		"203.0.113.7/32 [tun0] tunneled=true bypasses=false dropped=false",
+		"10.1.0.0/16 [tun0] tunneled=true bypasses=false dropped=false",
+		"10.0.0.0/8 [tun0 eth0] tunneled=false bypasses=true dropped=false",
+		"192.168.1.10/32 [eth0] tunneled=false bypasses=true dropped=false",
+		"198.51.100.0/25 [] tunneled=false bypasses=false dropped=true",
+	}
+	for i, e := range entries {
+		got := fmt.Sprintf("%s %v tunneled=%t bypasses=%t dropped=%t", e.Destination, e.Interfaces, e.Tunneled, e.Bypasses, e.Dropped)
+		if got != expected[i] {
+			t.Errorf("Entry %d = %q, want %q", i, got, expected[i])
+		}
+	}
+
+	var perr *ParseError
+	if _, err := analyzeSplitTunnel(context.Background(), "tun0", []string{"2001:db8::1"}, nil, lookup); !errors.As(err, &perr) {
+		t.Errorf("Expected a ParseError for an IPv6 destination, got %v", err)
+	}
+}
+
+func TestBoundaryAddrs(t *testing.T) {
+	_, dst, _ := net.ParseCIDR("10.0.0.0/8")
+	_, inner, _ := net.ParseCIDR("10.20.0.0/16")
+	_, outer, _ := net.ParseCIDR("0.0.0.0/1")
+	var got []string
+	for _, a := range boundaryAddrs(dst, []*net.IPNet{inner, outer}) {
+		got = append(got, net.IPv4(byte(a>>24), byte(a>>16), byte(a>>8), byte(a)).String())
+	}
+	if fmt.Sprint(got) != "[10.0.0.0 10.19.255.255 10.20.0.0 10.20.255.255 10.21.0.0 10.255.255.255]" {
+		t.Errorf("Unexpected boundaries %v", got)
+	}
+}
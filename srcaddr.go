@@ -0,0 +1,25 @@
+package routing
+
+import (
+	"context"
+	"net"
+)
+
+// SourceAddrFor returns the local address the kernel would pick as the source of traffic to dst.
+// It connects a UDP socket to dst, which makes the kernel choose a route and source address without sending
+// anything, so it works for IPv4 and IPv6 on every platform and needs no privileges.
+func SourceAddrFor(dst net.IP) (net.IP, error) {
+	return SourceAddrForContext(context.Background(), dst)
+}
+
+// SourceAddrForContext is like SourceAddrFor but returns early if ctx is done.
+func SourceAddrForContext(ctx context.Context, dst net.IP) (net.IP, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", net.JoinHostPort(dst.String(), "9")) // Port 9 is discard; nothing is sent.
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
@@ -0,0 +1,32 @@
+package routing
+
+import (
+	"net"
+	"testing"
+)
+
+func TestSourceAddrForLoopback(t *testing.T) {
+	src, err := SourceAddrFor(net.IPv4(127, 0, 0, 1))
+	if err != nil {
+		t.Fatalf("SourceAddrFor failed %s", err.Error())
+	}
+	if !src.IsLoopback() {
+		t.Errorf("Expected a loopback source address, got %s", src)
+	}
+}
+
+func TestSourceAddrForMatchesDefaultRoute(t *testing.T) {
+	dst := net.IPv4(198, 51, 100, 1)
+	rt, err := KernelRouteTo(dst, nil)
+	if err != nil || rt.PrefSrc == "" {
+		t.Skipf("No kernel route to compare with: %v", err)
+	}
+
+	src, err := SourceAddrFor(dst)
+	if err != nil {
+		t.Fatalf("SourceAddrFor failed %s", err.Error())
+	}
+	if src.String() != rt.PrefSrc {
+		t.Errorf("Expected source %s, got %s", rt.PrefSrc, src)
+	}
+}
@@ -0,0 +1,81 @@
+package routing
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// SRv6Encap is the segment routing (SRv6) behaviour of a route: either the encapsulation of a seg6 route,
+// which steers traffic through a list of segments, or the action of a seg6local route, which processes traffic
+// addressed to a local segment.
+type SRv6Encap struct {
+	Local     bool     // The route is a seg6local route; otherwise it is a seg6 route.
+	Mode      string   // Encapsulation mode of seg6 routes, e.g. "encap" or "inline".
+	Segments  []net.IP // Segments to traverse, in order; for seg6local routes those of an End.B6 action.
+	Action    string   // Action of seg6local routes, e.g. "End.DT6".
+	Table     int      // Table looked up by End.T and End.DT* actions; zero if unset.
+	VRFTable  int      // VRF table looked up by End.DT4, End.DT6 and End.DT46 actions; zero if unset.
+	Nexthop   net.IP   // Next hop of End.X, End.DX4 and End.DX6 actions; nil if unset.
+	Interface string   // Outgoing interface of End.DX2 actions; empty if unset.
+}
+
+// String formats the encapsulation the way `ip route` prints it,
+// e.g. "encap seg6 mode encap segs 2 [ fc00::1 fc00::2 ]" or "encap seg6local action End.DT6 table 100".
+func (e SRv6Encap) String() string {
+	var b strings.Builder
+	if !e.Local {
+		fmt.Fprintf(&b, "encap seg6 mode %s %s", e.Mode, formatSegments(e.Segments))
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "encap seg6local action %s", e.Action)
+	if len(e.Segments) > 0 {
+		fmt.Fprintf(&b, " srh %s", formatSegments(e.Segments))
+	}
+	if e.Table != TableUnspec {
+		fmt.Fprintf(&b, " table %s", TableName(e.Table))
+	}
+	if e.VRFTable != TableUnspec {
+		fmt.Fprintf(&b, " vrftable %s", TableName(e.VRFTable))
+	}
+	if ip4 := e.Nexthop.To4(); ip4 != nil {
+		fmt.Fprintf(&b, " nh4 %s", ip4)
+	} else if e.Nexthop != nil {
+		fmt.Fprintf(&b, " nh6 %s", e.Nexthop)
+	}
+	if e.Interface != "" {
+		fmt.Fprintf(&b, " oif %s", e.Interface)
+	}
+
+	return b.String()
+}
+
+// formatSegments renders a segment list as ip does, e.g. "segs 2 [ fc00::1 fc00::2 ]".
+func formatSegments(segs []net.IP) string {
+	s := make([]string, len(segs))
+	for i, seg := range segs {
+		s[i] = seg.String()
+	}
+
+	return fmt.Sprintf("segs %d [ %s ]", len(segs), strings.Join(s, " "))
+}
+
+// seg6Modes names the encapsulation modes of seg6 routes by their SEG6_IPTUN_MODE_* value.
+var seg6Modes = []string{"inline", "encap", "l2encap", "encap.red", "l2encap.red"}
+
+// seg6LocalActions names the actions of seg6local routes by their SEG6_LOCAL_ACTION_* value.
+var seg6LocalActions = []string{
+	1: "End", 2: "End.X", 3: "End.T", 4: "End.DX2", 5: "End.DX6", 6: "End.DX4", 7: "End.DT6", 8: "End.DT4",
+	9: "End.B6", 10: "End.B6.Encaps", 11: "End.BM", 12: "End.S", 13: "End.AS", 14: "End.AM", 15: "End.BPF",
+	16: "End.DT46",
+}
+
+// nameOrIndex returns names[i], or i in decimal if it is unnamed.
+func nameOrIndex(names []string, i int) string {
+	if i >= 0 && i < len(names) && names[i] != "" {
+		return names[i]
+	}
+
+	return fmt.Sprint(i)
+}
@@ -0,0 +1,88 @@
+package routing
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// Lightweight tunnel attributes of routes and the SRv6 ones nested in them,
+// from linux/lwtunnel.h, linux/seg6_iptunnel.h and linux/seg6_local.h.
+const (
+	rtaEncapType           = 21
+	rtaEncap               = 22
+	lwtunnelEncapSeg6      = 5
+	lwtunnelEncapSeg6Local = 7
+	seg6IPTunnelSRH        = 1
+	seg6LocalAction        = 1
+	seg6LocalSRH           = 2
+	seg6LocalTable         = 3
+	seg6LocalNH4           = 4
+	seg6LocalNH6           = 5
+	seg6LocalOIF           = 7
+	seg6LocalVRFTable      = 9
+	sizeofSRHeader         = 8 // struct ipv6_sr_hdr without its segments.
+)
+
+// parseRouteEncap decodes the RTA_ENCAP attribute of a route given its attributes.
+// It returns nil for routes without encapsulation and for encapsulations other than SRv6.
+func parseRouteEncap(attrs map[uint16][]byte) *SRv6Encap {
+	typ, ok := attrs[rtaEncapType]
+	if !ok || len(typ) < 2 {
+		return nil
+	}
+	encap := netlinkAttrs(attrs[rtaEncap])
+
+	switch binary.NativeEndian.Uint16(typ) {
+	case lwtunnelEncapSeg6:
+		v := encap[seg6IPTunnelSRH]
+		if len(v) < 4 {
+			return nil
+		}
+		return &SRv6Encap{
+			Mode:     nameOrIndex(seg6Modes, int(int32(binary.NativeEndian.Uint32(v[0:4])))),
+			Segments: parseSRHeader(v[4:]),
+		}
+	case lwtunnelEncapSeg6Local:
+		e := &SRv6Encap{Local: true, Action: nameOrIndex(seg6LocalActions, int(nlUint32(encap[seg6LocalAction])))}
+		if v, ok := encap[seg6LocalSRH]; ok {
+			e.Segments = parseSRHeader(v)
+		}
+		if v, ok := encap[seg6LocalTable]; ok {
+			e.Table = int(nlUint32(v))
+		}
+		if v, ok := encap[seg6LocalVRFTable]; ok {
+			e.VRFTable = int(nlUint32(v))
+		}
+		if v, ok := encap[seg6LocalNH4]; ok && len(v) == net.IPv4len {
+			e.Nexthop = net.IP(append([]byte(nil), v...))
+		}
+		if v, ok := encap[seg6LocalNH6]; ok && len(v) == net.IPv6len {
+			e.Nexthop = net.IP(append([]byte(nil), v...))
+		}
+		if v, ok := encap[seg6LocalOIF]; ok {
+			e.Interface = interfaceName(int(nlUint32(v)))
+		}
+		return e
+	}
+
+	return nil
+}
+
+// parseSRHeader returns the segments of a segment routing header (struct ipv6_sr_hdr) in the order they are
+// traversed. The header stores them in reverse, the first segment to visit last.
+func parseSRHeader(b []byte) []net.IP {
+	if len(b) < sizeofSRHeader {
+		return nil
+	}
+	first := int(b[4])
+	var segs []net.IP
+	for i := first; i >= 0; i-- {
+		off := sizeofSRHeader + i*net.IPv6len
+		if off+net.IPv6len > len(b) {
+			continue
+		}
+		segs = append(segs, net.IP(append([]byte(nil), b[off:off+net.IPv6len]...)))
+	}
+
+	return segs
+}
@@ -0,0 +1,56 @@
+package routing
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+)
+
+func TestNetlinkSourceSRv6(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+	setLoopbackUp(t)
+
+	// Installing SRv6 routes is not supported by this package, so the ip command adds them.
+	for _, args := range [][]string{
+		{"-6", "route", "add", "fc00:1::/64", "encap", "seg6", "mode", "encap", "segs", "fc00::1,fc00::2", "dev", "lo"},
+		{"-6", "route", "add", "fc00:2::/64", "encap", "seg6local", "action", "End.DT6", "table", "100", "dev", "lo"},
+		{"route", "add", "198.51.100.0/24", "encap", "seg6", "mode", "encap", "segs", "fc00::3", "dev", "lo"},
+	} {
+		if out, err := exec.Command("ip", args...).CombinedOutput(); err != nil {
+			t.Skipf("Cannot add SRv6 routes: %s: %s", err, out)
+		}
+	}
+
+	routes, err := NetlinkSource{Table: TableMain}.Routes(context.Background())
+	if err != nil {
+		t.Fatalf("NetlinkSource failed %s", err.Error())
+	}
+	if len(routes) != 1 || routes[0].String() != "198.51.100.0/24 encap seg6 mode encap segs 1 [ fc00::3 ] dev lo scope link" {
+		t.Errorf("Unexpected IPv4 routes %v", routes)
+	}
+	if err := AddRoute(routes[0]); err == nil {
+		t.Error("Expected adding an SRv6 route to fail")
+	}
+
+	v6, err := NetlinkSource{Table: TableMain}.IPv6Routes(context.Background())
+	if err != nil {
+		t.Fatalf("NetlinkSource failed %s", err.Error())
+	}
+	found := make(map[string]string)
+	for _, rt := range v6 {
+		if rt.SRv6 != nil {
+			found[rt.Destination.String()] = rt.SRv6.String()
+		}
+	}
+	expected := map[string]string{
+		"fc00:1::/64": "encap seg6 mode encap segs 2 [ fc00::1 fc00::2 ]",
+		"fc00:2::/64": "encap seg6local action End.DT6 table 100",
+	}
+	for dst, encap := range expected {
+		if found[dst] != encap {
+			t.Errorf("Route %s has %q, want %q", dst, found[dst], encap)
+		}
+	}
+}
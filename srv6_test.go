@@ -0,0 +1,45 @@
+package routing
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestSRv6EncapString(t *testing.T) {
+	tests := []struct {
+		encap    SRv6Encap
+		expected string
+	}{
+		{SRv6Encap{Mode: "encap", Segments: []net.IP{net.ParseIP("fc00::1"), net.ParseIP("fc00::2")}}, "encap seg6 mode encap segs 2 [ fc00::1 fc00::2 ]"},
+		{SRv6Encap{Local: true, Action: "End.DT6", Table: 100}, "encap seg6local action End.DT6 table 100"},
+		{SRv6Encap{Local: true, Action: "End.DX4", Nexthop: net.ParseIP("192.0.2.1")}, "encap seg6local action End.DX4 nh4 192.0.2.1"},
+		{SRv6Encap{Local: true, Action: "End.B6", Segments: []net.IP{net.ParseIP("fc00::3")}}, "encap seg6local action End.B6 srh segs 1 [ fc00::3 ]"},
+	}
+	for _, tt := range tests {
+		if got := tt.encap.String(); got != tt.expected {
+			t.Errorf("String() = %q, want %q", got, tt.expected)
+		}
+	}
+
+	rt := RoutingTable{Destination: "0064A8C0", Mask: "00FFFFFF", Gateway: "0.0.0.0", Interface: "lo", SRv6: &tests[0].encap}
+	if got := rt.String(); got != "192.168.100.0/24 encap seg6 mode encap segs 2 [ fc00::1 fc00::2 ] dev lo scope link" {
+		t.Errorf("Unexpected route rendering %q", got)
+	}
+}
+
+func TestSRv6EncapJSON(t *testing.T) {
+	in := SRv6Encap{Local: true, Action: "End.DX6", Nexthop: net.ParseIP("fc00::9"), Table: 100}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal failed %s", err.Error())
+	}
+	var out SRv6Encap
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("Unmarshal failed %s", err.Error())
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("Round trip gave %+v, want %+v", out, in)
+	}
+}
@@ -0,0 +1,33 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// networkStateAttempts is how often NetworkState reads the state before giving up on it settling.
+const networkStateAttempts = 3
+
+var errNetworkUnsettled = errors.New("network configuration kept changing while it was read")
+
+// HostState is the network configuration of the host, read at one point in time.
+type HostState struct {
+	Taken      time.Time      // When the state was read.
+	Links      []Link         // The network interfaces.
+	Addresses  []Address      // The addresses assigned to the interfaces.
+	Routes     []RoutingTable // The IPv4 routes of every routing table.
+	IPv6Routes []IPv6Route    // The IPv6 routes of every routing table.
+	Rules      []Rule         // The IPv4 and IPv6 routing policy rules.
+	Neighbors  []Neighbor     // The IPv4 and IPv6 neighbor entries.
+}
+
+// NetworkState reads the host's links, addresses, routes, rules and neighbors together, so diagnostics
+// see one coherent state rather than separate reads taken at different times. The state is read again
+// if a link, address, route or rule changes while it is being read; neighbor entries change state too
+// often to be held still and are read as they are. If the configuration does not settle after three
+// attempts, the last state read is returned with an error.
+// It is only available on Linux and returns ErrNotSupported elsewhere.
+func NetworkState() (HostState, error) {
+	return NetworkStateContext(context.Background())
+}
@@ -0,0 +1,60 @@
+package routing
+
+import (
+	"context"
+	"syscall"
+	"time"
+)
+
+// NetworkStateContext is like NetworkState but returns early if ctx is done.
+func NetworkStateContext(ctx context.Context) (HostState, error) {
+	sub, err := netlinkSubscribe(syscall.RTNLGRP_LINK, syscall.RTNLGRP_IPV4_IFADDR, syscall.RTNLGRP_IPV6_IFADDR,
+		syscall.RTNLGRP_IPV4_ROUTE, syscall.RTNLGRP_IPV6_ROUTE, syscall.RTNLGRP_IPV4_RULE, syscall.RTNLGRP_IPV6_RULE)
+	if err != nil {
+		return HostState{}, err
+	}
+	defer sub.Close()
+
+	var state HostState
+	for range networkStateAttempts {
+		if state, err = readHostState(ctx); err != nil {
+			return HostState{}, err
+		}
+		changed, err := sub.Drain()
+		if err != nil {
+			return HostState{}, err
+		}
+		if !changed {
+			return state, nil
+		}
+	}
+
+	return state, errNetworkUnsettled
+}
+
+// readHostState reads every part of the host state once.
+func readHostState(ctx context.Context) (HostState, error) {
+	state := HostState{Taken: time.Now()}
+	var err error
+	if state.Links, err = Links(); err != nil {
+		return HostState{}, err
+	}
+	if state.Addresses, err = Addresses(); err != nil {
+		return HostState{}, err
+	}
+	src := NetlinkSource{Table: TableUnspec}
+	if state.Routes, err = src.Routes(ctx); err != nil {
+		return HostState{}, err
+	}
+	if state.IPv6Routes, err = src.IPv6Routes(ctx); err != nil {
+		return HostState{}, err
+	}
+	if state.Rules, err = RulesContext(ctx); err != nil {
+		return HostState{}, err
+	}
+	if state.Neighbors, err = NeighborsContext(ctx); err != nil {
+		return HostState{}, err
+	}
+
+	return state, nil
+}
@@ -0,0 +1,75 @@
+package routing
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"syscall"
+	"testing"
+)
+
+// setLoopbackUp brings up the loopback interface, which starts out down in a new network namespace.
+func setLoopbackUp(t *testing.T) {
+	t.Helper()
+	msg := make([]byte, syscall.SizeofIfInfomsg)
+	binary.NativeEndian.PutUint32(msg[4:8], 1)
+	binary.NativeEndian.PutUint32(msg[8:12], syscall.IFF_UP)
+	binary.NativeEndian.PutUint32(msg[12:16], syscall.IFF_UP)
+	if _, err := netlinkRequest(context.Background(), syscall.RTM_NEWLINK, 0, msg); err != nil {
+		t.Fatalf("Setting lo up failed %s", err.Error())
+	}
+}
+
+func TestNetworkState(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+	setLoopbackUp(t)
+
+	state, err := NetworkState()
+	if err != nil {
+		t.Fatalf("NetworkState failed %s", err.Error())
+	}
+	if len(state.Links) != 1 || state.Links[0].Name != "lo" || !state.Links[0].Up {
+		t.Errorf("Expected lo to be the only link and up, got %+v", state.Links)
+	}
+	found := false
+	for _, a := range state.Addresses {
+		found = found || a.IPNet.IP.Equal(net.IPv4(127, 0, 0, 1))
+	}
+	if !found {
+		t.Errorf("Expected 127.0.0.1 among the addresses, got %v", state.Addresses)
+	}
+	if len(state.Routes) == 0 {
+		t.Error("Expected the local routes of lo")
+	}
+	if len(state.Rules) == 0 {
+		t.Error("Expected the default rules")
+	}
+	if state.Taken.IsZero() {
+		t.Error("Expected the time the state was taken")
+	}
+}
+
+func TestSubscriptionDrain(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+
+	sub, err := netlinkSubscribe(syscall.RTNLGRP_LINK)
+	if err != nil {
+		t.Fatalf("netlinkSubscribe failed %s", err.Error())
+	}
+	defer sub.Close()
+
+	if changed, err := sub.Drain(); err != nil || changed {
+		t.Errorf("Drain = %v, %v before any change, want false", changed, err)
+	}
+	setLoopbackUp(t)
+	if changed, err := sub.Drain(); err != nil || !changed {
+		t.Errorf("Drain = %v, %v after lo came up, want true", changed, err)
+	}
+	if changed, err := sub.Drain(); err != nil || changed {
+		t.Errorf("Drain = %v, %v once drained, want false", changed, err)
+	}
+}
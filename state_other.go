@@ -0,0 +1,10 @@
+//go:build !linux
+
+package routing
+
+import "context"
+
+// NetworkStateContext always returns ErrNotSupported, as netlink is only available on Linux.
+func NetworkStateContext(ctx context.Context) (HostState, error) {
+	return HostState{}, ErrNotSupported
+}
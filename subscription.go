@@ -0,0 +1,142 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrSubscriptionClosed is returned by Subscription.Next once the subscription has been closed.
+var ErrSubscriptionClosed = errors.New("subscription closed")
+
+// SlowConsumerPolicy selects what a Watcher does when a subscriber's buffer is full.
+type SlowConsumerPolicy int
+
+const (
+	DropOldest SlowConsumerPolicy = iota // Discard the subscriber's oldest buffered change; see Subscription.Dropped.
+	Block                                // Wait until the subscriber makes room, holding up the watcher and every subscriber.
+)
+
+// Subscription receives the changes a Watcher reports, buffered independently of other subscribers.
+// It is safe for concurrent use.
+type Subscription struct {
+	w       *Watcher
+	policy  SlowConsumerPolicy
+	changes chan RouteChange
+	done    chan struct{} // Closed by Close.
+	once    sync.Once
+	dropped atomic.Uint64
+	pending []RouteChange // Changes held back under Block because the watcher's ctx was done; only touched by deliver.
+}
+
+// Subscribe returns a subscription to the changes the watcher reports, buffering up to buffer of them, at least one,
+// and handling a full buffer according to policy. If the watcher has observed the table already, the subscription
+// starts with every current route as added, as the first Next call does. Changes are delivered by Next, so a
+// goroutine must call it or Run for subscribers to receive anything.
+func (w *Watcher) Subscribe(buffer int, policy SlowConsumerPolicy) *Subscription {
+	s := &Subscription{
+		w:       w,
+		policy:  policy,
+		changes: make(chan RouteChange, max(buffer, 1)),
+		done:    make(chan struct{}),
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.previous != nil {
+		s.changes <- diffRoutes(nil, w.previous)
+	}
+	w.subs = append(w.subs, s)
+
+	return s
+}
+
+// Run polls the source until ctx is done, delivering changes to the subscribers, and returns ctx.Err().
+// Read failures are logged and retried after the poll interval.
+func (w *Watcher) Run(ctx context.Context) error {
+	for {
+		if _, err := w.Next(ctx); err == nil {
+			continue
+		}
+		if err := sleepContext(ctx, w.interval); err != nil {
+			return err
+		}
+	}
+}
+
+// Next blocks until the next change and returns it. It returns ctx.Err() once ctx is done and
+// ErrSubscriptionClosed once the subscription is closed.
+func (s *Subscription) Next(ctx context.Context) (RouteChange, error) {
+	select {
+	case change := <-s.changes:
+		return change, nil
+	case <-s.done:
+		return RouteChange{}, ErrSubscriptionClosed
+	case <-ctx.Done():
+		return RouteChange{}, ctx.Err()
+	}
+}
+
+// Dropped returns the number of changes discarded because the buffer was full under the DropOldest policy.
+// A subscriber that missed changes can read the whole table again to resynchronize.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close stops the subscription, discarding buffered changes, and releases a watcher blocked on it.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		close(s.done)
+
+		s.w.mu.Lock()
+		defer s.w.mu.Unlock()
+		if i := slices.Index(s.w.subs, s); i >= 0 {
+			s.w.subs = slices.Delete(s.w.subs, i, i+1)
+		}
+	})
+}
+
+// deliver buffers change for the subscriber, applying its policy when the buffer is full.
+// Under Block it only fails with ctx.Err(), when ctx is done while the buffer is full. The change is then held back
+// and buffered, after any held back before, by the next delivery, so that the subscriber still receives every change
+// in order.
+func (s *Subscription) deliver(ctx context.Context, change RouteChange) error {
+	if s.policy == Block {
+		s.pending = append(s.pending, change)
+		for len(s.pending) > 0 {
+			select {
+			case s.changes <- s.pending[0]:
+				s.pending = s.pending[1:]
+				continue
+			case <-s.done:
+				s.pending = nil
+				return nil
+			default:
+			}
+			select {
+			case s.changes <- s.pending[0]:
+				s.pending = s.pending[1:]
+			case <-s.done:
+				s.pending = nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+
+	for {
+		select {
+		case s.changes <- change:
+			return nil
+		default:
+		}
+		select {
+		case <-s.changes:
+			s.dropped.Add(1)
+		default: // The subscriber made room meanwhile.
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// changingSource returns a source whose default gateway changes on every read.
+func changingSource() RouteSource {
+	calls := 0
+	return routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		calls++
+		return ParseIPRoute(strings.NewReader(fmt.Sprintf("default via 192.0.2.%d dev eth0\n", calls)))
+	})
+}
+
+func TestWatcherSubscribe(t *testing.T) {
+	w := NewWatcher(changingSource(), time.Millisecond)
+	latest := w.Subscribe(1, DropOldest)
+	all := w.Subscribe(4, Block)
+
+	for range 3 {
+		if _, err := w.Next(context.Background()); err != nil {
+			t.Fatalf("Next failed %s", err.Error())
+		}
+	}
+
+	change, err := latest.Next(context.Background())
+	if err != nil || len(change.Added) != 1 || change.Added[0].Gateway != "192.0.2.3" {
+		t.Errorf("Expected only the latest change, got %+v, %v", change, err)
+	}
+	if latest.Dropped() != 2 {
+		t.Errorf("Dropped = %d, want 2", latest.Dropped())
+	}
+	for i := 1; i <= 3; i++ {
+		change, err := all.Next(context.Background())
+		if err != nil || len(change.Added) != 1 || change.Added[0].Gateway != fmt.Sprintf("192.0.2.%d", i) {
+			t.Errorf("Change %d = %+v, %v", i, change, err)
+		}
+	}
+	if all.Dropped() != 0 {
+		t.Errorf("Expected no change to be dropped, got %d", all.Dropped())
+	}
+
+	late := w.Subscribe(1, DropOldest)
+	change, err = late.Next(context.Background())
+	if err != nil || len(change.Added) != 1 || change.Added[0].Gateway != "192.0.2.3" || len(change.Removed) != 0 {
+		t.Errorf("Expected a late subscriber to start from the current table, got %+v, %v", change, err)
+	}
+
+	late.Close()
+	late.Close()
+	if _, err := late.Next(context.Background()); !errors.Is(err, ErrSubscriptionClosed) {
+		t.Errorf("Expected ErrSubscriptionClosed, got %v", err)
+	}
+}
+
+func TestWatcherSubscribeBlock(t *testing.T) {
+	w := NewWatcher(changingSource(), time.Millisecond)
+	sub := w.Subscribe(1, Block)
+	other := w.Subscribe(4, Block)
+	if _, err := w.Next(context.Background()); err != nil {
+		t.Fatalf("Next failed %s", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := w.Next(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Next to block on the full subscriber, got %v", err)
+	}
+
+	// The change sub had no room for still reached the subscriber after it, and is kept for sub.
+	for i := 1; i <= 2; i++ {
+		if change, err := other.Next(context.Background()); err != nil || change.Added[0].Gateway != fmt.Sprintf("192.0.2.%d", i) {
+			t.Errorf("Change %d of the other subscriber = %+v, %v", i, change, err)
+		}
+	}
+	next := make(chan error)
+	go func() {
+		_, err := w.Next(context.Background())
+		next <- err
+	}()
+	for i := 1; i <= 3; i++ {
+		if change, err := sub.Next(context.Background()); err != nil || change.Added[0].Gateway != fmt.Sprintf("192.0.2.%d", i) {
+			t.Errorf("Change %d of the blocking subscriber = %+v, %v", i, change, err)
+		}
+	}
+	if err := <-next; err != nil {
+		t.Fatalf("Next failed %s", err.Error())
+	}
+	if change, err := other.Next(context.Background()); err != nil || change.Added[0].Gateway != "192.0.2.3" {
+		t.Errorf("Change 3 of the other subscriber = %+v, %v", change, err)
+	}
+
+	if _, err := w.Next(context.Background()); err != nil { // Fills the buffer of sub again.
+		t.Fatalf("Next failed %s", err.Error())
+	}
+	done := make(chan error)
+	go func() {
+		_, err := w.Next(context.Background())
+		done <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	sub.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Next failed %s", err.Error())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Close to release the blocked watcher")
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	w := NewWatcher(changingSource(), time.Millisecond)
+	subs := []*Subscription{w.Subscribe(8, Block), w.Subscribe(8, Block)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	for i, sub := range subs {
+		for j := 1; j <= 3; j++ {
+			change, err := sub.Next(context.Background())
+			if err != nil || len(change.Added) != 1 || change.Added[0].Gateway != fmt.Sprintf("192.0.2.%d", j) {
+				t.Errorf("Subscriber %d, change %d = %+v, %v", i, j, change, err)
+			}
+		}
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run = %v, want context.Canceled", err)
+	}
+}
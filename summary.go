@@ -0,0 +1,95 @@
+package routing
+
+import (
+	"context"
+	"net"
+	"sort"
+)
+
+// InterfaceSummary condenses the IPv4 routes through one interface, e.g. for a dashboard.
+type InterfaceSummary struct {
+	Interface    string // The network interface.
+	Routes       int    // Number of routes through the interface, counting multipath routes once per interface.
+	Default      bool   // A default route leaves through the interface.
+	LowestMetric uint32 // Lowest metric of the routes through the interface.
+	AddressSpace uint64 // Number of IPv4 addresses covered by the routes, overlapping prefixes counted once.
+}
+
+// Summaries returns a summary of the routes through each interface, ordered by interface name.
+func Summaries() ([]InterfaceSummary, error) {
+	return SummariesContext(context.Background())
+}
+
+// SummariesContext is like Summaries but returns early if ctx is done.
+func SummariesContext(ctx context.Context) ([]InterfaceSummary, error) {
+	return defaultManager.Summaries(ctx)
+}
+
+// Summaries returns a summary of the manager's routes through each interface; see Summarize.
+func (m *Manager) Summaries(ctx context.Context) ([]InterfaceSummary, error) {
+	routes, err := m.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return Summarize(routes), nil
+}
+
+// Summarize returns a summary of the routes through each interface, ordered by interface name.
+// Routes without an interface, such as blackholes, and routes whose prefix cannot be decoded are left out.
+func Summarize(routes []RoutingTable) []InterfaceSummary {
+	type ipv4Span struct{ first, last uint32 }
+	byIface := make(map[string]*InterfaceSummary)
+	spans := make(map[string][]ipv4Span)
+
+	for _, rt := range routes {
+		dst, mask, ones, err := decodeDestination(rt)
+		if err != nil {
+			continue
+		}
+		ifaces := []string{rt.Interface}
+		if len(rt.Nexthops) > 0 {
+			ifaces = ifaces[:0]
+			for _, nh := range rt.Nexthops {
+				ifaces = append(ifaces, nh.Interface)
+			}
+		}
+
+		seen := make(map[string]bool, len(ifaces))
+		for _, iface := range ifaces {
+			if iface == "" || seen[iface] {
+				continue
+			}
+			seen[iface] = true
+
+			s, ok := byIface[iface]
+			if !ok {
+				s = &InterfaceSummary{Interface: iface, LowestMetric: rt.Metric}
+				byIface[iface] = s
+			}
+			s.Routes++
+			s.Default = s.Default || ones == 0
+			s.LowestMetric = min(s.LowestMetric, rt.Metric)
+			first, last := ipv4Range(&net.IPNet{IP: dst, Mask: net.IPMask(mask.To4())})
+			spans[iface] = append(spans[iface], ipv4Span{first, last})
+		}
+	}
+
+	summaries := make([]InterfaceSummary, 0, len(byIface))
+	for iface, s := range byIface {
+		ss := spans[iface]
+		sort.Slice(ss, func(i, j int) bool { return ss[i].first < ss[j].first })
+		var next uint64 // First address not yet counted.
+		for _, sp := range ss {
+			first, last := max(uint64(sp.first), next), uint64(sp.last)
+			if last >= first {
+				s.AddressSpace += last - first + 1
+				next = last + 1
+			}
+		}
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Interface < summaries[j].Interface })
+
+	return summaries
+}
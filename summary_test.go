@@ -0,0 +1,48 @@
+package routing
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("default via 192.168.1.1 dev eth0 metric 100\n" +
+		"192.168.1.0/24 dev eth0 proto kernel scope link src 192.168.1.20 metric 600\n" +
+		"192.168.1.128/25 via 192.168.1.1 dev eth0 metric 300\n" +
+		"10.0.0.0/8\n\tnexthop via 192.168.1.2 dev eth0 weight 1\n\tnexthop via 172.16.0.1 dev eth1 weight 1\n" +
+		"10.1.0.0/16 dev wg0\nblackhole 203.0.113.0/24\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	want := []InterfaceSummary{
+		{Interface: "eth0", Routes: 4, Default: true, LowestMetric: 0, AddressSpace: 1 << 32},
+		{Interface: "eth1", Routes: 1, AddressSpace: 1 << 24},
+		{Interface: "wg0", Routes: 1, AddressSpace: 1 << 16},
+	}
+	if got := Summarize(routes); !reflect.DeepEqual(got, want) {
+		t.Errorf("Summarize = %+v, want %+v", got, want)
+	}
+
+	want = []InterfaceSummary{{Interface: "eth0", Routes: 2, LowestMetric: 300, AddressSpace: 256}}
+	if got := Summarize(routes[1:3]); !reflect.DeepEqual(got, want) {
+		t.Errorf("Summarize = %+v, want %+v", got, want)
+	}
+}
+
+func TestSummaries(t *testing.T) {
+	useProcFS(t)
+
+	got, err := Summaries()
+	if err != nil {
+		t.Fatalf("Summaries failed %s", err.Error())
+	}
+	want := []InterfaceSummary{
+		{Interface: "eth0", Routes: 2, Default: true, LowestMetric: 100, AddressSpace: 1 << 32},
+		{Interface: "tun0", Routes: 1, AddressSpace: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Summaries = %+v, want %+v", got, want)
+	}
+}
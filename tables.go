@@ -0,0 +1,255 @@
+package routing
+
+import (
+	"bufio"
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reserved kernel routing table IDs.
+const (
+	TableUnspec  = 0   // Not a real table; selects every table when reading routes.
+	TableDefault = 253 // The "default" table, consulted after main.
+	TableMain    = 254 // The main table, the only one shown by /proc/net/route.
+	TableLocal   = 255 // The local table holding local and broadcast routes.
+)
+
+// iproute2Dirs are the directories searched for iproute2 name mapping files.
+// Newer distributions ship defaults under /usr/share and keep only overrides in /etc.
+var iproute2Dirs = []string{"/usr/share/iproute2", "/etc/iproute2"}
+
+// iproute2NamesTTL is how long the names read from an iproute2 mapping file are reused, so that formatting or
+// decoding a listing reads each file once rather than once per route.
+const iproute2NamesTTL = 5 * time.Second
+
+// iproute2Cache holds the names read from each iproute2 mapping file, keyed by file name.
+var iproute2Cache = struct {
+	sync.Mutex
+	now   func() time.Time // Clock used for expiry, replaceable in tests.
+	files map[string]cachedNames
+}{now: time.Now}
+
+// cachedNames are the names read from an iproute2 mapping file and the time they expire.
+type cachedNames struct {
+	names   map[int]string
+	expires time.Time
+}
+
+// readIPRoute2Names adds the names of an iproute2 "number name" mapping such as rt_tables, including its .d
+// directory, to names. Later files override earlier ones; missing files are ignored. The files are read again at
+// most every iproute2NamesTTL.
+func readIPRoute2Names(file string, names map[int]string) {
+	c := &iproute2Cache
+	c.Lock()
+	defer c.Unlock()
+
+	cached, ok := c.files[file]
+	if !ok || !c.now().Before(cached.expires) {
+		cached = cachedNames{names: make(map[int]string), expires: c.now().Add(iproute2NamesTTL)}
+		readIPRoute2Files(file, cached.names)
+		if c.files == nil {
+			c.files = make(map[string]cachedNames)
+		}
+		c.files[file] = cached
+	}
+	maps.Copy(names, cached.names)
+}
+
+// readIPRoute2Files reads the iproute2 mapping file and its .d directory from every directory in iproute2Dirs.
+func readIPRoute2Files(file string, names map[int]string) {
+	for _, dir := range iproute2Dirs {
+		paths := []string{filepath.Join(dir, file)}
+		extra, _ := filepath.Glob(filepath.Join(dir, file+".d", "*.conf"))
+		paths = append(paths, extra...)
+
+		for _, path := range paths {
+			f, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+			parseIPRoute2Names(f, names)
+			f.Close()
+		}
+	}
+}
+
+// parseIPRoute2Names parses lines of "number name" from f into names, skipping comments.
+func parseIPRoute2Names(f *os.File, names map[int]string) {
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		id, err := strconv.ParseInt(fields[0], 0, 64)
+		if err != nil {
+			continue
+		}
+		names[int(id)] = fields[1]
+	}
+}
+
+// tableNames returns the known routing table names keyed by ID, including the reserved tables.
+func tableNames() map[int]string {
+	names := map[int]string{
+		TableUnspec:  "unspec",
+		TableDefault: "default",
+		TableMain:    "main",
+		TableLocal:   "local",
+	}
+	readIPRoute2Names("rt_tables", names)
+
+	return names
+}
+
+// TableName returns the name of a routing table ID from rt_tables, or the ID in decimal if it has no name.
+func TableName(id int) string {
+	return nameOrNumber(tableNames(), id)
+}
+
+// nameOrNumber returns the name of id in names, or id in decimal if it has none.
+func nameOrNumber(names map[int]string, id int) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+
+	return strconv.Itoa(id)
+}
+
+// TableID resolves a routing table name such as "main" or a decimal ID to its numeric ID.
+func TableID(name string) (int, bool) {
+	return numberOrName(tableNames(), name)
+}
+
+// numberOrName is the inverse of nameOrNumber: it resolves name through names or parses it as a decimal number.
+// A name given to several numbers resolves to the lowest of them, so the result does not depend on map order.
+func numberOrName(names map[int]string, name string) (int, bool) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, true
+	}
+	for _, id := range slices.Sorted(maps.Keys(names)) {
+		if names[id] == name {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
+
+// ProtocolName returns the name of a route protocol number, e.g. "kernel" for 2 or "dhcp" for 16.
+// Names come from rt_protos, falling back to the kernel's well-known values and then to the number in decimal.
+func ProtocolName(proto int) string {
+	return nameOrNumber(protocolNames(), proto)
+}
+
+// protocolNames returns the known route protocol names keyed by number.
+func protocolNames() map[int]string {
+	names := map[int]string{
+		0: "unspec", 1: "redirect", 2: "kernel", 3: "boot", 4: "static", 8: "gated", 9: "ra",
+		10: "mrt", 11: "zebra", 12: "bird", 13: "dnrouted", 14: "xorp", 15: "ntk", 16: "dhcp",
+		17: "mrouted", 18: "keepalived", 42: "babel", 99: "openr", 186: "bgp", 187: "isis",
+		188: "ospf", 189: "rip", 192: "eigrp",
+	}
+	readIPRoute2Names("rt_protos", names)
+
+	return names
+}
+
+// ScopeName returns the name of a route scope number, e.g. "link" for 253.
+// Names come from rt_scopes, falling back to the kernel's well-known values and then to the number in decimal.
+func ScopeName(scope int) string {
+	return nameOrNumber(scopeNames(), scope)
+}
+
+// scopeNames returns the known route scope names keyed by number.
+func scopeNames() map[int]string {
+	names := map[int]string{0: "global", 200: "site", 253: "link", 254: "host", 255: "nowhere"}
+	readIPRoute2Names("rt_scopes", names)
+
+	return names
+}
+
+// RealmName returns the name of a route realm number, e.g. "cosmos" for 0.
+// Names come from rt_realms, falling back to the number in decimal.
+func RealmName(realm int) string {
+	return nameOrNumber(realmNames(), realm)
+}
+
+// realmNames returns the known route realm names keyed by number.
+func realmNames() map[int]string {
+	names := map[int]string{0: "cosmos"}
+	readIPRoute2Names("rt_realms", names)
+
+	return names
+}
+
+// ParseDSField parses a TOS value as ip accepts it: a number such as "0x10", or a name from rt_dsfield.
+func ParseDSField(s string) (uint8, bool) {
+	if v, err := strconv.ParseUint(s, 0, 8); err == nil {
+		return uint8(v), true
+	}
+	names := make(map[int]string)
+	readIPRoute2Names("rt_dsfield", names)
+	if v, ok := numberOrName(names, s); ok && v >= 0 && v <= 0xff {
+		return uint8(v), true
+	}
+
+	return 0, false
+}
+
+// routeNames holds the iproute2 name mappings used while decoding a batch of routes.
+// Loading them once per batch avoids re-reading the mapping files for every route.
+type routeNames struct {
+	protos map[int]string
+	scopes map[int]string
+	realms map[int]string
+}
+
+// loadRouteNames reads the protocol, scope and realm name mappings.
+func loadRouteNames() routeNames {
+	return routeNames{protos: protocolNames(), scopes: scopeNames(), realms: realmNames()}
+}
+
+// KernelTable describes a routing table known to the system.
+type KernelTable struct {
+	ID     int    // Numeric table ID.
+	Name   string // Name from rt_tables, or the ID in decimal.
+	Routes int    // Number of routes currently in the table; zero for named but empty tables.
+}
+
+// RouteTables lists the routing tables named in rt_tables together with every table holding routes.
+// Routes are counted with a NetlinkSource reading all tables, so it requires Linux.
+func RouteTables(ctx context.Context) ([]KernelTable, error) {
+	routes, err := NetlinkSource{}.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := tableNames()
+	counts := make(map[int]int)
+	for id := range names {
+		if id != TableUnspec {
+			counts[id] = 0
+		}
+	}
+	for _, rt := range routes {
+		counts[rt.Table]++
+	}
+
+	tables := make([]KernelTable, 0, len(counts))
+	for id, n := range counts {
+		tables = append(tables, KernelTable{ID: id, Name: nameOrNumber(names, id), Routes: n})
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].ID < tables[j].ID })
+
+	return tables, nil
+}
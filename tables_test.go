@@ -0,0 +1,141 @@
+package routing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTableNames(t *testing.T) {
+	if TableName(TableMain) != "main" || TableName(TableLocal) != "local" || TableName(4242) != "4242" {
+		t.Errorf("Unexpected table names %s %s %s", TableName(TableMain), TableName(TableLocal), TableName(4242))
+	}
+	if id, ok := TableID("default"); !ok || id != TableDefault {
+		t.Errorf("Expected default to resolve to %d, got %d", TableDefault, id)
+	}
+	if id, ok := TableID("100"); !ok || id != 100 {
+		t.Errorf("Expected a numeric table ID, got %d", id)
+	}
+}
+
+func TestTableNamesCached(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	saved := iproute2Dirs
+	iproute2Dirs = []string{dir}
+	iproute2Cache.now = func() time.Time { return now }
+	iproute2Cache.files = nil
+	t.Cleanup(func() {
+		iproute2Dirs = saved
+		iproute2Cache.now = time.Now
+		iproute2Cache.files = nil
+	})
+
+	path := filepath.Join(dir, "rt_tables")
+	if err := os.WriteFile(path, []byte("100 vpn\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := TableName(100); got != "vpn" {
+		t.Fatalf("TableName(100) = %s, want vpn", got)
+	}
+
+	// Routes formatted right after each other reuse the names rather than reading rt_tables again.
+	if err := os.WriteFile(path, []byte("100 backup\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	route := RoutingTable{Destination: "00000000", Mask: "00000000", Interface: "tun0", Table: 100}
+	if got := route.String(); !strings.Contains(got, "table vpn") {
+		t.Errorf("Expected the cached table name, got %q", got)
+	}
+
+	now = now.Add(iproute2NamesTTL)
+	if got := TableName(100); got != "backup" {
+		t.Errorf("TableName(100) = %s after the names expired, want backup", got)
+	}
+}
+
+func TestTableIDDuplicateNames(t *testing.T) {
+	dir := t.TempDir()
+	saved := iproute2Dirs
+	iproute2Dirs = []string{dir}
+	iproute2Cache.files = nil
+	t.Cleanup(func() {
+		iproute2Dirs = saved
+		iproute2Cache.files = nil
+	})
+
+	if err := os.WriteFile(filepath.Join(dir, "rt_tables"), []byte("300 vpn\n200 vpn\n100 vpn\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for range 20 {
+		if id, ok := TableID("vpn"); !ok || id != 100 {
+			t.Fatalf("TableID(vpn) = %d, want the lowest ID 100", id)
+		}
+	}
+}
+
+func TestParseIPRouteTable(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("default via 10.8.0.1 dev tun0 table 100 proto static\n10.8.0.0/24 dev tun0 scope link\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	if routes[0].Table != 100 || routes[1].Table != TableMain {
+		t.Errorf("Unexpected tables %d %d", routes[0].Table, routes[1].Table)
+	}
+	if routes[0].String() != "default via 10.8.0.1 dev tun0 table 100 proto static" {
+		t.Errorf("Unexpected route %q", routes[0].String())
+	}
+}
+
+func TestRouteTables(t *testing.T) {
+	tables, err := RouteTables(context.Background())
+	if err != nil {
+		t.Fatalf("Calling routing library failed %s", err.Error())
+	}
+
+	found := false
+	for _, table := range tables {
+		if table.ID == TableMain && table.Name == "main" {
+			found = table.Routes > 0
+		}
+	}
+	if !found {
+		t.Errorf("Expected a populated main table in %+v", tables)
+	}
+}
+
+func TestProtocolAndScopeNames(t *testing.T) {
+	if ProtocolName(16) != "dhcp" || ProtocolName(2) != "kernel" || ProtocolName(250) != "250" {
+		t.Errorf("Unexpected protocol names %s %s %s", ProtocolName(16), ProtocolName(2), ProtocolName(250))
+	}
+	if ScopeName(253) != "link" || ScopeName(0) != "global" {
+		t.Errorf("Unexpected scope names %s %s", ScopeName(253), ScopeName(0))
+	}
+}
+
+func TestRealmName(t *testing.T) {
+	if RealmName(0) != "cosmos" || RealmName(4242) != "4242" {
+		t.Errorf("Unexpected realm names %s %s", RealmName(0), RealmName(4242))
+	}
+}
+
+func TestParseIPRouteRealms(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("blackhole 198.51.100.0/24 realm 5\nblackhole 203.0.113.0/24 realms 3/7\nblackhole 192.0.2.0/24 realms 4/cosmos\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	expected := []struct{ to, from, line string }{
+		{"5", "", "blackhole 198.51.100.0/24 realm 5"},
+		{"7", "3", "blackhole 203.0.113.0/24 realms 3/7"},
+		{"cosmos", "4", "blackhole 192.0.2.0/24 realms 4/cosmos"},
+	}
+	for i, rt := range routes {
+		e := expected[i]
+		if rt.Realm != e.to || rt.FromRealm != e.from || rt.String() != e.line {
+			t.Errorf("Route %d has realms %q/%q and renders as %q, want %q/%q and %q", i, rt.FromRealm, rt.Realm, rt.String(), e.from, e.to, e.line)
+		}
+	}
+}
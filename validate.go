@@ -0,0 +1,153 @@
+package routing
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// IssueKind classifies a problem reported by Validate.
+type IssueKind uint8
+
+// Kinds of problems Validate detects.
+const (
+	IssueConflictingGateways IssueKind = iota + 1 // Routes for the same prefix and metric use different nexthops.
+	IssueDuplicateDefault                         // Several default routes in one table share a metric.
+	IssueUnreachableGateway                       // A gateway lies outside every subnet connected to its interface.
+)
+
+// String returns a short description of the kind, e.g. "conflicting gateways".
+func (k IssueKind) String() string {
+	switch k {
+	case IssueConflictingGateways:
+		return "conflicting gateways"
+	case IssueDuplicateDefault:
+		return "duplicate default route"
+	case IssueUnreachableGateway:
+		return "unreachable gateway"
+	}
+
+	return fmt.Sprintf("issue(%d)", uint8(k))
+}
+
+// RouteIssue is a problem Validate found in a routing table.
+type RouteIssue struct {
+	Kind   IssueKind      // What is wrong.
+	Routes []RoutingTable // The routes involved, in table order.
+}
+
+// String formats the issue with the routes involved, e.g. `unreachable gateway: default via 10.0.0.1 dev eth0`.
+func (i RouteIssue) String() string {
+	routes := make([]string, len(i.Routes))
+	for n, rt := range i.Routes {
+		routes[n] = rt.String()
+	}
+
+	return i.Kind.String() + ": " + strings.Join(routes, "; ")
+}
+
+// Validate checks routes for common configuration mistakes and returns the problems found, in table order.
+// More specific prefixes overriding less specific ones are normal and not reported; only identical prefixes
+// with the same metric and different nexthops are. Gateways are checked against the routes' own connected
+// subnets, so routes must include the link routes of their interfaces. Non-unicast routes are ignored.
+func Validate(routes []RoutingTable) []RouteIssue {
+	var issues []RouteIssue
+
+	type prefixKey struct {
+		table  int
+		prefix string
+		metric uint32
+	}
+	seen := make(map[prefixKey]int) // Index in issues, or -1 for a prefix seen once.
+	first := make(map[prefixKey]RoutingTable)
+	for _, rt := range routes {
+		if !rt.Type.isUnicast() {
+			continue
+		}
+		_, _, ones, err := decodeDestination(rt)
+		if err != nil {
+			continue
+		}
+		key := prefixKey{rt.Table, rt.Destination + "/" + rt.Mask, rt.Metric}
+		prev, ok := first[key]
+		if !ok {
+			first[key] = rt
+			seen[key] = -1
+			continue
+		}
+
+		kind := IssueDuplicateDefault
+		if ones > 0 {
+			if nexthopKey(prev) == nexthopKey(rt) {
+				continue
+			}
+			kind = IssueConflictingGateways
+		}
+		if n := seen[key]; n >= 0 {
+			issues[n].Routes = append(issues[n].Routes, rt)
+			continue
+		}
+		seen[key] = len(issues)
+		issues = append(issues, RouteIssue{Kind: kind, Routes: []RoutingTable{prev, rt}})
+	}
+
+	for _, rt := range routes {
+		if !rt.Type.isUnicast() {
+			continue
+		}
+		if len(rt.Nexthops) == 0 {
+			if !rt.OnLink && !gatewayConnected(routes, rt.Gateway, rt.Interface) {
+				issues = append(issues, RouteIssue{Kind: IssueUnreachableGateway, Routes: []RoutingTable{rt}})
+			}
+			continue
+		}
+		for _, nh := range rt.Nexthops {
+			if !nh.OnLink && !gatewayConnected(routes, nh.Gateway, nh.Interface) {
+				issues = append(issues, RouteIssue{Kind: IssueUnreachableGateway, Routes: []RoutingTable{rt}})
+				break
+			}
+		}
+	}
+
+	return issues
+}
+
+// nexthopKey identifies where a route sends traffic, for comparing routes to the same prefix.
+func nexthopKey(rt RoutingTable) string {
+	if len(rt.Nexthops) == 0 {
+		return rt.Gateway + " dev " + rt.Interface
+	}
+
+	keys := make([]string, len(rt.Nexthops))
+	for i, nh := range rt.Nexthops {
+		keys[i] = fmt.Sprintf("%s dev %s weight %d", nh.Gateway, nh.Interface, nh.Weight)
+	}
+
+	return strings.Join(keys, ", ")
+}
+
+// gatewayConnected reports whether gateway is unset or lies in a directly connected subnet of iface.
+func gatewayConnected(routes []RoutingTable, gateway, iface string) bool {
+	gw := net.ParseIP(gateway)
+	if gw == nil || gw.IsUnspecified() {
+		return true
+	}
+
+	for _, rt := range routes {
+		if rt.Interface != iface || !rt.Type.isUnicast() {
+			continue
+		}
+		if rtGw := net.ParseIP(rt.Gateway); rtGw != nil && !rtGw.IsUnspecified() {
+			continue
+		}
+		dst, mask, _, err := decodeDestination(rt)
+		if err != nil {
+			continue
+		}
+		if (&net.IPNet{IP: dst, Mask: net.IPMask(mask)}).Contains(gw) {
+			return true
+		}
+	}
+
+	return false
+}
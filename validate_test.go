@@ -0,0 +1,59 @@
+package routing
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	table, err := ParseIPRoute(strings.NewReader(`default via 192.168.1.1 dev eth0 metric 100
+default via 192.168.1.254 dev eth0 metric 100
+default via 10.8.0.1 dev tun0 metric 50
+10.0.0.0/8 via 192.168.1.2 dev eth0
+10.0.0.0/8 via 192.168.1.3 dev eth0
+10.1.0.0/16 via 192.168.1.3 dev eth0
+172.16.0.0/12 via 172.31.0.1 dev eth0
+172.17.0.0/16 via 172.31.0.1 dev eth0 onlink
+blackhole 10.0.0.0/8
+192.168.1.0/24 dev eth0 scope link
+`))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	issues := Validate(table)
+	want := []struct {
+		kind   IssueKind
+		routes int
+		first  string
+	}{
+		{IssueDuplicateDefault, 2, "192.168.1.1"},
+		{IssueConflictingGateways, 2, "192.168.1.2"},
+		{IssueUnreachableGateway, 1, "10.8.0.1"},
+		{IssueUnreachableGateway, 1, "172.31.0.1"},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %v", len(want), issues)
+	}
+	for i, w := range want {
+		if issues[i].Kind != w.kind || len(issues[i].Routes) != w.routes || issues[i].Routes[0].Gateway != w.first {
+			t.Errorf("Issue %d is %v, want %s for %s", i, issues[i], w.kind, w.first)
+		}
+	}
+
+	if got := issues[2].String(); got != "unreachable gateway: default via 10.8.0.1 dev tun0 metric 50" {
+		t.Errorf("Unexpected issue string %q", got)
+	}
+	if issues := Validate(table[9:]); len(issues) != 0 {
+		t.Errorf("Expected a link route alone to be valid, got %v", issues)
+	}
+
+	// DHCP clients install backup defaults with metrics such as 600 and 700.
+	backups, err := ParseIPRoute(strings.NewReader("default via 192.168.1.1 dev eth0 metric 600\ndefault via 192.168.1.254 dev eth0 metric 700\n192.168.1.0/24 dev eth0 scope link\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	if issues := Validate(backups); len(issues) != 0 {
+		t.Errorf("Expected defaults with different metrics to be valid, got %v", issues)
+	}
+}
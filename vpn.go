@@ -0,0 +1,74 @@
+package routing
+
+import (
+	"context"
+	"net"
+	"sort"
+)
+
+// vpnProbeAddr stands for "the internet" when asking the kernel for the effective default route: a documentation
+// address (RFC 5737) that no VPN pushes a specific route for, so it takes whatever route catches everything else.
+var vpnProbeAddr = net.IPv4(198, 51, 100, 1)
+
+// IsDefaultRouteVPN reports whether traffic to the internet leaves through a VPN: a tun/tap or WireGuard device.
+// The effective route is asked from the kernel, so split routes such as OpenVPN's two /1 routes and the policy
+// routing of wg-quick are taken into account. For a VPN it also returns the underlying physical path, the best
+// default route through another kind of device in any table, or nil if there is none.
+// It is only available on Linux and returns ErrNotSupported elsewhere.
+func IsDefaultRouteVPN() (bool, *RoutingTable, error) {
+	return IsDefaultRouteVPNContext(context.Background())
+}
+
+// IsDefaultRouteVPNContext is like IsDefaultRouteVPN but returns early if ctx is done.
+func IsDefaultRouteVPNContext(ctx context.Context) (bool, *RoutingTable, error) {
+	effective, err := KernelRouteToContext(ctx, vpnProbeAddr, nil)
+	if err != nil {
+		return false, nil, err
+	}
+	kinds, err := DeviceKindsContext(ctx)
+	if err != nil {
+		return false, nil, err
+	}
+	if !isVPNDevice(kinds[effective.Interface]) {
+		return false, nil, nil
+	}
+
+	routes, err := NetlinkSource{}.Routes(ctx)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return true, underlyingDefault(ClassifyDevices(routes, kinds)), nil
+}
+
+// isVPNDevice reports whether devices of kind k are typically VPN endpoints.
+func isVPNDevice(k DeviceKind) bool {
+	return k == DeviceTunTap || k == DeviceWireGuard
+}
+
+// underlyingDefault returns the default route with the lowest metric among usable ones not through an overlay device,
+// preferring the main table on ties, or nil if there is none. The routes must have been classified.
+func underlyingDefault(routes []RoutingTable) *RoutingTable {
+	var candidates []RoutingTable
+	for _, rt := range routes {
+		if rt.DeviceKind == DeviceUnknown || rt.DeviceKind.Overlay() || !isForwarding(rt.Type, rt.Flags) || !flagContains(rt.Flags, "U") {
+			continue
+		}
+		if _, _, ones, err := decodeDestination(rt); err == nil && ones == 0 {
+			candidates = append(candidates, rt)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Metric != b.Metric {
+			return a.Metric < b.Metric
+		}
+		return a.Table == TableMain && b.Table != TableMain
+	})
+
+	return &candidates[0]
+}
@@ -0,0 +1,54 @@
+package routing
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestIsDefaultRouteVPN(t *testing.T) {
+	if !inNewNetns(t) {
+		return
+	}
+	setLoopbackUp(t)
+
+	// Creating veth pairs and tun devices is left to the ip command.
+	ip := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("ip", args...).CombinedOutput(); err != nil {
+			t.Skipf("Running ip %s failed: %s: %s", strings.Join(args, " "), err, out)
+		}
+	}
+	ip("link", "add", "veth0", "type", "veth", "peer", "name", "veth1")
+	ip("link", "set", "veth0", "up")
+	ip("link", "set", "veth1", "up")
+
+	routes, err := ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev veth0 onlink\n0.0.0.0/1 dev tun0\n128.0.0.0/1 dev tun0\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	if err := AddRoute(routes[0]); err != nil {
+		t.Fatalf("AddRoute failed %s", err.Error())
+	}
+
+	vpn, underlying, err := IsDefaultRouteVPN()
+	if err != nil || vpn || underlying != nil {
+		t.Fatalf("IsDefaultRouteVPN = %t, %v, %v without a VPN", vpn, underlying, err)
+	}
+
+	ip("tuntap", "add", "tun0", "mode", "tun")
+	ip("link", "set", "tun0", "up")
+	for _, rt := range routes[1:] {
+		if err := AddRoute(rt); err != nil {
+			t.Fatalf("AddRoute failed %s", err.Error())
+		}
+	}
+
+	vpn, underlying, err = IsDefaultRouteVPN()
+	if err != nil || !vpn {
+		t.Fatalf("IsDefaultRouteVPN = %t, %v with routes through tun0", vpn, err)
+	}
+	if underlying == nil || underlying.Interface != "veth0" || underlying.Gateway != "192.0.2.1" {
+		t.Errorf("Expected the route through veth0 as the underlying path, got %v", underlying)
+	}
+}
@@ -0,0 +1,37 @@
+package routing
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnderlyingDefault(t *testing.T) {
+	routes, err := ParseIPRoute(strings.NewReader("0.0.0.0/1 dev tun0\n128.0.0.0/1 dev tun0\ndefault dev wg0 table 51820\n" +
+		"default via 192.0.2.1 dev eth0 metric 100\ndefault via 198.51.100.1 dev wlan0 metric 100 table 100\n" +
+		"default via 203.0.113.1 dev wlan0 metric 20 table 100 linkdown\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	kinds := map[string]DeviceKind{"tun0": DeviceTunTap, "wg0": DeviceWireGuard, "eth0": DevicePhysical, "wlan0": DevicePhysical}
+
+	got := underlyingDefault(ClassifyDevices(routes, kinds))
+	if got == nil || got.String() != "default via 192.0.2.1 dev eth0 metric 100" {
+		t.Errorf("Expected the eth0 default route, got %v", got)
+	}
+	backup, err := ParseIPRoute(strings.NewReader("default via 192.0.2.1 dev eth0 metric 700\ndefault via 198.51.100.1 dev wlan0 metric 600 table 100\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	if got := underlyingDefault(ClassifyDevices(backup, kinds)); got == nil || got.Interface != "wlan0" {
+		t.Errorf("Expected the wlan0 default route with metric 600, got %v", got)
+	}
+	if got := underlyingDefault(ClassifyDevices(routes[:3], kinds)); got != nil {
+		t.Errorf("Expected no underlying route, got %v", got)
+	}
+	if got := underlyingDefault(routes); got != nil {
+		t.Errorf("Expected unclassified routes to be skipped, got %v", got)
+	}
+	if !isVPNDevice(DeviceWireGuard) || isVPNDevice(DeviceVXLAN) {
+		t.Error("Expected only tun/tap and WireGuard devices to be VPNs")
+	}
+}
@@ -0,0 +1,215 @@
+package routing
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RouteChange describes the difference between two observations of the routing table.
+type RouteChange struct {
+	Added   []RoutingTable // Routes present now that were not present before.
+	Removed []RoutingTable // Routes present before that are no longer present.
+}
+
+// Watcher polls a RouteSource and reports changes to the routing table.
+// Next and Run must not be called concurrently, but any number of goroutines may Subscribe to the changes.
+type Watcher struct {
+	source   RouteSource
+	interval time.Duration
+	quiet    time.Duration           // Quiet period changes are coalesced over; zero reports every change seen. See Coalesce.
+	maxDelay time.Duration           // Longest a coalesced change is held back; zero waits for the table to settle.
+	previous map[string]RoutingTable // Routes from the last observation, keyed by their String form.
+	routes   []RoutingTable          // The last observation in source order.
+	logger   *slog.Logger            // Receives read failures and recoveries; set by Manager.Watch.
+	failing  bool                    // The last read failed.
+
+	mu   sync.Mutex      // Guards subs, and previous and routes against Subscribe.
+	subs []*Subscription // Subscribers the changes are delivered to.
+}
+
+// NewWatcher returns a Watcher polling source every interval.
+// A non-positive interval defaults to one second.
+func NewWatcher(source RouteSource, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	return &Watcher{source: source, interval: interval, logger: discardLogger}
+}
+
+// Coalesce makes the watcher deliver bursts of changes, such as the dozens of routes an interface flap removes and
+// installs again, as a single change: once a change is seen, the table is read again every quiet period until it
+// stops changing, and the net difference is reported, or nothing if the table is back as it was. With a positive
+// maxDelay, a change is reported at the latest that long after it was seen even if the table keeps changing.
+// A zero quiet period restores the default raw mode, reporting every change as soon as it is seen.
+// It must be called before the watcher is used and returns w.
+func (w *Watcher) Coalesce(quiet, maxDelay time.Duration) *Watcher {
+	w.quiet, w.maxDelay = max(quiet, 0), max(maxDelay, 0)
+
+	return w
+}
+
+// Next blocks until the routing table changes and returns the difference.
+// The first call reports every current route as added. It returns ctx.Err() once ctx is done. If ctx is done while
+// a Block subscriber's buffer is full, the change still reaches the other subscribers and is buffered for that one
+// by the next call.
+func (w *Watcher) Next(ctx context.Context) (RouteChange, error) {
+	first := w.previous == nil
+
+	for {
+		routes, current, err := w.read(ctx)
+		if err != nil {
+			return RouteChange{}, err
+		}
+
+		change := diffRoutes(w.previous, current)
+		changed := len(change.Added) > 0 || len(change.Removed) > 0
+		if changed && !first && w.quiet > 0 {
+			if routes, current, err = w.settle(ctx, current); err != nil {
+				return RouteChange{}, err
+			}
+			change = diffRoutes(w.previous, current)
+			changed = len(change.Added) > 0 || len(change.Removed) > 0
+		}
+
+		w.mu.Lock()
+		w.previous, w.routes = current, routes
+		subs := slices.Clone(w.subs)
+		w.mu.Unlock()
+		if first || changed {
+			// A subscriber blocking until ctx is done must not keep the change from those after it.
+			var deliverErr error
+			for _, sub := range subs {
+				if err := sub.deliver(ctx, change); err != nil && deliverErr == nil {
+					deliverErr = err
+				}
+			}
+			if deliverErr != nil {
+				return RouteChange{}, deliverErr
+			}
+			return change, nil
+		}
+
+		if err := sleepContext(ctx, w.interval); err != nil {
+			return RouteChange{}, err
+		}
+	}
+}
+
+// settle reads the table every quiet period until it matches the previous read, or until maxDelay has passed,
+// starting from the changed table current. It returns the last read.
+func (w *Watcher) settle(ctx context.Context, current map[string]RoutingTable) ([]RoutingTable, map[string]RoutingTable, error) {
+	var deadline time.Time
+	if w.maxDelay > 0 {
+		deadline = time.Now().Add(w.maxDelay)
+	}
+
+	for {
+		wait := w.quiet
+		if !deadline.IsZero() {
+			wait = min(wait, time.Until(deadline))
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, nil, err
+		}
+		routes, next, err := w.read(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		settled := diffRoutes(current, next)
+		if len(settled.Added) == 0 && len(settled.Removed) == 0 || !deadline.IsZero() && !time.Now().Before(deadline) {
+			return routes, next, nil
+		}
+		current = next
+	}
+}
+
+// read reads the table from the source, keyed by the String form of the routes, logging failures and recoveries.
+func (w *Watcher) read(ctx context.Context) ([]RoutingTable, map[string]RoutingTable, error) {
+	routes, err := w.source.Routes(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Warn("watcher failed to read the routing table", "err", err)
+			w.failing = true
+		}
+		return nil, nil, err
+	}
+	if w.failing {
+		w.logger.Info("watcher reading the routing table again")
+		w.failing = false
+	}
+
+	current := make(map[string]RoutingTable, len(routes))
+	for _, rt := range routes {
+		current[rt.String()] = rt
+	}
+
+	return routes, current, nil
+}
+
+// OnDefaultGatewayChange calls fn whenever the default route of the routing table changes gateway or interface,
+// polling /proc/net/route every second. It blocks until ctx is done or the table cannot be read and returns that error.
+func OnDefaultGatewayChange(ctx context.Context, fn func(old, new RoutingTable)) error {
+	return defaultManager.OnDefaultGatewayChange(ctx, fn)
+}
+
+// OnDefaultGatewayChange is like the package-level OnDefaultGatewayChange but watches the manager's source.
+// The default route found on the first read is the baseline and is not reported. A zero RoutingTable stands for
+// no default route, as old when one appears and as new when it goes away.
+func (m *Manager) OnDefaultGatewayChange(ctx context.Context, fn func(old, new RoutingTable)) error {
+	w := m.Watch(0)
+	if _, err := w.Next(ctx); err != nil {
+		return err
+	}
+	current, _ := defaultRoute(w.routes)
+
+	for {
+		if _, err := w.Next(ctx); err != nil {
+			return err
+		}
+		next, _ := defaultRoute(w.routes)
+		if next.Gateway != current.Gateway || next.Interface != current.Interface {
+			fn(current, next)
+		}
+		current = next
+	}
+}
+
+// DiffRoutes compares two routing tables, such as one saved earlier and the current one: routes only after holds
+// are reported as added, routes only before holds as removed. Routes are compared by their String form.
+func DiffRoutes(before, after []RoutingTable) RouteChange {
+	keyed := func(routes []RoutingTable) map[string]RoutingTable {
+		m := make(map[string]RoutingTable, len(routes))
+		for _, rt := range routes {
+			m[rt.String()] = rt
+		}
+		return m
+	}
+
+	return diffRoutes(keyed(before), keyed(after))
+}
+
+// diffRoutes compares two keyed observations of the routing table.
+// Added and removed routes are sorted by their String form so the result is stable.
+func diffRoutes(previous, current map[string]RoutingTable) RouteChange {
+	var change RouteChange
+
+	for key, rt := range current {
+		if _, ok := previous[key]; !ok {
+			change.Added = append(change.Added, rt)
+		}
+	}
+	for key, rt := range previous {
+		if _, ok := current[key]; !ok {
+			change.Removed = append(change.Removed, rt)
+		}
+	}
+	sort.Slice(change.Added, func(i, j int) bool { return change.Added[i].String() < change.Added[j].String() })
+	sort.Slice(change.Removed, func(i, j int) bool { return change.Removed[i].String() < change.Removed[j].String() })
+
+	return change
+}
@@ -0,0 +1,219 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatcherNext(t *testing.T) {
+	tables := []string{
+		"default via 192.168.1.1 dev eth0\n192.168.1.0/24 dev eth0\n",
+		"default via 192.168.1.1 dev eth0\n192.168.1.0/24 dev eth0\n",
+		"default via 10.8.0.1 dev tun0\n192.168.1.0/24 dev eth0\n",
+	}
+	calls := 0
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		text := tables[min(calls, len(tables)-1)]
+		calls++
+		return ParseIPRoute(strings.NewReader(text))
+	})
+
+	w := NewWatcher(src, time.Millisecond)
+
+	change, err := w.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next failed %s", err.Error())
+	}
+	if len(change.Added) != 2 || len(change.Removed) != 0 {
+		t.Errorf("Expected the initial table as added, got %+v", change)
+	}
+
+	change, err = w.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next failed %s", err.Error())
+	}
+	if calls != 3 {
+		t.Errorf("Expected the unchanged table to be polled again, got %d calls", calls)
+	}
+	if len(change.Added) != 1 || change.Added[0].Gateway != "10.8.0.1" || len(change.Removed) != 1 || change.Removed[0].Gateway != "192.168.1.1" {
+		t.Errorf("Unexpected change %+v", change)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := w.Next(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline error, got %v", err)
+	}
+}
+
+func TestGetLinuxRoutingTableContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	table := new([]RoutingTable)
+	if err := GetLinuxRoutingTableContext(ctx, table); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancellation error, got %v", err)
+	}
+}
+
+func TestWatcherLogsRecovery(t *testing.T) {
+	fail := true
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		if fail {
+			return nil, errors.New("source unavailable")
+		}
+		return ParseIPRoute(strings.NewReader("default via 192.168.1.1 dev eth0\n"))
+	})
+
+	var buf bytes.Buffer
+	w := NewManager(WithSource(src), WithLogger(slog.New(slog.NewTextHandler(&buf, nil)))).Watch(time.Millisecond)
+	if _, err := w.Next(context.Background()); err == nil {
+		t.Fatal("Expected the source error")
+	}
+	fail = false
+	if _, err := w.Next(context.Background()); err != nil {
+		t.Fatalf("Next failed %s", err.Error())
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "level=WARN") || !strings.Contains(out, "source unavailable") {
+		t.Errorf("Expected the failure to be logged, got %q", out)
+	}
+	if !strings.Contains(out, "reading the routing table again") {
+		t.Errorf("Expected the recovery to be logged, got %q", out)
+	}
+}
+
+func TestOnDefaultGatewayChange(t *testing.T) {
+	tables := []string{
+		"default via 192.168.1.1 dev eth0\n192.168.1.0/24 dev eth0\n",
+		"default via 192.168.1.1 dev eth0\n192.168.1.0/24 dev eth0\n10.0.0.0/8 via 192.168.1.2 dev eth0\n",
+		"default via 10.8.0.1 dev tun0\n192.168.1.0/24 dev eth0\n",
+		"192.168.1.0/24 dev eth0\n",
+	}
+	calls := 0
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		if calls == len(tables) {
+			return nil, errors.New("done")
+		}
+		calls++
+		return ParseIPRoute(strings.NewReader(tables[calls-1]))
+	})
+
+	var got []string
+	err := NewManager(WithSource(src)).OnDefaultGatewayChange(context.Background(), func(old, new RoutingTable) {
+		got = append(got, old.Gateway+" -> "+new.Gateway)
+	})
+	if err == nil || err.Error() != "done" {
+		t.Errorf("Expected the source error, got %v", err)
+	}
+	if want := "192.168.1.1 -> 10.8.0.1, 10.8.0.1 -> "; strings.Join(got, ", ") != want {
+		t.Errorf("Got changes %q, want %q", strings.Join(got, ", "), want)
+	}
+}
+
+func TestDiffRoutes(t *testing.T) {
+	before, err := ParseIPRoute(strings.NewReader("default via 192.168.1.1 dev eth0\n10.0.0.0/8 via 192.168.1.254 dev eth0\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	after, err := ParseIPRoute(strings.NewReader("default via 192.168.1.1 dev eth0\n10.0.0.0/8 via 192.168.1.253 dev eth0\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+
+	change := DiffRoutes(before, after)
+	if len(change.Added) != 1 || change.Added[0].Gateway != "192.168.1.253" {
+		t.Errorf("Unexpected added routes %v", change.Added)
+	}
+	if len(change.Removed) != 1 || change.Removed[0].Gateway != "192.168.1.254" {
+		t.Errorf("Unexpected removed routes %v", change.Removed)
+	}
+	if change := DiffRoutes(before, before); len(change.Added)+len(change.Removed) != 0 {
+		t.Errorf("Expected no change, got %+v", change)
+	}
+}
+
+// scriptedSource returns the tables in order, repeating the last one.
+func scriptedSource(t *testing.T, tables ...string) RouteSource {
+	calls := 0
+	return routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		text := tables[min(calls, len(tables)-1)]
+		calls++
+		routes, err := ParseIPRoute(strings.NewReader(text))
+		if err != nil {
+			t.Errorf("ParseIPRoute failed %s", err.Error())
+		}
+		return routes, err
+	})
+}
+
+func TestWatcherCoalesce(t *testing.T) {
+	base := "default via 192.168.1.1 dev eth0\n"
+	w := NewWatcher(scriptedSource(t,
+		base,
+		base+"10.1.0.0/16 dev eth1\n",
+		base+"10.1.0.0/16 dev eth1\n10.2.0.0/16 dev eth1\n",
+	), time.Millisecond).Coalesce(time.Millisecond, 0)
+
+	if _, err := w.Next(context.Background()); err != nil {
+		t.Fatalf("Next failed %s", err.Error())
+	}
+	change, err := w.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next failed %s", err.Error())
+	}
+	if len(change.Added) != 2 || len(change.Removed) != 0 {
+		t.Errorf("Expected the burst as one change, got %+v", change)
+	}
+}
+
+func TestWatcherCoalesceFlap(t *testing.T) {
+	base := "default via 192.168.1.1 dev eth0\n192.168.1.0/24 dev eth0\n"
+	w := NewWatcher(scriptedSource(t,
+		base,
+		"",
+		base,
+		base,
+		base+"10.1.0.0/16 dev eth1\n",
+	), time.Millisecond).Coalesce(time.Millisecond, 0)
+
+	if _, err := w.Next(context.Background()); err != nil {
+		t.Fatalf("Next failed %s", err.Error())
+	}
+	change, err := w.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next failed %s", err.Error())
+	}
+	if len(change.Added) != 1 || change.Added[0].Interface != "eth1" || len(change.Removed) != 0 {
+		t.Errorf("Expected the flap to be hidden, got %+v", change)
+	}
+}
+
+func TestWatcherCoalesceMaxDelay(t *testing.T) {
+	calls := 0
+	src := routeSourceFunc(func(ctx context.Context) ([]RoutingTable, error) {
+		calls++
+		return ParseIPRoute(strings.NewReader(fmt.Sprintf("default via 192.0.2.%d dev eth0\n", calls)))
+	})
+	w := NewWatcher(src, time.Millisecond).Coalesce(time.Millisecond, 20*time.Millisecond)
+	if _, err := w.Next(context.Background()); err != nil {
+		t.Fatalf("Next failed %s", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	change, err := w.Next(ctx)
+	if err != nil {
+		t.Fatalf("Expected a change despite the churn, got %s", err.Error())
+	}
+	if len(change.Added) != 1 || len(change.Removed) != 1 || change.Removed[0].Gateway != "192.0.2.1" {
+		t.Errorf("Expected the net change since the first table, got %+v", change)
+	}
+}
@@ -0,0 +1,165 @@
+package routing
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strings"
+)
+
+// WireGuardReport compares the AllowedIPs of a WireGuard interface with the routes through it.
+type WireGuardReport struct {
+	Interface string       // The WireGuard interface, e.g. "wg0".
+	Unrouted  []*net.IPNet // AllowedIPs not covered by routes through the interface: traffic to them leaves elsewhere.
+	Unallowed []*net.IPNet // Destinations routed through the interface that no peer allows: WireGuard drops that traffic.
+}
+
+// OK reports whether the AllowedIPs and the routes through the interface match.
+func (r WireGuardReport) OK() bool {
+	return len(r.Unrouted) == 0 && len(r.Unallowed) == 0
+}
+
+// CheckWireGuard compares the AllowedIPs of the WireGuard interface iface, read with `wg show`, with the IPv4 and
+// IPv6 routes of every routing table, such as the separate table wg-quick uses for a full tunnel.
+// Reading the routes is only available on Linux and returns ErrNotSupported elsewhere.
+func CheckWireGuard(iface string) (WireGuardReport, error) {
+	return CheckWireGuardContext(context.Background(), iface)
+}
+
+// CheckWireGuardContext is like CheckWireGuard but returns early if ctx is done.
+func CheckWireGuardContext(ctx context.Context, iface string) (WireGuardReport, error) {
+	allowed, err := WireGuardAllowedIPsContext(ctx, iface)
+	if err != nil {
+		return WireGuardReport{}, err
+	}
+	routes, err := NetlinkSource{}.Routes(ctx)
+	if err != nil {
+		return WireGuardReport{}, err
+	}
+	v6, err := NetlinkSource{}.IPv6Routes(ctx)
+	if err != nil {
+		return WireGuardReport{}, err
+	}
+
+	return CheckWireGuardRoutes(iface, allowed, routes, v6), nil
+}
+
+// CheckWireGuardRoutes compares the AllowedIPs of the WireGuard interface iface with the routes through it.
+// A prefix counts as routed, or allowed, when it is covered by one prefix of the other side or by several together,
+// as when wg-quick splits 0.0.0.0/0 into two /1 routes. Routes that drop traffic, such as blackholes, are ignored.
+func CheckWireGuardRoutes(iface string, allowed []*net.IPNet, routes []RoutingTable, v6routes []IPv6Route) WireGuardReport {
+	var routed []*net.IPNet
+	for _, rt := range routes {
+		if !rt.Type.isUnicast() || !routesThrough(rt, iface) {
+			continue
+		}
+		dst, mask, _, err := decodeDestination(rt)
+		if err == nil {
+			routed = append(routed, &net.IPNet{IP: dst, Mask: net.IPMask(mask.To4())})
+		}
+	}
+	for _, rt := range v6routes {
+		if rt.Type.isUnicast() && rt.Interface == iface && rt.Destination != nil {
+			routed = append(routed, rt.Destination)
+		}
+	}
+
+	report := WireGuardReport{Interface: iface}
+	for _, p := range allowed {
+		if !prefixCovered(p, routed) {
+			report.Unrouted = append(report.Unrouted, p)
+		}
+	}
+	for _, p := range routed {
+		if !prefixCovered(p, allowed) {
+			report.Unallowed = append(report.Unallowed, p)
+		}
+	}
+
+	return report
+}
+
+// routesThrough reports whether rt sends traffic through iface, on any of its paths.
+func routesThrough(rt RoutingTable, iface string) bool {
+	if rt.Interface == iface {
+		return true
+	}
+	for _, nh := range rt.Nexthops {
+		if nh.Interface == iface {
+			return true
+		}
+	}
+
+	return false
+}
+
+// prefixCovered reports whether every address of p belongs to one of the prefixes of cover.
+func prefixCovered(p *net.IPNet, cover []*net.IPNet) bool {
+	ones, bits := p.Mask.Size()
+	var inside bool
+	for _, c := range cover {
+		cOnes, cBits := c.Mask.Size()
+		if cBits != bits {
+			continue
+		}
+		if cOnes <= ones && c.Contains(p.IP) {
+			return true
+		}
+		inside = inside || cOnes > ones && p.Contains(c.IP)
+	}
+	if !inside || ones == bits {
+		return false
+	}
+
+	// Only prefixes inside p are left, which may cover it together: check both of its halves.
+	mask := net.CIDRMask(ones+1, bits)
+	lower := &net.IPNet{IP: p.IP.Mask(p.Mask), Mask: mask}
+	upper := &net.IPNet{IP: append(net.IP(nil), lower.IP...), Mask: mask}
+	upper.IP[ones/8] |= 0x80 >> (ones % 8)
+
+	return prefixCovered(lower, cover) && prefixCovered(upper, cover)
+}
+
+// WireGuardAllowedIPs returns the AllowedIPs of every peer of the WireGuard interface iface, by running
+// `wg show iface allowed-ips`, which needs the wg tool and usually root privileges.
+func WireGuardAllowedIPs(iface string) ([]*net.IPNet, error) {
+	return WireGuardAllowedIPsContext(context.Background(), iface)
+}
+
+// WireGuardAllowedIPsContext is like WireGuardAllowedIPs but returns early if ctx is done.
+func WireGuardAllowedIPsContext(ctx context.Context, iface string) ([]*net.IPNet, error) {
+	out, err := exec.CommandContext(ctx, "wg", "show", iface, "allowed-ips").Output()
+	if err != nil {
+		return nil, fmt.Errorf("running wg: %w", err)
+	}
+
+	return ParseWireGuardAllowedIPs(strings.NewReader(string(out)))
+}
+
+// ParseWireGuardAllowedIPs parses the output of `wg show <interface> allowed-ips`: a line per peer holding its
+// public key and its prefixes, or "(none)" for peers without any.
+func ParseWireGuardAllowedIPs(r io.Reader) ([]*net.IPNet, error) {
+	var allowed []*net.IPNet
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		for _, f := range fields[1:] {
+			if f == "(none)" {
+				continue
+			}
+			_, p, err := net.ParseCIDR(f)
+			if err != nil {
+				return nil, &ParseError{Line: n, Column: "allowed ips", Value: f, Err: err}
+			}
+			allowed = append(allowed, p)
+		}
+	}
+
+	return allowed, scanner.Err()
+}
@@ -0,0 +1,59 @@
+package routing
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+const wgAllowedIPsFixture = "xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=\t10.9.0.0/24 fd00:9::/64\n" +
+	"TrMvSoP4jYQlY6RIzBgbssQqY3vxI2Pi+y71lOWWXX0=\t0.0.0.0/0\n" +
+	"gN65BkIKy1eCE9pP1wdc8ROUtkHLF2PfAqYdyYBz6EA=\t(none)\n"
+
+func TestParseWireGuardAllowedIPs(t *testing.T) {
+	allowed, err := ParseWireGuardAllowedIPs(strings.NewReader(wgAllowedIPsFixture))
+	if err != nil {
+		t.Fatalf("ParseWireGuardAllowedIPs failed %s", err.Error())
+	}
+	if fmt.Sprint(allowed) != "[10.9.0.0/24 fd00:9::/64 0.0.0.0/0]" {
+		t.Errorf("Unexpected AllowedIPs %v", allowed)
+	}
+
+	if _, err := ParseWireGuardAllowedIPs(strings.NewReader("key\t10.9.0.0/33\n")); err == nil {
+		t.Error("Expected an invalid prefix to fail")
+	}
+}
+
+func TestCheckWireGuardRoutes(t *testing.T) {
+	allowed, err := ParseWireGuardAllowedIPs(strings.NewReader(wgAllowedIPsFixture))
+	if err != nil {
+		t.Fatalf("ParseWireGuardAllowedIPs failed %s", err.Error())
+	}
+	routes, err := ParseIPRoute(strings.NewReader("0.0.0.0/1 dev wg0\n128.0.0.0/1 dev wg0\n10.9.0.0/24 dev wg0\n" +
+		"default via 192.0.2.1 dev eth0\nblackhole 198.51.100.0/24\n"))
+	if err != nil {
+		t.Fatalf("ParseIPRoute failed %s", err.Error())
+	}
+	_, v6dst, _ := net.ParseCIDR("fd00:8::/64")
+	v6 := []IPv6Route{{Destination: v6dst, Interface: "wg0"}}
+
+	report := CheckWireGuardRoutes("wg0", allowed, routes, v6)
+	if fmt.Sprint(report.Unrouted) != "[fd00:9::/64]" || fmt.Sprint(report.Unallowed) != "[fd00:8::/64]" || report.OK() {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	report = CheckWireGuardRoutes("wg0", allowed[:1], routes[:1], nil)
+	if len(report.Unrouted) != 0 || fmt.Sprint(report.Unallowed) != "[0.0.0.0/1]" {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	// Without the second half of the split default route, 0.0.0.0/0 is no longer fully routed.
+	report = CheckWireGuardRoutes("wg0", allowed[2:], routes[:1], nil)
+	if fmt.Sprint(report.Unrouted) != "[0.0.0.0/0]" || len(report.Unallowed) != 0 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if report := CheckWireGuardRoutes("wg0", allowed[2:], routes[:2], nil); !report.OK() {
+		t.Errorf("Expected the split default route to cover 0.0.0.0/0, got %+v", report)
+	}
+}